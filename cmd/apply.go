@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikefero/osiris/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFile        string
+	applyDryRun      bool
+	applyStrategy    string
+	applyOnlyMissing bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Apply a configuration to a control plane",
+	Long: `The apply command creates or replaces the items of a configuration file (a
+previous dump) on a control plane. Resources are applied in topological order
+(root nodes first), and all references within the file are validated before
+any item is written. With the patch strategy only the fields present in the
+file are updated on existing items.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		startCtx, startCancel := context.WithCancel(context.Background())
+		defer startCancel()
+		app := app.NewApply(app.ApplyOptions{
+			File:        applyFile,
+			DryRun:      applyDryRun,
+			Strategy:    applyStrategy,
+			OnlyMissing: applyOnlyMissing,
+			Output:      cmd.OutOrStdout(),
+		})
+		if err := app.Start(startCtx); err != nil {
+			return fmt.Errorf("unable to start apply operation: %w", err)
+		}
+
+		stopCtx, stopCancel := context.WithCancel(context.Background())
+		defer stopCancel()
+		if err := app.Stop(stopCtx); err != nil {
+			return fmt.Errorf("unable to stop apply operation: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	applyCmd.Flags().StringVar(&applyFile, "file", "",
+		"configuration file (a previous dump) to apply to the control plane")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false,
+		"validate the configuration file without applying it")
+	applyCmd.Flags().StringVar(&applyStrategy, "strategy", app.ApplyStrategyPut,
+		"how items are written: put replaces each item, patch updates only the fields present in the file")
+	applyCmd.Flags().BoolVar(&applyOnlyMissing, "only-missing", false,
+		"only create the items that do not exist on the control plane; existing items are skipped")
+	applyCmd.MarkFlagsMutuallyExclusive("strategy", "only-missing")
+	_ = applyCmd.MarkFlagRequired("file")
+	rootCmd.AddCommand(applyCmd)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var (
+	configInitFile  string
+	configInitForce bool
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the osiris configuration",
+}
+
+var configInitCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Generate an example configuration file",
+	Long: `The init command writes a commented example configuration file with all
+keys set to their default values. An existing file is not overwritten unless
+--force is specified.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if err := config.WriteExampleFile(configInitFile, configInitForce); err != nil {
+			return fmt.Errorf("unable to write example configuration: %w", err)
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Wrote example configuration to %s\n", configInitFile)
+		return nil
+	},
+}
+
+func init() {
+	configInitCmd.Flags().StringVar(&configInitFile, "file", config.DefaultExampleFilename,
+		"file to write the example configuration to")
+	configInitCmd.Flags().BoolVar(&configInitForce, "force", false,
+		"overwrite the configuration file if it already exists")
+	configCmd.AddCommand(configInitCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// errNoControlPlanes is returned when the control planes file does not
+// contain a valid control plane.
+var errNoControlPlanes = errors.New("no valid control planes found")
+
+// forEachControlPlane runs the operation for each control plane listed in the
+// control planes file, or once for the configured control plane if no file is
+// specified. Invalid lines are reported and skipped, and a failing control
+// plane does not prevent the remaining control planes from being processed.
+// The configuration is loaded once and shared by the control planes; up to
+// control_plane_concurrency control planes are processed simultaneously
+// unless serial is set (e.g. when prompting the operator).
+func forEachControlPlane(cmd *cobra.Command, filename string, serial bool,
+	run func(*config.Config, *config.ControlPlane) error,
+) error {
+	if len(filename) == 0 {
+		return run(nil, nil)
+	}
+
+	controlPlanes, invalid, err := config.ReadControlPlanesFile(filename)
+	if err != nil {
+		return err
+	}
+	for _, err := range invalid {
+		fmt.Fprintf(cmd.ErrOrStderr(), "skipping %s: %v\n", filename, err)
+	}
+	if len(controlPlanes) == 0 {
+		return fmt.Errorf("%w: %s", errNoControlPlanes, filename)
+	}
+
+	cfg, err := config.NewConfig()
+	if err != nil {
+		return fmt.Errorf("unable to load config: %w", err)
+	}
+	concurrency := cfg.ControlPlaneConcurrency
+	if serial {
+		concurrency = 1
+	}
+	return runControlPlanes(controlPlanes, concurrency, func(controlPlane *config.ControlPlane) error {
+		return run(cfg, controlPlane)
+	})
+}
+
+// runControlPlanes runs the operation for each control plane using a pool of
+// at most concurrency workers; the control planes are processed one at a time
+// if concurrency is less than two. The errors of the control planes are
+// isolated from one another and returned together, in the order of the
+// control planes, once every control plane has been processed.
+func runControlPlanes(controlPlanes []config.ControlPlane, concurrency int,
+	run func(*config.ControlPlane) error,
+) error {
+	concurrency = max(1, min(concurrency, len(controlPlanes)))
+	errs := make([]error, len(controlPlanes))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				controlPlane := controlPlanes[i]
+				if err := run(&controlPlane); err != nil {
+					errs[i] = fmt.Errorf("control plane %s: %w", controlPlane.ID, err)
+				}
+			}
+		}()
+	}
+	for i := range controlPlanes {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errors.Join(errs...)
+}
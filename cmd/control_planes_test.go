@@ -0,0 +1,177 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunControlPlanes(t *testing.T) {
+	controlPlanes := []config.ControlPlane{
+		{ID: uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f")},
+		{ID: uuid.MustParse("c0ffee00-015e-4190-837e-0fcc5d72a52f")},
+		{ID: uuid.MustParse("deadbeef-015e-4190-837e-0fcc5d72a52f")},
+	}
+
+	t.Run("verify control planes are serialized with a concurrency of one", func(t *testing.T) {
+		dir := t.TempDir()
+		var inFlight, maxInFlight atomic.Int32
+		err := runControlPlanes(controlPlanes, 1, func(controlPlane *config.ControlPlane) error {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				maximum := maxInFlight.Load()
+				if current <= maximum || maxInFlight.CompareAndSwap(maximum, current) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			filename := controlPlane.Filename(filepath.Join(dir, "osiris.json"))
+			return os.WriteFile(filename, []byte(controlPlane.ID.String()), 0o600)
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(1), maxInFlight.Load())
+
+		for _, controlPlane := range controlPlanes {
+			data, err := os.ReadFile(controlPlane.Filename(filepath.Join(dir, "osiris.json")))
+			require.NoError(t, err)
+			require.Equal(t, controlPlane.ID.String(), string(data))
+		}
+	})
+
+	t.Run("verify control planes are processed simultaneously up to the concurrency", func(t *testing.T) {
+		var inFlight, maxInFlight atomic.Int32
+		err := runControlPlanes(controlPlanes, 2, func(*config.ControlPlane) error {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				maximum := maxInFlight.Load()
+				if current <= maximum || maxInFlight.CompareAndSwap(maximum, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, int32(2), maxInFlight.Load())
+	})
+
+	t.Run("verify a failing control plane does not abort the others", func(t *testing.T) {
+		errFailed := errors.New("failed")
+		var processed atomic.Int32
+		err := runControlPlanes(controlPlanes, 2, func(controlPlane *config.ControlPlane) error {
+			processed.Add(1)
+			if controlPlane.ID == controlPlanes[0].ID {
+				return errFailed
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, errFailed)
+		require.ErrorContains(t, err, controlPlanes[0].ID.String())
+		require.Equal(t, int32(3), processed.Load())
+	})
+}
+
+func TestForEachControlPlane(t *testing.T) {
+	t.Run("verify control planes dumped simultaneously are isolated", func(t *testing.T) {
+		var mutex sync.Mutex
+		active := make(map[string]int)
+		var overlapped atomic.Bool
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			segments := strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")
+			mutex.Lock()
+			active[segments[0]]++
+			if len(active) > 1 {
+				overlapped.Store(true)
+			}
+			mutex.Unlock()
+			defer func() {
+				mutex.Lock()
+				if active[segments[0]]--; active[segments[0]] == 0 {
+					delete(active, segments[0])
+				}
+				mutex.Unlock()
+			}()
+			time.Sleep(time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			if len(segments) == 2 && segments[1] == "services" {
+				_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-%s"}]}`, segments[0])
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		controlPlanesFile := filepath.Join(dir, "control-planes.txt")
+		require.NoError(t, os.WriteFile(controlPlanesFile, []byte(ids[0].String()+"\n"+ids[1].String()+"\n"), 0o600))
+		logFilename := filepath.Join(dir, "osiris.log")
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_OUTPUT_FILE", filepath.Join(dir, "osiris.json"))
+		t.Setenv("OSIRIS_LOGGER_FILENAME", logFilename)
+		viper.Reset()
+		t.Cleanup(viper.Reset)
+		rootCmd.SetArgs([]string{
+			"dump", "--control-planes-file", controlPlanesFile, "--control-plane-concurrency", "2",
+		})
+		t.Cleanup(func() {
+			rootCmd.SetArgs(nil)
+			dumpControlPlanesFile = ""
+		})
+		require.NoError(t, rootCmd.Execute())
+		require.True(t, overlapped.Load(), "control planes were not dumped simultaneously")
+
+		for _, id := range ids {
+			data, err := os.ReadFile(filepath.Join(dir, "osiris-"+id.String()+".json"))
+			require.NoError(t, err)
+			var results map[string][]map[string]interface{}
+			require.NoError(t, json.Unmarshal(data, &results))
+			require.Equal(t, []map[string]interface{}{{"id": "svc-" + id.String()}}, results["service"])
+		}
+
+		data, err := os.ReadFile(logFilename)
+		require.NoError(t, err)
+		logged := make(map[string]bool)
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var entry map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+			if id, ok := entry["control-plane-id"].(string); ok {
+				logged[id] = true
+			}
+		}
+		for _, id := range ids {
+			require.True(t, logged[id.String()], "control plane %s was not logged", id)
+		}
+	})
+}
@@ -20,31 +20,154 @@ import (
 	"fmt"
 
 	"github.com/mikefero/osiris/internal/app"
+	"github.com/mikefero/osiris/internal/config"
 	"github.com/spf13/cobra"
 )
 
+var (
+	dumpResume            bool
+	dumpControlPlanesFile string
+	dumpOnly              string
+	dumpStrict            bool
+	dumpEvents            string
+	dumpDefaultsFile      string
+	dumpNestTargets       bool
+	dumpSkipForbidden     bool
+	dumpSinceFile         string
+	dumpStream            bool
+	dumpOutputTemplate    string
+	dumpLikeDeck          string
+	dumpOutputSplitSize   int64
+	dumpOutputDir         string
+	dumpRawOut            string
+	dumpFilters           []string
+	dumpVerifyOutput      bool
+)
+
 var dumpCmd = &cobra.Command{
 	Use:   "dump",
 	Short: "Dump a control plane configuration",
 	Long: `The dump command gathers a control plane configuration, sanitizes it
 (if enabled), and saves it to a file.`,
-	RunE: func(_ *cobra.Command, _ []string) error {
-		startCtx, startCancel := context.WithCancel(context.Background())
-		defer startCancel()
-		app := app.NewDump()
-		if err := app.Start(startCtx); err != nil {
-			return fmt.Errorf("unable to start dump operation: %w", err)
+	PreRunE: func(cmd *cobra.Command, _ []string) error {
+		if err := bindNegatedFlags(cmd, map[string]string{
+			"fail-fast": "skip_failed_pages",
+		}); err != nil {
+			return err
 		}
+		return bindFlags(cmd, map[string]string{
+			"continue-on-error":         "continue_on_error",
+			"control-plane-concurrency": "control_plane_concurrency",
+			"error-file":                "error_file",
+			"include-metadata":          "include_metadata",
+			"max-response-bytes":        "max_response_bytes",
+			"checkpoint-file":           "checkpoint_file",
+			"partial-pages":             "partial_pages",
+			"ignore-hook-errors":        "hooks.ignore_errors",
+		})
+	},
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		return forEachControlPlane(cmd, dumpControlPlanesFile, false, func(cfg *config.Config, controlPlane *config.ControlPlane) error {
+			startCtx, startCancel := context.WithCancel(context.Background())
+			defer startCancel()
+			app := app.NewDump(app.DumpOptions{
+				Resume:          dumpResume,
+				ControlPlane:    controlPlane,
+				Config:          cfg,
+				Only:            dumpOnly,
+				Strict:          dumpStrict,
+				Events:          dumpEvents,
+				DefaultsFile:    dumpDefaultsFile,
+				NestTargets:     dumpNestTargets,
+				SkipForbidden:   dumpSkipForbidden,
+				SinceFile:       dumpSinceFile,
+				Stream:          dumpStream,
+				OutputTemplate:  dumpOutputTemplate,
+				LikeDeck:        dumpLikeDeck,
+				OutputSplitSize: dumpOutputSplitSize,
+				OutputDir:       dumpOutputDir,
+				RawOut:          dumpRawOut,
+				Filters:         dumpFilters,
+				VerifyOutput:    dumpVerifyOutput,
+			})
+			if err := app.Start(startCtx); err != nil {
+				return fmt.Errorf("unable to start dump operation: %w", err)
+			}
 
-		stopCtx, stopCancel := context.WithCancel(context.Background())
-		defer stopCancel()
-		if err := app.Stop(stopCtx); err != nil {
-			return fmt.Errorf("unable to stop dump operation: %w", err)
-		}
-		return nil
+			stopCtx, stopCancel := context.WithCancel(context.Background())
+			defer stopCancel()
+			if err := app.Stop(stopCtx); err != nil {
+				return fmt.Errorf("unable to stop dump operation: %w", err)
+			}
+			return nil
+		})
 	},
 }
 
 func init() {
+	dumpCmd.Flags().StringVar(&dumpControlPlanesFile, "control-planes-file", "",
+		"file containing one control plane ID (or ID,name) per line to dump")
+	dumpCmd.Flags().Int("control-plane-concurrency", 0,
+		"maximum number of control planes of the control planes file dumped simultaneously")
+	dumpCmd.Flags().StringVar(&dumpOnly, "only", "",
+		"name of the single resource to dump (e.g. route)")
+	dumpCmd.Flags().StringVar(&dumpLikeDeck, "like-deck", "",
+		"decK state file; only the resources it references (e.g. services and routes) are dumped")
+	dumpCmd.MarkFlagsMutuallyExclusive("only", "like-deck")
+	dumpCmd.Flags().StringArrayVar(&dumpFilters, "filter", nil,
+		"JMESPath filter of the items written (e.g. \"service[?protocol == 'grpc']\"); may be repeated")
+	dumpCmd.Flags().BoolVar(&dumpStrict, "strict", false,
+		"fail when listed items violate the expectations of their resource, or do not match the "+
+			"reported total, rather than warning")
+	dumpCmd.Flags().StringVar(&dumpEvents, "events", "",
+		"emit a JSONL event stream to stdout (--events=<file> for a file or named pipe)")
+	dumpCmd.Flags().Lookup("events").NoOptDefVal = "-"
+	dumpCmd.Flags().StringVar(&dumpDefaultsFile, "include-defaults-file", "",
+		"JSON or YAML file of default field values for each resource; matching fields are stripped from the output")
+	dumpCmd.Flags().BoolVar(&dumpNestTargets, "nest-targets", false,
+		"nest the targets under their upstream in the output rather than in a separate target collection")
+	dumpCmd.Flags().BoolVar(&dumpSkipForbidden, "skip-forbidden", false,
+		"skip the resources the bearer token is not authorized to list (403) rather than failing")
+	dumpCmd.Flags().StringVar(&dumpSinceFile, "since-file", "",
+		"prior dump to compare against; only new and changed items are written along with a removed items manifest")
+	dumpCmd.Flags().BoolVar(&dumpStream, "stream", false,
+		"write each page to the output as it is listed to bound memory for massive control planes")
+	dumpCmd.MarkFlagsMutuallyExclusive("stream", "since-file")
+	dumpCmd.MarkFlagsMutuallyExclusive("stream", "nest-targets")
+	dumpCmd.Flags().StringVar(&dumpOutputTemplate, "output-template", "",
+		"Go text/template file the results are rendered through instead of JSON (e.g. a CSV of service hosts)")
+	dumpCmd.MarkFlagsMutuallyExclusive("stream", "output-template")
+	dumpCmd.Flags().BoolVar(&dumpVerifyOutput, "verify-output", false,
+		"read back and parse the output file once written and fail if its item counts do not match the results")
+	dumpCmd.MarkFlagsMutuallyExclusive("verify-output", "stream")
+	dumpCmd.Flags().Int64Var(&dumpOutputSplitSize, "output-split-size", 0,
+		"split the output into numbered parts of at most this many bytes along with an index of the parts")
+	dumpCmd.Flags().StringVar(&dumpOutputDir, "output-dir", "",
+		"directory the results are written to as decK state files; one file for each service and a global file")
+	dumpCmd.MarkFlagsMutuallyExclusive("output-dir", "stream")
+	dumpCmd.MarkFlagsMutuallyExclusive("output-dir", "since-file")
+	dumpCmd.MarkFlagsMutuallyExclusive("output-dir", "output-template")
+	dumpCmd.MarkFlagsMutuallyExclusive("output-dir", "output-split-size")
+	dumpCmd.Flags().StringVar(&dumpRawOut, "raw-out", "",
+		"file the unmodified API responses are written to alongside the output; contains unsanitized secrets")
+	dumpCmd.MarkFlagsMutuallyExclusive("raw-out", "stream")
+	dumpCmd.Flags().BoolVar(&dumpResume, "resume", false,
+		"resume an interrupted dump from the checkpoint file")
+	dumpCmd.Flags().String("checkpoint-file", "",
+		"file used to persist the pagination progress so an interrupted dump can be resumed")
+	dumpCmd.Flags().Bool("continue-on-error", false,
+		"continue with the remaining resources when a resource fails")
+	dumpCmd.Flags().String("error-file", "errors.json",
+		"file for the structured error report when continuing on error")
+	dumpCmd.Flags().Bool("partial-pages", false,
+		"retain the pages of a resource retrieved before a page request fails")
+	dumpCmd.Flags().Bool("fail-fast", true,
+		"fail a resource when one of its pages fails; with false a failed numbered page is skipped as a gap")
+	dumpCmd.Flags().Bool("ignore-hook-errors", false,
+		"continue the dump when a pre-write or post-write hook fails")
+	dumpCmd.Flags().Bool("include-metadata", false,
+		"retain metadata fields (timestamps and certificate metadata) in the output")
+	dumpCmd.Flags().Int64("max-response-bytes", 100*1024*1024,
+		"maximum size of a response body from the admin API in bytes")
 	rootCmd.AddCommand(dumpCmd)
 }
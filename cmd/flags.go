@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// bindFlags binds the command flags to their configuration keys, keyed by
+// flag name, so that flags take precedence over environment variables and the
+// configuration file. Flags are bound when the command is executed to ensure
+// flags shared by multiple commands do not override one another.
+func bindFlags(cmd *cobra.Command, flags map[string]string) error {
+	for flag, key := range flags {
+		if err := viper.BindPFlag(key, cmd.Flags().Lookup(flag)); err != nil {
+			return fmt.Errorf("unable to bind flag %s: %w", flag, err)
+		}
+	}
+	return nil
+}
+
+// bindNegatedFlags binds the boolean command flags to the inverse of their
+// configuration keys, keyed by flag name (e.g. --fail-fast=false enables
+// skip_failed_pages). A flag only overrides the configuration when it is
+// specified.
+func bindNegatedFlags(cmd *cobra.Command, flags map[string]string) error {
+	for flag, key := range flags {
+		if !cmd.Flags().Changed(flag) {
+			continue
+		}
+		value, err := cmd.Flags().GetBool(flag)
+		if err != nil {
+			return fmt.Errorf("unable to bind flag %s: %w", flag, err)
+		}
+		viper.Set(key, !value)
+	}
+	return nil
+}
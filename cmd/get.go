@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikefero/osiris/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var getOutput string
+
+var getCmd = &cobra.Command{
+	Use:   "get <endpoint>",
+	Short: "Get the items of an arbitrary control plane endpoint",
+	Long: `The get command retrieves every page of an endpoint relative to the
+control plane (e.g. services/<id>/routes) and prints the items as JSON; the
+authentication, pagination, and rate limiting of the other commands apply.
+The items of an endpoint matching a resource are sanitized as for a dump;
+other endpoints are refused unless --no-sanitize is specified.`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, _ []string) error {
+		if err := bindNegatedFlags(cmd, map[string]string{
+			"no-sanitize": "sanitize",
+		}); err != nil {
+			return err
+		}
+		return bindFlags(cmd, map[string]string{
+			"include-metadata": "include_metadata",
+		})
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		startCtx, startCancel := context.WithCancel(context.Background())
+		defer startCancel()
+		app := app.NewGet(app.GetOptions{
+			Endpoint:   args[0],
+			OutputFile: getOutput,
+			Output:     cmd.OutOrStdout(),
+		})
+		if err := app.Start(startCtx); err != nil {
+			return fmt.Errorf("unable to start get operation: %w", err)
+		}
+
+		stopCtx, stopCancel := context.WithCancel(context.Background())
+		defer stopCancel()
+		if err := app.Stop(stopCtx); err != nil {
+			return fmt.Errorf("unable to stop get operation: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	getCmd.Flags().StringVar(&getOutput, "output", "",
+		"file to write the items to rather than stdout")
+	getCmd.Flags().Bool("no-sanitize", false,
+		"write the items without sanitization; required for endpoints not matching a resource")
+	getCmd.Flags().Bool("include-metadata", false,
+		"retain metadata fields (timestamps and certificate metadata) in the items")
+	rootCmd.AddCommand(getCmd)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/app"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetCommand(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/keys") {
+			_, _ = w.Write([]byte(`{"data":[{"id":"key-1","kid":"kid-1","jwk":"secret-jwk"}]}`))
+			return
+		}
+		if r.URL.Query().Get("offset") == "page-2" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"route-3"}],"next":null}`))
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"data":[{"id":"route-1"},{"id":"route-2"}],"next":%q}`,
+			r.URL.Path[1:]+"?offset=page-2")
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("OSIRIS_BASE_URL", server.URL)
+	t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+
+	execute := func(t *testing.T, args ...string) (string, error) {
+		t.Helper()
+		viper.Reset()
+		t.Cleanup(viper.Reset)
+		for _, name := range []string{"output", "no-sanitize"} {
+			flag := getCmd.Flags().Lookup(name)
+			require.NoError(t, flag.Value.Set(flag.DefValue))
+			flag.Changed = false
+		}
+		var output bytes.Buffer
+		rootCmd.SetOut(&output)
+		rootCmd.SetArgs(append([]string{"get"}, args...))
+		t.Cleanup(func() {
+			rootCmd.SetOut(nil)
+			rootCmd.SetArgs(nil)
+		})
+		err := rootCmd.Execute()
+		return output.String(), err
+	}
+
+	t.Run("verify the items of every page are printed", func(t *testing.T) {
+		output, err := execute(t, "services/svc-1/routes")
+		require.NoError(t, err)
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &items))
+		require.Equal(t, []map[string]interface{}{
+			{"id": "route-1"},
+			{"id": "route-2"},
+			{"id": "route-3"},
+		}, items)
+	})
+
+	t.Run("verify the items are written to the output file", func(t *testing.T) {
+		outputFilename := filepath.Join(dir, "routes.json")
+		output, err := execute(t, "/services/svc-1/routes", "--output", outputFilename)
+		require.NoError(t, err)
+		require.Empty(t, output)
+
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &items))
+		require.Len(t, items, 3)
+	})
+
+	t.Run("verify the secret fields of a resource are sanitized", func(t *testing.T) {
+		output, err := execute(t, "keys")
+		require.NoError(t, err)
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output), &items))
+		require.Equal(t, []map[string]interface{}{{"id": "key-1", "kid": "kid-1", "jwk": "<redacted>"}}, items)
+	})
+
+	t.Run("verify endpoints not matching a resource require disabling sanitization", func(t *testing.T) {
+		_, err := execute(t, "status/unknown")
+		require.ErrorIs(t, err, app.ErrUnsanitizedEndpoint)
+
+		output, err := execute(t, "status/unknown", "--no-sanitize")
+		require.NoError(t, err)
+		require.Contains(t, output, "route-1")
+	})
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/mikefero/osiris/internal/app"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var levelsCmd = &cobra.Command{
+	Use:   "levels",
+	Short: "Print the parallel levels of the insertion and deletion orders",
+	Long: `The levels command prints the parallel levels computed from the resource
+dependency graph for the insertion (apply) and deletion (reset) orders. The
+resources of a level are processed concurrently and the levels are processed in
+order; use it to verify dependency changes (including the configured
+resource_dependencies) and to tune concurrency.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		config, err := config.NewConfig()
+		if err != nil {
+			return fmt.Errorf("unable to load configuration: %w", err)
+		}
+		return app.WriteLevels(cmd.OutOrStdout(), config.ResourceDependencies)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(levelsCmd)
+}
@@ -17,36 +17,135 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/mikefero/osiris/internal/app"
+	"github.com/mikefero/osiris/internal/config"
 	"github.com/spf13/cobra"
 )
 
+var (
+	errPlanOutRequired      = errors.New("--plan-only requires --plan-out")
+	errConfirmationRequired = errors.New("stdin is not a terminal; use --yes to reset without confirmation")
+)
+
+var (
+	resetReportJSON        bool
+	resetControlPlanesFile string
+	resetOrphansOnly       bool
+	resetEvents            string
+	resetSkipForbidden     bool
+	resetPlanOut           string
+	resetPlanOnly          bool
+	resetYes               bool
+	resetAllowLargeDelete  bool
+	resetKeepLicense       bool
+)
+
 var resetCmd = &cobra.Command{
 	Use:   "reset",
 	Short: "Reset a control plane configuration",
 	Long: `The reset command deletes all resources from a control plane.
 Resources are deleted in reverse topological order (leaf nodes first),
 ensuring proper dependency resolution.`,
-	RunE: func(_ *cobra.Command, _ []string) error {
-		startCtx, startCancel := context.WithCancel(context.Background())
-		defer startCancel()
-
-		app := app.NewReset()
-		if err := app.Start(startCtx); err != nil {
-			return fmt.Errorf("unable to start reset operation: %w", err)
+	PreRunE: func(cmd *cobra.Command, _ []string) error {
+		return bindFlags(cmd, map[string]string{
+			"operator":                  "operator",
+			"continue-on-error":         "continue_on_error",
+			"control-plane-concurrency": "control_plane_concurrency",
+		})
+	},
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if resetPlanOnly && len(resetPlanOut) == 0 {
+			return errPlanOutRequired
 		}
-
-		stopCtx, stopCancel := context.WithCancel(context.Background())
-		defer stopCancel()
-		if err := app.Stop(stopCtx); err != nil {
-			return fmt.Errorf("unable to stop reset operation: %w", err)
+		var confirm io.Reader
+		if !resetYes && !resetPlanOnly {
+			if !isTerminal(cmd.InOrStdin()) {
+				return errConfirmationRequired
+			}
+			confirm = cmd.InOrStdin()
 		}
-		return nil
+		// The control planes are reset one at a time when prompting so that
+		// the prompts do not interleave
+		serial := confirm != nil
+		return forEachControlPlane(cmd, resetControlPlanesFile, serial, func(cfg *config.Config, controlPlane *config.ControlPlane) error {
+			startCtx, startCancel := context.WithCancel(context.Background())
+			defer startCancel()
+
+			app := app.NewReset(app.ResetOptions{
+				ReportJSON:       resetReportJSON,
+				Output:           cmd.OutOrStdout(),
+				ControlPlane:     controlPlane,
+				Config:           cfg,
+				OrphansOnly:      resetOrphansOnly,
+				Events:           resetEvents,
+				SkipForbidden:    resetSkipForbidden,
+				PlanOut:          resetPlanOut,
+				PlanOnly:         resetPlanOnly,
+				Confirm:          confirm,
+				Prompt:           cmd.ErrOrStderr(),
+				AllowLargeDelete: resetAllowLargeDelete,
+				KeepLicense:      resetKeepLicense,
+			})
+			if err := app.Start(startCtx); err != nil {
+				return fmt.Errorf("unable to start reset operation: %w", err)
+			}
+
+			stopCtx, stopCancel := context.WithCancel(context.Background())
+			defer stopCancel()
+			if err := app.Stop(stopCtx); err != nil {
+				return fmt.Errorf("unable to stop reset operation: %w", err)
+			}
+			return nil
+		})
 	},
 }
 
 func init() {
+	resetCmd.Flags().StringVar(&resetControlPlanesFile, "control-planes-file", "",
+		"file containing one control plane ID (or ID,name) per line to reset")
+	resetCmd.Flags().Int("control-plane-concurrency", 0,
+		"maximum number of control planes of the control planes file reset simultaneously")
+	resetCmd.Flags().String("operator", "",
+		"operator recorded in the audit log for each deleted item")
+	resetCmd.Flags().BoolVar(&resetReportJSON, "report-json", false,
+		"print a structured JSON report of the reset to stdout on completion")
+	resetCmd.Flags().BoolVar(&resetOrphansOnly, "orphans-only", false,
+		"delete only orphaned items (e.g. targets whose upstream no longer exists)")
+	resetCmd.Flags().BoolVar(&resetSkipForbidden, "skip-forbidden", false,
+		"skip the resources the bearer token is not authorized to list (403) rather than failing")
+	resetCmd.Flags().StringVar(&resetEvents, "events", "",
+		"emit a JSONL event stream to stdout (--events=<file> for a file or named pipe)")
+	resetCmd.Flags().Lookup("events").NoOptDefVal = "-"
+	resetCmd.Flags().StringVar(&resetPlanOut, "plan-out", "",
+		"write the reset plan (ordered levels and item counts) to a JSON or YAML file before deleting")
+	resetCmd.Flags().BoolVar(&resetPlanOnly, "plan-only", false,
+		"write the reset plan to the --plan-out file without deleting any items")
+	resetCmd.Flags().BoolVar(&resetYes, "yes", false,
+		"reset without prompting for confirmation (required when stdin is not a terminal)")
+	resetCmd.Flags().BoolVar(&resetAllowLargeDelete, "allow-large-delete", false,
+		"delete resources with more items than the delete_confirm_threshold rather than aborting")
+	resetCmd.Flags().BoolVar(&resetKeepLicense, "keep-license", false,
+		"leave the licenses of an enterprise gateway in place rather than deleting them")
+	resetCmd.Flags().Bool("continue-on-error", false,
+		"continue with the remaining resources and levels when a resource fails")
+	resetCmd.MarkFlagsMutuallyExclusive("plan-out", "orphans-only")
 	rootCmd.AddCommand(resetCmd)
 }
+
+// isTerminal returns true if the reader is a terminal (character device).
+func isTerminal(r io.Reader) bool {
+	file, ok := r.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResetCommand(t *testing.T) {
+	t.Run("verify reset is refused when stdin is not a terminal", func(t *testing.T) {
+		viper.Reset()
+		t.Cleanup(viper.Reset)
+		var output bytes.Buffer
+		rootCmd.SetIn(strings.NewReader("yes\n"))
+		rootCmd.SetOut(&output)
+		rootCmd.SetErr(&output)
+		rootCmd.SetArgs([]string{"reset"})
+		t.Cleanup(func() {
+			rootCmd.SetIn(nil)
+			rootCmd.SetOut(nil)
+			rootCmd.SetErr(nil)
+			rootCmd.SetArgs(nil)
+		})
+
+		// The answer on stdin is never read since stdin is not a terminal
+		err := rootCmd.Execute()
+		require.ErrorIs(t, err, errConfirmationRequired)
+	})
+}
@@ -18,10 +18,14 @@ package cmd
 import (
 	"os"
 
+	"github.com/mikefero/osiris/internal/config"
 	"github.com/spf13/cobra"
 )
 
-var license string
+var (
+	license     string
+	configFiles []string
+)
 
 // Options contains the options for the root command.
 type Options struct {
@@ -33,6 +37,24 @@ var rootCmd = &cobra.Command{
 	Use:   "app-name",
 	Short: "Application Name",
 	Long:  `The app-name description.`,
+	PersistentPreRunE: func(cmd *cobra.Command, _ []string) error {
+		return bindFlags(cmd, map[string]string{
+			"no-color":           "logger.no_color",
+			"base-url-from-file": "base_url_file",
+		})
+	},
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringSliceVar(&configFiles, "config", nil,
+		"configuration file; may be specified multiple times with later files merged over earlier files")
+	rootCmd.PersistentFlags().Bool("no-color", false,
+		"disable the colored levels of the console log encoding (also disabled by NO_COLOR)")
+	rootCmd.PersistentFlags().String("base-url-from-file", "",
+		"file containing the base URL for the admin API; read at startup")
+	cobra.OnInitialize(func() {
+		config.SetFiles(configFiles)
+	})
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
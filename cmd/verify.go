@@ -0,0 +1,72 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mikefero/osiris/internal/app"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyAgainst      string
+	verifyIgnoreFields []string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify a control plane configuration against a golden file",
+	Long: `The verify command gathers a control plane configuration and compares it
+against a golden file (a previous dump), exiting with a non-zero status if any
+differences are found.`,
+	PreRunE: func(cmd *cobra.Command, _ []string) error {
+		return bindFlags(cmd, map[string]string{
+			"include-metadata": "include_metadata",
+		})
+	},
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		startCtx, startCancel := context.WithCancel(context.Background())
+		defer startCancel()
+		app := app.NewVerify(app.VerifyOptions{
+			Against:      verifyAgainst,
+			IgnoreFields: verifyIgnoreFields,
+			Output:       cmd.OutOrStdout(),
+		})
+		if err := app.Start(startCtx); err != nil {
+			return fmt.Errorf("unable to start verify operation: %w", err)
+		}
+
+		stopCtx, stopCancel := context.WithCancel(context.Background())
+		defer stopCancel()
+		if err := app.Stop(stopCtx); err != nil {
+			return fmt.Errorf("unable to stop verify operation: %w", err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifyAgainst, "against", "",
+		"golden file to compare the control plane configuration against")
+	verifyCmd.Flags().StringSliceVar(&verifyIgnoreFields, "ignore-fields", nil,
+		"fields to ignore during comparison (dot separated for nested fields)")
+	verifyCmd.Flags().Bool("include-metadata", false,
+		"retain metadata fields (timestamps and certificate metadata) during comparison")
+	_ = verifyCmd.MarkFlagRequired("against")
+	rootCmd.AddCommand(verifyCmd)
+}
@@ -0,0 +1,410 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/backoff"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+)
+
+// ErrReadOnly is returned when a mutating operation (reset or apply) is
+// started while read-only mode is enabled.
+var ErrReadOnly = errors.New("read-only mode is enabled; refusing to modify the control plane")
+
+// checkReadOnly returns ErrReadOnly if read-only mode is enabled; it is checked
+// before any request is made by the mutating operations.
+func checkReadOnly(config *config.Config, operation string) error {
+	if config.ReadOnly {
+		return fmt.Errorf("unable to %s: %w", operation, ErrReadOnly)
+	}
+	return nil
+}
+
+// withOperationTimeout derives a context bounded by the configured operation
+// timeout so that all requests, including their retries, collectively respect
+// it. The context is returned unbounded if no operation timeout is
+// configured.
+func withOperationTimeout(ctx context.Context, config *config.Config) (context.Context, context.CancelFunc) {
+	if config.Timeouts.Operation <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, config.Timeouts.Operation)
+}
+
+// provideConfig returns the provider of the configuration; the configuration
+// already loaded for the control planes of a fleet is shared rather than
+// loaded again for each control plane.
+func provideConfig(cfg *config.Config) func() (*config.Config, error) {
+	return func() (*config.Config, error) {
+		if cfg != nil {
+			return cfg, nil
+		}
+		return config.NewConfig()
+	}
+}
+
+// forControlPlane returns the configuration and logger of the control plane
+// when operating on a fleet of control planes; the log lines of the control
+// planes processed simultaneously are distinguished by the control plane ID.
+func forControlPlane(cfg *config.Config, controlPlane *config.ControlPlane, logger *zap.Logger,
+) (*config.Config, *zap.Logger) {
+	if controlPlane == nil {
+		return cfg, logger
+	}
+	return cfg.ForControlPlane(*controlPlane), logger.With(zap.Stringer("control-plane-id", controlPlane.ID))
+}
+
+// newClient creates the API client using the configured backoff; an error is
+// returned if the backoff configuration is invalid.
+func newClient(config *config.Config, logger *zap.Logger) (*client.Client, error) {
+	backoff, err := backoff.New(config.Backoff)
+	if err != nil {
+		return nil, err
+	}
+	client := client.NewClient(config, logger)
+	client.SetBackoff(backoff)
+	return client, nil
+}
+
+// logRequestStats logs the request statistics of the client once an
+// operation completes so that the concurrency, page size, and rate limit can
+// be tuned.
+func logRequestStats(client *client.Client, logger *zap.Logger) {
+	stats := client.Stats()
+	logger.Info("Request statistics",
+		zap.Int64("requests", stats.Requests),
+		zap.Int64("retries", stats.Retries),
+		zap.Int64("bytes", stats.Bytes),
+		zap.Duration("average-latency", stats.AverageLatency))
+}
+
+// newRegistry creates the resource registry with the configured dependency
+// overrides applied.
+func newRegistry(dependencies map[string][]string) (*resource.Registry, error) {
+	registry, err := resource.NewRegistry()
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.SetDependencies(dependencies); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+// supportedResources detects the version of the gateway, unless already
+// detected, and returns the resources available in that version; resources
+// that are not supported are skipped. All resources are returned if the
+// version cannot be detected.
+func supportedResources(ctx context.Context, client *client.Client, resources []resource.Resource,
+	logger *zap.Logger,
+) []resource.Resource {
+	version, ok := client.Version()
+	if !ok {
+		var err error
+		if version, err = client.DetectVersion(ctx); err != nil {
+			logger.Warn("Unable to detect gateway version; processing all resources", zap.Error(err))
+			return resources
+		}
+	}
+	supported := make([]resource.Resource, 0, len(resources))
+	for _, res := range resources {
+		if constrained, ok := res.(resource.VersionConstrained); ok && !constrained.Supported(version) {
+			logger.Info("Skipping resource not supported by gateway version",
+				zap.String("resource", res.Name()),
+				zap.String("version", version.String()))
+			continue
+		}
+		supported = append(supported, res)
+	}
+	return supported
+}
+
+// supportedLevels returns the deletion levels without the resources that are
+// not supported by the version of the gateway; levels left without resources
+// are removed.
+func supportedLevels(ctx context.Context, client *client.Client, levels [][]resource.Resource,
+	logger *zap.Logger,
+) [][]resource.Resource {
+	var resources []resource.Resource
+	for _, level := range levels {
+		resources = append(resources, level...)
+	}
+	supported := supportedResources(ctx, client, resources, logger)
+	filtered := make([][]resource.Resource, 0, len(levels))
+	for _, level := range levels {
+		level = slices.DeleteFunc(slices.Clone(level), func(res resource.Resource) bool {
+			return !slices.Contains(supported, res)
+		})
+		if len(level) > 0 {
+			filtered = append(filtered, level)
+		}
+	}
+	return filtered
+}
+
+// eventsStdout is the destination of the event stream for stdout.
+const eventsStdout = "-"
+
+// openEvents opens the destination of the event stream; `-` writes the events
+// to stdout and any other destination is opened for writing (e.g. a file or a
+// named pipe). A nil emitter is returned if the destination is empty,
+// disabling the event stream. The returned function closes the destination.
+func openEvents(destination string) (*event.Emitter, func() error, error) {
+	switch destination {
+	case "":
+		return nil, func() error { return nil }, nil
+	case eventsStdout:
+		return event.NewEmitter(os.Stdout), func() error { return nil }, nil
+	}
+	file, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open event stream: %w", err)
+	}
+	return event.NewEmitter(file), file.Close, nil
+}
+
+// emitCompleted emits the event marking the completion of a resource along
+// with the number of items processed.
+func emitCompleted(events *event.Emitter, name string, count int, startTime time.Time) {
+	events.Emit(event.Event{
+		Event:    event.ResourceCompleted,
+		Resource: name,
+		Count:    count,
+		Duration: time.Since(startTime).String(),
+	})
+}
+
+// emitFailed emits the event marking the failure of a resource.
+func emitFailed(events *event.Emitter, name string, err error) {
+	events.Emit(event.Event{
+		Event:    event.ResourceFailed,
+		Resource: name,
+		Error:    err.Error(),
+	})
+}
+
+// emitDone emits the event marking the completion of the operation along
+// with the number of items processed and the error of the operation, if any.
+func emitDone(events *event.Emitter, count int, startTime time.Time, err error) {
+	done := event.Event{
+		Event:    event.Done,
+		Count:    count,
+		Duration: time.Since(startTime).String(),
+	}
+	if err != nil {
+		done.Error = err.Error()
+	}
+	events.Emit(done)
+}
+
+// isForbidden returns true if the error indicates that the bearer token is
+// not authorized to list a resource.
+func isForbidden(err error) bool {
+	return errors.Is(err, client.ErrForbidden)
+}
+
+// isNotFound returns true if the error is the result of writing an item that
+// does not exist.
+func isNotFound(err error) bool {
+	return errors.Is(err, client.ErrNotFound)
+}
+
+// skippedResources records the names of the skipped resources; it is safe
+// for concurrent use and a nil value discards the names.
+type skippedResources struct {
+	mutex   sync.Mutex
+	skipped []string
+}
+
+func (s *skippedResources) add(name string) {
+	if s == nil {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.skipped = append(s.skipped, name)
+}
+
+// names returns the names of the skipped resources in sorted order.
+func (s *skippedResources) names() []string {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	names := slices.Clone(s.skipped)
+	sort.Strings(names)
+	return names
+}
+
+// resourceRetry is the retry policy for listing an entire resource. The zero
+// value does not retry.
+type resourceRetry struct {
+	// retries is the number of times the listing is retried after it fails.
+	retries int
+	// onEmpty is the number of times the listing is retried after it
+	// succeeds without items.
+	onEmpty int
+	// backoff paces the attempts of the listing; the attempts are not paced
+	// if nil.
+	backoff *backoff.Backoff
+}
+
+// newResourceRetry creates the retry policy for listing an entire resource
+// using the configured resource retries and backoff.
+func newResourceRetry(config *config.Config) (resourceRetry, error) {
+	backoff, err := backoff.New(config.Backoff)
+	if err != nil {
+		return resourceRetry{}, err
+	}
+	return resourceRetry{
+		retries: config.ResourceRetries,
+		onEmpty: config.RetryOnEmpty,
+		backoff: backoff,
+	}, nil
+}
+
+// listResource lists the items of the resource, retrying the entire listing
+// according to the retry policy when it fails; listing is idempotent. A
+// listing without items is also retried according to the retry policy as an
+// empty listing may be transient (e.g. eventual consistency right after
+// writes). The result of the last attempt is returned.
+func listResource(ctx context.Context, client *client.Client, res resource.Resource, retry resourceRetry,
+	logger *zap.Logger,
+) (resource.ResourceData, error) {
+	data, err := listResourceAttempts(ctx, client, res, retry, logger)
+	for attempt := 1; err == nil && len(data.Data) == 0 && attempt <= retry.onEmpty; attempt++ {
+		wait := retry.wait(attempt)
+		logger.Info("Listing resource returned no items; retrying",
+			zap.String("resource", res.Name()),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry-after", wait))
+		if !sleep(ctx, wait) {
+			break
+		}
+		data, err = listResourceAttempts(ctx, client, res, retry, logger)
+	}
+	return data, err
+}
+
+// listResourceAttempts lists the items of the resource, retrying the entire
+// listing according to the retry policy when it fails. The result of the last
+// attempt is returned.
+func listResourceAttempts(ctx context.Context, client *client.Client, res resource.Resource, retry resourceRetry,
+	logger *zap.Logger,
+) (resource.ResourceData, error) {
+	ctx = withResource(ctx, res)
+	for attempt := 1; ; attempt++ {
+		data, err := res.List(ctx, client, logger)
+		if err == nil || attempt > retry.retries || ctx.Err() != nil || isForbidden(err) {
+			return data, err
+		}
+
+		wait := retry.wait(attempt)
+		logger.Warn("Listing resource failed; retrying",
+			zap.String("resource", res.Name()),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry-after", wait),
+			zap.Error(err))
+		if !sleep(ctx, wait) {
+			return data, err
+		}
+	}
+}
+
+// wait returns the duration to wait after the attempt; the attempts are not
+// paced without a backoff.
+func (r resourceRetry) wait(attempt int) time.Duration {
+	if r.backoff == nil {
+		return 0
+	}
+	return r.backoff.Next(attempt)
+}
+
+// sleep waits for the duration; false is returned if the context is done
+// before the duration elapses.
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// withResource returns a context identifying the resource the requests made
+// with the context are for so that the requests may be rate limited for each
+// resource.
+func withResource(ctx context.Context, res resource.Resource) context.Context {
+	return client.WithResource(ctx, res.Path())
+}
+
+// resolveBaseURL replaces the base URL with the address returned by the base
+// URL discovery URL, if configured. The discovery URL is requested using the
+// configured transport (TLS, proxy, and timeouts).
+func resolveBaseURL(ctx context.Context, config *config.Config, logger *zap.Logger) error {
+	if len(config.BaseURLDiscovery) == 0 {
+		return nil
+	}
+	client, err := newClient(config, logger)
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+	baseURL, err := client.DiscoverBaseURL(ctx, config.BaseURLDiscovery)
+	if err != nil {
+		return fmt.Errorf("error discovering base URL from %s: %w", config.BaseURLDiscovery, err)
+	}
+	logger.Info("Discovered base URL",
+		zap.String("base-url-discovery", config.BaseURLDiscovery),
+		zap.String("base-url", baseURL))
+	config.BaseURL = baseURL
+	return nil
+}
+
+// resolveControlPlaneID resolves the control plane ID from the control plane
+// name when the ID is not configured. The configuration is updated with the
+// resolved ID so that subsequent clients target the control plane.
+func resolveControlPlaneID(ctx context.Context, config *config.Config, logger *zap.Logger) error {
+	if config.ControlPlaneID != uuid.Nil || len(config.ControlPlaneName) == 0 {
+		return nil
+	}
+	client, err := newClient(config, logger)
+	if err != nil {
+		return fmt.Errorf("error creating client: %w", err)
+	}
+	id, err := client.LookupControlPlaneID(ctx, config.ControlPlaneName)
+	if err != nil {
+		return fmt.Errorf("error resolving control plane ID: %w", err)
+	}
+	config.ControlPlaneID = id
+	return nil
+}
@@ -0,0 +1,442 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/logger"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidReferences is returned when the items of the apply file reference
+// items that do not exist in the file.
+var ErrInvalidReferences = errors.New("invalid references")
+
+// ErrInvalidApplyStrategy is returned when the apply strategy is not a known
+// strategy.
+var ErrInvalidApplyStrategy = errors.New("apply strategy must be put or patch")
+
+const (
+	// ApplyStrategyPut creates or replaces each item; the fields of an
+	// existing item not present in the file are reset.
+	ApplyStrategyPut = "put"
+	// ApplyStrategyPatch updates each existing item with only the fields
+	// present in the file, preserving the remaining fields, and creates the
+	// items that do not exist.
+	ApplyStrategyPatch = "patch"
+)
+
+// ApplyOptions contains the options for the apply command.
+type ApplyOptions struct {
+	// File is the file (a previous dump) to apply to the control plane.
+	File string
+	// Strategy is how the items are written; put (the default if empty) or
+	// patch.
+	Strategy string
+	// OnlyMissing only creates the items that do not exist on the control
+	// plane; existing items are skipped rather than written.
+	OnlyMissing bool
+	// DryRun validates the file without applying it.
+	DryRun bool
+	// Output is the writer used for the apply summary.
+	Output io.Writer
+}
+
+// NewApply creates a new fx application for the apply command.
+// It provides the necessary dependencies and registers the apply
+// functionality.
+func NewApply(opts ApplyOptions) *fx.App {
+	return fx.New(
+		fx.Supply(opts),
+		fx.Provide(
+			config.NewConfig,
+			func(config *config.Config) (*zap.Logger, error) {
+				return logger.NewLogger(config.Logger, logger.LoggerCommandTypeApply)
+			},
+		),
+		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
+			return &fxevent.ZapLogger{Logger: logger}
+		}),
+		fx.Invoke(registerApply),
+	)
+}
+
+func registerApply(lc fx.Lifecycle, config *config.Config, opts ApplyOptions, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := checkReadOnly(config, "apply"); err != nil {
+				return err
+			}
+			logger.Info("starting osiris",
+				zap.String("version", Version),
+				zap.String("commit", Commit),
+				zap.String("os-arch", OsArch),
+				zap.String("go-version", GoVersion),
+				zap.String("build-date", BuildDate),
+			)
+			ctx, cancel := withOperationTimeout(ctx, config)
+			defer cancel()
+			logger.Info("Starting apply",
+				zap.String("file", opts.File),
+				zap.String("strategy", opts.Strategy),
+				zap.Bool("only-missing", opts.OnlyMissing),
+				zap.Bool("dry-run", opts.DryRun))
+			if err := validateApplyStrategy(opts.Strategy); err != nil {
+				logger.Error("error validating apply strategy", zap.Error(err))
+				return err
+			}
+			results, err := readApplyFile(opts.File)
+			if err != nil {
+				logger.Error("error reading apply file",
+					zap.String("file", opts.File),
+					zap.Error(err))
+				return fmt.Errorf("error reading apply file: %w", err)
+			}
+			registry, err := newRegistry(config.ResourceDependencies)
+			if err != nil {
+				logger.Error("error creating resource registry", zap.Error(err))
+				return fmt.Errorf("error creating resource registry: %w", err)
+			}
+
+			// Validate the entire file before issuing any write so that all
+			// dangling references are reported up front
+			levels, err := validateApply(registry, results)
+			if err != nil {
+				logger.Error("error validating apply file", zap.Error(err))
+				return err
+			}
+			jsonFields, err := newJSONFields(config.ResourceJSONFields, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating resource JSON fields", zap.Error(err))
+				return fmt.Errorf("error creating resource JSON fields: %w", err)
+			}
+			if err := jsonFields.encode(results); err != nil {
+				logger.Error("error encoding resource JSON fields", zap.Error(err))
+				return err
+			}
+			if opts.DryRun {
+				fmt.Fprintf(opts.Output, "validated %d items; no changes applied (dry run)\n", countItems(results))
+				logger.Info("Apply dry run completed successfully")
+				return nil
+			}
+
+			if err := resolveBaseURL(ctx, config, logger); err != nil {
+				logger.Error("error discovering base URL", zap.Error(err))
+				return err
+			}
+			if err := resolveControlPlaneID(ctx, config, logger); err != nil {
+				logger.Error("error resolving control plane", zap.Error(err))
+				return err
+			}
+			warnProvenance(opts.File, config, logger)
+			client, err := newClient(config, logger)
+			if err != nil {
+				logger.Error("error creating client", zap.Error(err))
+				return fmt.Errorf("error creating client: %w", err)
+			}
+			defer logRequestStats(client, logger)
+			if err := client.Ping(ctx); err != nil {
+				logger.Error("error executing preflight request", zap.Error(err))
+				return fmt.Errorf("error executing preflight request: %w", err)
+			}
+			counts, err := applyLevels(ctx, client, levels, results, applyOptions{
+				strategy:    opts.Strategy,
+				onlyMissing: opts.OnlyMissing,
+			}, logger)
+			if err != nil {
+				logger.Error("error executing apply", zap.Error(err))
+				return fmt.Errorf("error applying data: %w", err)
+			}
+			if opts.OnlyMissing {
+				fmt.Fprintf(opts.Output, "applied %d items; skipped %d existing items\n", counts.applied, counts.skipped)
+			} else {
+				fmt.Fprintf(opts.Output, "applied %d items\n", counts.applied)
+			}
+			logger.Info("Apply completed successfully")
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("stopping osiris")
+			if err := logger.Sync(); err != nil {
+				logger.Error("failed to sync logger", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
+
+// readApplyFile reads a previously written dump file, decoding numbers as
+// json.Number so that large integers are applied exactly. The index of a
+// split dump is reassembled from its parts.
+func readApplyFile(filename string) (map[string][]map[string]interface{}, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	if index, ok := parseSplitIndex(data); ok {
+		if data, err = reassembleSplit(filepath.Dir(filename), index); err != nil {
+			return nil, err
+		}
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var results map[string][]map[string]interface{}
+	if err := decoder.Decode(&results); err != nil {
+		return nil, fmt.Errorf("error unmarshaling file: %w", err)
+	}
+	return results, nil
+}
+
+// validateApply validates that the resources of the apply file are known,
+// that the insertion order of the resources is acyclic, and that every
+// reference within the file resolves. The resources are returned ordered for
+// insertion. All dangling references are reported rather than only the first.
+func validateApply(registry *resource.Registry, results map[string][]map[string]interface{},
+) ([][]resource.Resource, error) {
+	levels, err := registry.GetResourcesForInsertion()
+	if err != nil {
+		return nil, fmt.Errorf("error ordering resources for insertion: %w", err)
+	}
+	if err := validateReferences(registry.GetResources(), results); err != nil {
+		return nil, err
+	}
+	return levels, nil
+}
+
+// validateReferences validates that every item has an identifier and that
+// every reference to another resource resolves to an item within the results.
+func validateReferences(resources []resource.Resource, results map[string][]map[string]interface{}) error {
+	known := make(map[string]resource.Resource, len(resources))
+	for _, res := range resources {
+		known[res.Name()] = res
+	}
+
+	// Collect the IDs of the items of each resource
+	var errs []error
+	ids := make(map[string]map[string]struct{}, len(results))
+	names := make([]string, 0, len(results))
+	for name, items := range results {
+		names = append(names, name)
+		ids[name] = make(map[string]struct{}, len(items))
+		for _, item := range items {
+			if id, err := resource.ParseID(item["id"]); err == nil {
+				ids[name][id] = struct{}{}
+			}
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		res, ok := known[name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown resource %q", name))
+			continue
+		}
+		var references map[string]string
+		if referencer, ok := res.(resource.Referencer); ok {
+			references = referencer.References()
+		}
+		fields := make([]string, 0, len(references))
+		for field := range references {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for i, item := range results[name] {
+			id := itemID(item)
+			if len(id) == 0 {
+				errs = append(errs, fmt.Errorf("%s at index %d: missing id or name field", name, i))
+				continue
+			}
+			for _, field := range fields {
+				referenceID, ok := referencedID(item[field])
+				if !ok {
+					continue
+				}
+				if _, exists := ids[references[field]][referenceID]; !exists {
+					errs = append(errs, fmt.Errorf("%s %s: %s %s not found", name, id, references[field],
+						referenceID))
+				}
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidReferences, errors.Join(errs...))
+	}
+	return nil
+}
+
+// referencedID returns the ID of a reference field, which is either an object
+// containing the ID or the ID itself; false is returned if the field is not
+// set.
+func referencedID(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		id, err := resource.ParseID(value["id"])
+		return id, err == nil
+	case string:
+		return value, len(value) > 0
+	default:
+		return "", false
+	}
+}
+
+func countItems(results map[string][]map[string]interface{}) int {
+	count := 0
+	for _, items := range results {
+		count += len(items)
+	}
+	return count
+}
+
+// applyOptions contains the options for applying the items of each resource.
+type applyOptions struct {
+	// strategy is how the items are written; put or patch.
+	strategy string
+	// onlyMissing only creates the items that do not exist; the existence of
+	// each item is checked by ID and name before it is created and existing
+	// items are skipped.
+	onlyMissing bool
+}
+
+// applyCounts are the numbers of items applied and skipped by an apply.
+type applyCounts struct {
+	// applied is the number of items written.
+	applied int
+	// skipped is the number of existing items skipped when only creating the
+	// missing items.
+	skipped int
+}
+
+// applyLevels creates or replaces (or patches using the patch strategy) the
+// items of each resource, level by level in insertion order, so that
+// referenced items exist before the items referencing them. When only
+// creating the missing items, the existing items are skipped. The numbers of
+// items applied and skipped are returned.
+func applyLevels(ctx context.Context, client *client.Client, levels [][]resource.Resource,
+	results map[string][]map[string]interface{}, opts applyOptions, logger *zap.Logger,
+) (applyCounts, error) {
+	startTime := time.Now()
+	var counts applyCounts
+	for i, level := range levels {
+		for _, res := range level {
+			items := results[res.Name()]
+			if len(items) == 0 {
+				continue
+			}
+			skipped := 0
+			for _, item := range items {
+				id := itemID(item)
+				applied, err := applyItem(ctx, client, res, id, item, opts, logger)
+				if err != nil {
+					logger.Error("error applying item",
+						zap.String("resource", res.Name()),
+						zap.String("id", id),
+						zap.Error(err))
+					return counts, fmt.Errorf("error applying resource %s with ID %s: %w", res.Name(), id, err)
+				}
+				if !applied {
+					skipped++
+					continue
+				}
+				counts.applied++
+			}
+			counts.skipped += skipped
+			logger.Info("Applied items for resource",
+				zap.String("resource", res.Name()),
+				zap.Int("level", i),
+				zap.Int("items", len(items)-skipped),
+				zap.Int("skipped", skipped))
+		}
+	}
+	logger.Info("Successfully applied items",
+		zap.Int("item-count", counts.applied),
+		zap.Int("skipped-count", counts.skipped),
+		zap.Duration("duration", time.Since(startTime)))
+	return counts, nil
+}
+
+// validateApplyStrategy returns ErrInvalidApplyStrategy if the strategy is not
+// a known strategy; an empty strategy is the put strategy.
+func validateApplyStrategy(strategy string) error {
+	switch strategy {
+	case "", ApplyStrategyPut, ApplyStrategyPatch:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidApplyStrategy, strategy)
+	}
+}
+
+// applyItem writes the item using the strategy. With the patch strategy an
+// item that does not exist is created using a put since there is nothing to
+// patch. When only creating the missing items, an existing item is skipped
+// and a missing item is created using a put. False is returned if the item
+// was skipped. The existence of an item is checked using its ID and, if not
+// found, its name.
+func applyItem(ctx context.Context, client *client.Client, res resource.Resource, id string,
+	item map[string]interface{}, opts applyOptions, logger *zap.Logger,
+) (bool, error) {
+	endpointWithID := fmt.Sprintf("%s/%s", res.Path(), id)
+	if opts.onlyMissing {
+		existing, err := client.GetEndpointItem(ctx, endpointWithID)
+		if err != nil {
+			return false, fmt.Errorf("error checking existence: %w", err)
+		}
+
+		// An item with the same name but a different ID (e.g. created
+		// independently on the control plane) also exists
+		if name, _ := item["name"].(string); existing == nil && len(name) > 0 {
+			existing, err = client.GetEndpointItem(ctx, fmt.Sprintf("%s/%s", res.Path(), url.PathEscape(name)))
+			if err != nil {
+				return false, fmt.Errorf("error checking existence by name: %w", err)
+			}
+		}
+		if existing != nil {
+			logger.Debug("Item exists; skipping",
+				zap.String("resource", res.Name()),
+				zap.String("id", id))
+			return false, nil
+		}
+		return true, client.PutEndpoint(ctx, endpointWithID, item)
+	}
+	if opts.strategy != ApplyStrategyPatch {
+		return true, client.PutEndpoint(ctx, endpointWithID, item)
+	}
+	if err := client.PatchEndpoint(ctx, endpointWithID, item); !isNotFound(err) {
+		return true, err
+	}
+	logger.Debug("Item does not exist; creating",
+		zap.String("resource", res.Name()),
+		zap.String("id", id))
+	return true, client.PutEndpoint(ctx, endpointWithID, item)
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestApply(t *testing.T) {
+	t.Run("verify all dangling references are reported", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		_, err = validateApply(registry, map[string][]map[string]interface{}{
+			"service": {{"id": "svc-1", "name": "svc"}},
+			"route": {
+				{"id": "route-1", "service": map[string]interface{}{"id": "svc-1"}},
+				{"id": "route-2", "service": map[string]interface{}{"id": "svc-missing"}},
+			},
+			"plugin": {
+				{"id": "plugin-1", "name": "acl", "consumer": map[string]interface{}{"id": "consumer-missing"}},
+				{"id": "plugin-2", "name": "cors", "route": nil, "service": nil},
+			},
+		})
+		require.ErrorIs(t, err, ErrInvalidReferences)
+		require.ErrorContains(t, err, "route route-2: service svc-missing not found")
+		require.ErrorContains(t, err, "plugin plugin-1: consumer consumer-missing not found")
+		require.NotContains(t, err.Error(), "route-1")
+		require.NotContains(t, err.Error(), "plugin-2")
+	})
+
+	t.Run("verify unknown resources and items without an ID are reported", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		_, err = validateApply(registry, map[string][]map[string]interface{}{
+			"services": {{"id": "svc-1"}},
+			"consumer": {{"username": "alice"}},
+		})
+		require.ErrorIs(t, err, ErrInvalidReferences)
+		require.ErrorContains(t, err, `unknown resource "services"`)
+		require.ErrorContains(t, err, "consumer at index 0: missing id or name field")
+	})
+
+	t.Run("verify items are applied in insertion order", func(t *testing.T) {
+		var mutex sync.Mutex
+		var paths []string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPut, r.Method)
+			mutex.Lock()
+			paths = append(paths, filepath.Base(filepath.Dir(r.URL.Path)))
+			mutex.Unlock()
+			w.WriteHeader(http.StatusOK)
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		results := map[string][]map[string]interface{}{
+			"route":   {{"id": "route-1", "service": map[string]interface{}{"id": "svc-1"}}},
+			"service": {{"id": "svc-1"}},
+		}
+		levels, err := validateApply(registry, results)
+		require.NoError(t, err)
+		counts, err := applyLevels(context.Background(), client, levels, results, applyOptions{}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, 2, counts.applied)
+		require.Equal(t, []string{"services", "routes"}, paths)
+	})
+
+	t.Run("verify patch strategy patches existing items and creates missing items", func(t *testing.T) {
+		var mutex sync.Mutex
+		var requests []string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requests = append(requests, r.Method+" "+filepath.Base(r.URL.Path))
+			mutex.Unlock()
+			var item map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			require.Len(t, item, 2)
+			require.Contains(t, item, "host")
+			if r.Method == http.MethodPatch && strings.HasSuffix(r.URL.Path, "/svc-2") {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		results := map[string][]map[string]interface{}{
+			"service": {{"id": "svc-1", "host": "a.example.com"}, {"id": "svc-2", "host": "b.example.com"}},
+		}
+		levels, err := validateApply(registry, results)
+		require.NoError(t, err)
+		counts, err := applyLevels(context.Background(), client, levels, results,
+			applyOptions{strategy: ApplyStrategyPatch}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, 2, counts.applied)
+		require.Equal(t, []string{"PATCH svc-1", "PATCH svc-2", "PUT svc-2"}, requests)
+	})
+
+	t.Run("verify only missing items are created", func(t *testing.T) {
+		var mutex sync.Mutex
+		var requests []string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requests = append(requests, r.Method+" "+filepath.Base(r.URL.Path))
+			mutex.Unlock()
+			switch {
+			case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/svc-1"):
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"svc-1","host":"a.example.com"}`))
+			case r.Method == http.MethodGet:
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		results := map[string][]map[string]interface{}{
+			"service": {{"id": "svc-1", "host": "a.example.com"}, {"id": "svc-2", "host": "b.example.com"}},
+		}
+		levels, err := validateApply(registry, results)
+		require.NoError(t, err)
+		counts, err := applyLevels(context.Background(), client, levels, results,
+			applyOptions{onlyMissing: true}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, applyCounts{applied: 1, skipped: 1}, counts)
+		require.Equal(t, []string{"GET svc-1", "GET svc-2", "PUT svc-2"}, requests)
+	})
+
+	t.Run("verify items existing with the same name are not created", func(t *testing.T) {
+		var mutex sync.Mutex
+		var requests []string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requests = append(requests, r.Method+" "+filepath.Base(r.URL.Path))
+			mutex.Unlock()
+			switch {
+			case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/billing"):
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"id":"svc-other","name":"billing","host":"a.example.com"}`))
+			case r.Method == http.MethodGet:
+				w.WriteHeader(http.StatusNotFound)
+			default:
+				w.WriteHeader(http.StatusOK)
+			}
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		results := map[string][]map[string]interface{}{
+			"service": {
+				{"id": "svc-1", "name": "billing", "host": "a.example.com"},
+				{"id": "svc-2", "name": "orders", "host": "b.example.com"},
+			},
+		}
+		levels, err := validateApply(registry, results)
+		require.NoError(t, err)
+		counts, err := applyLevels(context.Background(), client, levels, results,
+			applyOptions{onlyMissing: true}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, applyCounts{applied: 1, skipped: 1}, counts)
+		require.ElementsMatch(t, []string{"GET svc-1", "GET billing", "GET svc-2", "GET orders", "PUT svc-2"},
+			requests)
+	})
+
+	t.Run("verify unknown apply strategy is rejected", func(t *testing.T) {
+		require.NoError(t, validateApplyStrategy(""))
+		require.NoError(t, validateApplyStrategy(ApplyStrategyPut))
+		require.NoError(t, validateApplyStrategy(ApplyStrategyPatch))
+		require.ErrorIs(t, validateApplyStrategy("merge"), ErrInvalidApplyStrategy)
+	})
+
+	t.Run("verify pre-apply validation fails before any item is written", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "osiris.json")
+		require.NoError(t, os.WriteFile(filename, []byte(`{
+			"route": [{"id": "route-1", "service": {"id": "svc-missing"}}]
+		}`), 0o600))
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		viper.Reset()
+		defer viper.Reset()
+
+		var output bytes.Buffer
+		app := NewApply(ApplyOptions{File: filename, Output: &output})
+		err := app.Start(context.Background())
+		require.ErrorIs(t, err, ErrInvalidReferences)
+		require.ErrorContains(t, err, "route route-1: service svc-missing not found")
+		require.NoError(t, app.Stop(context.Background()))
+		require.Zero(t, requests.Load())
+		require.Empty(t, output.String())
+	})
+}
@@ -0,0 +1,51 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"github.com/mikefero/osiris/internal/config"
+	"go.uber.org/zap"
+)
+
+// auditLog records a structured entry for each item deleted from a control
+// plane.
+type auditLog struct {
+	logger *zap.Logger
+	config *config.Config
+}
+
+func newAuditLog(logger *zap.Logger, config *config.Config) *auditLog {
+	return &auditLog{
+		logger: logger,
+		config: config,
+	}
+}
+
+// deleted records the deletion of items from a resource.
+func (a *auditLog) deleted(resourceName string, items ...map[string]interface{}) {
+	for _, item := range items {
+		a.logger.Info("Deleted item",
+			zap.String("control-plane-id", a.config.ControlPlaneID.String()),
+			zap.String("resource", resourceName),
+			zap.String("item", itemID(item)),
+			zap.String("operator", a.config.Operator))
+	}
+}
+
+// sync flushes any buffered audit entries.
+func (a *auditLog) sync() error {
+	return a.logger.Sync()
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrResetNotConfirmed is returned when the operator does not confirm the
+// reset of a control plane.
+var ErrResetNotConfirmed = errors.New("reset was not confirmed")
+
+// confirmAnswer is the answer, other than the control plane ID, that confirms
+// the reset.
+const confirmAnswer = "yes"
+
+// confirmReset writes the target of the reset and the number of items to
+// delete for each resource, as counted by the plan (only the orphans when
+// deleting only the orphaned items), to the prompt and reads the answer of
+// the operator from the input; the reset is confirmed if the answer is the
+// control plane ID or `yes`.
+func confirmReset(prompt io.Writer, input io.Reader, baseURL string, plan *resetPlan, orphansOnly bool) error {
+	fmt.Fprintf(prompt, "Base URL:         %s\n", baseURL)
+	fmt.Fprintf(prompt, "Control plane ID: %s\n", plan.ControlPlaneID)
+	if orphansOnly {
+		fmt.Fprintln(prompt, "The following orphaned items will be deleted; other items are kept:")
+	} else {
+		fmt.Fprintln(prompt, "The following items will be deleted:")
+	}
+	for _, level := range plan.Levels {
+		for _, res := range level.Resources {
+			if res.Items > 0 {
+				fmt.Fprintf(prompt, "  %s: %d\n", res.Name, res.Items)
+			}
+		}
+	}
+	fmt.Fprintf(prompt, "  total: %d\n", plan.Items)
+	fmt.Fprintf(prompt, "Type the control plane ID or %q to proceed: ", confirmAnswer)
+
+	answer, err := bufio.NewReader(input).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error reading confirmation: %w", err)
+	}
+	answer = strings.TrimSpace(answer)
+	if answer != plan.ControlPlaneID && !strings.EqualFold(answer, confirmAnswer) {
+		return ErrResetNotConfirmed
+	}
+	return nil
+}
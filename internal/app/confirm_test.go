@@ -0,0 +1,131 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfirmReset(t *testing.T) {
+	plan := &resetPlan{
+		ControlPlaneID: "4168295f-015e-4190-837e-0fcc5d72a52f",
+		Levels: []planLevel{
+			{Resources: []planResource{{Name: "route", Items: 2}, {Name: "plugin", Items: 0}}},
+			{Resources: []planResource{{Name: "service", Items: 1}}},
+		},
+		Items: 3,
+	}
+
+	t.Run("verify the prompt contains the target and the counts to delete", func(t *testing.T) {
+		var prompt bytes.Buffer
+		require.NoError(t, confirmReset(&prompt, strings.NewReader("yes\n"), "http://localhost:3737", plan, false))
+		require.Contains(t, prompt.String(), "http://localhost:3737")
+		require.Contains(t, prompt.String(), plan.ControlPlaneID)
+		require.Contains(t, prompt.String(), "route: 2")
+		require.Contains(t, prompt.String(), "service: 1")
+		require.Contains(t, prompt.String(), "total: 3")
+		require.NotContains(t, prompt.String(), "plugin")
+	})
+
+	t.Run("verify the reset is confirmed by the control plane ID or yes", func(t *testing.T) {
+		for _, answer := range []string{plan.ControlPlaneID + "\n", "yes\n", "YES", "  yes  \n"} {
+			var prompt bytes.Buffer
+			require.NoError(t, confirmReset(&prompt, strings.NewReader(answer), "", plan, false), answer)
+		}
+	})
+
+	t.Run("verify any other answer denies the reset", func(t *testing.T) {
+		for _, answer := range []string{"no\n", "y\n", "\n", "", "4168295f\n"} {
+			var prompt bytes.Buffer
+			err := confirmReset(&prompt, strings.NewReader(answer), "", plan, false)
+			require.ErrorIs(t, err, ErrResetNotConfirmed, answer)
+		}
+	})
+
+	t.Run("verify only the orphans are counted when deleting only the orphaned items", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			require.Fail(t, "unexpected request", r.URL.Path)
+		}))
+		upstream := &fakeResource{name: "upstream", path: "upstreams", items: []map[string]interface{}{
+			{"id": "ups-1"},
+		}}
+		target := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "target", path: "targets", items: []map[string]interface{}{
+				{"id": "tgt-1", "upstream": map[string]interface{}{"id": "ups-1"}},
+				{"id": "tgt-2", "upstream": map[string]interface{}{"id": "ups-2"}},
+				{"id": "tgt-3", "upstream": map[string]interface{}{"id": "ups-3"}},
+			}},
+			references: map[string]string{"upstream": "upstream"},
+		}
+		orphans, err := planReset(context.Background(), client, [][]resource.Resource{{target}, {upstream}},
+			deleteOptions{orphansOnly: true}, zap.NewNop())
+		require.NoError(t, err)
+		orphans.ControlPlaneID = plan.ControlPlaneID
+
+		var prompt bytes.Buffer
+		require.NoError(t, confirmReset(&prompt, strings.NewReader("yes\n"), "", orphans, true))
+		require.Contains(t, prompt.String(), "orphaned items will be deleted")
+		require.Contains(t, prompt.String(), "target: 2")
+		require.Contains(t, prompt.String(), "total: 2")
+		require.NotContains(t, prompt.String(), "target: 3")
+		require.NotContains(t, prompt.String(), "upstream")
+	})
+
+	t.Run("verify a denied reset deletes no items", func(t *testing.T) {
+		var deletes atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				deletes.Add(1)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/services") {
+				_, _ = w.Write([]byte(`{"data":[{"id":"service-1"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		viper.Reset()
+		defer viper.Reset()
+
+		var prompt bytes.Buffer
+		app := NewReset(ResetOptions{Confirm: strings.NewReader("no\n"), Prompt: &prompt})
+		err := app.Start(context.Background())
+		require.ErrorIs(t, err, ErrResetNotConfirmed)
+		require.NoError(t, app.Stop(context.Background()))
+		require.Zero(t, deletes.Load())
+		require.Contains(t, prompt.String(), server.URL)
+		require.Contains(t, prompt.String(), "service: 1")
+	})
+}
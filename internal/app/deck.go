@@ -0,0 +1,353 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNoDeckResources is returned when a decK state file does not reference
+// any resource known to osiris.
+var ErrNoDeckResources = errors.New("decK file references no known resources")
+
+// ErrOutputDirIncompatible is returned when the output directory is combined
+// with an option producing a single output file.
+var ErrOutputDirIncompatible = errors.New("output directory is incompatible with the option")
+
+const (
+	// deckFormatVersion is the decK file format version of the layout files.
+	deckFormatVersion = "3.0"
+	// deckServicesDir is the directory of the output directory containing the
+	// file of each service.
+	deckServicesDir = "services"
+	// deckGlobalFilename is the file of the output directory containing the
+	// entities not nested within a service.
+	deckGlobalFilename = "global.yaml"
+)
+
+// deckResources maps the entity keys of a decK state file to the names of the
+// osiris resources.
+var deckResources = map[string]string{
+	"acls":                                   "acl",
+	"basicauth_credentials":                  "basic-auth",
+	"ca_certificates":                        "ca-certificate",
+	"certificates":                           "certificate",
+	"consumer_groups":                        "consumer-group",
+	"consumers":                              "consumer",
+	"custom_plugins":                         "custom-plugin",
+	"degraphql_routes":                       "degraphql-route",
+	"graphql_rate_limiting_cost_decorations": "graphql-rate-limiting-advanced-cost",
+	"hmacauth_credentials":                   "hmac-auth",
+	"jwt_secrets":                            "jwt",
+	"key_sets":                               "key-set",
+	"keyauth_credentials":                    "key-auth",
+	"keys":                                   "key",
+	"mtls_auth_credentials":                  "mtls-auth",
+	"partials":                               "partial",
+	"plugins":                                "plugin",
+	"routes":                                 "route",
+	"services":                               "service",
+	"snis":                                   "sni",
+	"targets":                                "target",
+	"upstreams":                              "upstream",
+	"vaults":                                 "vault",
+}
+
+// deckKeys maps the names of the osiris resources to the entity keys of a
+// decK state file.
+var deckKeys = func() map[string]string {
+	keys := make(map[string]string, len(deckResources))
+	for key, name := range deckResources {
+		keys[name] = key
+	}
+	return keys
+}()
+
+// deckNesting are the resources nested within the entities of their parent
+// resource in the decK layout, referenced using the field named after the
+// parent. The plugins are nested within their route before the routes are
+// nested within their service so that the plugins are carried along.
+var deckNesting = []struct {
+	child  string
+	parent string
+}{
+	{child: "plugin", parent: "route"},
+	{child: "plugin", parent: "service"},
+	{child: "plugin", parent: "consumer"},
+	{child: "route", parent: "service"},
+	{child: "target", parent: "upstream"},
+	{child: "sni", parent: "certificate"},
+	{child: "acl", parent: "consumer"},
+	{child: "basic-auth", parent: "consumer"},
+	{child: "hmac-auth", parent: "consumer"},
+	{child: "jwt", parent: "consumer"},
+	{child: "key-auth", parent: "consumer"},
+	{child: "mtls-auth", parent: "consumer"},
+}
+
+// readDeckResources reads the decK state file and returns the sorted names of
+// the osiris resources it references. The top-level entity keys are mapped
+// to resources along with the entities nested within them (e.g. the routes
+// of a service); metadata keys such as `_format_version` are ignored.
+func readDeckResources(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading decK file: %w", err)
+	}
+	var state map[string]interface{}
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("error parsing decK file: %w", err)
+	}
+
+	names := make(map[string]struct{})
+	collectDeckResources(state, names)
+	if len(names) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNoDeckResources, filename)
+	}
+	resources := make([]string, 0, len(names))
+	for name := range names {
+		resources = append(resources, name)
+	}
+	sort.Strings(resources)
+	return resources, nil
+}
+
+// collectDeckResources adds the resources of the entity keys of the decK
+// object to the names, descending into the entities of each key.
+func collectDeckResources(object map[string]interface{}, names map[string]struct{}) {
+	for key, value := range object {
+		name, ok := deckResources[key]
+		if !ok {
+			continue
+		}
+		entities, ok := value.([]interface{})
+		if !ok {
+			continue
+		}
+		names[name] = struct{}{}
+		for _, entity := range entities {
+			if nested, ok := entity.(map[string]interface{}); ok {
+				collectDeckResources(nested, names)
+			}
+		}
+	}
+}
+
+// filterResources returns the resources whose names are included; an error
+// is returned if an included name is not a known resource.
+func filterResources(resources []resource.Resource, include []string) ([]resource.Resource, error) {
+	byName := make(map[string]resource.Resource, len(resources))
+	for _, res := range resources {
+		byName[res.Name()] = res
+	}
+	filtered := make([]resource.Resource, 0, len(include))
+	for _, name := range include {
+		res, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownResource, name)
+		}
+		filtered = append(filtered, res)
+	}
+	return filtered, nil
+}
+
+// validateOutputDir returns an error wrapping ErrOutputDirIncompatible if the
+// output directory is combined with an option producing a single output.
+func validateOutputDir(opts DumpOptions) error {
+	if len(opts.OutputDir) == 0 {
+		return nil
+	}
+	switch {
+	case opts.Stream:
+		return fmt.Errorf("%w: stream", ErrOutputDirIncompatible)
+	case len(opts.SinceFile) > 0:
+		return fmt.Errorf("%w: since file", ErrOutputDirIncompatible)
+	case len(opts.OutputTemplate) > 0:
+		return fmt.Errorf("%w: output template", ErrOutputDirIncompatible)
+	case opts.OutputSplitSize > 0:
+		return fmt.Errorf("%w: output split size", ErrOutputDirIncompatible)
+	case opts.Output != nil:
+		return fmt.Errorf("%w: output opener", ErrOutputDirIncompatible)
+	default:
+		return nil
+	}
+}
+
+// nestDeckEntities returns the results keyed by decK entity key with the
+// entities nested within their parent entity (e.g. the routes of a service)
+// and the references to other entities replaced by the referenced ID. Entities
+// whose parent was not listed remain at the top level, and the names of the
+// resources unknown to decK are returned separately.
+func nestDeckEntities(results []resource.ResourceData) (map[string][]map[string]interface{}, []string) {
+	entities := toResultMap(results)
+	for _, nesting := range deckNesting {
+		parents := make(map[string]map[string]interface{})
+		for _, item := range entities[nesting.parent] {
+			if id, err := resource.ParseID(item["id"]); err == nil {
+				parents[id] = item
+			}
+		}
+		key := deckKeys[nesting.child]
+		var remaining []map[string]interface{}
+		for _, child := range entities[nesting.child] {
+			id, _ := referencedID(child[nesting.parent])
+			parent, ok := parents[id]
+			if !ok {
+				remaining = append(remaining, child)
+				continue
+			}
+			nested, _ := parent[key].([]map[string]interface{})
+			delete(child, nesting.parent)
+			parent[key] = append(nested, child)
+		}
+		entities[nesting.child] = remaining
+	}
+
+	state := make(map[string][]map[string]interface{})
+	var unknown []string
+	for name, items := range entities {
+		key, ok := deckKeys[name]
+		if !ok {
+			if len(items) > 0 {
+				unknown = append(unknown, name)
+			}
+			continue
+		}
+		if len(items) > 0 {
+			flattenDeckReferences(items)
+			state[key] = items
+		}
+	}
+	sort.Strings(unknown)
+	return state, unknown
+}
+
+// flattenDeckReferences replaces the references of the entities to other
+// entities (e.g. `{"id": "…"}`) with the referenced ID, as decK references
+// entities by name or ID, descending into the nested entities.
+func flattenDeckReferences(entities []map[string]interface{}) {
+	for _, entity := range entities {
+		for field, value := range entity {
+			switch value := value.(type) {
+			case map[string]interface{}:
+				if id, ok := referencedID(value); ok && len(value) == 1 {
+					entity[field] = id
+				}
+			case []map[string]interface{}:
+				flattenDeckReferences(value)
+			}
+		}
+	}
+}
+
+// deckServiceFilename returns the name of the file of the service within the
+// services directory; the service is named after its name, or its ID if the
+// service is unnamed.
+func deckServiceFilename(service map[string]interface{}) string {
+	name, _ := service["name"].(string)
+	if len(name) == 0 {
+		name, _ = resource.ParseID(service["id"])
+	}
+	return filepath.Base(name) + ".yaml"
+}
+
+// writeDeckLayout writes the results to the directory as decK state files:
+// one file for each service, containing its routes and plugins, in the
+// services directory and a global file containing the remaining entities
+// (e.g. consumers and certificates). Each file is filtered using the
+// pre-write hook and the post-write hook is executed for each file.
+func writeDeckLayout(ctx context.Context, results []resource.ResourceData, hooks *hooks, logger *zap.Logger,
+	dir string,
+) error {
+	state, unknown := nestDeckEntities(results)
+	if len(unknown) > 0 {
+		logger.Warn("Omitting resources unknown to decK from the output directory",
+			zap.Strings("resources", unknown))
+	}
+	if err := os.MkdirAll(filepath.Join(dir, deckServicesDir), 0o700); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+
+	services := state[deckKeys["service"]]
+	delete(state, deckKeys["service"])
+	written := make(map[string]bool, len(services))
+	for _, service := range services {
+		filename := deckServiceFilename(service)
+		written[filename] = true
+		if err := writeDeckFile(ctx, filepath.Join(dir, deckServicesDir, filename), map[string]interface{}{
+			deckKeys["service"]: []map[string]interface{}{service},
+		}, hooks, logger); err != nil {
+			return err
+		}
+	}
+	if err := removeStaleDeckFiles(filepath.Join(dir, deckServicesDir), written, logger); err != nil {
+		return err
+	}
+
+	global := make(map[string]interface{}, len(state))
+	for key, items := range state {
+		global[key] = items
+	}
+	if err := writeDeckFile(ctx, filepath.Join(dir, deckGlobalFilename), global, hooks, logger); err != nil {
+		return err
+	}
+	logger.Info("Wrote decK layout to output directory",
+		zap.String("output-dir", dir),
+		zap.Int("service-count", len(services)))
+	return nil
+}
+
+// removeStaleDeckFiles removes the service files of the services directory
+// that were not written by the current dump (e.g. a deleted or renamed
+// service) so that the output directory only contains the current services.
+func removeStaleDeckFiles(dir string, written map[string]bool, logger *zap.Logger) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("error reading services directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || filepath.Ext(entry.Name()) != ".yaml" || written[entry.Name()] {
+			continue
+		}
+		filename := filepath.Join(dir, entry.Name())
+		logger.Debug("Removing stale service file", zap.String("filename", filename))
+		if err := os.Remove(filename); err != nil {
+			return fmt.Errorf("error removing stale service file %s: %w", filename, err)
+		}
+	}
+	return nil
+}
+
+// writeDeckFile writes the entities to the file as a decK state file.
+func writeDeckFile(ctx context.Context, filename string, entities map[string]interface{}, hooks *hooks,
+	logger *zap.Logger,
+) error {
+	entities["_format_version"] = deckFormatVersion
+	data, err := yaml.Marshal(entities)
+	if err != nil {
+		return fmt.Errorf("error marshaling decK file %s: %w", filename, err)
+	}
+	return writeData(ctx, data, hooks, logger, fileOutput(filename))
+}
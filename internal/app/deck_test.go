@@ -0,0 +1,238 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLikeDeck(t *testing.T) {
+	t.Run("verify only the resources referenced by the decK file are dumped", func(t *testing.T) {
+		var mutex sync.Mutex
+		var requested []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requested = append(requested, r.URL.Path)
+			mutex.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/services"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"svc-1","host":"example.com"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/routes"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"route-1","service":{"id":"svc-1"}}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":[{"id":"unexpected"}]}`))
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		deckFilename := filepath.Join(dir, "kong.yaml")
+		require.NoError(t, os.WriteFile(deckFilename, []byte(`_format_version: "3.0"
+_info:
+  select_tags:
+  - team-a
+services:
+- name: example
+  host: example.com
+  routes:
+  - name: example
+    paths:
+    - /example
+`), 0o600))
+		outputFilename := filepath.Join(dir, "osiris.json")
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_OUTPUT_FILE", outputFilename)
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		viper.Reset()
+		defer viper.Reset()
+
+		app := NewDump(DumpOptions{LikeDeck: deckFilename})
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		var actual map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &actual))
+		require.Len(t, actual, 2)
+		require.Contains(t, actual, "service")
+		require.Contains(t, actual, "route")
+		for _, path := range requested {
+			require.NotContains(t, path, "plugins")
+			require.NotContains(t, path, "consumers")
+		}
+	})
+
+	t.Run("verify decK entity keys are mapped to resources", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "kong.yaml")
+		require.NoError(t, os.WriteFile(filename, []byte(`_format_version: "3.0"
+consumers:
+- username: alice
+  keyauth_credentials:
+  - key: secret
+  plugins:
+  - name: rate-limiting
+    config:
+      routes: []
+upstreams:
+- name: example
+  targets:
+  - target: 10.0.0.1:80
+`), 0o600))
+		actual, err := readDeckResources(filename)
+		require.NoError(t, err)
+		require.Equal(t, []string{"consumer", "key-auth", "plugin", "target", "upstream"}, actual)
+	})
+
+	t.Run("verify a decK file without known resources is rejected", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "kong.yaml")
+		require.NoError(t, os.WriteFile(filename, []byte("_format_version: \"3.0\"\n"), 0o600))
+		_, err := readDeckResources(filename)
+		require.ErrorIs(t, err, ErrNoDeckResources)
+	})
+}
+
+func TestOutputDir(t *testing.T) {
+	t.Run("verify a decK file is written for each service along with a global file", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/services"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"svc-1","name":"billing","host":"billing.internal"},` +
+					`{"id":"svc-2","name":"orders","host":"orders.internal"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/routes"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"route-1","name":"billing","service":{"id":"svc-1"}},` +
+					`{"id":"route-2","name":"orders","service":{"id":"svc-2"}}]}`))
+			case strings.HasSuffix(r.URL.Path, "/plugins"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"plugin-1","name":"key-auth","route":{"id":"route-1"}},` +
+					`{"id":"plugin-2","name":"cors","service":{"id":"svc-2"}},` +
+					`{"id":"plugin-3","name":"prometheus"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/consumers"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"consumer-1","username":"alice"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		outputDir := filepath.Join(dir, "kong")
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_OUTPUT_FILE", filepath.Join(dir, "osiris.json"))
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		viper.Reset()
+		defer viper.Reset()
+
+		// A service file written by a previous dump of a since deleted service
+		stale := filepath.Join(outputDir, "services", "deleted.yaml")
+		require.NoError(t, os.MkdirAll(filepath.Dir(stale), 0o700))
+		require.NoError(t, os.WriteFile(stale, []byte("services: []\n"), 0o600))
+
+		app := NewDump(DumpOptions{OutputDir: outputDir})
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		require.NoFileExists(t, filepath.Join(dir, "osiris.json"))
+		require.NoFileExists(t, stale)
+		require.FileExists(t, filepath.Join(outputDir, "osiris.meta.json"))
+
+		readState := func(filename string) map[string]interface{} {
+			data, err := os.ReadFile(filename)
+			require.NoError(t, err)
+			var state map[string]interface{}
+			require.NoError(t, yaml.Unmarshal(data, &state))
+			require.Equal(t, "3.0", state["_format_version"])
+			return state
+		}
+
+		billing := filepath.Join(outputDir, "services", "billing.yaml")
+		resources, err := readDeckResources(billing)
+		require.NoError(t, err)
+		require.Equal(t, []string{"plugin", "route", "service"}, resources)
+		require.Equal(t, map[string]interface{}{
+			"_format_version": "3.0",
+			"services": []interface{}{map[string]interface{}{
+				"id":   "svc-1",
+				"name": "billing",
+				"host": "billing.internal",
+				"routes": []interface{}{map[string]interface{}{
+					"id":   "route-1",
+					"name": "billing",
+					"plugins": []interface{}{map[string]interface{}{
+						"id":   "plugin-1",
+						"name": "key-auth",
+					}},
+				}},
+			}},
+		}, readState(billing))
+
+		orders := readState(filepath.Join(outputDir, "services", "orders.yaml"))
+		service := orders["services"].([]interface{})[0].(map[string]interface{})
+		require.Len(t, service["routes"], 1)
+		require.Equal(t, []interface{}{map[string]interface{}{"id": "plugin-2", "name": "cors"}}, service["plugins"])
+
+		global := readState(filepath.Join(outputDir, "global.yaml"))
+		require.NotContains(t, global, "services")
+		require.NotContains(t, global, "routes")
+		require.Equal(t, []interface{}{map[string]interface{}{"id": "plugin-3", "name": "prometheus"}},
+			global["plugins"])
+		require.Equal(t, []interface{}{map[string]interface{}{"id": "consumer-1", "username": "alice"}},
+			global["consumers"])
+	})
+
+	t.Run("verify references to entities that are not nested are flattened", func(t *testing.T) {
+		state, unknown := nestDeckEntities([]resource.ResourceData{
+			{Name: "consumer", Data: []map[string]interface{}{{"id": "consumer-1"}}},
+			{Name: "key-auth", Data: []map[string]interface{}{{"id": "key-1", "consumer": map[string]interface{}{
+				"id": "consumer-1",
+			}}}},
+			{Name: "plugin", Data: []map[string]interface{}{{"id": "plugin-1", "consumer": map[string]interface{}{
+				"id": "consumer-2",
+			}}}},
+			{Name: "config-store", Data: []map[string]interface{}{{"id": "store-1"}}},
+		})
+		require.Equal(t, []string{"config-store"}, unknown)
+		require.Equal(t, map[string][]map[string]interface{}{
+			"consumers": {{"id": "consumer-1", "keyauth_credentials": []map[string]interface{}{{"id": "key-1"}}}},
+			"plugins":   {{"id": "plugin-1", "consumer": "consumer-2"}},
+		}, state)
+	})
+
+	t.Run("verify the output directory is incompatible with a single output", func(t *testing.T) {
+		for _, opts := range []DumpOptions{
+			{OutputDir: "kong", Stream: true},
+			{OutputDir: "kong", SinceFile: "osiris.json"},
+			{OutputDir: "kong", OutputTemplate: "output.tmpl"},
+			{OutputDir: "kong", OutputSplitSize: 1024},
+		} {
+			require.ErrorIs(t, validateOutputDir(opts), ErrOutputDirIncompatible)
+		}
+		require.NoError(t, validateOutputDir(DumpOptions{OutputDir: "kong"}))
+	})
+}
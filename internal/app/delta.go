@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mikefero/osiris/internal/diff"
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// deltaResults returns the items of the results that are new or changed
+// compared to the prior dump, along with the identities of the items of each
+// resource removed since the prior dump.
+func deltaResults(prior map[string][]map[string]interface{}, results []resource.ResourceData,
+) ([]resource.ResourceData, map[string][]string, error) {
+	// Round trip the results through JSON so that they are comparable with
+	// the prior dump read from the file; the items written are the originals
+	live, err := roundTrip(toResultMap(results))
+	if err != nil {
+		return nil, nil, fmt.Errorf("error normalizing results: %w", err)
+	}
+
+	changed := make(map[string]map[string]struct{})
+	removed := make(map[string][]string)
+	for _, res := range diff.Compare(prior, live, nil).Resources {
+		keys := make(map[string]struct{}, len(res.Added)+len(res.Changed))
+		for _, key := range append(res.Added, res.Changed...) {
+			keys[key] = struct{}{}
+		}
+		changed[res.Name] = keys
+		if len(res.Removed) > 0 {
+			removed[res.Name] = res.Removed
+		}
+	}
+
+	var delta []resource.ResourceData
+	for _, data := range results {
+		keys := changed[data.Name]
+		if len(keys) == 0 {
+			continue
+		}
+		var items []map[string]interface{}
+		for i, item := range live[data.Name] {
+			if _, ok := keys[diff.ItemKey(item, i)]; ok {
+				items = append(items, data.Data[i])
+			}
+		}
+		delta = append(delta, resource.ResourceData{Name: data.Name, Data: items})
+	}
+	return delta, removed, nil
+}
+
+// removedFilename returns the filename of the manifest of removed items for
+// the output file (e.g. `osiris.json` becomes `osiris-removed.json`).
+func removedFilename(outputFilename string) string {
+	ext := filepath.Ext(outputFilename)
+	return fmt.Sprintf("%s-removed%s", strings.TrimSuffix(outputFilename, ext), ext)
+}
+
+// writeRemoved writes the manifest of the identities of the items of each
+// resource removed since the prior dump.
+func writeRemoved(filename string, removed map[string][]string, indent string) error {
+	data, err := json.MarshalIndent(removed, "", indent)
+	if err != nil {
+		return fmt.Errorf("error marshaling removed items: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return fmt.Errorf("error writing removed items: %w", err)
+	}
+	return nil
+}
+
+// countRemoved returns the number of items removed.
+func countRemoved(removed map[string][]string) int {
+	count := 0
+	for _, keys := range removed {
+		count += len(keys)
+	}
+	return count
+}
@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDeltaResults(t *testing.T) {
+	t.Run("verify only new and changed items are written and removed items are listed", func(t *testing.T) {
+		priorFilename := filepath.Join(t.TempDir(), "osiris.json")
+		require.NoError(t, writeResults(context.Background(), []resource.ResourceData{
+			{Name: "service", Data: []map[string]interface{}{
+				{"id": "svc-1", "name": "unchanged", "port": json.Number("80")},
+				{"id": "svc-2", "name": "changed", "port": json.Number("80")},
+				{"id": "svc-3", "name": "removed"},
+			}},
+			{Name: "route", Data: []map[string]interface{}{
+				{"id": "route-1", "paths": []interface{}{"/"}},
+			}},
+			{Name: "upstream", Data: []map[string]interface{}{{"id": "upstream-1"}}},
+		}, newHooks(config.Hooks{}, zap.NewNop()), zap.NewNop(), fileOutput(priorFilename), "  "))
+		prior, err := readResults(priorFilename)
+		require.NoError(t, err)
+
+		delta, removed, err := deltaResults(prior, []resource.ResourceData{
+			{Name: "service", Data: []map[string]interface{}{
+				{"id": "svc-1", "name": "unchanged", "port": json.Number("80")},
+				{"id": "svc-2", "name": "changed", "port": json.Number("8080")},
+				{"id": "svc-4", "name": "added"},
+			}},
+			{Name: "route", Data: []map[string]interface{}{
+				{"id": "route-1", "paths": []string{"/"}},
+			}},
+			{Name: "consumer", Data: []map[string]interface{}{{"id": "consumer-1"}}},
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, []resource.ResourceData{
+			{Name: "service", Data: []map[string]interface{}{
+				{"id": "svc-2", "name": "changed", "port": json.Number("8080")},
+				{"id": "svc-4", "name": "added"},
+			}},
+			{Name: "consumer", Data: []map[string]interface{}{{"id": "consumer-1"}}},
+		}, delta)
+		require.Equal(t, map[string][]string{
+			"service":  {"svc-3"},
+			"upstream": {"upstream-1"},
+		}, removed)
+
+		manifest := removedFilename(priorFilename)
+		require.Equal(t, filepath.Join(filepath.Dir(priorFilename), "osiris-removed.json"), manifest)
+		require.NoError(t, writeRemoved(manifest, removed, "  "))
+		data, err := os.ReadFile(manifest)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"service":["svc-3"],"upstream":["upstream-1"]}`, string(data))
+	})
+}
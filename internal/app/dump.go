@@ -16,28 +16,156 @@ limitations under the License.
 package app
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/mikefero/osiris/internal/client"
 	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/filter"
 	"github.com/mikefero/osiris/internal/logger"
 	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
 	"go.uber.org/fx"
 	"go.uber.org/fx/fxevent"
 	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
 )
 
+// ErrCheckpointFileRequired is returned when resuming a dump without a
+// checkpoint file.
+var ErrCheckpointFileRequired = errors.New("checkpoint file is required to resume")
+
+// ErrInvalidIndent is returned when the indent string of the output contains
+// characters other than spaces and tabs.
+var ErrInvalidIndent = errors.New("indent string must contain only spaces and tabs")
+
+// ErrInvalidEmptyResourcePolicy is returned when the empty resource policy is
+// not a known policy.
+var ErrInvalidEmptyResourcePolicy = errors.New("empty resource policy must be omit, empty-array, or null")
+
+// ErrUnknownResource is returned when the selected resource is not a known
+// resource.
+var ErrUnknownResource = errors.New("unknown resource")
+
+// ErrNestTargetsIncompatible is returned when nesting the targets is combined
+// with an option that does not list the upstreams of the targets.
+var ErrNestTargetsIncompatible = errors.New("nesting targets requires listing their upstreams")
+
+// DumpOptions contains the options for the dump command.
+type DumpOptions struct {
+	// Resume resumes an interrupted dump from the checkpoint file.
+	Resume bool
+	// Strict fails the dump when listed items violate the expectations of
+	// their resource, or when the number of items retrieved from an endpoint
+	// does not match its reported total, rather than logging a warning.
+	Strict bool
+	// ControlPlane is the control plane to dump when operating on a fleet of
+	// control planes; the configured control plane is used if nil.
+	ControlPlane *config.ControlPlane
+	// Config is the configuration loaded once for the control planes of a
+	// fleet; the configuration is loaded if nil.
+	Config *config.Config
+	// Only is the name of the single resource to dump; all resources are
+	// dumped if empty.
+	Only string
+	// LikeDeck is a decK state file; only the resources it references are
+	// dumped so that the dump stays aligned with the decK file.
+	LikeDeck string
+	// Events is the destination of the JSONL event stream; `-` writes the
+	// events to stdout and the event stream is disabled if empty.
+	Events string
+	// DefaultsFile is the file of default field values for each resource;
+	// fields matching their default value are stripped from the output.
+	DefaultsFile string
+	// NestTargets nests the targets under their upstream in the output rather
+	// than in a separate target collection.
+	NestTargets bool
+	// SkipForbidden skips the resources the bearer token is not authorized to
+	// list (403) rather than failing the dump.
+	SkipForbidden bool
+	// SinceFile is a prior dump the output is compared against; only the new
+	// and changed items are written and the removed items are written to a
+	// manifest alongside the output. The complete dump is written if empty.
+	SinceFile string
+	// Stream writes each page of a resource to the output as it is listed
+	// rather than holding all of the items in memory before writing; used for
+	// massive control planes.
+	Stream bool
+	// OutputTemplate is a Go text/template file the results are rendered
+	// through rather than being written as JSON; the template is executed
+	// with the items of each resource keyed by resource name.
+	OutputTemplate string
+	// OutputSplitSize splits the output file into numbered parts of at most
+	// this many bytes along with an index describing how to reassemble them
+	// (e.g. for artifact stores limiting the file size); the output is not
+	// split if zero.
+	OutputSplitSize int64
+	// OutputDir is a directory the results are written to as decK state
+	// files rather than to the output file: one file for each service
+	// containing its routes and plugins along with a global file containing
+	// the remaining entities (e.g. consumers and certificates).
+	OutputDir string
+	// RawOut is a file the unmodified API responses are written to alongside
+	// the output, keyed by endpoint, before any fields are stripped or
+	// sanitized; used to debug field stripping and sanitization. The file
+	// contains secrets and no raw output is written if empty.
+	RawOut string
+	// Filters are JMESPath filter expressions (e.g.
+	// `service[?protocol == 'grpc']`); only the items of a resource matching
+	// every filter applying to the resource are written.
+	Filters []string
+	// VerifyOutput reads back and parses the output file once written and
+	// fails the dump if the number of items of a resource does not match the
+	// gathered results. The output is written to a temporary file that only
+	// replaces the output file once verified.
+	VerifyOutput bool
+	// Output opens the destination the results are written to (e.g. a buffer
+	// or a network sink when embedding osiris); the output file is used if
+	// nil.
+	Output OutputOpener
+}
+
+// OutputOpener opens the destination the dump results are written to; the
+// destination is closed once the results are written.
+type OutputOpener func() (io.WriteCloser, error)
+
+// output is the destination of the dump results.
+type output struct {
+	// name identifies the destination in logs and hooks.
+	name string
+	// open opens the destination.
+	open OutputOpener
+}
+
+// fileOutput returns the output writing the results to the file.
+func fileOutput(filename string) output {
+	return output{
+		name: filename,
+		open: func() (io.WriteCloser, error) {
+			return os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+		},
+	}
+}
+
 // NewDump creates a new fx application for the dump command.
 // It provides the necessary dependencies and registers the dump functionality.
-func NewDump() *fx.App {
+func NewDump(opts DumpOptions) *fx.App {
 	return fx.New(
+		fx.Supply(opts),
 		fx.Provide(
-			config.NewConfig,
+			provideConfig(opts.Config),
 			func(config *config.Config) (*zap.Logger, error) {
 				return logger.NewLogger(config.Logger, logger.LoggerCommandTypeDump)
 			},
@@ -49,9 +177,10 @@ func NewDump() *fx.App {
 	)
 }
 
-func registerDump(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
+func registerDump(lc fx.Lifecycle, config *config.Config, opts DumpOptions, logger *zap.Logger) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			config, logger := forControlPlane(config, opts.ControlPlane, logger)
 			logger.Info("starting osiris",
 				zap.String("version", Version),
 				zap.String("commit", Commit),
@@ -59,20 +188,330 @@ func registerDump(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
 				zap.String("go-version", GoVersion),
 				zap.String("build-date", BuildDate),
 			)
+			ctx, cancel := withOperationTimeout(ctx, config)
+			defer cancel()
 			logger.Info("Starting dump")
-			client := client.NewClient(config, logger)
-			if results, err := listData(ctx, client, logger); err != nil {
-				logger.Error("error executing dump", zap.Error(err))
-				return fmt.Errorf("error listing data: %w", err)
+			startTime := time.Now()
+			events, closeEvents, err := openEvents(opts.Events)
+			if err != nil {
+				logger.Error("error opening event stream", zap.Error(err))
+				return err
+			}
+			//nolint: errcheck
+			defer closeEvents()
+			sanitizer, err := newSanitizer(config)
+			if err != nil {
+				logger.Error("error creating sanitizer", zap.Error(err))
+				return fmt.Errorf("error creating sanitizer: %w", err)
+			}
+			registry, err := resource.NewRegistry()
+			if err != nil {
+				logger.Error("error creating resource registry", zap.Error(err))
+				return fmt.Errorf("error creating resource registry: %w", err)
+			}
+			if err := validateIndent(config.IndentString); err != nil {
+				logger.Error("error validating output indent", zap.Error(err))
+				return err
+			}
+			emptyPolicy, err := parseEmptyResourcePolicy(config.EmptyResourcePolicy)
+			if err != nil {
+				logger.Error("error validating empty resource policy", zap.Error(err))
+				return err
+			}
+			outputKeyCase, err := parseKeyCase(config.OutputKeyCase)
+			if err != nil {
+				logger.Error("error validating output key case", zap.Error(err))
+				return err
+			}
+			outputTemplate, err := parseOutputTemplate(opts.OutputTemplate)
+			if err != nil {
+				logger.Error("error validating output template",
+					zap.String("output-template", opts.OutputTemplate),
+					zap.Error(err))
+				return err
+			}
+			if err := validateSplit(opts); err != nil {
+				logger.Error("error validating output split size", zap.Error(err))
+				return err
+			}
+			if err := validateOutputDir(opts); err != nil {
+				logger.Error("error validating output directory", zap.Error(err))
+				return err
+			}
+			if err := validateNestTargets(opts); err != nil {
+				logger.Error("error validating nested targets", zap.Error(err))
+				return err
+			}
+			git, err := newGitCommitter(config.Git, logger)
+			if err != nil {
+				logger.Error("error creating git committer", zap.Error(err))
+				return err
+			}
+			if git != nil {
+				if err := git.validate(opts, dumpOutputPath(config, opts)); err != nil {
+					logger.Error("error validating git repository", zap.Error(err))
+					return err
+				}
+			}
+			if err := validateRawOut(opts, config.OutputFile); err != nil {
+				logger.Error("error validating raw output", zap.Error(err))
+				return err
+			}
+			if err := validateVerifyOutput(opts, config); err != nil {
+				logger.Error("error validating output verification", zap.Error(err))
+				return err
+			}
+			if opts.Stream {
+				if err := validateStream(opts, config); err != nil {
+					logger.Error("error validating stream options", zap.Error(err))
+					return err
+				}
+			}
+			outputDir := opts.OutputDir
+			if len(outputDir) > 0 && opts.ControlPlane != nil {
+				outputDir = opts.ControlPlane.Filename(outputDir)
+			}
+			var prior map[string][]map[string]interface{}
+			sinceFile := opts.SinceFile
+			if len(sinceFile) > 0 && opts.ControlPlane != nil {
+				sinceFile = opts.ControlPlane.Filename(sinceFile)
+			}
+			if len(sinceFile) > 0 {
+				if prior, err = readResults(sinceFile); err != nil {
+					logger.Error("error reading prior dump",
+						zap.String("since-file", sinceFile),
+						zap.Error(err))
+					return fmt.Errorf("error reading prior dump: %w", err)
+				}
+			}
+			resources, err := selectResources(registry, opts.Only)
+			if err != nil {
+				logger.Error("error selecting resources", zap.Error(err))
+				return err
+			}
+			if len(opts.LikeDeck) > 0 {
+				include, err := readDeckResources(opts.LikeDeck)
+				if err != nil {
+					logger.Error("error reading decK file",
+						zap.String("like-deck", opts.LikeDeck),
+						zap.Error(err))
+					return err
+				}
+				if resources, err = filterResources(resources, include); err != nil {
+					logger.Error("error selecting resources", zap.Error(err))
+					return err
+				}
+				logger.Info("Dumping the resources referenced by the decK file",
+					zap.String("like-deck", opts.LikeDeck),
+					zap.Strings("resources", include))
+			}
+			stripper, err := newStripper(config.ResourceStripFields, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating resource field stripper", zap.Error(err))
+				return fmt.Errorf("error creating resource field stripper: %w", err)
+			}
+			jsonFields, err := newJSONFields(config.ResourceJSONFields, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating resource JSON fields", zap.Error(err))
+				return fmt.Errorf("error creating resource JSON fields: %w", err)
+			}
+			transforms, err := newTransforms(config.Transforms, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating transforms", zap.Error(err))
+				return fmt.Errorf("error creating transforms: %w", err)
+			}
+			filters, err := newFilters(opts.Filters, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating filters", zap.Error(err))
+				return fmt.Errorf("error creating filters: %w", err)
+			}
+			defaults, err := readDefaults(opts.DefaultsFile, registry.GetResources())
+			if err != nil {
+				logger.Error("error reading defaults file",
+					zap.String("defaults-file", opts.DefaultsFile),
+					zap.Error(err))
+				return fmt.Errorf("error reading defaults file: %w", err)
+			}
+			if err := resolveBaseURL(ctx, config, logger); err != nil {
+				logger.Error("error discovering base URL", zap.Error(err))
+				return err
+			}
+			if err := resolveControlPlaneID(ctx, config, logger); err != nil {
+				logger.Error("error resolving control plane", zap.Error(err))
+				return err
+			}
+			client, err := newClient(config, logger)
+			if err != nil {
+				logger.Error("error creating client", zap.Error(err))
+				return fmt.Errorf("error creating client: %w", err)
+			}
+			defer logRequestStats(client, logger)
+			checkpoint, err := openCheckpoint(config, opts)
+			if err != nil {
+				logger.Error("error opening checkpoint", zap.Error(err))
+				return fmt.Errorf("error opening checkpoint: %w", err)
+			}
+			if checkpoint != nil {
+				//nolint: errcheck
+				defer checkpoint.Close()
+				client.SetCheckpoint(checkpoint)
+			}
+			client.SetEmitter(events)
+			client.SetStrictTotals(opts.Strict)
+			raw, rawOut := newRawRecorder(opts, logger)
+			client.SetRawRecorder(raw)
+			retry, err := newResourceRetry(config)
+			if err != nil {
+				logger.Error("error creating resource retry", zap.Error(err))
+				return fmt.Errorf("error creating resource retry: %w", err)
+			}
+			if err := client.Ping(ctx); err != nil {
+				logger.Error("error executing preflight request", zap.Error(err))
+				return fmt.Errorf("error executing preflight request: %w", err)
+			}
+			resources = supportedResources(ctx, client, resources, logger)
+			skipped := &skippedResources{}
+			timings := &resourceTimings{}
+			defer timings.log(logger)
+			listOpts := listOptions{
+				stripper:        stripper,
+				defaults:        defaults,
+				sanitizer:       sanitizer,
+				transforms:      transforms,
+				filters:         filters,
+				jsonFields:      jsonFields,
+				continueOnError: config.ContinueOnError,
+				strict:          opts.Strict,
+				retry:           retry,
+				skipForbidden:   opts.SkipForbidden,
+				skipped:         skipped,
+				emptyPolicy:     emptyPolicy,
+				events:          events,
+				timings:         timings,
+			}
+			hooks := newHooks(config.Hooks, logger)
+			out := fileOutput(config.OutputFile)
+			if opts.Output != nil {
+				out.open = opts.Output
+			}
+			if opts.OutputSplitSize > 0 {
+				out = splitOutput(config.OutputFile, opts.OutputSplitSize)
+			}
+			var itemCount int
+			var listErr error
+			if opts.Stream {
+				itemCount, listErr = streamDump(ctx, client, resources, listOpts, outputKeyCase, out,
+					config.IndentString, logger)
+				if listErr != nil && !config.ContinueOnError {
+					logger.Error("error executing dump", zap.Error(listErr))
+					emitDone(events, itemCount, startTime, listErr)
+					return fmt.Errorf("error listing data: %w", listErr)
+				}
+				if err := hooks.postWrite(ctx, out.name); err != nil {
+					emitDone(events, itemCount, startTime, err)
+					return fmt.Errorf("error writing results: %w", err)
+				}
 			} else {
-				if err := writeResults(results, logger, config.OutputFile); err != nil {
+				var results []resource.ResourceData
+				results, listErr = listData(ctx, client, resources, listOpts, logger)
+				if listErr != nil && !config.ContinueOnError {
+					logger.Error("error executing dump", zap.Error(listErr))
+					emitDone(events, countResults(results), startTime, listErr)
+					return fmt.Errorf("error listing data: %w", listErr)
+				}
+				if opts.NestTargets {
+					results = nestTargets(results)
+				}
+				results = convertKeyCase(results, outputKeyCase)
+				if prior != nil {
+					delta, removed, err := deltaResults(prior, results)
+					if err != nil {
+						logger.Error("error comparing results with prior dump", zap.Error(err))
+						return err
+					}
+					manifest := removedFilename(config.OutputFile)
+					if err := writeRemoved(manifest, removed, config.IndentString); err != nil {
+						logger.Error("error writing removed items",
+							zap.String("removed-filename", manifest),
+							zap.Error(err))
+						return err
+					}
+					logger.Info("Compared results with prior dump",
+						zap.String("since-file", sinceFile),
+						zap.Int("item-count", countResults(results)),
+						zap.Int("changed-count", countResults(delta)),
+						zap.Int("removed-count", countRemoved(removed)))
+					results = delta
+				}
+				itemCount = countResults(results)
+				if opts.VerifyOutput {
+					out = verifiedOutput(config.OutputFile, resultCounts(results))
+				}
+				if len(outputDir) > 0 {
+					err = writeDeckLayout(ctx, results, hooks, logger, outputDir)
+				} else {
+					err = writeDump(ctx, results, outputTemplate, hooks, logger, out, config.IndentString)
+				}
+				if err != nil {
 					logger.Error("error writing results",
 						zap.String("output-filename", config.OutputFile),
 						zap.Error(err))
+					emitDone(events, itemCount, startTime, err)
 					return fmt.Errorf("error writing results: %w", err)
 				}
 			}
-			logger.Info("Dump completed successfully")
+			if raw != nil {
+				if err := raw.WriteFile(rawOut, config.IndentString); err != nil {
+					logger.Error("error writing raw output",
+						zap.String("raw-out", rawOut),
+						zap.Error(err))
+					emitDone(events, itemCount, startTime, err)
+					return err
+				}
+			}
+			metaFilename := provenanceFilename(config.OutputFile)
+			if len(outputDir) > 0 {
+				metaFilename = filepath.Join(outputDir, filepath.Base(metaFilename))
+			}
+			if opts.Output == nil {
+				if err := writeProvenance(metaFilename, newProvenance(config, client, time.Now())); err != nil {
+					logger.Error("error writing provenance",
+						zap.String("provenance-filename", metaFilename),
+						zap.Error(err))
+					emitDone(events, itemCount, startTime, err)
+					return err
+				}
+			}
+			emitDone(events, itemCount, startTime, listErr)
+			if listErr != nil {
+				logger.Error("error executing dump; partial results written", zap.Error(listErr))
+				if err := writeErrorReport(listErr, config.ErrorFile, logger); err != nil {
+					return fmt.Errorf("error writing error report: %w", err)
+				}
+				return fmt.Errorf("error listing data: %w", listErr)
+			}
+			if checkpoint != nil {
+				if err := removeCheckpoint(checkpoint, config.CheckpointFile); err != nil {
+					logger.Error("error removing checkpoint",
+						zap.String("checkpoint-file", config.CheckpointFile),
+						zap.Error(err))
+					return err
+				}
+			}
+			if git != nil {
+				if _, err := git.commit(gitCommit{
+					ControlPlaneID:   config.ControlPlaneID.String(),
+					ControlPlaneName: config.ControlPlaneName,
+					Timestamp:        startTime.UTC().Format(time.RFC3339),
+					ItemCount:        itemCount,
+				}, dumpOutputPath(config, opts), metaFilename); err != nil {
+					logger.Error("error committing output to git repository", zap.Error(err))
+					return fmt.Errorf("error committing output: %w", err)
+				}
+			}
+			logger.Info("Dump completed successfully",
+				zap.Int("item-count", itemCount),
+				zap.Strings("skipped-resources", skipped.names()))
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -85,9 +524,255 @@ func registerDump(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
 	})
 }
 
-func listData(ctx context.Context, client *client.Client, logger *zap.Logger) ([]resource.ResourceData, error) {
-	resources := resource.NewRegistry().GetResources()
-	errChan := make(chan error, len(resources))
+// dumpOutputPath returns the path the results of the dump are written to;
+// the output directory for the control plane if specified and the output file
+// otherwise.
+func dumpOutputPath(config *config.Config, opts DumpOptions) string {
+	if len(opts.OutputDir) == 0 {
+		return config.OutputFile
+	}
+	if opts.ControlPlane != nil {
+		return opts.ControlPlane.Filename(opts.OutputDir)
+	}
+	return opts.OutputDir
+}
+
+// selectResources returns the resources to dump. When a single resource is
+// selected it is looked up directly; resources are listed concurrently and
+// the dependency graph is never built or sorted for a dump.
+func selectResources(registry *resource.Registry, only string) ([]resource.Resource, error) {
+	if len(only) == 0 {
+		return registry.GetResources(), nil
+	}
+	res, ok := registry.GetResource(only)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownResource, only)
+	}
+	return []resource.Resource{res}, nil
+}
+
+// openCheckpoint opens the checkpoint used to persist the pagination progress
+// of the dump; nil is returned if checkpointing is disabled.
+func openCheckpoint(config *config.Config, opts DumpOptions) (*client.Checkpoint, error) {
+	if len(config.CheckpointFile) == 0 {
+		if opts.Resume {
+			return nil, ErrCheckpointFileRequired
+		}
+		return nil, nil //nolint: nilnil
+	}
+	return client.OpenCheckpoint(config.CheckpointFile, opts.Resume)
+}
+
+// removeCheckpoint closes and removes the checkpoint once the dump has
+// completed successfully.
+func removeCheckpoint(checkpoint *client.Checkpoint, filename string) error {
+	if err := checkpoint.Close(); err != nil {
+		return err
+	}
+	if err := os.Remove(filename); err != nil {
+		return fmt.Errorf("error removing checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// newSanitizer creates the sanitizer for the listed data; a sanitizer that
+// performs no sanitization is returned if sanitization is disabled.
+func newSanitizer(config *config.Config) (*sanitize.Sanitizer, error) {
+	if !config.Sanitize {
+		return &sanitize.Sanitizer{}, nil
+	}
+	return sanitize.NewSanitizer(config.Sanitization)
+}
+
+// newStripper creates the sanitizer that drops the fields excluded from the
+// output of each resource; an error is returned if a resource is unknown.
+func newStripper(fields map[string][]string, resources []resource.Resource) (*sanitize.Sanitizer, error) {
+	names := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		names[res.Name()] = struct{}{}
+	}
+	for name := range fields {
+		if _, ok := names[name]; !ok {
+			return nil, fmt.Errorf("unknown resource in resource strip fields: %q", name)
+		}
+	}
+	return sanitize.NewSanitizer(config.Sanitization{
+		Strategy: string(sanitize.StrategyDrop),
+		Fields:   fields,
+	})
+}
+
+// sanitizeItems drops the fields excluded from the output of the resource
+// using the stripper, if any, and sanitizes the secret fields of its items in
+// place.
+func sanitizeItems(stripper, sanitizer *sanitize.Sanitizer, resourceName string,
+	items []map[string]interface{},
+) error {
+	if stripper != nil {
+		if err := stripper.Sanitize(resourceName, items); err != nil {
+			return err
+		}
+	}
+	return sanitizer.Sanitize(resourceName, items)
+}
+
+// newTransforms creates the transforms of the listed items; nil is returned if
+// no transform rules are configured and an error is returned if the resource
+// of a rule is unknown.
+func newTransforms(rules []config.TransformRule, resources []resource.Resource) (*sanitize.Transforms, error) {
+	if len(rules) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+	transforms, err := sanitize.NewTransforms(rules)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		names[res.Name()] = struct{}{}
+	}
+	for _, name := range transforms.Resources() {
+		if _, ok := names[name]; !ok && name != sanitize.AllResources {
+			return nil, fmt.Errorf("%w in transforms: %q", ErrUnknownResource, name)
+		}
+	}
+	return transforms, nil
+}
+
+// newFilters creates the filters of the listed items; nil is returned if no
+// filter expressions are specified and an error is returned if the resource
+// of an expression is unknown.
+func newFilters(expressions []string, resources []resource.Resource) (*filter.Filters, error) {
+	if len(expressions) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+	filters, err := filter.New(expressions)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		names[res.Name()] = struct{}{}
+	}
+	for _, name := range filters.Resources() {
+		if _, ok := names[name]; !ok && name != filter.AllResources {
+			return nil, fmt.Errorf("%w in filters: %q", ErrUnknownResource, name)
+		}
+	}
+	return filters, nil
+}
+
+// readDefaults reads the default field values for each resource from the
+// defaults file; nil is returned if no defaults file is specified. The
+// resources of the defaults file must be known resources.
+func readDefaults(filename string, resources []resource.Resource) (*sanitize.Defaults, error) {
+	if len(filename) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+	defaults, err := sanitize.ReadDefaultsFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		names[res.Name()] = struct{}{}
+	}
+	for _, name := range defaults.Resources() {
+		if _, ok := names[name]; !ok {
+			return nil, fmt.Errorf("%w in defaults file: %q", ErrUnknownResource, name)
+		}
+	}
+	return defaults, nil
+}
+
+// emptyResourcePolicy is how resources without items are written to the
+// output.
+type emptyResourcePolicy string
+
+const (
+	// emptyResourceOmit leaves the resources without items out of the output.
+	emptyResourceOmit emptyResourcePolicy = "omit"
+	// emptyResourceEmptyArray writes the resources without items as an empty
+	// array.
+	emptyResourceEmptyArray emptyResourcePolicy = "empty-array"
+	// emptyResourceNull writes the resources without items as null.
+	emptyResourceNull emptyResourcePolicy = "null"
+)
+
+// parseEmptyResourcePolicy parses the empty resource policy; the resources
+// are omitted if the policy is empty.
+func parseEmptyResourcePolicy(policy string) (emptyResourcePolicy, error) {
+	switch p := emptyResourcePolicy(strings.ToLower(policy)); p {
+	case "":
+		return emptyResourceOmit, nil
+	case emptyResourceOmit, emptyResourceEmptyArray, emptyResourceNull:
+		return p, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidEmptyResourcePolicy, policy)
+	}
+}
+
+// data returns the data of a resource without items and whether the resource
+// is retained in the results.
+func (p emptyResourcePolicy) data() ([]map[string]interface{}, bool) {
+	switch p {
+	case emptyResourceEmptyArray:
+		return []map[string]interface{}{}, true
+	case emptyResourceNull:
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// listOptions are the options used when listing data from resources.
+type listOptions struct {
+	// stripper is used to drop the fields excluded from the output of each
+	// resource; no fields are dropped if nil.
+	stripper *sanitize.Sanitizer
+	// defaults is used to drop the fields matching their default value; no
+	// fields are dropped if nil.
+	defaults *sanitize.Defaults
+	// sanitizer is used to sanitize the secret fields of the listed data.
+	sanitizer *sanitize.Sanitizer
+	// transforms are applied to the listed items once sanitized; no items are
+	// transformed if nil.
+	transforms *sanitize.Transforms
+	// filters keep only the listed items matching the filter expressions of
+	// their resource; no items are filtered if nil.
+	filters *filter.Filters
+	// jsonFields is used to decode the JSON encoded string fields of each
+	// resource; no fields are decoded if nil.
+	jsonFields *jsonFields
+	// continueOnError continues listing the remaining resources when a
+	// resource fails; all errors are aggregated and returned along with the
+	// results of the successful resources.
+	continueOnError bool
+	// strict fails a resource whose listed items violate the expectations of
+	// the resource; violations are only logged otherwise.
+	strict bool
+	// retry is the retry policy for listing an entire resource.
+	retry resourceRetry
+	// skipForbidden skips the resources the bearer token is not authorized
+	// to list rather than failing; the skipped resources are recorded in
+	// skipped.
+	skipForbidden bool
+	skipped       *skippedResources
+	// emptyPolicy is how the resources without items are retained in the
+	// results; the resources are omitted by default.
+	emptyPolicy emptyResourcePolicy
+	// events is the emitter of the event stream; no events are emitted if
+	// nil.
+	events *event.Emitter
+	// timings collects the timing of each resource listed; no timings are
+	// collected if nil.
+	timings *resourceTimings
+}
+
+func listData(ctx context.Context, client *client.Client, resources []resource.Resource, opts listOptions,
+	logger *zap.Logger,
+) ([]resource.ResourceData, error) {
+	errs := newResourceErrors()
 	var mutex sync.Mutex
 	var results []resource.ResourceData
 	var wg sync.WaitGroup
@@ -95,31 +780,100 @@ func listData(ctx context.Context, client *client.Client, logger *zap.Logger) ([
 	logger.Info("Listing data from resources",
 		zap.Int("resource-count", len(resources)))
 
+	// The resources share a context canceled by the first error so that the
+	// in-flight requests of the remaining resources are abandoned when
+	// failing fast
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
 	// Iterate over the resources and start a goroutine for each one
 	startTime := time.Now()
 	for _, res := range resources {
 		wg.Add(1)
 		go func(res resource.Resource) {
 			defer wg.Done()
+			defer recoverResource(res, operationList, opts.events, errs.add, logger)
+			resStartTime := time.Now()
+			opts.events.Emit(event.Event{Event: event.ResourceStarted, Resource: res.Name()})
 
 			// List the resource items
-			data, err := res.List(ctx, client, logger)
-			if err != nil {
+			recordTiming := opts.timings.begin(client, res)
+			data, err := listResource(runCtx, client, res, opts.retry, logger)
+			recordTiming(len(data.Data))
+			switch {
+			case err == nil:
+			case opts.skipForbidden && isForbidden(err):
+				logger.Warn("Skipping resource; not authorized to list",
+					zap.String("resource", res.Name()),
+					zap.Error(err))
+				opts.skipped.add(res.Name())
+				emitCompleted(opts.events, res.Name(), 0, resStartTime)
+				return
+			case isPartialPages(err) && len(data.Data) > 0 && opts.continueOnError:
+				// Retain the partial data along with the error; failing fast
+				// fails the dump rather than writing truncated data
+				logger.Warn("Partial data listed for resource",
+					zap.String("resource", res.Name()),
+					zap.Int("items", len(data.Data)),
+					zap.Error(err))
+				errs.add(&operationError{
+					resource:  res.Name(),
+					operation: operationList,
+					err:       fmt.Errorf("error listing resource %s: %w", res.Name(), err),
+				})
+			default:
 				logger.Error("error listing resource",
 					zap.String("resource", res.Name()),
 					zap.Error(err))
-				errChan <- fmt.Errorf("error listing resource %s: %w", res.Name(), err)
+				errs.add(&operationError{
+					resource:  res.Name(),
+					operation: operationList,
+					err:       fmt.Errorf("error listing resource %s: %w", res.Name(), err),
+				})
+				emitFailed(opts.events, res.Name(), err)
 				return
 			}
 			if len(data.Data) == 0 {
 				logger.Debug("No data found for resource",
-					zap.String("resource", res.Name()))
+					zap.String("resource", res.Name()),
+					zap.String("empty-resource-policy", string(opts.emptyPolicy)))
+				if empty, ok := opts.emptyPolicy.data(); ok {
+					mutex.Lock()
+					results = append(results, resource.ResourceData{Name: res.Name(), Data: empty})
+					mutex.Unlock()
+				}
+				emitCompleted(opts.events, res.Name(), 0, resStartTime)
+				return
+			}
+			if err := validateItems(res, data.Data, opts.strict, logger); err != nil {
+				errs.add(&operationError{
+					resource:  res.Name(),
+					operation: operationValidate,
+					err:       fmt.Errorf("error validating resource %s: %w", res.Name(), err),
+				})
+				emitFailed(opts.events, res.Name(), err)
 				return
 			}
+			opts.jsonFields.decode(res.Name(), data.Data)
+			data.Data = opts.filters.Filter(res.Name(), data.Data)
+			if opts.defaults != nil {
+				opts.defaults.Strip(res.Name(), data.Data)
+			}
+			if err := sanitizeItems(opts.stripper, opts.sanitizer, res.Name(), data.Data); err != nil {
+				errs.add(&operationError{
+					resource:  res.Name(),
+					operation: operationSanitize,
+					err:       fmt.Errorf("error sanitizing resource %s: %w", res.Name(), err),
+				})
+				emitFailed(opts.events, res.Name(), err)
+				return
+			}
+			opts.transforms.Transform(res.Name(), data.Data)
 
 			mutex.Lock()
 			results = append(results, data)
 			mutex.Unlock()
+			emitCompleted(opts.events, res.Name(), len(data.Data), resStartTime)
 		}(res)
 	}
 
@@ -130,18 +884,39 @@ func listData(ctx context.Context, client *client.Client, logger *zap.Logger) ([
 		close(done)
 	}()
 
+	// Fail fast on the first error, canceling the remaining resources, unless
+	// continuing on error
+	failFast := errs.failed
+	if opts.continueOnError {
+		failFast = nil
+	}
 	select {
 	case <-ctx.Done():
 		logger.Warn("Context was canceled while listing data from resources",
 			zap.Error(ctx.Err()))
 		return nil, ctx.Err()
+	case err := <-failFast:
+		// Wait for the canceled resources to return so that no request is
+		// in flight once the dump fails
+		cancel()
+		<-done
+		logger.Error("Error occurred while listing data from resources",
+			zap.Error(err))
+		return nil, err
 	case <-done:
-		close(errChan)
-		if len(errChan) > 0 {
-			err := <-errChan
-			logger.Error("Error occurred while listing data from resources",
-				zap.Error(err))
-			return nil, err
+		if failures := errs.all(); len(failures) > 0 {
+			if !opts.continueOnError {
+				logger.Error("Error occurred while listing data from resources",
+					zap.Error(failures[0]))
+				return nil, failures[0]
+			}
+
+			// Aggregate all errors and return the successful results
+			logger.Error("Errors occurred while listing data from resources",
+				zap.Int("error-count", len(failures)),
+				zap.Int("resource-count", len(resources)),
+				zap.Duration("duration", time.Since(startTime)))
+			return results, errors.Join(failures...)
 		}
 	}
 
@@ -152,42 +927,251 @@ func listData(ctx context.Context, client *client.Client, logger *zap.Logger) ([
 	return results, nil
 }
 
-func writeResults(results []resource.ResourceData, logger *zap.Logger, outputFilename string) error {
-	// Create a map where the keys are the endpoint names
-	resultMap := make(map[string][]map[string]interface{})
+// countResults returns the number of items listed.
+func countResults(results []resource.ResourceData) int {
+	count := 0
+	for _, data := range results {
+		count += len(data.Data)
+	}
+	return count
+}
+
+// validateItems validates the listed items of a resource that implements
+// resource.Validator. In strict mode all violations are returned; otherwise
+// the violations are logged as warnings.
+func validateItems(res resource.Resource, items []map[string]interface{}, strict bool, logger *zap.Logger) error {
+	validator, ok := res.(resource.Validator)
+	if !ok {
+		return nil
+	}
+	var errs []error
+	for _, item := range items {
+		if err := validator.Validate(item); err != nil {
+			if !strict {
+				logger.Warn("Listed item failed validation",
+					zap.String("resource", res.Name()),
+					zap.Error(err))
+				continue
+			}
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateNestTargets returns an error wrapping ErrNestTargetsIncompatible
+// if the targets are nested while only the targets are dumped; the upstreams
+// are not listed so the targets could not be nested.
+func validateNestTargets(opts DumpOptions) error {
+	if opts.NestTargets && opts.Only == "target" {
+		return fmt.Errorf("%w: only %s", ErrNestTargetsIncompatible, opts.Only)
+	}
+	return nil
+}
+
+// nestTargets moves the targets into the `targets` field of their upstream,
+// removing the upstream reference of each target. Targets whose upstream was
+// not listed remain in the target collection.
+func nestTargets(results []resource.ResourceData) []resource.ResourceData {
+	upstreams := make(map[string]map[string]interface{})
+	for _, data := range results {
+		if data.Name != "upstream" {
+			continue
+		}
+		for _, item := range data.Data {
+			if id, err := resource.ParseID(item["id"]); err == nil {
+				upstreams[id] = item
+			}
+		}
+	}
+
+	nested := make([]resource.ResourceData, 0, len(results))
+	for _, data := range results {
+		if data.Name != "target" {
+			nested = append(nested, data)
+			continue
+		}
+		var remaining []map[string]interface{}
+		for _, target := range data.Data {
+			id, _ := referencedID(target["upstream"])
+			upstream, ok := upstreams[id]
+			if !ok {
+				remaining = append(remaining, target)
+				continue
+			}
+			targets, _ := upstream["targets"].([]map[string]interface{})
+			delete(target, "upstream")
+			upstream["targets"] = append(targets, target)
+		}
+		if len(remaining) > 0 {
+			nested = append(nested, resource.ResourceData{Name: data.Name, Data: remaining})
+		}
+	}
+	return nested
+}
+
+// orderedResults are the listed results keyed by resource name, serialized
+// with the resource names in alphabetical order so that the output is
+// reproducible regardless of the output format.
+type orderedResults struct {
+	names []string
+	data  map[string][]map[string]interface{}
+}
+
+func newOrderedResults(results []resource.ResourceData) *orderedResults {
+	data := toResultMap(results)
+	names := make([]string, 0, len(data))
+	for name := range data {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return &orderedResults{names: names, data: data}
+}
+
+// MarshalJSON serializes the results as a JSON object with the resource
+// names in order.
+func (r *orderedResults) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range r.names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.data[name])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
 
-	// Convert the slice of Results to a map
+// MarshalYAML serializes the results as a YAML mapping with the resource
+// names in order; a resource without data is serialized as null as with JSON.
+func (r *orderedResults) MarshalYAML() (interface{}, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, name := range r.names {
+		value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+		if r.data[name] != nil {
+			if err := value.Encode(r.data[name]); err != nil {
+				return nil, err
+			}
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: name}, value)
+	}
+	return node, nil
+}
+
+// toResultMap converts the listed resource data into a map where the keys are
+// the endpoint names.
+func toResultMap(results []resource.ResourceData) map[string][]map[string]interface{} {
+	resultMap := make(map[string][]map[string]interface{})
 	for _, result := range results {
 		resultMap[result.Name] = result.Data
 	}
+	return resultMap
+}
+
+// validateIndent validates that the indent string of the output contains only
+// whitespace so that the output remains valid JSON.
+func validateIndent(indent string) error {
+	if len(strings.Trim(indent, " \t")) > 0 {
+		return fmt.Errorf("%w: %q", ErrInvalidIndent, indent)
+	}
+	return nil
+}
+
+// writeResults writes the results to the output as JSON indented using the
+// indent string (compact if empty), filtering the output using the pre-write
+// hook and executing the post-write hook.
+func writeResults(ctx context.Context, results []resource.ResourceData, hooks *hooks, logger *zap.Logger,
+	out output, indent string,
+) error {
+	if err := validateIndent(indent); err != nil {
+		return err
+	}
+	ordered := newOrderedResults(results)
 
 	logger.Info("Marshaling results to JSON",
-		zap.Int("endpointCount", len(resultMap)))
+		zap.Int("endpointCount", len(ordered.names)))
 
-	// Marshal the map to JSON with pretty formatting
-	startTime := time.Now()
-	jsonData, err := json.MarshalIndent(resultMap, "", "  ")
+	// Marshal the map to JSON with pretty formatting unless compact
+	var jsonData []byte
+	var err error
+	if len(indent) > 0 {
+		jsonData, err = json.MarshalIndent(ordered, "", indent)
+	} else {
+		jsonData, err = json.Marshal(ordered)
+	}
 	if err != nil {
 		logger.Error("error marshaling results", zap.Error(err))
 		return fmt.Errorf("error marshaling results: %w", err)
 	}
+	return writeData(ctx, jsonData, hooks, logger, out)
+}
+
+// writeDump writes the results to the output, rendering the results through
+// the output template if specified and as JSON otherwise.
+func writeDump(ctx context.Context, results []resource.ResourceData, tmpl *template.Template, hooks *hooks,
+	logger *zap.Logger, out output, indent string,
+) error {
+	if tmpl == nil {
+		return writeResults(ctx, results, hooks, logger, out, indent)
+	}
+	data, err := renderTemplate(tmpl, results)
+	if err != nil {
+		logger.Error("error rendering output template", zap.Error(err))
+		return err
+	}
+	return writeData(ctx, data, hooks, logger, out)
+}
+
+// writeData writes the encoded results to the output, filtering the output
+// using the pre-write hook and executing the post-write hook.
+func writeData(ctx context.Context, data []byte, hooks *hooks, logger *zap.Logger, out output) error {
+	outputFilename := out.name
+	startTime := time.Now()
+	data, err := hooks.preWrite(ctx, outputFilename, data)
+	if err != nil {
+		return err
+	}
 
 	logger.Debug("Writing results to file",
 		zap.String("output-filename", outputFilename),
-		zap.Int("bytes", len(jsonData)),
-		zap.Duration("duration", time.Since(startTime)))
+		zap.Int("bytes", len(data)))
 
-	if err := os.WriteFile(outputFilename, jsonData, 0o600); err != nil {
+	if err := writeOutput(out, data); err != nil {
 		logger.Error("error writing file",
 			zap.String("output-filename", outputFilename),
 			zap.Error(err))
 		return fmt.Errorf("error writing file: %w", err)
 	}
 
-	logger.Info("Successfully wrote results to JSON file",
+	logger.Info("Successfully wrote results to file",
 		zap.String("output-filename", outputFilename),
-		zap.Int("bytes", len(jsonData)),
+		zap.Int("bytes", len(data)),
 		zap.Duration("duration", time.Since(startTime)))
 
-	return nil
+	return hooks.postWrite(ctx, outputFilename)
+}
+
+// writeOutput opens the output, writes the data, and closes the output.
+func writeOutput(out output, data []byte) error {
+	w, err := out.open()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		//nolint: errcheck
+		w.Close()
+		return err
+	}
+	return w.Close()
 }
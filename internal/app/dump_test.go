@@ -0,0 +1,663 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// fakePartialResource is a fake resource whose listing only retrieves some of
+// its pages.
+type fakePartialResource struct {
+	fakeResource
+}
+
+func (r *fakePartialResource) List(context.Context, *client.Client, *zap.Logger) (resource.ResourceData, error) {
+	return resource.ResourceData{Name: r.name, Data: r.items},
+		fmt.Errorf("error listing resource %s: %w", r.name, client.ErrPartialPages)
+}
+
+// fakeTrackedResource is a fake resource recording when its listing returns.
+type fakeTrackedResource struct {
+	fakeResource
+	returned atomic.Bool
+}
+
+func (r *fakeTrackedResource) List(ctx context.Context, c *client.Client, logger *zap.Logger,
+) (resource.ResourceData, error) {
+	defer r.returned.Store(true)
+	return r.fakeResource.List(ctx, c, logger)
+}
+
+func TestDump(t *testing.T) {
+	t.Run("verify large integers round-trip exactly", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"item-1","big":9007199254740993,"ratio":0.25}]}`))
+		}))
+
+		results, err := listData(context.Background(), client, []resource.Resource{
+			&fakeResource{name: "fake", path: "fakes"},
+		}, listOptions{sanitizer: &sanitize.Sanitizer{}}, zap.NewNop())
+		require.NoError(t, err)
+
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+		require.NoError(t, writeResults(context.Background(), results,
+			newHooks(config.Hooks{}, zap.NewNop()), zap.NewNop(), fileOutput(outputFilename), "  "))
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"big": 9007199254740993`)
+		require.Contains(t, string(data), `"ratio": 0.25`)
+	})
+	t.Run("verify resource strip fields apply only to the named resource", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"item-1","client_certificate":{"id":"cert-1"}}]}`))
+		}))
+
+		resources := []resource.Resource{
+			&fakeResource{name: "service", path: "services"},
+			&fakeResource{name: "certificate", path: "certificates"},
+		}
+		stripper, err := newStripper(map[string][]string{
+			"service": {"client_certificate"},
+		}, resources)
+		require.NoError(t, err)
+		results, err := listData(context.Background(), client, resources, listOptions{
+			stripper:  stripper,
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.NoError(t, err)
+
+		resultMap := toResultMap(results)
+		require.NotContains(t, resultMap["service"][0], "client_certificate")
+		require.Contains(t, resultMap["certificate"][0], "client_certificate")
+	})
+
+	t.Run("verify resource strip fields are validated against known resources", func(t *testing.T) {
+		_, err := newStripper(map[string][]string{
+			"services": {"client_certificate"},
+		}, []resource.Resource{&fakeResource{name: "service", path: "services"}})
+		require.ErrorContains(t, err, "services")
+	})
+
+	t.Run("verify each control plane in the control planes file is dumped", func(t *testing.T) {
+		var mutex sync.Mutex
+		requested := make(map[string]bool)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			requested[strings.Split(strings.TrimPrefix(r.URL.Path, "/"), "/")[0]] = true
+			mutex.Unlock()
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		ids := []uuid.UUID{uuid.New(), uuid.New()}
+		filename := filepath.Join(dir, "control-planes.txt")
+		require.NoError(t, os.WriteFile(filename,
+			[]byte(ids[0].String()+"\n"+ids[1].String()+",production\n"), 0o600))
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_OUTPUT_FILE", filepath.Join(dir, "osiris.json"))
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		viper.Reset()
+		defer viper.Reset()
+
+		controlPlanes, invalid, err := config.ReadControlPlanesFile(filename)
+		require.NoError(t, err)
+		require.Empty(t, invalid)
+		for _, controlPlane := range controlPlanes {
+			app := NewDump(DumpOptions{ControlPlane: &controlPlane})
+			require.NoError(t, app.Start(context.Background()))
+			require.NoError(t, app.Stop(context.Background()))
+		}
+
+		for _, id := range ids {
+			require.True(t, requested[id.String()], "control plane %s was not requested", id)
+			require.FileExists(t, filepath.Join(dir, "osiris-"+id.String()+".json"))
+		}
+	})
+
+	t.Run("verify single resource dump matches the full dump of the resource", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/consumers"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"consumer-1","username":"alice"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/consumers/consumer-1/consumer_groups"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"group-1"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		all, err := selectResources(registry, "")
+		require.NoError(t, err)
+		full, err := listData(context.Background(), client, all, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.NoError(t, err)
+
+		only, err := selectResources(registry, "consumer")
+		require.NoError(t, err)
+		require.Len(t, only, 1)
+		single, err := listData(context.Background(), client, only, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.NoError(t, err)
+
+		require.Len(t, single, 1)
+		require.Equal(t, toResultMap(full)["consumer"], toResultMap(single)["consumer"])
+		require.Equal(t, []string{"group-1"}, toResultMap(single)["consumer"][0]["groups"])
+	})
+
+	t.Run("verify fields matching the defaults file are stripped", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1","port":80,"retries":3},{"id":"svc-2","port":8080}]}`))
+		}))
+		defaultsFile := filepath.Join(t.TempDir(), "defaults.yaml")
+		require.NoError(t, os.WriteFile(defaultsFile, []byte("service:\n  port: 80\n  retries: 5\n"), 0o600))
+		defaults, err := readDefaults(defaultsFile, []resource.Resource{&fakeResource{name: "service"}})
+		require.NoError(t, err)
+
+		results, err := listData(context.Background(), client, []resource.Resource{
+			&fakeResource{name: "service", path: "services"},
+		}, listOptions{defaults: defaults, sanitizer: &sanitize.Sanitizer{}}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{
+			{"id": "svc-1", "retries": json.Number("3")},
+			{"id": "svc-2", "port": json.Number("8080")},
+		}, toResultMap(results)["service"])
+	})
+
+	t.Run("verify defaults file is validated against known resources", func(t *testing.T) {
+		defaultsFile := filepath.Join(t.TempDir(), "defaults.yaml")
+		require.NoError(t, os.WriteFile(defaultsFile, []byte("services:\n  port: 80\n"), 0o600))
+		_, err := readDefaults(defaultsFile, []resource.Resource{&fakeResource{name: "service"}})
+		require.ErrorIs(t, err, ErrUnknownResource)
+	})
+
+	t.Run("verify targets are nested under their upstream", func(t *testing.T) {
+		results := []resource.ResourceData{
+			{Name: "upstream", Data: []map[string]interface{}{{"id": "ups-1"}, {"id": "ups-2"}}},
+			{Name: "target", Data: []map[string]interface{}{
+				{"id": "tgt-1", "target": "a:80", "upstream": map[string]interface{}{"id": "ups-1"}},
+				{"id": "tgt-2", "target": "b:80", "upstream": map[string]interface{}{"id": "ups-1"}},
+				{"id": "tgt-3", "target": "c:80", "upstream": map[string]interface{}{"id": "ups-9"}},
+			}},
+			{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}}},
+		}
+
+		resultMap := toResultMap(nestTargets(results))
+		require.Equal(t, []map[string]interface{}{
+			{"id": "ups-1", "targets": []map[string]interface{}{
+				{"id": "tgt-1", "target": "a:80"},
+				{"id": "tgt-2", "target": "b:80"},
+			}},
+			{"id": "ups-2"},
+		}, resultMap["upstream"])
+		require.Equal(t, []map[string]interface{}{
+			{"id": "tgt-3", "target": "c:80", "upstream": map[string]interface{}{"id": "ups-9"}},
+		}, resultMap["target"])
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}}, resultMap["service"])
+	})
+
+	t.Run("verify nesting targets is rejected when only the targets are dumped", func(t *testing.T) {
+		require.ErrorIs(t, validateNestTargets(DumpOptions{NestTargets: true, Only: "target"}),
+			ErrNestTargetsIncompatible)
+		require.NoError(t, validateNestTargets(DumpOptions{NestTargets: true, Only: "upstream"}))
+		require.NoError(t, validateNestTargets(DumpOptions{NestTargets: true}))
+		require.NoError(t, validateNestTargets(DumpOptions{Only: "target"}))
+	})
+
+	t.Run("verify unknown single resource returns error", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		_, err = selectResources(registry, "consumers")
+		require.ErrorIs(t, err, ErrUnknownResource)
+	})
+
+	t.Run("verify validation rejects items missing a required field in strict mode", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1","host":"example.com"},{"id":"svc-2"}]}`))
+		}))
+		resources := []resource.Resource{&fakeValidatingResource{
+			fakeResource: fakeResource{name: "service", path: "services"},
+			required:     "host",
+		}}
+
+		_, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+			strict:    true,
+		}, zap.NewNop())
+		require.ErrorIs(t, err, resource.ErrInvalidItem)
+		require.ErrorContains(t, err, "svc-2")
+		require.NotContains(t, err.Error(), "svc-1")
+
+		results, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Len(t, toResultMap(results)["service"], 2)
+	})
+
+	t.Run("verify secret fields without a generated reference fail the resource", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"name":"alice","key":"secret"}]}`))
+		}))
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                string(sanitize.StrategyReference),
+			SecretReferenceTemplate: "{vault://kv/{{ .ID }}/{{ .Field }}}",
+			Fields:                  map[string][]string{"key-auth": {"key"}},
+		})
+		require.NoError(t, err)
+
+		results, err := listData(context.Background(), client,
+			[]resource.Resource{&fakeResource{name: "key-auth", path: "key-auths"}},
+			listOptions{sanitizer: sanitizer}, zap.NewNop())
+		require.ErrorIs(t, err, sanitize.ErrSecretReference)
+		require.NotContains(t, toResultMap(results), "key-auth")
+	})
+
+	t.Run("verify output is indented using the indent string", func(t *testing.T) {
+		results := []resource.ResourceData{{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}}}}
+		hooks := newHooks(config.Hooks{}, zap.NewNop())
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+
+		require.NoError(t, writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), "\t"))
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.Equal(t, "{\n\t\"service\": [\n\t\t{\n\t\t\t\"id\": \"svc-1\"\n\t\t}\n\t]\n}", string(data))
+
+		require.NoError(t, writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), ""))
+		data, err = os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.Equal(t, `{"service":[{"id":"svc-1"}]}`, string(data))
+	})
+
+	t.Run("verify resource keys are ordered identically across output formats", func(t *testing.T) {
+		results := []resource.ResourceData{
+			{Name: "upstream", Data: []map[string]interface{}{{"id": "ups-1"}}},
+			{Name: "consumer", Data: []map[string]interface{}{{"id": "consumer-1"}}},
+			{Name: "route", Data: []map[string]interface{}{{"id": "route-1"}}},
+			{Name: "key-set", Data: []map[string]interface{}{{"id": "set-1"}}},
+		}
+		ordered := newOrderedResults(results)
+
+		jsonData, err := json.Marshal(ordered)
+		require.NoError(t, err)
+		var jsonKeys []string
+		decoder := json.NewDecoder(bytes.NewReader(jsonData))
+		_, err = decoder.Token()
+		require.NoError(t, err)
+		for decoder.More() {
+			key, err := decoder.Token()
+			require.NoError(t, err)
+			jsonKeys = append(jsonKeys, key.(string))
+			var value interface{}
+			require.NoError(t, decoder.Decode(&value))
+		}
+
+		yamlData, err := yaml.Marshal(ordered)
+		require.NoError(t, err)
+		var document yaml.Node
+		require.NoError(t, yaml.Unmarshal(yamlData, &document))
+		var yamlKeys []string
+		mapping := document.Content[0]
+		for i := 0; i < len(mapping.Content); i += 2 {
+			yamlKeys = append(yamlKeys, mapping.Content[i].Value)
+		}
+
+		require.Equal(t, []string{"consumer", "key-set", "route", "upstream"}, jsonKeys)
+		require.Equal(t, jsonKeys, yamlKeys)
+	})
+
+	t.Run("verify indent string must contain only whitespace", func(t *testing.T) {
+		results := []resource.ResourceData{{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}}}}
+		err := writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()), zap.NewNop(),
+			fileOutput(filepath.Join(t.TempDir(), "osiris.json")), "--")
+		require.ErrorIs(t, err, ErrInvalidIndent)
+	})
+
+	t.Run("verify results are written to a custom output", func(t *testing.T) {
+		results := []resource.ResourceData{
+			{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}}},
+			{Name: "route", Data: []map[string]interface{}{{"id": "route-1"}}},
+		}
+		buffer := &closeBuffer{}
+		out := output{name: "buffer", open: func() (io.WriteCloser, error) { return buffer, nil }}
+		require.NoError(t, writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()),
+			zap.NewNop(), out, ""))
+		require.True(t, buffer.closed)
+		require.JSONEq(t, `{"route":[{"id":"route-1"}],"service":[{"id":"svc-1"}]}`, buffer.String())
+	})
+
+	t.Run("verify resources without items are written using the empty resource policy", func(t *testing.T) {
+		client := newTestClient(t, http.NotFoundHandler())
+		resources := []resource.Resource{
+			&fakeResource{name: "service", path: "services", items: newFakeItems(1)},
+			&fakeResource{name: "route", path: "routes", items: []map[string]interface{}{}},
+		}
+
+		for policy, expected := range map[string]string{
+			"":            `{"service":[{"id":"item-0"}]}`,
+			"omit":        `{"service":[{"id":"item-0"}]}`,
+			"empty-array": `{"route":[],"service":[{"id":"item-0"}]}`,
+			"null":        `{"route":null,"service":[{"id":"item-0"}]}`,
+		} {
+			emptyPolicy, err := parseEmptyResourcePolicy(policy)
+			require.NoError(t, err)
+			results, err := listData(context.Background(), client, resources, listOptions{
+				sanitizer:   &sanitize.Sanitizer{},
+				emptyPolicy: emptyPolicy,
+			}, zap.NewNop())
+			require.NoError(t, err)
+
+			buffer := &closeBuffer{}
+			out := output{name: "buffer", open: func() (io.WriteCloser, error) { return buffer, nil }}
+			require.NoError(t, writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()),
+				zap.NewNop(), out, ""))
+			require.JSONEq(t, expected, buffer.String(), policy)
+		}
+
+		_, err := parseEmptyResourcePolicy("empty")
+		require.ErrorIs(t, err, ErrInvalidEmptyResourcePolicy)
+	})
+
+	t.Run("verify resources not supported by the gateway version are skipped", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.Count(r.URL.Path, "/") == 1:
+				_, _ = w.Write([]byte(`{"version":"3.4.1.0-enterprise-edition"}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":[]}`))
+			}
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		resources := supportedResources(context.Background(), client, registry.GetResources(), zap.NewNop())
+		version, ok := client.Version()
+		require.True(t, ok)
+		require.Equal(t, "3.4.1.0-enterprise-edition", version.String())
+		names := make([]string, 0, len(resources))
+		for _, res := range resources {
+			names = append(names, res.Name())
+		}
+		require.Len(t, resources, len(registry.GetResources())-1)
+		require.NotContains(t, names, "partial")
+		require.Contains(t, names, "key")
+		require.Contains(t, names, "key-set")
+	})
+
+	t.Run("verify resource listing is retried after a failure", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}]}`))
+		}))
+		resources := []resource.Resource{&fakeResource{name: "service", path: "services"}}
+
+		results, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+			retry:     resourceRetry{retries: 2},
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, int32(2), requests.Load())
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}}, toResultMap(results)["service"])
+
+		requests.Store(0)
+		_, err = listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.Error(t, err)
+		require.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("verify an empty resource listing is retried when enabled", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if requests.Add(1) == 1 {
+				_, _ = w.Write([]byte(`{"data":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}]}`))
+		}))
+		resources := []resource.Resource{&fakeResource{name: "service", path: "services"}}
+
+		results, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+			retry:     resourceRetry{onEmpty: 2},
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, int32(2), requests.Load())
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}}, toResultMap(results)["service"])
+
+		requests.Store(0)
+		results, err = listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, int32(1), requests.Load())
+		require.NotContains(t, toResultMap(results), "service")
+	})
+
+	t.Run("verify forbidden resources are skipped only when requested", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/vaults") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}]}`))
+		}))
+		resources := []resource.Resource{
+			&fakeResource{name: "service", path: "services"},
+			&fakeResource{name: "vault", path: "vaults"},
+		}
+
+		skipped := &skippedResources{}
+		results, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer:     &sanitize.Sanitizer{},
+			skipForbidden: true,
+			skipped:       skipped,
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}}, toResultMap(results)["service"])
+		require.Equal(t, []string{"vault"}, skipped.names())
+
+		_, err = listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.True(t, isForbidden(err))
+		require.ErrorContains(t, err, "not authorized")
+	})
+
+	t.Run("verify events are emitted as resources are listed", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"item-1"},{"id":"item-2"}]}`))
+		}))
+		var buf bytes.Buffer
+		emitter := event.NewEmitter(&buf)
+		client.SetEmitter(emitter)
+
+		_, err := listData(context.Background(), client, []resource.Resource{
+			&fakeResource{name: "service", path: "services"},
+		}, listOptions{sanitizer: &sanitize.Sanitizer{}, events: emitter}, zap.NewNop())
+		require.NoError(t, err)
+
+		events := parseEvents(t, &buf)
+		require.Len(t, events, 3)
+		require.Equal(t, event.Event{Event: event.ResourceStarted, Time: events[0].Time, Resource: "service"},
+			events[0])
+		require.Equal(t, event.RequestRetried, events[1].Event)
+		require.Equal(t, 1, events[1].Attempt)
+		require.Equal(t, event.ResourceCompleted, events[2].Event)
+		require.Equal(t, "service", events[2].Resource)
+		require.Equal(t, 2, events[2].Count)
+	})
+
+	t.Run("verify all resources are processed when the gateway version is unknown", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		resources := supportedResources(context.Background(), client, registry.GetResources(), zap.NewNop())
+		require.Len(t, resources, len(registry.GetResources()))
+		_, ok := client.Version()
+		require.False(t, ok)
+	})
+
+	t.Run("verify partial pages fail the dump unless continuing on error", func(t *testing.T) {
+		c := newTestClient(t, http.NotFoundHandler())
+		resources := []resource.Resource{
+			&fakePartialResource{fakeResource{name: "service", path: "services", items: newFakeItems(2)}},
+		}
+
+		_, err := listData(context.Background(), c, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.ErrorIs(t, err, client.ErrPartialPages)
+
+		results, err := listData(context.Background(), c, resources, listOptions{
+			sanitizer:       &sanitize.Sanitizer{},
+			continueOnError: true,
+		}, zap.NewNop())
+		require.ErrorIs(t, err, client.ErrPartialPages)
+		require.Len(t, toResultMap(results)["service"], 2)
+	})
+
+	t.Run("verify the first error cancels the in-flight requests of the other resources", func(t *testing.T) {
+		canceled := make(chan struct{})
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/failing") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			select {
+			case <-r.Context().Done():
+				close(canceled)
+			case <-time.After(5 * time.Second):
+			}
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		startTime := time.Now()
+		slow := &fakeTrackedResource{fakeResource: fakeResource{name: "slow", path: "slow"}}
+		_, err := listData(context.Background(), client, []resource.Resource{
+			&fakeResource{name: "failing", path: "failing"},
+			slow,
+		}, listOptions{sanitizer: &sanitize.Sanitizer{}}, zap.NewNop())
+		require.ErrorContains(t, err, "error listing resource failing")
+		require.Less(t, time.Since(startTime), 5*time.Second)
+		require.True(t, slow.returned.Load(), "listing of the sibling resource had not returned")
+		select {
+		case <-canceled:
+		case <-time.After(5 * time.Second):
+			require.Fail(t, "request of the sibling resource was not canceled")
+		}
+	})
+
+	t.Run("verify continuing on error does not cancel the other resources", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/failing") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			time.Sleep(50 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"slow-1"}]}`))
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		results, err := listData(context.Background(), client, []resource.Resource{
+			&fakeResource{name: "failing", path: "failing"},
+			&fakeResource{name: "slow", path: "slow"},
+		}, listOptions{sanitizer: &sanitize.Sanitizer{}, continueOnError: true}, zap.NewNop())
+		require.ErrorContains(t, err, "error listing resource failing")
+		require.Equal(t, []map[string]interface{}{{"id": "slow-1"}}, toResultMap(results)["slow"])
+	})
+}
+
+// fakeValidatingResource is a fake resource whose items must contain the
+// required field.
+type fakeValidatingResource struct {
+	fakeResource
+	required string
+}
+
+func (r *fakeValidatingResource) Validate(item map[string]interface{}) error {
+	if _, ok := item[r.required]; !ok {
+		return fmt.Errorf("%w: %s missing %s", resource.ErrInvalidItem, item["id"], r.required)
+	}
+	return nil
+}
+
+// closeBuffer is a buffer recording whether it was closed.
+type closeBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closeBuffer) Close() error {
+	b.closed = true
+	return nil
+}
@@ -0,0 +1,211 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+)
+
+// ErrPanic is returned when processing a resource panics (e.g. a failed type
+// assertion while listing); the panic is recovered so that the buffered logs
+// are flushed when the application stops rather than lost in a crash.
+var ErrPanic = errors.New("panic while processing resource")
+
+const (
+	// operationList is the operation for listing the items of a resource.
+	operationList = "list"
+	// operationDelete is the operation for deleting an item of a resource.
+	operationDelete = "delete"
+	// operationValidate is the operation for validating the listed items of a
+	// resource.
+	operationValidate = "validate"
+	// operationSanitize is the operation for sanitizing the listed items of a
+	// resource.
+	operationSanitize = "sanitize"
+)
+
+// operationError is an error that occurred while performing an operation on a
+// resource or one of its items.
+type operationError struct {
+	resource  string
+	operation string
+	item      string
+	err       error
+}
+
+// Error implements the error interface for operationError.
+func (e *operationError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *operationError) Unwrap() error {
+	return e.err
+}
+
+// recoverResource recovers a panic of the operation on the resource, logging
+// the panic along with its stack, emitting the failure of the resource, and
+// reporting an error wrapping ErrPanic; it must be deferred by the function
+// performing the operation.
+func recoverResource(res resource.Resource, operation string, events *event.Emitter, report func(error),
+	logger *zap.Logger,
+) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	logger.Error("Recovered from panic while processing resource",
+		zap.String("resource", res.Name()),
+		zap.String("operation", operation),
+		zap.Any("panic", r),
+		zap.Stack("stack"))
+	err := &operationError{
+		resource:  res.Name(),
+		operation: operation,
+		err:       fmt.Errorf("error processing resource %s: %w: %v", res.Name(), ErrPanic, r),
+	}
+	emitFailed(events, res.Name(), err)
+	report(err)
+}
+
+// recovered performs the operation on the resource, returning an error
+// wrapping ErrPanic if the operation panics.
+func recovered(res resource.Resource, operation string, events *event.Emitter, logger *zap.Logger,
+	fn func() error,
+) (err error) {
+	defer recoverResource(res, operation, events, func(panicErr error) { err = panicErr }, logger)
+	return fn()
+}
+
+// resourceErrors collects the errors of the goroutines processing resources;
+// the first error is also signaled on failed so that the processing can fail
+// fast. Adding an error never blocks, regardless of the number of errors of a
+// resource.
+type resourceErrors struct {
+	mutex  sync.Mutex
+	errs   []error
+	failed chan error
+}
+
+// newResourceErrors creates an empty collection of resource errors.
+func newResourceErrors() *resourceErrors {
+	return &resourceErrors{failed: make(chan error, 1)}
+}
+
+// add adds the error to the collection, signaling it if it is the first.
+func (e *resourceErrors) add(err error) {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.errs = append(e.errs, err)
+	if len(e.errs) == 1 {
+		e.failed <- err
+	}
+}
+
+// all returns the errors collected in the order they were added.
+func (e *resourceErrors) all() []error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return slices.Clone(e.errs)
+}
+
+// isPartialPages returns true if the error indicates that only some of the
+// pages of a resource were retrieved.
+func isPartialPages(err error) bool {
+	return errors.Is(err, client.ErrPartialPages)
+}
+
+// errorReportEntry is a single failed resource or item in the error report.
+type errorReportEntry struct {
+	// Resource is the name of the resource that failed.
+	Resource string `json:"resource,omitempty"`
+	// Operation is the operation that failed (e.g. list or delete).
+	Operation string `json:"operation,omitempty"`
+	// Item is the ID of the item that failed, if applicable.
+	Item string `json:"item,omitempty"`
+	// Method is the HTTP method of the failed request, if applicable.
+	Method string `json:"method,omitempty"`
+	// URL is the URL of the failed request, if applicable.
+	URL string `json:"url,omitempty"`
+	// Status is the HTTP status code of the failed request, if applicable.
+	Status int `json:"status,omitempty"`
+	// Error is the error message.
+	Error string `json:"error"`
+}
+
+// newErrorReport creates the error report entries from an error; aggregated
+// errors produce an entry for each of the underlying errors.
+func newErrorReport(err error) []errorReportEntry {
+	if err == nil {
+		return nil
+	}
+
+	var entries []errorReportEntry
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			entries = append(entries, newErrorReport(e)...)
+		}
+		return entries
+	}
+
+	entry := errorReportEntry{
+		Error: err.Error(),
+	}
+	var errOperation *operationError
+	if errors.As(err, &errOperation) {
+		entry.Resource = errOperation.resource
+		entry.Operation = errOperation.operation
+		entry.Item = errOperation.item
+	}
+	var errRequest *client.RequestError
+	if errors.As(err, &errRequest) {
+		entry.Method = errRequest.Method
+		entry.URL = errRequest.URL
+		entry.Status = errRequest.StatusCode
+	}
+	return append(entries, entry)
+}
+
+// writeErrorReport writes the structured error report for an error to the
+// specified file.
+func writeErrorReport(err error, filename string, logger *zap.Logger) error {
+	entries := newErrorReport(err)
+	jsonData, marshalErr := json.MarshalIndent(entries, "", "  ")
+	if marshalErr != nil {
+		return fmt.Errorf("error marshaling error report: %w", marshalErr)
+	}
+	if writeErr := os.WriteFile(filename, jsonData, 0o600); writeErr != nil {
+		logger.Error("error writing error report",
+			zap.String("error-filename", filename),
+			zap.Error(writeErr))
+		return fmt.Errorf("error writing error report: %w", writeErr)
+	}
+
+	logger.Info("Wrote error report",
+		zap.String("error-filename", filename),
+		zap.Int("error-count", len(entries)))
+	return nil
+}
@@ -0,0 +1,220 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestErrorReport(t *testing.T) {
+	t.Run("verify failed items appear in the error report", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/services"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/routes"):
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				w.WriteHeader(http.StatusBadGateway)
+			}
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		resources := []resource.Resource{
+			&fakeResource{name: "service", path: "services"},
+			&fakeResource{name: "route", path: "routes"},
+			&fakeResource{name: "plugin", path: "plugins"},
+		}
+		results, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer:       &sanitize.Sanitizer{},
+			continueOnError: true,
+		}, zap.NewNop())
+		require.Error(t, err)
+		require.Equal(t, []resource.ResourceData{
+			{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}}},
+		}, results)
+
+		filename := filepath.Join(t.TempDir(), "errors.json")
+		require.NoError(t, writeErrorReport(err, filename, zap.NewNop()))
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		var entries []errorReportEntry
+		require.NoError(t, json.Unmarshal(data, &entries))
+		require.Len(t, entries, 2)
+
+		statuses := map[string]int{}
+		for _, entry := range entries {
+			require.Equal(t, operationList, entry.Operation)
+			require.Equal(t, http.MethodGet, entry.Method)
+			require.Contains(t, entry.URL, "/"+resourcePath(entry.Resource))
+			require.NotEmpty(t, entry.Error)
+			statuses[entry.Resource] = entry.Status
+		}
+		require.Equal(t, map[string]int{
+			"route":  http.StatusInternalServerError,
+			"plugin": http.StatusBadGateway,
+		}, statuses)
+	})
+
+	t.Run("verify first error is returned without continue on error", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		resources := []resource.Resource{&fakeResource{name: "service", path: "services"}}
+		results, err := listData(context.Background(), client, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, zap.NewNop())
+		require.Error(t, err)
+		require.Nil(t, results)
+		require.Len(t, newErrorReport(err), 1)
+	})
+}
+
+// panicResource is a resource that panics when listed.
+type panicResource struct {
+	fakeResource
+}
+
+func (r *panicResource) List(_ context.Context, _ *client.Client, _ *zap.Logger) (resource.ResourceData, error) {
+	var value interface{} = "not a map"
+	_ = value.(map[string]interface{})
+	return resource.ResourceData{}, nil
+}
+
+// panicPartialResource is a resource that lists only some of its pages and
+// whose validation panics.
+type panicPartialResource struct {
+	fakePartialResource
+}
+
+func (r *panicPartialResource) Validate(map[string]interface{}) error {
+	panic("validation panic")
+}
+
+func TestRecoverResource(t *testing.T) {
+	t.Run("verify a panic while listing is captured as an error", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}]}`))
+		}))
+		core, logs := observer.New(zap.ErrorLevel)
+
+		resources := []resource.Resource{
+			&fakeResource{name: "service", path: "services"},
+			&panicResource{fakeResource{name: "route", path: "routes"}},
+		}
+		results, err := listData(context.Background(), c, resources, listOptions{
+			sanitizer:       &sanitize.Sanitizer{},
+			continueOnError: true,
+		}, zap.New(core))
+		require.ErrorIs(t, err, ErrPanic)
+		require.Len(t, results, 1)
+		entries := newErrorReport(err)
+		require.Len(t, entries, 1)
+		require.Equal(t, "route", entries[0].Resource)
+
+		panics := logs.FilterMessage("Recovered from panic while processing resource").All()
+		require.Len(t, panics, 1)
+		require.Contains(t, panics[0].ContextMap()["stack"], "recoverResource")
+	})
+
+	t.Run("verify a panic while deleting is captured as an error", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		res := &panicResource{fakeResource{name: "route", path: "routes"}}
+		_, err := deleteLevels(context.Background(), c, [][]resource.Resource{{res}},
+			deleteOptions{audit: newNopAuditLog()}, &skippedResources{}, zap.NewNop())
+		require.ErrorIs(t, err, ErrPanic)
+	})
+
+	t.Run("verify a resource failing more than once does not block the listing", func(t *testing.T) {
+		c := newTestClient(t, http.NotFoundHandler())
+		res := &panicPartialResource{fakePartialResource{fakeResource{
+			name: "service", path: "services", items: newFakeItems(2),
+		}}}
+
+		var buf bytes.Buffer
+		_, err := listData(context.Background(), c, []resource.Resource{res}, listOptions{
+			sanitizer:       &sanitize.Sanitizer{},
+			continueOnError: true,
+			events:          event.NewEmitter(&buf),
+		}, zap.NewNop())
+		require.ErrorIs(t, err, client.ErrPartialPages)
+		require.ErrorIs(t, err, ErrPanic)
+		require.Len(t, newErrorReport(err), 2)
+		events := parseEvents(t, &buf)
+		require.Equal(t, event.ResourceFailed, events[len(events)-1].Event)
+	})
+
+	t.Run("verify a panic while streaming is captured as an error", func(t *testing.T) {
+		c := newTestClient(t, http.NotFoundHandler())
+		resources := []resource.Resource{
+			&panicResource{fakeResource{name: "route", path: "routes"}},
+			&fakeResource{name: "service", path: "services", items: newFakeItems(1)},
+		}
+
+		var buf, output bytes.Buffer
+		count, err := streamData(context.Background(), c, resources, listOptions{
+			sanitizer:       &sanitize.Sanitizer{},
+			continueOnError: true,
+			events:          event.NewEmitter(&buf),
+		}, keyCaseNone, newStreamWriter(&output, ""), zap.NewNop())
+		require.ErrorIs(t, err, ErrPanic)
+		require.Equal(t, 1, count)
+		require.True(t, json.Valid(output.Bytes()))
+		events := parseEvents(t, &buf)
+		require.Equal(t, event.ResourceFailed, events[1].Event)
+		require.Equal(t, "route", events[1].Resource)
+	})
+
+	t.Run("verify a panic while planning or deleting orphans is captured as an error", func(t *testing.T) {
+		c := newTestClient(t, http.NotFoundHandler())
+		levels := [][]resource.Resource{{&panicResource{fakeResource{name: "route", path: "routes"}}}}
+
+		_, err := planReset(context.Background(), c, levels, deleteOptions{}, zap.NewNop())
+		require.ErrorIs(t, err, ErrPanic)
+
+		var buf bytes.Buffer
+		_, err = resetOrphans(context.Background(), c, levels,
+			deleteOptions{audit: newNopAuditLog(), events: event.NewEmitter(&buf)}, zap.NewNop())
+		require.ErrorIs(t, err, ErrPanic)
+		events := parseEvents(t, &buf)
+		require.Len(t, events, 1)
+		require.Equal(t, event.ResourceFailed, events[0].Event)
+	})
+}
+
+func resourcePath(name string) string {
+	return name + "s"
+}
@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/logger"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidEndpoint is returned when the endpoint of the get command is not
+// a path relative to the control plane.
+var ErrInvalidEndpoint = errors.New("invalid endpoint")
+
+// ErrUnsanitizedEndpoint is returned when the items of an endpoint not
+// matching a resource would be written without sanitization while
+// sanitization is enabled.
+var ErrUnsanitizedEndpoint = errors.New("endpoint does not match a resource and cannot be sanitized")
+
+// GetOptions contains the options for the get command.
+type GetOptions struct {
+	// Endpoint is the path of the endpoint, relative to the control plane, to
+	// retrieve (e.g. `services` or `services/<id>/routes`).
+	Endpoint string
+	// OutputFile is the file the items are written to; the items are written
+	// to Output if empty.
+	OutputFile string
+	// Output is the writer used for the items when no output file is
+	// specified.
+	Output io.Writer
+}
+
+// NewGet creates a new fx application for the get command.
+// It provides the necessary dependencies and registers the get
+// functionality.
+func NewGet(opts GetOptions) *fx.App {
+	return fx.New(
+		fx.Supply(opts),
+		fx.Provide(
+			config.NewConfig,
+			func(config *config.Config) (*zap.Logger, error) {
+				return logger.NewLogger(config.Logger, logger.LoggerCommandTypeGet)
+			},
+		),
+		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
+			return &fxevent.ZapLogger{Logger: logger}
+		}),
+		fx.Invoke(registerGet),
+	)
+}
+
+func registerGet(lc fx.Lifecycle, config *config.Config, opts GetOptions, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			logger.Info("starting osiris",
+				zap.String("version", Version),
+				zap.String("commit", Commit),
+				zap.String("os-arch", OsArch),
+				zap.String("go-version", GoVersion),
+				zap.String("build-date", BuildDate),
+			)
+			ctx, cancel := withOperationTimeout(ctx, config)
+			defer cancel()
+			endpoint, err := validateEndpoint(opts.Endpoint)
+			if err != nil {
+				logger.Error("error validating endpoint", zap.Error(err))
+				return err
+			}
+			if err := validateIndent(config.IndentString); err != nil {
+				logger.Error("error validating output indent", zap.Error(err))
+				return err
+			}
+			logger.Info("Starting get", zap.String("endpoint", endpoint))
+			registry, err := resource.NewRegistry()
+			if err != nil {
+				logger.Error("error creating resource registry", zap.Error(err))
+				return fmt.Errorf("error creating resource registry: %w", err)
+			}
+			res := endpointResource(registry.GetResources(), endpoint)
+			if res == nil && config.Sanitize {
+				logger.Error("refusing to get an endpoint that cannot be sanitized",
+					zap.String("endpoint", endpoint))
+				return fmt.Errorf("%w: %s; use --no-sanitize to get it as is", ErrUnsanitizedEndpoint, endpoint)
+			}
+			sanitizer, err := newSanitizer(config)
+			if err != nil {
+				logger.Error("error creating sanitizer", zap.Error(err))
+				return fmt.Errorf("error creating sanitizer: %w", err)
+			}
+			stripper, err := newStripper(config.ResourceStripFields, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating resource field stripper", zap.Error(err))
+				return fmt.Errorf("error creating resource field stripper: %w", err)
+			}
+			if err := resolveBaseURL(ctx, config, logger); err != nil {
+				logger.Error("error discovering base URL", zap.Error(err))
+				return err
+			}
+			if err := resolveControlPlaneID(ctx, config, logger); err != nil {
+				logger.Error("error resolving control plane", zap.Error(err))
+				return err
+			}
+			client, err := newClient(config, logger)
+			if err != nil {
+				logger.Error("error creating client", zap.Error(err))
+				return fmt.Errorf("error creating client: %w", err)
+			}
+			defer logRequestStats(client, logger)
+			items, err := client.GetEndpoint(ctx, endpoint)
+			if err != nil {
+				logger.Error("error getting endpoint",
+					zap.String("endpoint", endpoint),
+					zap.Error(err))
+				return fmt.Errorf("error getting endpoint %s: %w", endpoint, err)
+			}
+			if items == nil {
+				items = []map[string]interface{}{}
+			}
+			if res != nil {
+				if err := sanitizeItems(stripper, sanitizer, res.Name(), items); err != nil {
+					logger.Error("error sanitizing endpoint",
+						zap.String("endpoint", endpoint),
+						zap.Error(err))
+					return fmt.Errorf("error sanitizing endpoint %s: %w", endpoint, err)
+				}
+			}
+			data, err := json.MarshalIndent(items, "", config.IndentString)
+			if err != nil {
+				return fmt.Errorf("error marshaling items: %w", err)
+			}
+			data = append(data, '\n')
+			if len(opts.OutputFile) > 0 {
+				err = writeOutput(fileOutput(opts.OutputFile), data)
+			} else {
+				_, err = opts.Output.Write(data)
+			}
+			if err != nil {
+				logger.Error("error writing items",
+					zap.String("output-filename", opts.OutputFile),
+					zap.Error(err))
+				return fmt.Errorf("error writing items: %w", err)
+			}
+			logger.Info("Get completed successfully",
+				zap.String("endpoint", endpoint),
+				zap.Int("item-count", len(items)))
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("stopping osiris")
+			if err := logger.Sync(); err != nil {
+				logger.Error("failed to sync logger", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
+
+// endpointResource returns the resource whose items are listed by the
+// endpoint; the endpoint matches the path of the resource or ends with it
+// (e.g. `services/<id>/routes` for routes). Nil is returned if no resource
+// matches.
+func endpointResource(resources []resource.Resource, endpoint string) resource.Resource {
+	for _, res := range resources {
+		if endpoint == res.Path() || strings.HasSuffix(endpoint, "/"+res.Path()) {
+			return res
+		}
+	}
+	return nil
+}
+
+// validateEndpoint returns the endpoint without its leading slashes; an error
+// wrapping ErrInvalidEndpoint is returned if the endpoint is empty, is an
+// absolute URL, has a query, or escapes the control plane using dot segments.
+func validateEndpoint(endpoint string) (string, error) {
+	endpoint = strings.TrimLeft(strings.TrimSpace(endpoint), "/")
+	if len(endpoint) == 0 {
+		return "", fmt.Errorf("%w: endpoint is required", ErrInvalidEndpoint)
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidEndpoint, err)
+	}
+	switch {
+	case endpointURL.IsAbs() || len(endpointURL.Host) > 0:
+		return "", fmt.Errorf("%w: %s is not relative to the control plane", ErrInvalidEndpoint, endpoint)
+	case len(endpointURL.RawQuery) > 0 || len(endpointURL.Fragment) > 0:
+		return "", fmt.Errorf("%w: %s has a query", ErrInvalidEndpoint, endpoint)
+	case slices.Contains(strings.Split(endpointURL.Path, "/"), ".."):
+		return "", fmt.Errorf("%w: %s escapes the control plane", ErrInvalidEndpoint, endpoint)
+	}
+	return strings.TrimSuffix(endpoint, "/"), nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEndpoint(t *testing.T) {
+	t.Run("verify endpoints relative to the control plane are accepted", func(t *testing.T) {
+		for endpoint, expected := range map[string]string{
+			"services":               "services",
+			"/services/svc-1/routes": "services/svc-1/routes",
+			"v1/targets/":            "v1/targets",
+		} {
+			actual, err := validateEndpoint(endpoint)
+			require.NoError(t, err, endpoint)
+			require.Equal(t, expected, actual)
+		}
+	})
+
+	t.Run("verify invalid endpoints are rejected", func(t *testing.T) {
+		for _, endpoint := range []string{
+			"",
+			"/",
+			"https://example.com/services",
+			"services?size=10",
+			"../../control-planes",
+			"services/%zz",
+		} {
+			_, err := validateEndpoint(endpoint)
+			require.ErrorIs(t, err, ErrInvalidEndpoint, endpoint)
+		}
+	})
+}
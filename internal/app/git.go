@@ -0,0 +1,226 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mikefero/osiris/internal/config"
+	"go.uber.org/zap"
+)
+
+// defaultCommitMessageTemplate is the template of the commit message when no
+// template is configured.
+const defaultCommitMessageTemplate = "Dump control plane {{ .ControlPlaneID }} at {{ .Timestamp }}"
+
+// ErrGitIncompatible is returned when committing the output to a Git
+// repository is combined with an output that cannot be committed or an
+// output outside of the working tree.
+var ErrGitIncompatible = errors.New("git repository is incompatible with the output")
+
+// gitCommit is the data the commit message template is executed with.
+type gitCommit struct {
+	// ControlPlaneID is the ID of the dumped control plane.
+	ControlPlaneID string
+	// ControlPlaneName is the name of the dumped control plane; empty if not
+	// known.
+	ControlPlaneName string
+	// Timestamp is the time of the dump in RFC 3339 format.
+	Timestamp string
+	// ItemCount is the number of items dumped.
+	ItemCount int
+}
+
+// gitMutex serializes the commits of the dumps of a fleet of control planes
+// running simultaneously since a repository accepts a single commit at a time.
+var gitMutex sync.Mutex
+
+// gitCommitter commits the output of a dump to a Git repository so that
+// backups are versioned; a commit is only created when the output changed.
+// The repository is operated on in-process so that the git command is not
+// required.
+type gitCommitter struct {
+	repoPath string
+	message  *template.Template
+	// author is the author and committer of the commits; the author of the
+	// repository configuration is used if nil.
+	author *mail.Address
+	logger *zap.Logger
+}
+
+// newGitCommitter creates the committer of the output to the Git repository;
+// nil is returned if no repository is configured. The commit message
+// template and the author are validated up front.
+func newGitCommitter(config config.Git, logger *zap.Logger) (*gitCommitter, error) {
+	if len(config.RepoPath) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+	text := config.CommitMessageTemplate
+	if len(text) == 0 {
+		text = defaultCommitMessageTemplate
+	}
+	message, err := template.New("commit-message").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid commit message template: %w", err)
+	}
+	if err := message.Execute(&bytes.Buffer{}, gitCommit{}); err != nil {
+		return nil, fmt.Errorf("invalid commit message template: %w", err)
+	}
+
+	var author *mail.Address
+	if len(config.Author) > 0 {
+		if author, err = mail.ParseAddress(config.Author); err != nil {
+			return nil, fmt.Errorf("invalid git author %q: %w", config.Author, err)
+		}
+	}
+	return &gitCommitter{
+		repoPath: config.RepoPath,
+		message:  message,
+		author:   author,
+		logger:   logger,
+	}, nil
+}
+
+// validate returns an error wrapping ErrGitIncompatible if the output
+// cannot be committed to the repository: the output must be a file or
+// directory within the working tree.
+func (g *gitCommitter) validate(opts DumpOptions, outputPath string) error {
+	switch {
+	case opts.Output != nil:
+		return fmt.Errorf("%w: output opener", ErrGitIncompatible)
+	case opts.OutputSplitSize > 0:
+		return fmt.Errorf("%w: output split size", ErrGitIncompatible)
+	}
+	if _, err := g.relativePath(outputPath); err != nil {
+		return err
+	}
+	return nil
+}
+
+// relativePath returns the slash-separated path relative to the working tree;
+// the working tree itself is `.` (e.g. an output directory at the root of the
+// repository). An error wrapping ErrGitIncompatible is returned if the path
+// is outside of the working tree.
+func (g *gitCommitter) relativePath(path string) (string, error) {
+	repoPath, err := filepath.Abs(g.repoPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid git repository path: %w", err)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("invalid output path: %w", err)
+	}
+	relative, err := filepath.Rel(repoPath, absPath)
+	if err != nil || relative == ".." || strings.HasPrefix(relative, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s is outside of the working tree %s", ErrGitIncompatible, path, g.repoPath)
+	}
+	return filepath.ToSlash(relative), nil
+}
+
+// commit commits the output along with the sidecars of the output (e.g. the
+// provenance) if the output changed; false is returned if the output is
+// unchanged and no commit was created. The sidecars are not considered when
+// detecting a change since they differ for every dump (e.g. the time of the
+// dump). The files removed from an output directory are removed from the
+// repository as well.
+func (g *gitCommitter) commit(data gitCommit, output string, sidecars ...string) (bool, error) {
+	gitMutex.Lock()
+	defer gitMutex.Unlock()
+	startTime := time.Now()
+	outputPath, err := g.relativePath(output)
+	if err != nil {
+		return false, err
+	}
+	var sidecarPaths []string
+	for _, sidecar := range sidecars {
+		if _, err := os.Stat(sidecar); err != nil {
+			continue
+		}
+		if path, err := g.relativePath(sidecar); err == nil {
+			sidecarPaths = append(sidecarPaths, path)
+		}
+	}
+
+	repo, err := git.PlainOpen(g.repoPath)
+	if err != nil {
+		return false, fmt.Errorf("error opening git repository %s: %w", g.repoPath, err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("error opening git working tree %s: %w", g.repoPath, err)
+	}
+	paths := append([]string{outputPath}, sidecarPaths...)
+	for _, path := range paths {
+		if _, err := worktree.Add(path); err != nil {
+			return false, fmt.Errorf("error adding %s to git repository: %w", path, err)
+		}
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return false, fmt.Errorf("error getting git repository status: %w", err)
+	}
+	if !outputChanged(status, outputPath, sidecarPaths) {
+		g.logger.Info("Output unchanged; skipping git commit",
+			zap.String("repo-path", g.repoPath),
+			zap.String("output", outputPath))
+		return false, nil
+	}
+
+	var message bytes.Buffer
+	if err := g.message.Execute(&message, data); err != nil {
+		return false, fmt.Errorf("error generating commit message: %w", err)
+	}
+	opts := &git.CommitOptions{}
+	if g.author != nil {
+		opts.Author = &object.Signature{Name: g.author.Name, Email: g.author.Address, When: time.Now()}
+	}
+	if _, err := worktree.Commit(message.String(), opts); err != nil {
+		return false, fmt.Errorf("error committing to git repository: %w", err)
+	}
+	g.logger.Info("Committed output to git repository",
+		zap.String("repo-path", g.repoPath),
+		zap.Strings("paths", paths),
+		zap.Duration("duration", time.Since(startTime)))
+	return true, nil
+}
+
+// outputChanged returns true if a staged file of the output, other than the
+// sidecars, differs from the last commit.
+func outputChanged(status git.Status, outputPath string, sidecarPaths []string) bool {
+	for path, fileStatus := range status {
+		if fileStatus.Staging == git.Unmodified || fileStatus.Staging == git.Untracked ||
+			slices.Contains(sidecarPaths, path) {
+			continue
+		}
+		if outputPath == "." || path == outputPath || strings.HasPrefix(path, outputPath+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newGitRepo initializes a Git repository in a temporary directory.
+func newGitRepo(t *testing.T) (string, *git.Repository) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	return dir, repo
+}
+
+// gitLog returns the commits of the repository, newest first.
+func gitLog(t *testing.T, repo *git.Repository) []*object.Commit {
+	t.Helper()
+	iter, err := repo.Log(&git.LogOptions{})
+	require.NoError(t, err)
+	var commits []*object.Commit
+	require.NoError(t, iter.ForEach(func(commit *object.Commit) error {
+		commits = append(commits, commit)
+		return nil
+	}))
+	return commits
+}
+
+// gitFiles returns the names of the files of the commit.
+func gitFiles(t *testing.T, commit *object.Commit) []string {
+	t.Helper()
+	files, err := commit.Files()
+	require.NoError(t, err)
+	var names []string
+	require.NoError(t, files.ForEach(func(file *object.File) error {
+		names = append(names, file.Name)
+		return nil
+	}))
+	return names
+}
+
+func TestGitCommitter(t *testing.T) {
+	t.Run("verify a commit is created only when the output changes", func(t *testing.T) {
+		dir, repo := newGitRepo(t)
+		committer, err := newGitCommitter(config.Git{
+			RepoPath:              dir,
+			CommitMessageTemplate: "Backup {{ .ControlPlaneID }} at {{ .Timestamp }} ({{ .ItemCount }} items)",
+			Author:                "Osiris <osiris@example.com>",
+		}, zap.NewNop())
+		require.NoError(t, err)
+
+		output := filepath.Join(dir, "osiris.json")
+		sidecar := filepath.Join(dir, "osiris.meta.json")
+		data := gitCommit{ControlPlaneID: "cp-1", Timestamp: "2025-01-02T03:04:05Z", ItemCount: 2}
+		require.NoError(t, os.WriteFile(output, []byte(`{"service":[{"id":"svc-1"}]}`), 0o600))
+		require.NoError(t, os.WriteFile(sidecar, []byte(`{"dumped_at":"1"}`), 0o600))
+		committed, err := committer.commit(data, output, sidecar)
+		require.NoError(t, err)
+		require.True(t, committed)
+		commits := gitLog(t, repo)
+		require.Len(t, commits, 1)
+		require.Equal(t, "Backup cp-1 at 2025-01-02T03:04:05Z (2 items)", commits[0].Message)
+		require.Equal(t, "Osiris", commits[0].Author.Name)
+		require.Equal(t, "osiris@example.com", commits[0].Author.Email)
+		require.Equal(t, []string{"osiris.json", "osiris.meta.json"}, gitFiles(t, commits[0]))
+
+		// Only the sidecar differs; no commit is created
+		require.NoError(t, os.WriteFile(sidecar, []byte(`{"dumped_at":"2"}`), 0o600))
+		committed, err = committer.commit(data, output, sidecar)
+		require.NoError(t, err)
+		require.False(t, committed)
+		require.Len(t, gitLog(t, repo), 1)
+
+		require.NoError(t, os.WriteFile(output, []byte(`{"service":[{"id":"svc-2"}]}`), 0o600))
+		committed, err = committer.commit(data, output, sidecar)
+		require.NoError(t, err)
+		require.True(t, committed)
+		require.Len(t, gitLog(t, repo), 2)
+	})
+
+	t.Run("verify the output directory may be the working tree", func(t *testing.T) {
+		dir, repo := newGitRepo(t)
+		committer, err := newGitCommitter(config.Git{
+			RepoPath: dir,
+			Author:   "Osiris <osiris@example.com>",
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.NoError(t, committer.validate(DumpOptions{}, dir))
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "services.json"), []byte(`[]`), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "routes.json"), []byte(`[]`), 0o600))
+		committed, err := committer.commit(gitCommit{ControlPlaneID: "cp-1"}, dir)
+		require.NoError(t, err)
+		require.True(t, committed)
+
+		// Files removed from the output are removed from the repository
+		require.NoError(t, os.Remove(filepath.Join(dir, "routes.json")))
+		committed, err = committer.commit(gitCommit{ControlPlaneID: "cp-1"}, dir)
+		require.NoError(t, err)
+		require.True(t, committed)
+		commits := gitLog(t, repo)
+		require.Len(t, commits, 2)
+		require.Equal(t, []string{"services.json"}, gitFiles(t, commits[0]))
+	})
+
+	t.Run("verify the output must be within the working tree", func(t *testing.T) {
+		repo := t.TempDir()
+		committer, err := newGitCommitter(config.Git{RepoPath: repo}, zap.NewNop())
+		require.NoError(t, err)
+		require.NoError(t, committer.validate(DumpOptions{}, filepath.Join(repo, "backups", "osiris.json")))
+		require.ErrorIs(t, committer.validate(DumpOptions{}, filepath.Join(t.TempDir(), "osiris.json")),
+			ErrGitIncompatible)
+		require.ErrorIs(t, committer.validate(DumpOptions{OutputSplitSize: 1024}, filepath.Join(repo, "osiris.json")),
+			ErrGitIncompatible)
+	})
+
+	t.Run("verify the configuration is validated", func(t *testing.T) {
+		committer, err := newGitCommitter(config.Git{}, zap.NewNop())
+		require.NoError(t, err)
+		require.Nil(t, committer)
+		_, err = newGitCommitter(config.Git{RepoPath: ".", CommitMessageTemplate: "{{ .Unknown }}"}, zap.NewNop())
+		require.Error(t, err)
+		_, err = newGitCommitter(config.Git{RepoPath: ".", Author: "not an address"}, zap.NewNop())
+		require.Error(t, err)
+	})
+}
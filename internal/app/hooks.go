@@ -0,0 +1,115 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/mikefero/osiris/internal/config"
+	"go.uber.org/zap"
+)
+
+// hookFilePlaceholder is replaced with the output filename in hook commands.
+const hookFilePlaceholder = "{file}"
+
+// hooks executes the external commands configured for writing the output
+// file. Commands are split on whitespace and are not executed by a shell.
+type hooks struct {
+	config config.Hooks
+	logger *zap.Logger
+}
+
+func newHooks(config config.Hooks, logger *zap.Logger) *hooks {
+	return &hooks{
+		config: config,
+		logger: logger,
+	}
+}
+
+// preWrite filters the output using the pre-write command, if any, returning
+// the filtered output. The original output is returned if the command fails
+// and errors are ignored.
+func (h *hooks) preWrite(ctx context.Context, filename string, data []byte) ([]byte, error) {
+	if len(h.config.PreWrite) == 0 {
+		return data, nil
+	}
+	filtered, err := h.run(ctx, "pre-write", h.config.PreWrite, filename, data)
+	if err != nil {
+		if h.config.IgnoreErrors {
+			return data, nil
+		}
+		return nil, err
+	}
+	return filtered, nil
+}
+
+// postWrite executes the post-write command, if any.
+func (h *hooks) postWrite(ctx context.Context, filename string) error {
+	if len(h.config.PostWrite) == 0 {
+		return nil
+	}
+	if _, err := h.run(ctx, "post-write", h.config.PostWrite, filename, nil); err != nil &&
+		!h.config.IgnoreErrors {
+		return err
+	}
+	return nil
+}
+
+func (h *hooks) run(ctx context.Context, name string, command string, filename string,
+	stdin []byte,
+) ([]byte, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, fmt.Errorf("invalid %s hook: empty command", name)
+	}
+	for i, arg := range args {
+		args[i] = strings.ReplaceAll(arg, hookFilePlaceholder, filename)
+	}
+
+	if h.config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.config.Timeout)
+		defer cancel()
+	}
+
+	startTime := time.Now()
+	//nolint: gosec
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		h.logger.Error("error executing hook",
+			zap.String("hook", name),
+			zap.Strings("command", args),
+			zap.String("stderr", stderr.String()),
+			zap.Bool("ignore-errors", h.config.IgnoreErrors),
+			zap.Error(err))
+		return nil, fmt.Errorf("error executing %s hook %q: %w", name, command, err)
+	}
+
+	h.logger.Info("Executed hook",
+		zap.String("hook", name),
+		zap.Strings("command", args),
+		zap.Duration("duration", time.Since(startTime)))
+	return stdout.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHooks(t *testing.T) {
+	results := []resource.ResourceData{
+		{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}}},
+	}
+
+	t.Run("verify post-write hook is executed with the output file", func(t *testing.T) {
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+		hooks := newHooks(config.Hooks{
+			PostWrite: "touch {file}.marker",
+			Timeout:   10 * time.Second,
+		}, zap.NewNop())
+		require.NoError(t, writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), "  "))
+		require.FileExists(t, outputFilename+".marker")
+	})
+
+	t.Run("verify pre-write hook filters the output", func(t *testing.T) {
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+		hooks := newHooks(config.Hooks{
+			PreWrite: "tr s S",
+			Timeout:  10 * time.Second,
+		}, zap.NewNop())
+		require.NoError(t, writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), "  "))
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"Svc-1"`)
+	})
+
+	t.Run("verify failing hook fails the operation", func(t *testing.T) {
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+		hooks := newHooks(config.Hooks{
+			PostWrite: "false",
+			Timeout:   10 * time.Second,
+		}, zap.NewNop())
+		err := writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), "  ")
+		require.ErrorContains(t, err, "post-write")
+	})
+
+	t.Run("verify failing hook is ignored when errors are ignored", func(t *testing.T) {
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+		hooks := newHooks(config.Hooks{
+			PreWrite:     "false",
+			PostWrite:    "false",
+			Timeout:      10 * time.Second,
+			IgnoreErrors: true,
+		}, zap.NewNop())
+		require.NoError(t, writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), "  "))
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"svc-1"`)
+	})
+
+	t.Run("verify hook is terminated after the timeout", func(t *testing.T) {
+		outputFilename := filepath.Join(t.TempDir(), "osiris.json")
+		hooks := newHooks(config.Hooks{
+			PostWrite: "sleep 10",
+			Timeout:   100 * time.Millisecond,
+		}, zap.NewNop())
+		startTime := time.Now()
+		err := writeResults(context.Background(), results, hooks, zap.NewNop(), fileOutput(outputFilename), "  ")
+		require.Error(t, err)
+		require.Less(t, time.Since(startTime), 5*time.Second)
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// jsonFields are the fields of each resource whose values are JSON encoded
+// strings (e.g. a double-encoded config blob). The fields are decoded into
+// nested values in the output for readable dumps and clean diffs, and encoded
+// into strings again when applied.
+type jsonFields struct {
+	fields map[string][][]string
+}
+
+// newJSONFields creates the JSON fields of each resource, keyed by resource
+// name; nested fields are specified using a dot separated path. Nil is
+// returned if no fields are specified and an error is returned if a resource
+// is unknown.
+func newJSONFields(fields map[string][]string, resources []resource.Resource) (*jsonFields, error) {
+	if len(fields) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+	names := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		names[res.Name()] = struct{}{}
+	}
+	paths := make(map[string][][]string, len(fields))
+	for name, resourceFields := range fields {
+		if _, ok := names[name]; !ok {
+			return nil, fmt.Errorf("%w in resource JSON fields: %q", ErrUnknownResource, name)
+		}
+		for _, field := range resourceFields {
+			paths[name] = append(paths[name], strings.Split(field, "."))
+		}
+	}
+	return &jsonFields{fields: paths}, nil
+}
+
+// decode replaces the JSON encoded string of each field of the items with
+// its decoded object or array. Values that are not strings or do not encode
+// an object or array are left as is.
+func (f *jsonFields) decode(name string, items []map[string]interface{}) {
+	if f == nil {
+		return
+	}
+	for _, path := range f.fields[name] {
+		for _, item := range items {
+			updateField(item, path, decodeJSONField)
+		}
+	}
+}
+
+// encode replaces the object or array of each field of the items with its
+// JSON encoded string so that the items are applied as listed. Values that
+// are already strings are left as is.
+func (f *jsonFields) encode(results map[string][]map[string]interface{}) error {
+	if f == nil {
+		return nil
+	}
+	for name, paths := range f.fields {
+		for _, path := range paths {
+			for _, item := range results[name] {
+				var err error
+				updateField(item, path, func(value interface{}) interface{} {
+					encoded, encodeErr := encodeJSONField(value)
+					if encodeErr != nil {
+						err = fmt.Errorf("error encoding %s field %s: %w", name, strings.Join(path, "."), encodeErr)
+						return value
+					}
+					return encoded
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// updateField replaces the value of the field at the path, if present, with
+// the updated value.
+func updateField(item map[string]interface{}, path []string, update func(interface{}) interface{}) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := item[key].(map[string]interface{})
+		if !ok {
+			return
+		}
+		item = nested
+	}
+	key := path[len(path)-1]
+	if value, ok := item[key]; ok {
+		item[key] = update(value)
+	}
+}
+
+func decodeJSONField(value interface{}) interface{} {
+	encoded, ok := value.(string)
+	if !ok {
+		return value
+	}
+	trimmed := strings.TrimSpace(encoded)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return value
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(trimmed)))
+	decoder.UseNumber()
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil || decoder.More() {
+		return value
+	}
+	return decoded
+}
+
+func encodeJSONField(value interface{}) (interface{}, error) {
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestJSONFields(t *testing.T) {
+	resources := []resource.Resource{&fakeResource{name: "plugin", path: "plugins"}}
+
+	t.Run("verify a double-encoded field is expanded in the dump and collapsed for apply", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"plugin-1","config":"{\"limit\":10,\"paths\":[\"/a\"]}"}]}`))
+		}))
+		fields, err := newJSONFields(map[string][]string{"plugin": {"config"}}, resources)
+		require.NoError(t, err)
+
+		results, err := listData(context.Background(), c, resources, listOptions{
+			sanitizer:  &sanitize.Sanitizer{},
+			jsonFields: fields,
+		}, zap.NewNop())
+		require.NoError(t, err)
+		resultMap := toResultMap(results)
+		require.Equal(t, map[string]interface{}{
+			"limit": json.Number("10"),
+			"paths": []interface{}{"/a"},
+		}, resultMap["plugin"][0]["config"])
+
+		require.NoError(t, fields.encode(resultMap))
+		require.JSONEq(t, `{"limit":10,"paths":["/a"]}`, resultMap["plugin"][0]["config"].(string))
+	})
+
+	t.Run("verify the encoded field is written back when applied", func(t *testing.T) {
+		var body map[string]interface{}
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			_ = json.Unmarshal(data, &body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		fields, err := newJSONFields(map[string][]string{"plugin": {"config.payload"}}, resources)
+		require.NoError(t, err)
+		results := map[string][]map[string]interface{}{
+			"plugin": {{"id": "plugin-1", "config": map[string]interface{}{
+				"payload": map[string]interface{}{"enabled": true},
+			}}},
+		}
+		require.NoError(t, fields.encode(results))
+
+		_, err = applyLevels(context.Background(), c, [][]resource.Resource{resources}, results, applyOptions{},
+			zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, `{"enabled":true}`, body["config"].(map[string]interface{})["payload"])
+	})
+
+	t.Run("verify values that are not JSON encoded are left as is", func(t *testing.T) {
+		fields, err := newJSONFields(map[string][]string{"plugin": {"config", "name"}}, resources)
+		require.NoError(t, err)
+		items := []map[string]interface{}{
+			{"id": "plugin-1", "config": "not json", "name": "{broken"},
+			{"id": "plugin-2", "config": json.Number("1")},
+		}
+		fields.decode("plugin", items)
+		require.Equal(t, "not json", items[0]["config"])
+		require.Equal(t, "{broken", items[0]["name"])
+		require.Equal(t, json.Number("1"), items[1]["config"])
+	})
+
+	t.Run("verify unknown resources are rejected", func(t *testing.T) {
+		_, err := newJSONFields(map[string][]string{"plugins": {"config"}}, resources)
+		require.ErrorIs(t, err, ErrUnknownResource)
+	})
+}
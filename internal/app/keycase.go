@@ -0,0 +1,148 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// ErrInvalidKeyCase is returned when the output key case is not a known case.
+var ErrInvalidKeyCase = errors.New("output key case must be none, snake, or camel")
+
+// keyCase is the casing of the item keys in the output.
+type keyCase string
+
+const (
+	// keyCaseNone leaves the item keys as returned by the admin API.
+	keyCaseNone keyCase = "none"
+	// keyCaseSnake converts the item keys to snake_case.
+	keyCaseSnake keyCase = "snake"
+	// keyCaseCamel converts the item keys to camelCase.
+	keyCaseCamel keyCase = "camel"
+)
+
+// parseKeyCase parses the output key case; the keys are left as is if the
+// case is empty.
+func parseKeyCase(value string) (keyCase, error) {
+	switch c := keyCase(strings.ToLower(value)); c {
+	case "":
+		return keyCaseNone, nil
+	case keyCaseNone, keyCaseSnake, keyCaseCamel:
+		return c, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidKeyCase, value)
+	}
+}
+
+// convertKeyCase converts the keys of the items of each resource, including
+// the keys of nested objects, to the key case.
+func convertKeyCase(results []resource.ResourceData, c keyCase) []resource.ResourceData {
+	var convert func(string) string
+	switch c {
+	case keyCaseSnake:
+		convert = toSnakeCase
+	case keyCaseCamel:
+		convert = toCamelCase
+	default:
+		return results
+	}
+
+	converted := make([]resource.ResourceData, 0, len(results))
+	for _, data := range results {
+		items := data.Data
+		if items != nil {
+			items = make([]map[string]interface{}, len(data.Data))
+			for i, item := range data.Data {
+				items[i] = convertKeys(item, convert)
+			}
+		}
+		converted = append(converted, resource.ResourceData{Name: data.Name, Data: items})
+	}
+	return converted
+}
+
+// convertKeys returns a copy of the object with its keys, and the keys of its
+// nested objects, converted.
+func convertKeys(object map[string]interface{}, convert func(string) string) map[string]interface{} {
+	converted := make(map[string]interface{}, len(object))
+	for key, value := range object {
+		converted[convert(key)] = convertValue(value, convert)
+	}
+	return converted
+}
+
+// convertValue converts the keys of the objects nested in the value.
+func convertValue(value interface{}, convert func(string) string) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		return convertKeys(value, convert)
+	case []map[string]interface{}:
+		converted := make([]map[string]interface{}, len(value))
+		for i, object := range value {
+			converted[i] = convertKeys(object, convert)
+		}
+		return converted
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, element := range value {
+			converted[i] = convertValue(element, convert)
+		}
+		return converted
+	default:
+		return value
+	}
+}
+
+// toCamelCase converts a snake_case key to camelCase (e.g. `created_at`
+// becomes `createdAt`).
+func toCamelCase(key string) string {
+	parts := strings.Split(key, "_")
+	var builder strings.Builder
+	builder.WriteString(parts[0])
+	for _, part := range parts[1:] {
+		if len(part) == 0 {
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		builder.WriteString(string(runes))
+	}
+	return builder.String()
+}
+
+// toSnakeCase converts a camelCase key to snake_case (e.g. `createdAt`
+// becomes `created_at` and `tlsHTTPPort` becomes `tls_http_port`).
+func toSnakeCase(key string) string {
+	runes := []rune(key)
+	var builder strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			previousLower := i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]))
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsUpper(runes[i-1]) && unicode.IsLower(runes[i+1])
+			if previousLower || nextLower {
+				builder.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyCase(t *testing.T) {
+	newResults := func() []resource.ResourceData {
+		return []resource.ResourceData{{
+			Name: "service",
+			Data: []map[string]interface{}{{
+				"id":              "svc-1",
+				"connect_timeout": 60000,
+				"client_certificate": map[string]interface{}{
+					"id": "cert-1",
+				},
+				"tags": []interface{}{"team_a"},
+				"plugins": []interface{}{
+					map[string]interface{}{"config": map[string]interface{}{"second_limit": 5}},
+				},
+				"targets": []map[string]interface{}{{"target_weight": 100}},
+			}},
+		}}
+	}
+
+	t.Run("verify snake case keys are converted to camel case recursively", func(t *testing.T) {
+		actual := convertKeyCase(newResults(), keyCaseCamel)
+		require.Equal(t, []resource.ResourceData{{
+			Name: "service",
+			Data: []map[string]interface{}{{
+				"id":             "svc-1",
+				"connectTimeout": 60000,
+				"clientCertificate": map[string]interface{}{
+					"id": "cert-1",
+				},
+				"tags": []interface{}{"team_a"},
+				"plugins": []interface{}{
+					map[string]interface{}{"config": map[string]interface{}{"secondLimit": 5}},
+				},
+				"targets": []map[string]interface{}{{"targetWeight": 100}},
+			}},
+		}}, actual)
+	})
+
+	t.Run("verify camel case keys are converted to snake case", func(t *testing.T) {
+		actual := convertKeyCase(convertKeyCase(newResults(), keyCaseCamel), keyCaseSnake)
+		require.Equal(t, newResults(), actual)
+		require.Equal(t, "tls_http_port", toSnakeCase("tlsHTTPPort"))
+		require.Equal(t, "port_80_enabled", toSnakeCase("port_80_enabled"))
+	})
+
+	t.Run("verify keys are left as is by default", func(t *testing.T) {
+		c, err := parseKeyCase("")
+		require.NoError(t, err)
+		require.Equal(t, newResults(), convertKeyCase(newResults(), c))
+
+		_, err = parseKeyCase("kebab")
+		require.ErrorIs(t, err, ErrInvalidKeyCase)
+	})
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// WriteLevels writes the parallel levels computed from the resource
+// dependency graph, with the overridden dependencies applied, for the
+// insertion (apply) and deletion (reset) orders; the resources of a level are
+// processed concurrently and the levels are processed in order.
+func WriteLevels(w io.Writer, dependencies map[string][]string) error {
+	registry, err := newRegistry(dependencies)
+	if err != nil {
+		return fmt.Errorf("error creating resource registry: %w", err)
+	}
+	insertion, err := registry.GetResourcesForInsertion()
+	if err != nil {
+		return fmt.Errorf("error generating insertion order: %w", err)
+	}
+	deletion, err := registry.GetResourcesForDeletion()
+	if err != nil {
+		return fmt.Errorf("error generating deletion order: %w", err)
+	}
+	return writeLevels(w, insertion, deletion)
+}
+
+// writeLevels writes the levels of the insertion and deletion orders with the
+// index and sorted resource names of each level.
+func writeLevels(w io.Writer, insertion [][]resource.Resource, deletion [][]resource.Resource) error {
+	var buf strings.Builder
+	for _, order := range []struct {
+		title  string
+		levels [][]resource.Resource
+	}{
+		{title: "Insertion order (apply)", levels: insertion},
+		{title: "Deletion order (reset)", levels: deletion},
+	} {
+		fmt.Fprintf(&buf, "%s:\n", order.title)
+		for i, level := range order.levels {
+			names := make([]string, 0, len(level))
+			for _, res := range level {
+				names = append(names, res.Name())
+			}
+			// The order of the resources within a level is not significant
+			sort.Strings(names)
+			fmt.Fprintf(&buf, "  Level %d: %s\n", i+1, strings.Join(names, ", "))
+		}
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
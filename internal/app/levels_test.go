@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteLevels(t *testing.T) {
+	t.Run("verify the levels of a fixture are printed in order", func(t *testing.T) {
+		service := &fakeResource{name: "service"}
+		route := &fakeResource{name: "route"}
+		plugin := &fakeResource{name: "plugin"}
+		upstream := &fakeResource{name: "upstream"}
+
+		var actual bytes.Buffer
+		require.NoError(t, writeLevels(&actual,
+			[][]resource.Resource{{service, upstream}, {route}, {plugin}},
+			[][]resource.Resource{{plugin}, {route}, {service, upstream}},
+		))
+		require.Equal(t, `Insertion order (apply):
+  Level 1: service, upstream
+  Level 2: route
+  Level 3: plugin
+Deletion order (reset):
+  Level 1: plugin
+  Level 2: route
+  Level 3: service, upstream
+`, actual.String())
+	})
+
+	t.Run("verify the printed levels match the registry ordering", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		insertion, err := registry.GetResourcesForInsertion()
+		require.NoError(t, err)
+		deletion, err := registry.GetResourcesForDeletion()
+		require.NoError(t, err)
+
+		var expected strings.Builder
+		expected.WriteString("Insertion order (apply):\n")
+		for i, level := range insertion {
+			fmt.Fprintf(&expected, "  Level %d: %s\n", i+1, strings.Join(resourceNames(level), ", "))
+		}
+		expected.WriteString("Deletion order (reset):\n")
+		for i, level := range deletion {
+			fmt.Fprintf(&expected, "  Level %d: %s\n", i+1, strings.Join(resourceNames(level), ", "))
+		}
+
+		var actual bytes.Buffer
+		require.NoError(t, WriteLevels(&actual, nil))
+		require.Equal(t, expected.String(), actual.String())
+		require.Contains(t, actual.String(), "route")
+	})
+}
+
+func resourceNames(resources []resource.Resource) []string {
+	names := make([]string, 0, len(resources))
+	for _, res := range resources {
+		names = append(names, res.Name())
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+)
+
+// resetOrphans lists the items of all resources, determines the orphaned
+// items, and deletes only those; the report of the reset is generated even if
+// an error occurs.
+func resetOrphans(ctx context.Context, client *client.Client, levels [][]resource.Resource, opts deleteOptions,
+	logger *zap.Logger,
+) (*resetReport, error) {
+	startTime := time.Now()
+	report := &resetReport{
+		Levels:    levelNames(levels),
+		Deletions: make(map[string]int),
+	}
+	var resources []resource.Resource
+	for _, level := range levels {
+		resources = append(resources, level...)
+	}
+
+	skipped := &skippedResources{}
+	err := func() error {
+		results, err := listOrphanCandidates(ctx, client, resources, opts, skipped, logger)
+		if err != nil {
+			return err
+		}
+
+		orphans := findOrphans(resources, results)
+		logger.Info("Deleting orphaned items", zap.Int("count", countItems(orphans)))
+
+		// Delete the orphans in deletion order so that orphaned children are
+		// deleted before their orphaned parents
+		for _, res := range resources {
+			for i, item := range orphans[res.Name()] {
+				err := recovered(res, operationDelete, opts.events, logger, func() error {
+					return res.Delete(withResource(ctx, res), client, item, logger)
+				})
+				if err != nil {
+					logger.Error("error deleting orphaned item",
+						zap.String("resource", res.Name()),
+						zap.String("item", itemID(item)),
+						zap.Error(err))
+					return &operationError{
+						resource:  res.Name(),
+						operation: operationDelete,
+						item:      itemID(item),
+						err: fmt.Errorf("error deleting orphaned item %d/%d for %s: %w",
+							i+1, len(orphans[res.Name()]), res.Name(), err),
+					}
+				}
+				opts.audit.deleted(res.Name(), item)
+				emitDeleted(opts.events, res.Name(), item)
+				report.Deletions[res.Name()]++
+			}
+		}
+		return nil
+	}()
+	report.Errors = newErrorReport(err)
+	report.Skipped = skipped.names()
+	report.Duration = time.Since(startTime).String()
+	report.Timings = opts.timings.sorted()
+	return report, err
+}
+
+// listOrphanCandidates returns the items of each resource, keyed by resource
+// name; the items listed when computing the reset plan are reused if any.
+func listOrphanCandidates(ctx context.Context, client *client.Client, resources []resource.Resource,
+	opts deleteOptions, skipped *skippedResources, logger *zap.Logger,
+) (map[string][]map[string]interface{}, error) {
+	if opts.plan != nil && opts.plan.results != nil {
+		for _, name := range opts.plan.Skipped {
+			skipped.add(name)
+		}
+		return opts.plan.results, nil
+	}
+	results := make(map[string][]map[string]interface{}, len(resources))
+	for _, res := range resources {
+		logger.Debug("Listing resource items", zap.String("resource", res.Name()))
+		recordTiming := opts.timings.begin(client, res)
+		var data resource.ResourceData
+		err := recovered(res, operationList, opts.events, logger, func() error {
+			var err error
+			data, err = listResource(ctx, client, res, opts.retry, logger)
+			return err
+		})
+		recordTiming(len(data.Data))
+		if opts.skipForbidden && isForbidden(err) {
+			// References to the items of a skipped resource are not
+			// considered since its items are unknown
+			logger.Warn("Skipping resource; not authorized to list",
+				zap.String("resource", res.Name()),
+				zap.Error(err))
+			skipped.add(res.Name())
+			continue
+		}
+		if err != nil {
+			return nil, &operationError{
+				resource:  res.Name(),
+				operation: operationList,
+				err:       fmt.Errorf("error listing resource %s: %w", res.Name(), err),
+			}
+		}
+		results[res.Name()] = data.Data
+	}
+	return results, nil
+}
+
+// findOrphans returns the items, keyed by resource name, having a reference
+// to an item of another resource that does not exist (e.g. a target whose
+// upstream was deleted). Items referencing an orphan are orphans as well, so
+// that deleting the orphans does not leave new orphans behind. References to
+// resources that were not listed are not considered.
+func findOrphans(resources []resource.Resource,
+	results map[string][]map[string]interface{},
+) map[string][]map[string]interface{} {
+	ids := make(map[string]map[string]struct{}, len(results))
+	for name, items := range results {
+		ids[name] = make(map[string]struct{}, len(items))
+		for _, item := range items {
+			if id, err := resource.ParseID(item["id"]); err == nil {
+				ids[name][id] = struct{}{}
+			}
+		}
+	}
+
+	orphaned := make(map[string]map[string]bool, len(results))
+	for changed := true; changed; {
+		changed = false
+		for _, res := range resources {
+			referencer, ok := res.(resource.Referencer)
+			if !ok {
+				continue
+			}
+			for _, item := range results[res.Name()] {
+				id, _ := resource.ParseID(item["id"])
+				if orphaned[res.Name()][id] || !isOrphan(item, referencer.References(), ids) {
+					continue
+				}
+				if orphaned[res.Name()] == nil {
+					orphaned[res.Name()] = make(map[string]bool)
+				}
+				orphaned[res.Name()][id] = true
+				delete(ids[res.Name()], id)
+				changed = true
+			}
+		}
+	}
+
+	orphans := make(map[string][]map[string]interface{}, len(orphaned))
+	for _, res := range resources {
+		for _, item := range results[res.Name()] {
+			if id, _ := resource.ParseID(item["id"]); orphaned[res.Name()][id] {
+				orphans[res.Name()] = append(orphans[res.Name()], item)
+			}
+		}
+	}
+	return orphans
+}
+
+// isOrphan returns true if any reference of the item refers to an item that
+// does not exist.
+func isOrphan(item map[string]interface{}, references map[string]string, ids map[string]map[string]struct{}) bool {
+	for field, name := range references {
+		referenced, ok := ids[name]
+		if !ok {
+			continue
+		}
+		id, ok := referencedID(item[field])
+		if !ok {
+			continue
+		}
+		if _, ok := referenced[id]; !ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"net/http"
+	"path"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// fakeReferencingResource is a fake resource whose items reference the items
+// of other resources.
+type fakeReferencingResource struct {
+	fakeResource
+	references map[string]string
+}
+
+func (r *fakeReferencingResource) References() map[string]string { return r.references }
+
+func TestResetOrphans(t *testing.T) {
+	t.Run("verify only orphaned items are deleted", func(t *testing.T) {
+		var mutex sync.Mutex
+		var deleted []string
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			mutex.Lock()
+			deleted = append(deleted, path.Base(r.URL.Path))
+			mutex.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		upstream := &fakeResource{name: "upstream", path: "upstreams", items: []map[string]interface{}{
+			{"id": "ups-1"},
+		}}
+		target := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "target", path: "targets", items: []map[string]interface{}{
+				{"id": "tgt-1", "upstream": map[string]interface{}{"id": "ups-1"}},
+				{"id": "tgt-2", "upstream": map[string]interface{}{"id": "ups-2"}},
+			}},
+			references: map[string]string{"upstream": "upstream"},
+		}
+		consumer := &fakeResource{name: "consumer", path: "consumers", items: []map[string]interface{}{
+			{"id": "csm-1"},
+		}}
+		acl := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "acl", path: "acls", items: []map[string]interface{}{
+				{"id": "acl-1", "consumer": map[string]interface{}{"id": "csm-1"}},
+				{"id": "acl-2", "consumer": map[string]interface{}{"id": "csm-9"}},
+				{"id": "acl-3"},
+			}},
+			references: map[string]string{"consumer": "consumer"},
+		}
+		levels := [][]resource.Resource{{target, acl}, {upstream, consumer}}
+
+		report, err := resetOrphans(context.Background(), client, levels,
+			deleteOptions{audit: newNopAuditLog()}, zap.NewNop())
+		require.NoError(t, err)
+		sort.Strings(deleted)
+		require.Equal(t, []string{"acl-2", "tgt-2"}, deleted)
+		require.Equal(t, map[string]int{"acl": 1, "target": 1}, report.Deletions)
+	})
+
+	t.Run("verify items referencing orphans are orphans", func(t *testing.T) {
+		service := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "service"},
+			references:   map[string]string{"client_certificate": "certificate"},
+		}
+		route := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "route"},
+			references:   map[string]string{"service": "service"},
+		}
+		certificate := &fakeResource{name: "certificate"}
+		results := map[string][]map[string]interface{}{
+			"route": {
+				{"id": "route-1", "service": map[string]interface{}{"id": "svc-1"}},
+				{"id": "route-2", "service": map[string]interface{}{"id": "svc-2"}},
+			},
+			"service": {
+				{"id": "svc-1", "client_certificate": map[string]interface{}{"id": "cert-9"}},
+				{"id": "svc-2"},
+			},
+			"certificate": {},
+		}
+
+		orphans := findOrphans([]resource.Resource{route, service, certificate}, results)
+		require.Equal(t, map[string][]map[string]interface{}{
+			"route":   {results["route"][0]},
+			"service": {results["service"][0]},
+		}, orphans)
+	})
+}
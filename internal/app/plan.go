@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// resetPlan is the reviewable plan of a reset operation.
+type resetPlan struct {
+	// ControlPlaneID is the control plane the plan was computed for.
+	ControlPlaneID string `json:"control_plane_id" yaml:"control_plane_id"`
+	// Levels are the deletion levels in the order they are processed.
+	Levels []planLevel `json:"levels" yaml:"levels"`
+	// Items is the total number of items to delete.
+	Items int `json:"items" yaml:"items"`
+	// Skipped are the names of the resources skipped because the bearer token
+	// is not authorized to list them.
+	Skipped []string `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+
+	// results are the items listed for each resource when computing the plan,
+	// keyed by resource name.
+	results map[string][]map[string]interface{}
+}
+
+// planLevel is a deletion level of the reset plan; the resources of a level
+// are deleted in parallel.
+type planLevel struct {
+	// Resources are the resources of the level.
+	Resources []planResource `json:"resources" yaml:"resources"`
+}
+
+// planResource is a resource of the reset plan.
+type planResource struct {
+	// Name is the name of the resource.
+	Name string `json:"name" yaml:"name"`
+	// Items is the number of items of the resource to delete; the number of
+	// orphaned items when deleting only the orphans.
+	Items int `json:"items" yaml:"items"`
+}
+
+// planReset lists the items of each resource in the deletion order and
+// returns the plan of the reset without deleting any items. When deleting
+// only the orphaned items, the plan counts the orphans of each resource. The
+// listed items are retained by the plan so that they are not listed again.
+func planReset(ctx context.Context, client *client.Client, levels [][]resource.Resource, opts deleteOptions,
+	logger *zap.Logger,
+) (*resetPlan, error) {
+	startTime := time.Now()
+	plan := &resetPlan{
+		Levels:  make([]planLevel, 0, len(levels)),
+		results: make(map[string][]map[string]interface{}),
+	}
+	skipped := &skippedResources{}
+	var listed []resource.Resource
+	for _, level := range levels {
+		for _, res := range level {
+			var resourceData resource.ResourceData
+			err := recovered(res, operationList, opts.events, logger, func() error {
+				var err error
+				resourceData, err = listResource(ctx, client, res, opts.retry, logger)
+				return err
+			})
+			if opts.skipForbidden && isForbidden(err) {
+				logger.Warn("Skipping resource; not authorized to list",
+					zap.String("resource", res.Name()),
+					zap.Error(err))
+				skipped.add(res.Name())
+				continue
+			}
+			if err != nil {
+				return nil, &operationError{
+					resource:  res.Name(),
+					operation: operationList,
+					err:       fmt.Errorf("error listing resource %s: %w", res.Name(), err),
+				}
+			}
+			listed = append(listed, res)
+			plan.results[res.Name()] = resourceData.Data
+		}
+	}
+	plan.Skipped = skipped.names()
+
+	deleted := plan.results
+	if opts.orphansOnly {
+		deleted = findOrphans(listed, plan.results)
+	}
+	for _, level := range levels {
+		resources := make([]planResource, 0, len(level))
+		for _, res := range level {
+			if _, ok := plan.results[res.Name()]; !ok {
+				continue
+			}
+			resources = append(resources, planResource{
+				Name:  res.Name(),
+				Items: len(deleted[res.Name()]),
+			})
+			plan.Items += len(deleted[res.Name()])
+		}
+		plan.Levels = append(plan.Levels, planLevel{Resources: resources})
+	}
+
+	logger.Info("Computed reset plan",
+		zap.Int("levels", len(plan.Levels)),
+		zap.Int("item-count", plan.Items),
+		zap.Bool("orphans-only", opts.orphansOnly),
+		zap.Strings("skipped-resources", plan.Skipped),
+		zap.Duration("duration", time.Since(startTime)))
+	return plan, nil
+}
+
+// writeResetPlan writes the reset plan to the file; the plan is written as
+// YAML if the file has a `.yaml` or `.yml` extension and as JSON otherwise.
+func writeResetPlan(filename string, plan *resetPlan) error {
+	var data []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		data, err = yaml.Marshal(plan)
+	default:
+		data, err = json.MarshalIndent(plan, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("error encoding reset plan: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0o600); err != nil {
+		return fmt.Errorf("error writing reset plan: %w", err)
+	}
+	return nil
+}
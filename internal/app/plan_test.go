@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+func TestResetPlan(t *testing.T) {
+	newLevels := func() [][]resource.Resource {
+		return [][]resource.Resource{
+			{
+				&fakeResource{name: "route", path: "routes", items: newFakeItems(3)},
+				&fakeResource{name: "target", path: "targets", items: newFakeItems(4)},
+			},
+			{&fakeResource{name: "service", path: "services", items: newFakeItems(2)}},
+		}
+	}
+	expected := &resetPlan{
+		Levels: []planLevel{
+			{Resources: []planResource{{Name: "route", Items: 3}, {Name: "target", Items: 4}}},
+			{Resources: []planResource{{Name: "service", Items: 2}}},
+		},
+		Items: 9,
+	}
+
+	t.Run("verify plan contains the deletion order and item counts without deleting", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			require.Failf(t, "unexpected request", "%s %s", r.Method, r.URL.Path)
+		}))
+
+		plan, err := planReset(context.Background(), client, newLevels(), deleteOptions{}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, expected.Levels, plan.Levels)
+		require.Equal(t, expected.Items, plan.Items)
+		require.Len(t, plan.results["target"], 4)
+	})
+
+	t.Run("verify plan file is written as JSON or YAML", func(t *testing.T) {
+		client := newTestClient(t, http.NotFoundHandler())
+		plan, err := planReset(context.Background(), client, newLevels(), deleteOptions{}, zap.NewNop())
+		require.NoError(t, err)
+
+		for _, tc := range []struct {
+			filename  string
+			unmarshal func([]byte, any) error
+		}{
+			{filename: "plan.json", unmarshal: json.Unmarshal},
+			{filename: "plan.yaml", unmarshal: yaml.Unmarshal},
+			{filename: "plan.yml", unmarshal: yaml.Unmarshal},
+		} {
+			filename := filepath.Join(t.TempDir(), tc.filename)
+			require.NoError(t, writeResetPlan(filename, plan))
+			data, err := os.ReadFile(filename)
+			require.NoError(t, err)
+			var actual resetPlan
+			require.NoError(t, tc.unmarshal(data, &actual), tc.filename)
+			require.Equal(t, expected, &actual, tc.filename)
+		}
+	})
+
+	t.Run("verify forbidden resources are skipped when requested", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		levels := newLevels()
+		levels[1] = append(levels[1], &fakeResource{name: "vault", path: "vaults"})
+
+		_, err := planReset(context.Background(), client, levels, deleteOptions{}, zap.NewNop())
+		require.True(t, isForbidden(err))
+
+		plan, err := planReset(context.Background(), client, levels, deleteOptions{skipForbidden: true},
+			zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, 9, plan.Items)
+		require.Equal(t, []string{"vault"}, plan.Skipped)
+	})
+}
@@ -0,0 +1,155 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"go.uber.org/zap"
+)
+
+// tokenFingerprintPrefix is the prefix of the fingerprint of a bearer token.
+const tokenFingerprintPrefix = "sha256:"
+
+// provenance records the source of a dump, written to a sidecar alongside
+// the output, so that a dump is not mistakenly applied to the wrong target.
+// It contains no secret material; the bearer token is only identified by a
+// truncated hash.
+type provenance struct {
+	// ControlPlaneID is the ID of the control plane the dump came from.
+	ControlPlaneID string `json:"control_plane_id"`
+	// Host is the host of the base URL of the admin API.
+	Host string `json:"host"`
+	// GatewayVersion is the detected version of the gateway; empty if the
+	// version was not detected.
+	GatewayVersion string `json:"gateway_version,omitempty"`
+	// TokenFingerprint identifies the bearer token used for the dump without
+	// revealing it; empty if no bearer token was used.
+	TokenFingerprint string `json:"token_fingerprint,omitempty"`
+	// OsirisVersion is the version of osiris that wrote the dump.
+	OsirisVersion string `json:"osiris_version"`
+	// DumpedAt is the time the dump was written.
+	DumpedAt time.Time `json:"dumped_at"`
+}
+
+// newProvenance creates the provenance of a dump of the control plane.
+func newProvenance(config *config.Config, client *client.Client, now time.Time) provenance {
+	p := provenance{
+		ControlPlaneID:   config.ControlPlaneID.String(),
+		Host:             baseURLHost(config.BaseURL),
+		TokenFingerprint: tokenFingerprint(config.BearerToken),
+		OsirisVersion:    Version,
+		DumpedAt:         now.UTC(),
+	}
+	if version, ok := client.Version(); ok {
+		p.GatewayVersion = version.String()
+	}
+	return p
+}
+
+// baseURLHost returns the host of the base URL; the base URL is returned if
+// it cannot be parsed.
+func baseURLHost(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil || len(parsed.Host) == 0 {
+		return baseURL
+	}
+	return parsed.Host
+}
+
+// tokenFingerprint returns a truncated SHA-256 hash identifying the bearer
+// token without revealing it; empty if there is no bearer token.
+func tokenFingerprint(token string) string {
+	if len(token) == 0 {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(token))
+	return tokenFingerprintPrefix + hex.EncodeToString(sum[:8])
+}
+
+// provenanceFilename returns the filename of the provenance sidecar of the
+// output file (e.g. `osiris.json` becomes `osiris.meta.json`); the index of a
+// split output shares the sidecar of the output.
+func provenanceFilename(outputFilename string) string {
+	ext := filepath.Ext(outputFilename)
+	base := strings.TrimSuffix(strings.TrimSuffix(outputFilename, ext), ".index")
+	return base + ".meta" + ext
+}
+
+// writeProvenance writes the provenance sidecar.
+func writeProvenance(filename string, p provenance) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling provenance: %w", err)
+	}
+	if err := os.WriteFile(filename, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing provenance: %w", err)
+	}
+	return nil
+}
+
+// readProvenance reads the provenance sidecar; nil is returned if the
+// sidecar does not exist (e.g. a dump written by an earlier version).
+func readProvenance(filename string) (*provenance, error) {
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil //nolint: nilnil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading provenance: %w", err)
+	}
+	var p provenance
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("error parsing provenance: %w", err)
+	}
+	return &p, nil
+}
+
+// warnProvenance logs a warning if the dump being applied came from a
+// different control plane or host than the target; applying to a different
+// target is allowed (e.g. a migration) but is called out in case it is a
+// mistake.
+func warnProvenance(filename string, config *config.Config, logger *zap.Logger) {
+	p, err := readProvenance(provenanceFilename(filename))
+	if err != nil {
+		logger.Warn("Unable to read provenance of apply file", zap.Error(err))
+		return
+	}
+	if p == nil {
+		return
+	}
+	host := baseURLHost(config.BaseURL)
+	controlPlaneID := config.ControlPlaneID.String()
+	if p.ControlPlaneID == controlPlaneID && p.Host == host {
+		return
+	}
+	logger.Warn("Applying a dump from a different control plane",
+		zap.String("source-control-plane-id", p.ControlPlaneID),
+		zap.String("source-host", p.Host),
+		zap.String("target-control-plane-id", controlPlaneID),
+		zap.String("target-host", host))
+}
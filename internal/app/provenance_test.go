@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestProvenance(t *testing.T) {
+	const token = "kpat_provenance-secret-token"
+
+	t.Run("verify the provenance sidecar is written with the dump", func(t *testing.T) {
+		controlPlaneID := uuid.New()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "Bearer "+token, r.Header.Get("Authorization"))
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Path == "/"+controlPlaneID.String() {
+				_, _ = w.Write([]byte(`{"version":"3.9.1.0-enterprise-edition"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_CONTROL_PLANE_ID", controlPlaneID.String())
+		t.Setenv("OSIRIS_BEARER_TOKEN", token)
+		t.Setenv("OSIRIS_OUTPUT_FILE", filepath.Join(dir, "osiris.json"))
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		viper.Reset()
+		defer viper.Reset()
+
+		startTime := time.Now().UTC()
+		app := NewDump(DumpOptions{})
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+
+		metaFilename := filepath.Join(dir, "osiris.meta.json")
+		data, err := os.ReadFile(metaFilename)
+		require.NoError(t, err)
+		p, err := readProvenance(metaFilename)
+		require.NoError(t, err)
+		require.Equal(t, controlPlaneID.String(), p.ControlPlaneID)
+		require.Equal(t, strings.TrimPrefix(server.URL, "http://"), p.Host)
+		require.Equal(t, "3.9.1.0-enterprise-edition", p.GatewayVersion)
+		require.Regexp(t, "^sha256:[0-9a-f]{16}$", p.TokenFingerprint)
+		require.Equal(t, Version, p.OsirisVersion)
+		require.False(t, p.DumpedAt.Before(startTime.Truncate(time.Second)))
+
+		// No secret material is recorded
+		require.NotContains(t, string(data), token)
+		require.NotContains(t, string(data), "provenance-secret")
+		require.NotContains(t, string(data), server.URL+"/")
+	})
+
+	t.Run("verify the token fingerprint identifies the token", func(t *testing.T) {
+		require.Equal(t, tokenFingerprint(token), tokenFingerprint(token))
+		require.NotEqual(t, tokenFingerprint(token), tokenFingerprint(token+"-other"))
+		require.Empty(t, tokenFingerprint(""))
+	})
+
+	t.Run("verify the sidecar filename of the output", func(t *testing.T) {
+		require.Equal(t, "osiris.meta.json", provenanceFilename("osiris.json"))
+		require.Equal(t, filepath.Join("out", "osiris.meta.json"),
+			provenanceFilename(filepath.Join("out", "osiris.index.json")))
+	})
+
+	t.Run("verify applying to a different control plane is warned", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "osiris.json")
+		source := uuid.New()
+		require.NoError(t, writeProvenance(provenanceFilename(filename), provenance{
+			ControlPlaneID: source.String(),
+			Host:           "us.api.konghq.com",
+		}))
+
+		core, entries := observer.New(zap.WarnLevel)
+		warnProvenance(filename, &config.Config{
+			BaseURL:        "https://us.api.konghq.com/v2/control-planes",
+			ControlPlaneID: source,
+		}, zap.New(core))
+		require.Zero(t, entries.Len())
+
+		warnProvenance(filename, &config.Config{
+			BaseURL:        "https://eu.api.konghq.com/v2/control-planes",
+			ControlPlaneID: uuid.New(),
+		}, zap.New(core))
+		require.Equal(t, 1, entries.FilterMessage("Applying a dump from a different control plane").Len())
+
+		// A dump without a sidecar is not warned
+		warnProvenance(filepath.Join(dir, "other.json"), &config.Config{ControlPlaneID: uuid.New()}, zap.New(core))
+		require.Equal(t, 1, entries.Len())
+	})
+}
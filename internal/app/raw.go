@@ -0,0 +1,62 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/mikefero/osiris/internal/client"
+	"go.uber.org/zap"
+)
+
+// ErrRawOutIncompatible is returned when the raw output is combined with an
+// option it cannot be written alongside.
+var ErrRawOutIncompatible = errors.New("raw output is incompatible with the option")
+
+// validateRawOut returns an error wrapping ErrRawOutIncompatible if the raw
+// output would overwrite the sanitized output or is combined with streaming;
+// the raw output holds all of the unmodified items in memory.
+func validateRawOut(opts DumpOptions, outputFile string) error {
+	if len(opts.RawOut) == 0 {
+		return nil
+	}
+	switch {
+	case opts.Stream:
+		return fmt.Errorf("%w: stream", ErrRawOutIncompatible)
+	case opts.Output == nil && filepath.Clean(opts.RawOut) == filepath.Clean(outputFile):
+		return fmt.Errorf("%w: raw output must not be the output file", ErrRawOutIncompatible)
+	default:
+		return nil
+	}
+}
+
+// newRawRecorder creates the recorder of the unmodified API responses along
+// with the raw output filename for the control plane; nil is returned if no
+// raw output is written.
+func newRawRecorder(opts DumpOptions, logger *zap.Logger) (*client.RawRecorder, string) {
+	if len(opts.RawOut) == 0 {
+		return nil, ""
+	}
+	rawOut := opts.RawOut
+	if opts.ControlPlane != nil {
+		rawOut = opts.ControlPlane.Filename(rawOut)
+	}
+	logger.Warn("Writing unmodified API responses; the raw output contains unsanitized secrets",
+		zap.String("raw-out", rawOut))
+	return client.NewRawRecorder(), rawOut
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRawOut(t *testing.T) {
+	t.Run("verify the raw output retains the fields removed from the output", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"key-1","secret":"s3cr3t","created_at":1700000000}]}`))
+		}))
+		raw := client.NewRawRecorder()
+		c.SetRawRecorder(raw)
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: string(sanitize.StrategyDrop),
+			Fields:   map[string][]string{"key": {"secret"}},
+		})
+		require.NoError(t, err)
+
+		results, err := listData(context.Background(), c, []resource.Resource{
+			&fakeResource{name: "key", path: "keys"},
+		}, listOptions{sanitizer: sanitizer}, zap.NewNop())
+		require.NoError(t, err)
+		item := toResultMap(results)["key"][0]
+		require.NotContains(t, item, "created_at")
+		require.NotContains(t, item, "secret")
+
+		filename := filepath.Join(t.TempDir(), "raw.json")
+		require.NoError(t, raw.WriteFile(filename, "  "))
+		info, err := os.Stat(filename)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		var endpoints map[string][]map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &endpoints))
+		require.Len(t, endpoints["keys"], 1)
+		require.Equal(t, "s3cr3t", endpoints["keys"][0]["secret"])
+		require.Contains(t, endpoints["keys"][0], "created_at")
+	})
+
+	t.Run("verify the raw output replaces an existing file readable by others", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "raw.json")
+		require.NoError(t, os.WriteFile(filename, []byte("{}"), 0o644))
+		require.NoError(t, os.Chmod(filename, 0o644))
+
+		require.NoError(t, client.NewRawRecorder().WriteFile(filename, ""))
+		info, err := os.Stat(filename)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		entries, err := os.ReadDir(filepath.Dir(filename))
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("verify the raw output includes the items resumed from a checkpoint", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			t.Error("unexpected request for a completed endpoint")
+		}))
+		checkpointFile := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+		require.NoError(t, os.WriteFile(checkpointFile,
+			[]byte(`{"endpoint":"keys","items":[{"id":"key-1","secret":"s3cr3t"}],"next":""}`+"\n"), 0o600))
+		checkpoint, err := client.OpenCheckpoint(checkpointFile, true)
+		require.NoError(t, err)
+		defer checkpoint.Close()
+		c.SetCheckpoint(checkpoint)
+		raw := client.NewRawRecorder()
+		c.SetRawRecorder(raw)
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: string(sanitize.StrategyDrop),
+			Fields:   map[string][]string{"key": {"secret"}},
+		})
+		require.NoError(t, err)
+
+		results, err := listData(context.Background(), c, []resource.Resource{
+			&fakeResource{name: "key", path: "keys"},
+		}, listOptions{sanitizer: sanitizer}, zap.NewNop())
+		require.NoError(t, err)
+		require.NotContains(t, toResultMap(results)["key"][0], "secret")
+
+		filename := filepath.Join(t.TempDir(), "raw.json")
+		require.NoError(t, raw.WriteFile(filename, ""))
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		var endpoints map[string][]map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &endpoints))
+		require.Len(t, endpoints["keys"], 1)
+		require.Equal(t, "s3cr3t", endpoints["keys"][0]["secret"])
+	})
+
+	t.Run("verify the raw output is validated", func(t *testing.T) {
+		require.NoError(t, validateRawOut(DumpOptions{}, "osiris.json"))
+		require.NoError(t, validateRawOut(DumpOptions{RawOut: "raw.json"}, "osiris.json"))
+		require.ErrorIs(t, validateRawOut(DumpOptions{RawOut: "./osiris.json"}, "osiris.json"),
+			ErrRawOutIncompatible)
+		require.ErrorIs(t, validateRawOut(DumpOptions{RawOut: "raw.json", Stream: true}, "osiris.json"),
+			ErrRawOutIncompatible)
+	})
+}
@@ -0,0 +1,146 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// ErrOutputVerification is returned when the output file read back after
+// writing does not contain the results that were gathered.
+var ErrOutputVerification = errors.New("output file verification failed")
+
+// ErrVerifyOutputIncompatible is returned when the output verification is
+// combined with an option whose output cannot be read back and compared with
+// the gathered results.
+var ErrVerifyOutputIncompatible = errors.New("output verification is incompatible with the option")
+
+// validateVerifyOutput returns an error wrapping ErrVerifyOutputIncompatible
+// if the output verification is combined with an option that does not write
+// the gathered results as is to the output file.
+func validateVerifyOutput(opts DumpOptions, config *config.Config) error {
+	if !opts.VerifyOutput {
+		return nil
+	}
+	switch {
+	case opts.Stream:
+		return fmt.Errorf("%w: stream", ErrVerifyOutputIncompatible)
+	case opts.Output != nil:
+		return fmt.Errorf("%w: custom output", ErrVerifyOutputIncompatible)
+	case len(opts.OutputTemplate) > 0:
+		return fmt.Errorf("%w: output template", ErrVerifyOutputIncompatible)
+	case opts.OutputSplitSize > 0:
+		return fmt.Errorf("%w: output split size", ErrVerifyOutputIncompatible)
+	case len(opts.OutputDir) > 0:
+		return fmt.Errorf("%w: output directory", ErrVerifyOutputIncompatible)
+	case len(config.Hooks.PreWrite) > 0:
+		return fmt.Errorf("%w: pre-write hook", ErrVerifyOutputIncompatible)
+	default:
+		return nil
+	}
+}
+
+// verifiedOutput returns the output writing the results to a temporary file
+// alongside the output file. Once written, the temporary file is read back
+// and the number of items of each resource is compared with the expected
+// counts; the temporary file replaces the output file only if it is valid so
+// that a previous output is never replaced by a corrupted write.
+func verifiedOutput(filename string, expected map[string]int) output {
+	return output{
+		name: filename,
+		open: func() (io.WriteCloser, error) {
+			file, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+			if err != nil {
+				return nil, fmt.Errorf("unable to create temporary output file: %w", err)
+			}
+			return &verifiedWriter{File: file, filename: filename, expected: expected}, nil
+		},
+	}
+}
+
+// verifiedWriter writes the output to a temporary file which is verified and
+// renamed to the output file when closed.
+type verifiedWriter struct {
+	*os.File
+	filename string
+	expected map[string]int
+}
+
+func (w *verifiedWriter) Close() error {
+	tempFilename := w.Name()
+	err := w.Sync()
+	if closeErr := w.File.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = verifyOutputFile(tempFilename, w.expected)
+	}
+	if err == nil {
+		err = os.Chmod(tempFilename, 0o600)
+	}
+	if err == nil {
+		err = os.Rename(tempFilename, w.filename)
+	}
+	if err != nil {
+		//nolint: errcheck
+		os.Remove(tempFilename)
+		return err
+	}
+	return nil
+}
+
+// verifyOutputFile reads back and parses the output file and returns an
+// error wrapping ErrOutputVerification if the file cannot be parsed or if the
+// number of items of a resource does not match the expected count.
+func verifyOutputFile(filename string, expected map[string]int) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return fmt.Errorf("%w: unable to read %s: %w", ErrOutputVerification, filename, err)
+	}
+	var results map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &results); err != nil {
+		return fmt.Errorf("%w: unable to parse %s: %w", ErrOutputVerification, filename, err)
+	}
+	for name, count := range expected {
+		if len(results[name]) != count {
+			return fmt.Errorf("%w: %s has %d items, expected %d", ErrOutputVerification, name,
+				len(results[name]), count)
+		}
+	}
+	for name, items := range results {
+		if _, ok := expected[name]; !ok && len(items) > 0 {
+			return fmt.Errorf("%w: unexpected resource %s", ErrOutputVerification, name)
+		}
+	}
+	return nil
+}
+
+// resultCounts returns the number of items of each resource of the results.
+func resultCounts(results []resource.ResourceData) map[string]int {
+	counts := make(map[string]int, len(results))
+	for _, result := range results {
+		counts[result.Name] = len(result.Data)
+	}
+	return counts
+}
@@ -0,0 +1,95 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestVerifiedOutput(t *testing.T) {
+	results := []resource.ResourceData{
+		{Name: "service", Data: []map[string]interface{}{{"id": "svc-1"}, {"id": "svc-2"}}},
+		{Name: "route", Data: []map[string]interface{}{{"id": "route-1"}}},
+		{Name: "plugin"},
+	}
+
+	t.Run("verify the output passes verification when written completely", func(t *testing.T) {
+		dir := t.TempDir()
+		outputFilename := filepath.Join(dir, "osiris.json")
+		require.NoError(t, writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()),
+			zap.NewNop(), verifiedOutput(outputFilename, resultCounts(results)), "  "))
+
+		written, err := readResults(outputFilename)
+		require.NoError(t, err)
+		require.Len(t, written["service"], 2)
+		require.Len(t, written["route"], 1)
+		info, err := os.Stat(outputFilename)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("verify a corrupted write fails and keeps the previous output", func(t *testing.T) {
+		dir := t.TempDir()
+		outputFilename := filepath.Join(dir, "osiris.json")
+		require.NoError(t, os.WriteFile(outputFilename, []byte(`{"service":[]}`), 0o600))
+
+		w, err := verifiedOutput(outputFilename, resultCounts(results)).open()
+		require.NoError(t, err)
+		_, err = w.Write([]byte(`{"service":[{"id":"svc-1"},{"id":"sv`))
+		require.NoError(t, err)
+		require.ErrorIs(t, w.Close(), ErrOutputVerification)
+
+		data, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"service":[]}`, string(data))
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("verify a missing item fails verification", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "osiris.json")
+		require.NoError(t, os.WriteFile(filename, []byte(`{"service":[{"id":"svc-1"}],"route":[{"id":"route-1"}]}`),
+			0o600))
+		require.ErrorIs(t, verifyOutputFile(filename, resultCounts(results)), ErrOutputVerification)
+	})
+
+	t.Run("verify output verification rejects incompatible options", func(t *testing.T) {
+		for _, opts := range []DumpOptions{
+			{VerifyOutput: true, Stream: true},
+			{VerifyOutput: true, OutputTemplate: "template.tmpl"},
+			{VerifyOutput: true, OutputSplitSize: 1024},
+			{VerifyOutput: true, OutputDir: "deck"},
+		} {
+			require.ErrorIs(t, validateVerifyOutput(opts, &config.Config{}), ErrVerifyOutputIncompatible)
+		}
+		require.ErrorIs(t, validateVerifyOutput(DumpOptions{VerifyOutput: true}, &config.Config{
+			Hooks: config.Hooks{PreWrite: "jq ."},
+		}), ErrVerifyOutputIncompatible)
+		require.NoError(t, validateVerifyOutput(DumpOptions{VerifyOutput: true}, &config.Config{}))
+	})
+}
@@ -17,12 +17,18 @@ package app
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/mikefero/osiris/internal/client"
 	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
 	"github.com/mikefero/osiris/internal/logger"
 	"github.com/mikefero/osiris/internal/resource"
 	"go.uber.org/fx"
@@ -30,15 +36,65 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrLargeDelete is returned when a reset would delete more items of a
+// resource than the delete confirmation threshold.
+var ErrLargeDelete = errors.New("reset exceeds the delete confirmation threshold")
+
+// licenseResource is the name of the resource of the enterprise licenses.
+const licenseResource = "license"
+
+// ResetOptions contains the options for the reset command.
+type ResetOptions struct {
+	// ReportJSON prints a structured report of the reset on completion.
+	ReportJSON bool
+	// Output is the writer used for the structured report.
+	Output io.Writer
+	// ControlPlane is the control plane to reset when operating on a fleet of
+	// control planes; the configured control plane is used if nil.
+	ControlPlane *config.ControlPlane
+	// Config is the configuration loaded once for the control planes of a
+	// fleet; the configuration is loaded if nil.
+	Config *config.Config
+	// OrphansOnly deletes only the items referencing an item of another
+	// resource that does not exist, leaving the remaining items intact.
+	OrphansOnly bool
+	// SkipForbidden skips the resources the bearer token is not authorized to
+	// list (403) rather than failing the reset.
+	SkipForbidden bool
+	// Events is the destination of the JSONL event stream; `-` writes the
+	// events to stdout and the event stream is disabled if empty.
+	Events string
+	// PlanOut is the file the reset plan is written to before deleting; the
+	// plan is not written if empty.
+	PlanOut string
+	// PlanOnly writes the reset plan without deleting any items.
+	PlanOnly bool
+	// Confirm is the input the operator's confirmation is read from before
+	// deleting; the reset proceeds without confirmation if nil.
+	Confirm io.Reader
+	// Prompt is the writer used for the confirmation prompt.
+	Prompt io.Writer
+	// AllowLargeDelete deletes the resources with more items than the delete
+	// confirmation threshold rather than aborting the reset.
+	AllowLargeDelete bool
+	// KeepLicense leaves the licenses of an enterprise gateway in place
+	// rather than deleting them.
+	KeepLicense bool
+}
+
 // NewReset creates a new fx application for the reset command.
 // It provides the necessary dependencies and registers the reset functionality.
-func NewReset() *fx.App {
+func NewReset(opts ResetOptions) *fx.App {
 	return fx.New(
+		fx.Supply(opts),
 		fx.Provide(
-			config.NewConfig,
+			provideConfig(opts.Config),
 			func(config *config.Config) (*zap.Logger, error) {
 				return logger.NewLogger(config.Logger, logger.LoggerCommandTypeReset)
 			},
+			func(config *config.Config) *auditLog {
+				return newAuditLog(logger.NewAuditLogger(config.Logger, logger.LoggerCommandTypeReset), config)
+			},
 		),
 		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
 			return &fxevent.ZapLogger{Logger: logger}
@@ -47,9 +103,16 @@ func NewReset() *fx.App {
 	)
 }
 
-func registerReset(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
+func registerReset(lc fx.Lifecycle, config *config.Config, opts ResetOptions, audit *auditLog,
+	logger *zap.Logger,
+) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
+			config, logger := forControlPlane(config, opts.ControlPlane, logger)
+			audit.config = config
+			if err := checkReadOnly(config, "reset"); err != nil {
+				return err
+			}
 			logger.Info("starting osiris",
 				zap.String("version", Version),
 				zap.String("commit", Commit),
@@ -57,9 +120,101 @@ func registerReset(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
 				zap.String("go-version", GoVersion),
 				zap.String("build-date", BuildDate),
 			)
+			ctx, cancel := withOperationTimeout(ctx, config)
+			defer cancel()
 			logger.Info("Starting reset operation")
-			client := client.NewClient(config, logger)
-			if err := deleteData(ctx, client, logger); err != nil {
+			startTime := time.Now()
+			events, closeEvents, err := openEvents(opts.Events)
+			if err != nil {
+				logger.Error("error opening event stream", zap.Error(err))
+				return err
+			}
+			//nolint: errcheck
+			defer closeEvents()
+			if err := resolveBaseURL(ctx, config, logger); err != nil {
+				logger.Error("error discovering base URL", zap.Error(err))
+				return err
+			}
+			if err := resolveControlPlaneID(ctx, config, logger); err != nil {
+				logger.Error("error resolving control plane", zap.Error(err))
+				return err
+			}
+			client, err := newClient(config, logger)
+			if err != nil {
+				logger.Error("error creating client", zap.Error(err))
+				return fmt.Errorf("error creating client: %w", err)
+			}
+			defer logRequestStats(client, logger)
+			if err := client.Ping(ctx); err != nil {
+				logger.Error("error executing preflight request", zap.Error(err))
+				return fmt.Errorf("error executing preflight request: %w", err)
+			}
+			client.SetEmitter(events)
+			retry, err := newResourceRetry(config)
+			if err != nil {
+				logger.Error("error creating resource retry", zap.Error(err))
+				return fmt.Errorf("error creating resource retry: %w", err)
+			}
+			deleteOpts := deleteOptions{
+				orphansOnly:     opts.OrphansOnly,
+				audit:           audit,
+				events:          events,
+				retry:           retry,
+				skipForbidden:   opts.SkipForbidden,
+				concurrency:     config.DeleteConcurrency,
+				dependencies:    config.ResourceDependencies,
+				continueOnError: config.ContinueOnError,
+				keepLicense:     opts.KeepLicense,
+				timings:         &resourceTimings{},
+			}
+			defer deleteOpts.timings.log(logger)
+			if !opts.AllowLargeDelete {
+				deleteOpts.threshold = config.DeleteConfirmThreshold
+			}
+			var plan *resetPlan
+			if len(opts.PlanOut) > 0 || opts.Confirm != nil {
+				plan, err = computePlan(ctx, client, config, deleteOpts, logger)
+				if err != nil {
+					logger.Error("error computing reset plan", zap.Error(err))
+					return err
+				}
+			}
+			if len(opts.PlanOut) > 0 {
+				planOut := opts.PlanOut
+				if opts.ControlPlane != nil {
+					planOut = opts.ControlPlane.Filename(planOut)
+				}
+				if err := writeResetPlan(planOut, plan); err != nil {
+					logger.Error("error writing reset plan", zap.Error(err))
+					return err
+				}
+				if opts.PlanOnly {
+					logger.Info("Reset plan written; no items were deleted",
+						zap.String("plan-filename", planOut))
+					return nil
+				}
+			}
+			if opts.Confirm != nil {
+				if err := confirmReset(opts.Prompt, opts.Confirm, config.BaseURL, plan, opts.OrphansOnly); err != nil {
+					logger.Error("reset was not confirmed", zap.Error(err))
+					return err
+				}
+				logger.Info("Reset confirmed by operator")
+			}
+			deleteOpts.plan = plan
+			report, err := deleteData(ctx, client, deleteOpts, logger)
+			deleted := 0
+			if report != nil {
+				deleted = countDeletions(report.Deletions)
+			}
+			emitDone(events, deleted, startTime, err)
+			if opts.ReportJSON && report != nil {
+				if reportErr := writeResetReport(opts.Output, report); reportErr != nil {
+					logger.Error("error writing reset report", zap.Error(reportErr))
+					return fmt.Errorf("error writing reset report: %w", reportErr)
+				}
+			}
+			if err != nil {
 				logger.Error("error executing reset", zap.Error(err))
 				return fmt.Errorf("error deleting data: %w", err)
 			}
@@ -68,6 +223,9 @@ func registerReset(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
 		},
 		OnStop: func(ctx context.Context) error {
 			logger.Info("stopping osiris")
+			if err := audit.sync(); err != nil {
+				logger.Error("failed to sync audit log", zap.Error(err))
+			}
 			if err := logger.Sync(); err != nil {
 				logger.Error("failed to sync logger", zap.Error(err))
 			}
@@ -76,20 +234,269 @@ func registerReset(lc fx.Lifecycle, config *config.Config, logger *zap.Logger) {
 	})
 }
 
-func deleteData(ctx context.Context, client *client.Client, logger *zap.Logger) error {
-	// Get ordered resources for deletion - Leaf items need to be deleted first
-	registry := resource.NewRegistry()
+// resetReport is the structured report of a reset operation.
+type resetReport struct {
+	// Levels are the names of the resources for each deletion level, in the
+	// order the levels are processed.
+	Levels [][]string `json:"levels"`
+	// Deletions is the number of items deleted for each resource.
+	Deletions map[string]int `json:"deletions"`
+	// Errors are the errors that occurred during the reset.
+	Errors []errorReportEntry `json:"errors,omitempty"`
+	// Skipped are the names of the resources skipped because the bearer token
+	// is not authorized to list them.
+	Skipped []string `json:"skipped,omitempty"`
+	// Duration is the duration of the reset.
+	Duration string `json:"duration"`
+	// Timings are the timings of the resources processed, slowest first.
+	Timings []resourceTiming `json:"timings,omitempty"`
+}
+
+// writeResetReport writes the structured reset report as JSON.
+func writeResetReport(w io.Writer, report *resetReport) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("error encoding reset report: %w", err)
+	}
+	return nil
+}
+
+// deleteOptions are the options used when deleting data from resources.
+type deleteOptions struct {
+	// orphansOnly deletes only the orphaned items.
+	orphansOnly bool
+	// audit records each deleted item.
+	audit *auditLog
+	// events is the emitter of the event stream; no events are emitted if
+	// nil.
+	events *event.Emitter
+	// retry is the retry policy for listing an entire resource.
+	retry resourceRetry
+	// skipForbidden skips the resources the bearer token is not authorized
+	// to list rather than failing.
+	skipForbidden bool
+	// concurrency is the maximum number of delete requests in flight across
+	// the resources of a level; the deletes are unbounded if zero.
+	concurrency int
+	// limiter caps the delete requests in flight across the resources of the
+	// level being deleted; the deletes are not limited if nil.
+	limiter *deleteLimiter
+	// threshold is the maximum number of items of a resource deleted without
+	// aborting the reset; the threshold is not checked if zero.
+	threshold int
+	// plan is the reset plan computed before deleting, if any; the plan is
+	// computed when checking the threshold if nil.
+	plan *resetPlan
+	// dependencies are the overridden dependencies of resources used to order
+	// the deletion, keyed by resource name.
+	dependencies map[string][]string
+	// continueOnError continues with the remaining resources of a level and
+	// the subsequent levels when a resource fails; all errors are returned
+	// once every level has been processed.
+	continueOnError bool
+	// keepLicense leaves the licenses in place rather than deleting them.
+	keepLicense bool
+	// timings collects the timing of each resource deleted; no timings are
+	// collected if nil.
+	timings *resourceTimings
+}
+
+// deleteLimiter caps the number of delete requests in flight across the
+// resources of a deletion level, shared as a pool of slots; a delete waits
+// for a free slot when the pool is saturated.
+type deleteLimiter struct {
+	slots chan struct{}
+}
+
+// newDeleteLimiter creates the limiter allowing the number of concurrent
+// deletes; nil is returned if the concurrency is not positive.
+func newDeleteLimiter(concurrency int) *deleteLimiter {
+	if concurrency <= 0 {
+		return nil
+	}
+	return &deleteLimiter{slots: make(chan struct{}, concurrency)}
+}
+
+// acquire waits for a free slot; the context error is returned if the context
+// is done first. A nil limiter acquires a slot immediately.
+func (l *deleteLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees the slot acquired.
+func (l *deleteLimiter) release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+func deleteData(ctx context.Context, client *client.Client, opts deleteOptions, logger *zap.Logger,
+) (*resetReport, error) {
+	levels, err := deletionLevels(opts, logger)
+	if err != nil {
+		return nil, err
+	}
+	levels = supportedLevels(ctx, client, levels, logger)
+	return resetResources(ctx, client, levels, opts, logger)
+}
+
+// resetResources checks the delete confirmation threshold before deleting
+// the items, or only the orphaned items, of the resources of each level.
+func resetResources(ctx context.Context, client *client.Client, levels [][]resource.Resource, opts deleteOptions,
+	logger *zap.Logger,
+) (*resetReport, error) {
+	startTime := time.Now()
+	if err := checkDeleteThreshold(ctx, client, levels, &opts, logger); err != nil {
+		logger.Error("reset exceeds the delete confirmation threshold", zap.Error(err))
+		return &resetReport{
+			Levels:    levelNames(levels),
+			Deletions: map[string]int{},
+			Errors:    newErrorReport(err),
+			Duration:  time.Since(startTime).String(),
+		}, err
+	}
+	if opts.orphansOnly {
+		return resetOrphans(ctx, client, levels, opts, logger)
+	}
+	return resetLevels(ctx, client, levels, opts, logger)
+}
+
+// levelNames returns the names of the resources of each deletion level.
+func levelNames(levels [][]resource.Resource) [][]string {
+	names := make([][]string, 0, len(levels))
+	for _, level := range levels {
+		levelNames := make([]string, 0, len(level))
+		for _, res := range level {
+			levelNames = append(levelNames, res.Name())
+		}
+		names = append(names, levelNames)
+	}
+	return names
+}
+
+// deletionLevels returns the resources ordered for deletion using the
+// overridden dependencies; leaf items need to be deleted first. The license
+// resource is left out when the licenses are kept.
+func deletionLevels(opts deleteOptions, logger *zap.Logger) ([][]resource.Resource, error) {
+	registry, err := newRegistry(opts.dependencies)
+	if err != nil {
+		return nil, fmt.Errorf("error creating resource registry: %w", err)
+	}
 	logger.Debug("Generating resource dependency graph for deletion")
 	levels, err := registry.GetResourcesForDeletion()
 	if err != nil {
-		return fmt.Errorf("error generating deletion order: %w", err)
+		return nil, fmt.Errorf("error generating deletion order: %w", err)
 	}
+	if !opts.keepLicense {
+		return levels, nil
+	}
+	kept := make([][]resource.Resource, 0, len(levels))
+	for _, level := range levels {
+		level = slices.DeleteFunc(slices.Clone(level), func(res resource.Resource) bool {
+			return res.Name() == licenseResource
+		})
+		if len(level) > 0 {
+			kept = append(kept, level)
+		}
+	}
+	return kept, nil
+}
 
+// computePlan computes the reset plan of the configured control plane.
+func computePlan(ctx context.Context, client *client.Client, config *config.Config, opts deleteOptions,
+	logger *zap.Logger,
+) (*resetPlan, error) {
+	levels, err := deletionLevels(opts, logger)
+	if err != nil {
+		return nil, err
+	}
+	levels = supportedLevels(ctx, client, levels, logger)
+	plan, err := planReset(ctx, client, levels, opts, logger)
+	if err != nil {
+		return nil, fmt.Errorf("error computing reset plan: %w", err)
+	}
+	plan.ControlPlaneID = config.ControlPlaneID.String()
+	return plan, nil
+}
+
+// resetLevels deletes the resources of each level and generates the report
+// of the reset; the report is generated even if an error occurs.
+func resetLevels(ctx context.Context, client *client.Client, levels [][]resource.Resource, opts deleteOptions,
+	logger *zap.Logger,
+) (*resetReport, error) {
+	startTime := time.Now()
+	report := &resetReport{
+		Levels: levelNames(levels),
+	}
+	skipped := &skippedResources{}
+	deletions, err := deleteLevels(ctx, client, levels, opts, skipped, logger)
+	report.Deletions = deletions
+	report.Skipped = skipped.names()
+	report.Errors = newErrorReport(err)
+	report.Duration = time.Since(startTime).String()
+	report.Timings = opts.timings.sorted()
+	return report, err
+}
+
+// checkDeleteThreshold returns an error wrapping ErrLargeDelete, naming each
+// resource, if the reset would delete more items of a resource than the
+// threshold; the items are counted using the reset plan before any item is
+// deleted so that pointing at the wrong control plane is caught up front. A
+// computed plan is retained by the options so that its listing is reused.
+func checkDeleteThreshold(ctx context.Context, client *client.Client, levels [][]resource.Resource,
+	opts *deleteOptions, logger *zap.Logger,
+) error {
+	if opts.threshold <= 0 {
+		return nil
+	}
+	if opts.plan == nil {
+		plan, err := planReset(ctx, client, levels, *opts, logger)
+		if err != nil {
+			return fmt.Errorf("error computing reset plan: %w", err)
+		}
+		opts.plan = plan
+	}
+	var exceeded []string
+	for _, level := range opts.plan.Levels {
+		for _, res := range level.Resources {
+			if res.Items > opts.threshold {
+				exceeded = append(exceeded, fmt.Sprintf("%s (%d items)", res.Name, res.Items))
+			}
+		}
+	}
+	if len(exceeded) > 0 {
+		return fmt.Errorf("%w of %d items: %s", ErrLargeDelete, opts.threshold, strings.Join(exceeded, ", "))
+	}
+	return nil
+}
+
+// deleteLevels deletes the resources of each level in sequence and returns
+// the number of items deleted for each resource.
+func deleteLevels(ctx context.Context, client *client.Client, levels [][]resource.Resource, opts deleteOptions,
+	skipped *skippedResources, logger *zap.Logger,
+) (map[string]int, error) {
+	resourceCount := 0
+	for _, level := range levels {
+		resourceCount += len(level)
+	}
 	logger.Info("Deleting data from resources",
 		zap.Int("levels", len(levels)),
-		zap.Int("resource-count", len(registry.GetResources())))
+		zap.Int("resource-count", resourceCount))
 
 	// Process each level in sequence
+	var mutex sync.Mutex
+	deletions := make(map[string]int)
+	var errs []error
 	startTime := time.Now()
 	for levelIdx, level := range levels {
 		levelStartTime := time.Now()
@@ -98,65 +505,25 @@ func deleteData(ctx context.Context, client *client.Client, logger *zap.Logger)
 			zap.Int("levels", len(level)))
 
 		var wg sync.WaitGroup
-		errChan := make(chan error, len(level))
+		levelErrs := newResourceErrors()
 		levelCtx, cancel := context.WithCancel(ctx)
 		defer cancel()
+		levelOpts := opts
+		levelOpts.limiter = newDeleteLimiter(opts.concurrency)
 
 		// Process all resources at this level in parallel
 		for _, res := range level {
 			wg.Add(1)
 			go func(r resource.Resource) {
 				defer wg.Done()
-				resStartTime := time.Now()
-
-				// Get all items for this resource
-				logger.Debug("Listing resource items", zap.String("resource", r.Name()))
-				resourceData, listErr := r.List(levelCtx, client, logger)
-				if listErr != nil {
-					logger.Error("error listing resource",
-						zap.String("resource", r.Name()),
-						zap.Error(listErr))
-					errChan <- fmt.Errorf("error listing resource %s: %w", r.Name(), listErr)
-					return
+				defer recoverResource(r, operationDelete, opts.events, levelErrs.add, logger)
+				deleted, err := deleteResource(levelCtx, client, r, levelOpts, skipped, logger)
+				mutex.Lock()
+				deletions[r.Name()] += deleted
+				mutex.Unlock()
+				if err != nil {
+					levelErrs.add(err)
 				}
-
-				itemCount := len(resourceData.Data)
-				if itemCount == 0 {
-					logger.Debug("No items to delete",
-						zap.String("resource", r.Name()),
-						zap.Duration("duration", time.Since(resStartTime)))
-					return
-				}
-				logger.Info("Deleting resource items",
-					zap.String("resource", r.Name()),
-					zap.Int("count", itemCount))
-
-				// Delete each item for this resource - fail fast on first error
-				for i, item := range resourceData.Data {
-					// Check if the context is done before proceeding with deletion
-					select {
-					case <-levelCtx.Done():
-						return // Context was canceled, stop processing
-					default:
-						// Continue with deletion
-					}
-
-					if deleteErr := r.Delete(levelCtx, client, item, logger); deleteErr != nil {
-						logger.Error("error deleting item",
-							zap.String("resource", r.Name()),
-							zap.Int("item", i+1),
-							zap.Int("total", itemCount),
-							zap.Error(deleteErr))
-						errChan <- fmt.Errorf("error deleting item %d/%d for %s: %w",
-							i+1, itemCount, r.Name(), deleteErr)
-						return
-					}
-				}
-
-				logger.Info("Successfully deleted items from resource",
-					zap.String("resource", r.Name()),
-					zap.Int("count", itemCount),
-					zap.Duration("duration", time.Since(resStartTime)))
 			}(res)
 		}
 
@@ -167,19 +534,61 @@ func deleteData(ctx context.Context, client *client.Client, logger *zap.Logger)
 			close(done)
 		}()
 
+		if opts.continueOnError {
+			// Wait for every resource of the level and collect the errors
+			// before proceeding to the next level
+			select {
+			case <-ctx.Done():
+				// Wait for the canceled resources to return so that the
+				// deletions are no longer written once returned
+				cancel()
+				<-done
+				logger.Warn("Context was canceled while deleting resources",
+					zap.Error(ctx.Err()))
+				return deletions, errors.Join(append(errs, ctx.Err())...)
+			case <-done:
+			}
+			for _, err := range levelErrs.all() {
+				logger.Error("Error occurred during resource deletion; continuing",
+					zap.Int("level", levelIdx+1),
+					zap.Error(err))
+				errs = append(errs, err)
+			}
+			logger.Info("Completed deletion level",
+				zap.Int("level", levelIdx+1),
+				zap.Duration("duration", time.Since(levelStartTime)))
+			continue
+		}
+
 		// Wait for either completion, error, or context cancellation
 		select {
 		case <-ctx.Done():
+			// Wait for the canceled resources to return so that the
+			// deletions are no longer written once returned
+			cancel()
+			<-done
 			logger.Warn("Context was canceled while deleting resources",
 				zap.Error(ctx.Err()))
-			return ctx.Err()
-		case err := <-errChan:
+			return deletions, ctx.Err()
+		case err := <-levelErrs.failed:
+			// Cancel the remaining resources of the level, including those
+			// waiting for a delete slot, and wait for them to return so that
+			// their deletions are counted
+			cancel()
+			<-done
 			logger.Error("Error occurred during resource deletion",
 				zap.Int("level", levelIdx+1),
 				zap.Error(err))
-			return err
+			return deletions, err
 		case <-done:
-			// All goroutines completed successfully
+			// All goroutines completed; an error may be collected without
+			// being selected
+			if failures := levelErrs.all(); len(failures) > 0 {
+				logger.Error("Error occurred during resource deletion",
+					zap.Int("level", levelIdx+1),
+					zap.Error(failures[0]))
+				return deletions, failures[0]
+			}
 		}
 
 		levelDuration := time.Since(levelStartTime)
@@ -189,10 +598,170 @@ func deleteData(ctx context.Context, client *client.Client, logger *zap.Logger)
 	}
 
 	totalDuration := time.Since(startTime)
+	if len(errs) > 0 {
+		logger.Error("Failed to delete all resources",
+			zap.Int("levels", len(levels)),
+			zap.Int("resource-count", resourceCount),
+			zap.Int("errors", len(errs)),
+			zap.Duration("duration", totalDuration))
+		return deletions, errors.Join(errs...)
+	}
 	logger.Info("Successfully deleted all resources",
 		zap.Int("levels", len(levels)),
-		zap.Int("resource-count", len(registry.GetResources())),
+		zap.Int("resource-count", resourceCount),
 		zap.Duration("duration", totalDuration))
 
-	return nil
+	return deletions, nil
+}
+
+// deleteResource lists and deletes all items for a single resource. If the
+// resource implements resource.BulkDeleter the items are deleted using a
+// single bulk operation, falling back to per-item deletion when bulk deletion
+// is not supported. The number of items deleted is returned.
+func deleteResource(ctx context.Context, client *client.Client, r resource.Resource, opts deleteOptions,
+	skipped *skippedResources, logger *zap.Logger,
+) (int, error) {
+	audit, events := opts.audit, opts.events
+	ctx = withResource(ctx, r)
+	resStartTime := time.Now()
+	events.Emit(event.Event{Event: event.ResourceStarted, Resource: r.Name()})
+	itemCount := 0
+	recordTiming := opts.timings.begin(client, r)
+	defer func() {
+		recordTiming(itemCount)
+	}()
+
+	// Get all items for this resource
+	logger.Debug("Listing resource items", zap.String("resource", r.Name()))
+	resourceData, listErr := listResource(ctx, client, r, opts.retry, logger)
+	if opts.skipForbidden && isForbidden(listErr) {
+		logger.Warn("Skipping resource; not authorized to list",
+			zap.String("resource", r.Name()),
+			zap.Error(listErr))
+		skipped.add(r.Name())
+		emitCompleted(events, r.Name(), 0, resStartTime)
+		return 0, nil
+	}
+	if listErr != nil {
+		logger.Error("error listing resource",
+			zap.String("resource", r.Name()),
+			zap.Error(listErr))
+		emitFailed(events, r.Name(), listErr)
+		return 0, &operationError{
+			resource:  r.Name(),
+			operation: operationList,
+			err:       fmt.Errorf("error listing resource %s: %w", r.Name(), listErr),
+		}
+	}
+
+	itemCount = len(resourceData.Data)
+	if itemCount == 0 {
+		logger.Debug("No items to delete",
+			zap.String("resource", r.Name()),
+			zap.Duration("duration", time.Since(resStartTime)))
+		emitCompleted(events, r.Name(), 0, resStartTime)
+		return 0, nil
+	}
+	logger.Info("Deleting resource items",
+		zap.String("resource", r.Name()),
+		zap.Int("count", itemCount))
+
+	// Attempt to delete all items for this resource in a single operation
+	if bulkDeleter, ok := r.(resource.BulkDeleter); ok {
+		if err := opts.limiter.acquire(ctx); err != nil {
+			return 0, nil // Context was canceled, stop processing
+		}
+		err := bulkDeleter.BulkDelete(ctx, client, resourceData.Data, logger)
+		opts.limiter.release()
+		switch {
+		case err == nil:
+			audit.deleted(r.Name(), resourceData.Data...)
+			emitDeleted(events, r.Name(), resourceData.Data...)
+			logger.Info("Successfully bulk deleted items from resource",
+				zap.String("resource", r.Name()),
+				zap.Int("count", itemCount),
+				zap.Duration("duration", time.Since(resStartTime)))
+			emitCompleted(events, r.Name(), itemCount, resStartTime)
+			return itemCount, nil
+		case errors.Is(err, resource.ErrBulkDeleteNotSupported):
+			logger.Debug("Bulk delete not supported; deleting items individually",
+				zap.String("resource", r.Name()))
+		default:
+			logger.Error("error bulk deleting items",
+				zap.String("resource", r.Name()),
+				zap.Int("count", itemCount),
+				zap.Error(err))
+			emitFailed(events, r.Name(), err)
+			return 0, &operationError{
+				resource:  r.Name(),
+				operation: operationDelete,
+				err:       fmt.Errorf("error bulk deleting %d items for %s: %w", itemCount, r.Name(), err),
+			}
+		}
+	}
+
+	// Delete each item for this resource - fail fast on first error
+	for i, item := range resourceData.Data {
+		// Check if the context is done before proceeding with deletion
+		select {
+		case <-ctx.Done():
+			return i, nil // Context was canceled, stop processing
+		default:
+			// Continue with deletion
+		}
+
+		// Wait for a free delete slot of the level; the context is done if the
+		// level failed while waiting
+		if err := opts.limiter.acquire(ctx); err != nil {
+			return i, nil
+		}
+		deleteErr := r.Delete(ctx, client, item, logger)
+		opts.limiter.release()
+		if deleteErr != nil {
+			logger.Error("error deleting item",
+				zap.String("resource", r.Name()),
+				zap.Int("item", i+1),
+				zap.Int("total", itemCount),
+				zap.Error(deleteErr))
+			emitFailed(events, r.Name(), deleteErr)
+			return i, &operationError{
+				resource:  r.Name(),
+				operation: operationDelete,
+				item:      itemID(item),
+				err: fmt.Errorf("error deleting item %d/%d for %s: %w",
+					i+1, itemCount, r.Name(), deleteErr),
+			}
+		}
+		audit.deleted(r.Name(), item)
+		emitDeleted(events, r.Name(), item)
+	}
+
+	logger.Info("Successfully deleted items from resource",
+		zap.String("resource", r.Name()),
+		zap.Int("count", itemCount),
+		zap.Duration("duration", time.Since(resStartTime)))
+	emitCompleted(events, r.Name(), itemCount, resStartTime)
+	return itemCount, nil
+}
+
+// emitDeleted emits an event for each item deleted from the resource.
+func emitDeleted(events *event.Emitter, name string, items ...map[string]interface{}) {
+	for _, item := range items {
+		events.Emit(event.Event{Event: event.ItemDeleted, Resource: name, Item: itemID(item)})
+	}
+}
+
+// countDeletions returns the number of items deleted.
+func countDeletions(deletions map[string]int) int {
+	count := 0
+	for _, deleted := range deletions {
+		count += deleted
+	}
+	return count
+}
+
+// itemID returns the identity of an item for reporting purposes.
+func itemID(item map[string]interface{}) string {
+	id, _ := resource.ItemID(item)
+	return id
 }
@@ -0,0 +1,635 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/backoff"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// fakeResource is a resource whose items are provided by the test; items are
+// listed from the API if none are provided.
+type fakeResource struct {
+	name  string
+	path  string
+	items []map[string]interface{}
+}
+
+func (r *fakeResource) Name() string           { return r.name }
+func (r *fakeResource) Path() string           { return r.path }
+func (r *fakeResource) Dependencies() []string { return nil }
+
+func (r *fakeResource) List(ctx context.Context, client *client.Client, _ *zap.Logger) (resource.ResourceData, error) {
+	if r.items != nil {
+		return resource.ResourceData{Data: r.items, Name: r.name}, nil
+	}
+	data, err := client.GetEndpoint(ctx, r.path)
+	if err != nil {
+		return resource.ResourceData{}, err
+	}
+	return resource.ResourceData{Data: data, Name: r.name}, nil
+}
+
+func (r *fakeResource) Delete(ctx context.Context, client *client.Client, item map[string]interface{},
+	_ *zap.Logger,
+) error {
+	return client.DeleteEndpoint(ctx, fmt.Sprintf("%s/%s", r.path, item["id"]))
+}
+
+// fakeSlowResource is a fake resource whose deletes take the delay to return
+// regardless of the context.
+type fakeSlowResource struct {
+	fakeResource
+	delay time.Duration
+}
+
+func (r *fakeSlowResource) Delete(context.Context, *client.Client, map[string]interface{}, *zap.Logger) error {
+	time.Sleep(r.delay)
+	return nil
+}
+
+// fakeBulkResource is a fake resource that supports bulk deletion.
+type fakeBulkResource struct {
+	fakeResource
+	supported bool
+}
+
+func (r *fakeBulkResource) BulkDelete(ctx context.Context, client *client.Client, _ []map[string]interface{},
+	_ *zap.Logger,
+) error {
+	if !r.supported {
+		return resource.ErrBulkDeleteNotSupported
+	}
+	return client.DeleteEndpoint(ctx, r.path)
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *client.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return client.NewClient(&config.Config{
+		BaseURL:        server.URL,
+		ControlPlaneID: uuid.New(),
+	}, zap.NewNop())
+}
+
+// newTestBackoff creates a backoff waiting a millisecond between attempts so
+// that retried requests do not slow down the tests.
+func newTestBackoff(t *testing.T) *backoff.Backoff {
+	t.Helper()
+	b, err := backoff.New(config.Backoff{Strategy: string(backoff.StrategyConstant), Base: time.Millisecond})
+	require.NoError(t, err)
+	return b
+}
+
+func newNopAuditLog() *auditLog {
+	return newAuditLog(zap.NewNop(), &config.Config{})
+}
+
+func newFakeItems(count int) []map[string]interface{} {
+	items := make([]map[string]interface{}, count)
+	for i := range items {
+		items[i] = map[string]interface{}{"id": fmt.Sprintf("item-%d", i)}
+	}
+	return items
+}
+
+func TestDeleteLevels(t *testing.T) {
+	t.Run("verify bulk delete replaces per-item deletes", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			requests.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		res := &fakeBulkResource{
+			fakeResource: fakeResource{name: "fake", path: "fakes", items: newFakeItems(25)},
+			supported:    true,
+		}
+		_, err := deleteLevels(context.Background(), client, [][]resource.Resource{{res}},
+			deleteOptions{audit: newNopAuditLog()}, &skippedResources{}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("verify concurrent deletes across a level never exceed the cap", func(t *testing.T) {
+		var inFlight, maxInFlight, requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			current := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				maximum := maxInFlight.Load()
+				if current <= maximum || maxInFlight.CompareAndSwap(maximum, current) {
+					break
+				}
+			}
+			requests.Add(1)
+			time.Sleep(2 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		level := make([]resource.Resource, 0, 6)
+		for i := range 6 {
+			level = append(level, &fakeResource{
+				name:  fmt.Sprintf("fake-%d", i),
+				path:  fmt.Sprintf("fakes-%d", i),
+				items: newFakeItems(5),
+			})
+		}
+		deletions, err := deleteLevels(context.Background(), client, [][]resource.Resource{level},
+			deleteOptions{audit: newNopAuditLog(), concurrency: 2}, &skippedResources{}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, 30, countDeletions(deletions))
+		require.Equal(t, int32(30), requests.Load())
+		require.LessOrEqual(t, maxInFlight.Load(), int32(2))
+	})
+
+	t.Run("verify a failed delete cancels the resources waiting for a slot", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests.Add(1)
+			if strings.Contains(r.URL.Path, "/failing/") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			time.Sleep(2 * time.Millisecond)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		level := []resource.Resource{
+			&fakeResource{name: "failing", path: "failing", items: newFakeItems(1)},
+		}
+		for i := range 4 {
+			level = append(level, &fakeResource{
+				name:  fmt.Sprintf("fake-%d", i),
+				path:  fmt.Sprintf("fakes-%d", i),
+				items: newFakeItems(50),
+			})
+		}
+		_, err := deleteLevels(context.Background(), client, [][]resource.Resource{level},
+			deleteOptions{audit: newNopAuditLog(), concurrency: 1}, &skippedResources{}, zap.NewNop())
+		require.Error(t, err)
+		require.Less(t, requests.Load(), int32(200))
+	})
+
+	t.Run("verify continue on error runs the later levels and reports all errors", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "/failing-") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		levels := [][]resource.Resource{
+			{
+				&fakeResource{name: "failing-1", path: "failing-1", items: newFakeItems(1)},
+				&fakeResource{name: "first", path: "first", items: newFakeItems(3)},
+			},
+			{
+				&fakeResource{name: "failing-2", path: "failing-2", items: newFakeItems(1)},
+			},
+			{
+				&fakeResource{name: "last", path: "last", items: newFakeItems(2)},
+			},
+		}
+		deletions, err := deleteLevels(context.Background(), client, levels,
+			deleteOptions{audit: newNopAuditLog(), continueOnError: true}, &skippedResources{}, zap.NewNop())
+		require.Error(t, err)
+		require.Equal(t, 3, deletions["first"])
+		require.Equal(t, 2, deletions["last"])
+
+		report := newErrorReport(err)
+		require.Len(t, report, 2)
+		resources := []string{report[0].Resource, report[1].Resource}
+		require.ElementsMatch(t, []string{"failing-1", "failing-2"}, resources)
+	})
+
+	t.Run("verify per-item deletes are used when bulk delete is not supported", func(t *testing.T) {
+		var requests atomic.Int32
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		res := &fakeBulkResource{
+			fakeResource: fakeResource{name: "fake", path: "fakes", items: newFakeItems(25)},
+		}
+		_, err := deleteLevels(context.Background(), client, [][]resource.Resource{{res}},
+			deleteOptions{audit: newNopAuditLog()}, &skippedResources{}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, int32(25), requests.Load())
+	})
+
+	t.Run("verify canceling a level waits for its resources before the report is encoded", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		for _, continueOnError := range []bool{false, true} {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			level := []resource.Resource{&fakeSlowResource{
+				fakeResource: fakeResource{name: "slow", path: "slow", items: newFakeItems(3)},
+				delay:        50 * time.Millisecond,
+			}}
+			deletions, err := deleteLevels(ctx, client, [][]resource.Resource{level},
+				deleteOptions{audit: newNopAuditLog(), continueOnError: continueOnError},
+				&skippedResources{}, zap.NewNop())
+			cancel()
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+
+			// The deletions are no longer written once returned
+			encoded, err := json.Marshal(resetReport{Deletions: deletions})
+			require.NoError(t, err)
+			time.Sleep(100 * time.Millisecond)
+			again, err := json.Marshal(resetReport{Deletions: deletions})
+			require.NoError(t, err)
+			require.JSONEq(t, string(encoded), string(again))
+			require.Equal(t, 1, deletions["slow"])
+		}
+	})
+
+	t.Run("verify forbidden resources are skipped only when requested", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodGet {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		levels := [][]resource.Resource{{
+			&fakeResource{name: "service", path: "services", items: newFakeItems(2)},
+			&fakeResource{name: "vault", path: "vaults"},
+		}}
+
+		report, err := resetLevels(context.Background(), client, levels,
+			deleteOptions{audit: newNopAuditLog(), skipForbidden: true}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"service": 2, "vault": 0}, report.Deletions)
+		require.Equal(t, []string{"vault"}, report.Skipped)
+
+		report, err = resetLevels(context.Background(), client, levels,
+			deleteOptions{audit: newNopAuditLog()}, zap.NewNop())
+		require.True(t, isForbidden(err))
+		require.Empty(t, report.Skipped)
+	})
+
+	t.Run("verify events are emitted as items are deleted", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		var buf bytes.Buffer
+		res := &fakeResource{name: "service", path: "services", items: newFakeItems(2)}
+		_, err := deleteLevels(context.Background(), client, [][]resource.Resource{{res}},
+			deleteOptions{audit: newNopAuditLog(), events: event.NewEmitter(&buf)}, &skippedResources{}, zap.NewNop())
+		require.NoError(t, err)
+
+		events := parseEvents(t, &buf)
+		require.Len(t, events, 4)
+		require.Equal(t, event.ResourceStarted, events[0].Event)
+		require.Equal(t, "service", events[0].Resource)
+		require.Equal(t, event.ItemDeleted, events[1].Event)
+		require.Equal(t, event.ItemDeleted, events[2].Event)
+		require.Equal(t, []string{"item-0", "item-1"}, []string{events[1].Item, events[2].Item})
+		require.Equal(t, event.ResourceCompleted, events[3].Event)
+		require.Equal(t, 2, events[3].Count)
+	})
+}
+
+// parseEvents parses the JSONL event stream.
+func parseEvents(t *testing.T, r io.Reader) []event.Event {
+	t.Helper()
+	var events []event.Event
+	decoder := json.NewDecoder(r)
+	for decoder.More() {
+		var e event.Event
+		require.NoError(t, decoder.Decode(&e))
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestResetReport(t *testing.T) {
+	t.Run("verify report contains levels, deletions, and errors", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/services/item-1") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		client.SetBackoff(newTestBackoff(t))
+
+		levels := [][]resource.Resource{
+			{&fakeResource{name: "route", path: "routes", items: newFakeItems(3)}},
+			{&fakeResource{name: "service", path: "services", items: newFakeItems(2)}},
+		}
+		report, err := resetLevels(context.Background(), client, levels, deleteOptions{audit: newNopAuditLog()},
+			zap.NewNop())
+		require.Error(t, err)
+
+		var stdout bytes.Buffer
+		require.NoError(t, writeResetReport(&stdout, report))
+		var actual resetReport
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &actual))
+		require.Equal(t, [][]string{{"route"}, {"service"}}, actual.Levels)
+		require.Equal(t, map[string]int{"route": 3, "service": 1}, actual.Deletions)
+		require.Len(t, actual.Errors, 1)
+		require.Equal(t, "service", actual.Errors[0].Resource)
+		require.Equal(t, "item-1", actual.Errors[0].Item)
+		require.Equal(t, http.StatusInternalServerError, actual.Errors[0].Status)
+		require.NotEmpty(t, actual.Duration)
+	})
+}
+
+func TestSupportedLevels(t *testing.T) {
+	for version, expected := range map[string][]string{
+		"3.9.1":    {"partial", "license"},
+		"3.10.0.0": nil,
+	} {
+		t.Run("verify resources not supported by gateway version "+version+" are not reset", func(t *testing.T) {
+			client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = fmt.Fprintf(w, `{"version":%q}`, version)
+			}))
+			registry, err := resource.NewRegistry()
+			require.NoError(t, err)
+			levels, err := registry.GetResourcesForDeletion()
+			require.NoError(t, err)
+
+			supported := supportedLevels(context.Background(), client, levels, zap.NewNop())
+			var all, names []string
+			for _, level := range levels {
+				for _, res := range level {
+					all = append(all, res.Name())
+				}
+			}
+			for _, level := range supported {
+				require.NotEmpty(t, level)
+				for _, res := range level {
+					names = append(names, res.Name())
+				}
+			}
+			require.Len(t, names, len(all)-len(expected))
+			for _, name := range expected {
+				require.NotContains(t, names, name)
+			}
+		})
+	}
+}
+
+func TestDeleteThreshold(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodDelete, r.Method)
+		requests.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	levels := [][]resource.Resource{
+		{&fakeResource{name: "route", path: "routes", items: newFakeItems(3)}},
+		{&fakeResource{name: "service", path: "services", items: newFakeItems(12)}},
+	}
+
+	t.Run("verify a reset exceeding the threshold is aborted before any item is deleted", func(t *testing.T) {
+		requests.Store(0)
+		report, err := resetResources(context.Background(), client, levels, deleteOptions{
+			audit:     newNopAuditLog(),
+			threshold: 10,
+		}, zap.NewNop())
+		require.ErrorIs(t, err, ErrLargeDelete)
+		require.ErrorContains(t, err, "service (12 items)")
+		require.NotContains(t, err.Error(), "route")
+		require.Zero(t, requests.Load())
+		require.Empty(t, report.Deletions)
+		require.Len(t, report.Errors, 1)
+	})
+
+	t.Run("verify the override bypasses the threshold", func(t *testing.T) {
+		var deletes atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodDelete {
+				deletes.Add(1)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/services") && deletes.Load() == 0 {
+				_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"},{"id":"svc-2"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		t.Setenv("OSIRIS_BASE_URL", server.URL)
+		t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+		t.Setenv("OSIRIS_LOGGER_AUDIT_FILENAME", filepath.Join(dir, "osiris-audit.log"))
+		t.Setenv("OSIRIS_DELETE_CONFIRM_THRESHOLD", "1")
+		viper.Reset()
+		defer viper.Reset()
+
+		var output bytes.Buffer
+		app := NewReset(ResetOptions{Output: &output})
+		require.ErrorIs(t, app.Start(context.Background()), ErrLargeDelete)
+		require.NoError(t, app.Stop(context.Background()))
+		require.Zero(t, deletes.Load())
+
+		app = NewReset(ResetOptions{Output: &output, AllowLargeDelete: true})
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		require.Equal(t, int32(2), deletes.Load())
+	})
+
+	t.Run("verify the precomputed plan is used to check the threshold", func(t *testing.T) {
+		requests.Store(0)
+		_, err := resetResources(context.Background(), client, levels, deleteOptions{
+			audit:     newNopAuditLog(),
+			threshold: 10,
+			plan: &resetPlan{Levels: []planLevel{
+				{Resources: []planResource{{Name: "route", Items: 3}}},
+				{Resources: []planResource{{Name: "service", Items: 5}}},
+			}},
+		}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, int32(15), requests.Load())
+	})
+
+	t.Run("verify an orphans-only reset exceeding the threshold is aborted", func(t *testing.T) {
+		requests.Store(0)
+		upstream := &fakeResource{name: "upstream", path: "upstreams", items: []map[string]interface{}{}}
+		target := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "target", path: "targets", items: newFakeItems(12)},
+			references:   map[string]string{"upstream": "upstream"},
+		}
+		for _, item := range target.items {
+			item["upstream"] = map[string]interface{}{"id": "ups-1"}
+		}
+		_, err := resetResources(context.Background(), client, [][]resource.Resource{{target}, {upstream}},
+			deleteOptions{
+				audit:       newNopAuditLog(),
+				orphansOnly: true,
+				threshold:   10,
+			}, zap.NewNop())
+		require.ErrorIs(t, err, ErrLargeDelete)
+		require.ErrorContains(t, err, "target (12 items)")
+		require.Zero(t, requests.Load())
+	})
+
+	t.Run("verify the orphans counted by the plan are checked against the threshold", func(t *testing.T) {
+		requests.Store(0)
+		upstream := &fakeResource{name: "upstream", path: "upstreams", items: []map[string]interface{}{
+			{"id": "ups-1"},
+		}}
+		target := &fakeReferencingResource{
+			fakeResource: fakeResource{name: "target", path: "targets", items: newFakeItems(12)},
+			references:   map[string]string{"upstream": "upstream"},
+		}
+		for i, item := range target.items {
+			item["upstream"] = map[string]interface{}{"id": "ups-1"}
+			if i == 0 {
+				item["upstream"] = map[string]interface{}{"id": "ups-2"}
+			}
+		}
+		report, err := resetResources(context.Background(), client, [][]resource.Resource{{target}, {upstream}},
+			deleteOptions{
+				audit:       newNopAuditLog(),
+				orphansOnly: true,
+				threshold:   10,
+			}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"target": 1}, report.Deletions)
+		require.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	t.Run("verify an audit entry is produced per deleted item", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		core, entries := observer.New(zap.InfoLevel)
+		controlPlaneID := uuid.New()
+		audit := newAuditLog(zap.New(core), &config.Config{
+			ControlPlaneID: controlPlaneID,
+			Operator:       "auditor",
+		})
+		levels := [][]resource.Resource{
+			{&fakeResource{name: "route", path: "routes", items: newFakeItems(2)}},
+			{&fakeBulkResource{
+				fakeResource: fakeResource{name: "service", path: "services", items: newFakeItems(3)},
+				supported:    true,
+			}},
+		}
+		_, err := deleteLevels(context.Background(), client, levels, deleteOptions{audit: audit}, &skippedResources{},
+			zap.NewNop())
+		require.NoError(t, err)
+
+		require.Equal(t, 5, entries.Len())
+		var items []string
+		for _, entry := range entries.All() {
+			fields := entry.ContextMap()
+			require.Equal(t, controlPlaneID.String(), fields["control-plane-id"])
+			require.Equal(t, "auditor", fields["operator"])
+			items = append(items, fmt.Sprintf("%s/%s", fields["resource"], fields["item"]))
+		}
+		require.ElementsMatch(t, []string{
+			"route/item-0", "route/item-1",
+			"service/item-0", "service/item-1", "service/item-2",
+		}, items)
+	})
+}
+
+func TestReadOnly(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Setenv("OSIRIS_BASE_URL", server.URL)
+	t.Setenv("OSIRIS_OUTPUT_FILE", filepath.Join(dir, "osiris.json"))
+	t.Setenv("OSIRIS_LOGGER_FILENAME", filepath.Join(dir, "osiris.log"))
+	t.Setenv("OSIRIS_READONLY", "1")
+
+	t.Run("verify reset is refused before any request is made", func(t *testing.T) {
+		viper.Reset()
+		defer viper.Reset()
+		requests.Store(0)
+
+		var output bytes.Buffer
+		app := NewReset(ResetOptions{Output: &output})
+		err := app.Start(context.Background())
+		require.ErrorIs(t, err, ErrReadOnly)
+		require.NoError(t, app.Stop(context.Background()))
+		require.Zero(t, requests.Load())
+	})
+
+	t.Run("verify apply is refused before any request is made", func(t *testing.T) {
+		viper.Reset()
+		defer viper.Reset()
+		requests.Store(0)
+
+		filename := filepath.Join(dir, "apply.json")
+		require.NoError(t, os.WriteFile(filename, []byte(`{"service": [{"id": "svc-1"}]}`), 0o600))
+		var output bytes.Buffer
+		app := NewApply(ApplyOptions{File: filename, Output: &output})
+		err := app.Start(context.Background())
+		require.ErrorIs(t, err, ErrReadOnly)
+		require.NoError(t, app.Stop(context.Background()))
+		require.Zero(t, requests.Load())
+	})
+
+	t.Run("verify dump succeeds", func(t *testing.T) {
+		viper.Reset()
+		defer viper.Reset()
+
+		app := NewDump(DumpOptions{})
+		require.NoError(t, app.Start(context.Background()))
+		require.NoError(t, app.Stop(context.Background()))
+		require.NotZero(t, requests.Load())
+		require.FileExists(t, filepath.Join(dir, "osiris.json"))
+	})
+}
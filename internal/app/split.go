@@ -0,0 +1,260 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidSplitSize is returned when the output split size is negative.
+var ErrInvalidSplitSize = errors.New("output split size must not be negative")
+
+// ErrSplitOutputOpener is returned when splitting the output is combined with
+// an output opener; only the output file can be split.
+var ErrSplitOutputOpener = errors.New("output split size requires the output file")
+
+// ErrSplitMismatch is returned when the reassembled parts of a split output
+// do not match its index (e.g. a part is missing or was modified).
+var ErrSplitMismatch = errors.New("split output parts do not match the index")
+
+// splitIndexVersion is the version of the index of a split output; it also
+// identifies a file being applied as an index rather than a dump.
+const splitIndexVersion = 1
+
+// maxSplitIndexBytes is the size above which a file is not considered an
+// index of a split output; this avoids decoding a large dump twice.
+const maxSplitIndexBytes = 1024 * 1024
+
+// splitIndex describes the parts of a split output and how to reassemble
+// them; concatenating the parts in order reproduces the output exactly.
+type splitIndex struct {
+	// Split is the version of the index.
+	Split int `json:"osiris_split"`
+	// Filename is the name of the reassembled output.
+	Filename string `json:"filename"`
+	// Bytes is the size of the reassembled output.
+	Bytes int64 `json:"bytes"`
+	// SHA256 is the hex encoded SHA-256 digest of the reassembled output.
+	SHA256 string `json:"sha256"`
+	// Parts are the parts in order, relative to the directory of the index.
+	Parts []splitPart `json:"parts"`
+	// Reassemble is the command reassembling the output from the parts.
+	Reassemble string `json:"reassemble"`
+}
+
+// splitPart describes a single part of a split output.
+type splitPart struct {
+	File   string `json:"file"`
+	Bytes  int64  `json:"bytes"`
+	SHA256 string `json:"sha256"`
+}
+
+// validateSplit returns an error if the output split size is invalid or the
+// output cannot be split.
+func validateSplit(opts DumpOptions) error {
+	switch {
+	case opts.OutputSplitSize < 0:
+		return ErrInvalidSplitSize
+	case opts.OutputSplitSize > 0 && opts.Output != nil:
+		return ErrSplitOutputOpener
+	default:
+		return nil
+	}
+}
+
+// splitFilenames returns the name of the index of the split output file and
+// the function naming each part (starting at one); the output osiris.json is
+// split into osiris.part001.json, osiris.part002.json, etc. and indexed by
+// osiris.index.json.
+func splitFilenames(filename string) (string, func(part int) string) {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filename, ext)
+	return base + ".index" + ext, func(part int) string {
+		return fmt.Sprintf("%s.part%03d%s", base, part, ext)
+	}
+}
+
+// splitOutput returns the output writing the results to numbered parts of
+// the output file of at most size bytes each, along with an index of the
+// parts. The output is identified by the index in logs and hooks.
+func splitOutput(filename string, size int64) output {
+	indexFilename, _ := splitFilenames(filename)
+	return output{
+		name: indexFilename,
+		open: func() (io.WriteCloser, error) {
+			return newSplitWriter(filename, size), nil
+		},
+	}
+}
+
+// splitWriter writes the output to numbered parts, starting a new part once
+// the current part reaches the split size, and writes the index of the parts
+// when closed.
+type splitWriter struct {
+	indexFilename string
+	partFilename  func(part int) string
+	size          int64
+	index         splitIndex
+	hash          hash.Hash
+	part          *os.File
+	partHash      hash.Hash
+	partBytes     int64
+}
+
+// newSplitWriter creates the split writer for the output file.
+func newSplitWriter(filename string, size int64) *splitWriter {
+	indexFilename, partFilename := splitFilenames(filename)
+	return &splitWriter{
+		indexFilename: indexFilename,
+		partFilename:  partFilename,
+		size:          size,
+		index: splitIndex{
+			Split:    splitIndexVersion,
+			Filename: filepath.Base(filename),
+		},
+		hash: sha256.New(),
+	}
+}
+
+// Write writes the data across as many parts as required.
+func (s *splitWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if s.part == nil || s.partBytes >= s.size {
+			if err := s.nextPart(); err != nil {
+				return written, err
+			}
+		}
+		chunk := p[:min(int64(len(p)), s.size-s.partBytes)]
+		n, err := s.part.Write(chunk)
+		s.hash.Write(chunk[:n])
+		s.partHash.Write(chunk[:n])
+		s.partBytes += int64(n)
+		written += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// nextPart closes the current part and creates the next part.
+func (s *splitWriter) nextPart() error {
+	if err := s.closePart(); err != nil {
+		return err
+	}
+	filename := s.partFilename(len(s.index.Parts) + 1)
+	part, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	s.part = part
+	s.partHash = sha256.New()
+	s.partBytes = 0
+	s.index.Parts = append(s.index.Parts, splitPart{File: filepath.Base(filename)})
+	return nil
+}
+
+// closePart closes the current part, recording its size and digest.
+func (s *splitWriter) closePart() error {
+	if s.part == nil {
+		return nil
+	}
+	part := &s.index.Parts[len(s.index.Parts)-1]
+	part.Bytes = s.partBytes
+	part.SHA256 = hex.EncodeToString(s.partHash.Sum(nil))
+	s.index.Bytes += s.partBytes
+	err := s.part.Close()
+	s.part = nil
+	return err
+}
+
+// Close closes the last part and writes the index; a single empty part is
+// written if no data was written.
+func (s *splitWriter) Close() error {
+	if len(s.index.Parts) == 0 {
+		if err := s.nextPart(); err != nil {
+			return err
+		}
+	}
+	if err := s.closePart(); err != nil {
+		return err
+	}
+	s.index.SHA256 = hex.EncodeToString(s.hash.Sum(nil))
+	files := make([]string, 0, len(s.index.Parts))
+	for _, part := range s.index.Parts {
+		files = append(files, part.File)
+	}
+	s.index.Reassemble = fmt.Sprintf("cat %s > %s", strings.Join(files, " "), s.index.Filename)
+
+	data, err := json.MarshalIndent(s.index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling split index: %w", err)
+	}
+	if err := os.WriteFile(s.indexFilename, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("error writing split index: %w", err)
+	}
+	return nil
+}
+
+// parseSplitIndex returns the index if the data is an index of a split
+// output.
+func parseSplitIndex(data []byte) (*splitIndex, bool) {
+	if len(data) > maxSplitIndexBytes {
+		return nil, false
+	}
+	var index splitIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, false
+	}
+	if index.Split != splitIndexVersion || len(index.Parts) == 0 {
+		return nil, false
+	}
+	return &index, true
+}
+
+// reassembleSplit concatenates the parts of the split output, relative to
+// the directory of the index, verifying the size and digest of each part and
+// of the reassembled output.
+func reassembleSplit(dir string, index *splitIndex) ([]byte, error) {
+	data := make([]byte, 0, index.Bytes)
+	for _, part := range index.Parts {
+		partData, err := os.ReadFile(filepath.Join(dir, part.File))
+		if err != nil {
+			return nil, fmt.Errorf("error reading split output part: %w", err)
+		}
+		digest := sha256.Sum256(partData)
+		if int64(len(partData)) != part.Bytes || hex.EncodeToString(digest[:]) != part.SHA256 {
+			return nil, fmt.Errorf("%w: %s", ErrSplitMismatch, part.File)
+		}
+		data = append(data, partData...)
+	}
+	digest := sha256.Sum256(data)
+	if int64(len(data)) != index.Bytes || hex.EncodeToString(digest[:]) != index.SHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrSplitMismatch, index.Filename)
+	}
+	return data, nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestSplitOutput(t *testing.T) {
+	results := []resource.ResourceData{
+		{Name: "service", Data: newFakeItems(200)},
+		{Name: "route", Data: newFakeItems(100)},
+	}
+	writeSplit := func(t *testing.T, size int64) (string, string) {
+		t.Helper()
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "osiris.json")
+		require.NoError(t, writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()),
+			zap.NewNop(), fileOutput(filename), "  "))
+		split := filepath.Join(dir, "split", "osiris.json")
+		require.NoError(t, os.Mkdir(filepath.Dir(split), 0o700))
+		require.NoError(t, writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()),
+			zap.NewNop(), splitOutput(split, size), "  "))
+		return filename, filepath.Join(dir, "split", "osiris.index.json")
+	}
+
+	t.Run("verify output larger than the split size reassembles to the original", func(t *testing.T) {
+		const size = 1024
+		filename, indexFilename := writeSplit(t, size)
+		original, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		require.Greater(t, len(original), 3*size)
+
+		data, err := os.ReadFile(indexFilename)
+		require.NoError(t, err)
+		index, ok := parseSplitIndex(data)
+		require.True(t, ok)
+		require.Len(t, index.Parts, (len(original)+size-1)/size)
+		require.Equal(t, "osiris.part001.json", index.Parts[0].File)
+		require.Equal(t, "osiris.json", index.Filename)
+		require.Equal(t, int64(len(original)), index.Bytes)
+
+		var reassembled bytes.Buffer
+		for i, part := range index.Parts {
+			partData, err := os.ReadFile(filepath.Join(filepath.Dir(indexFilename), part.File))
+			require.NoError(t, err)
+			require.LessOrEqual(t, len(partData), size, part.File)
+			if i < len(index.Parts)-1 {
+				require.Len(t, partData, size, part.File)
+			}
+			reassembled.Write(partData)
+		}
+		require.Equal(t, original, reassembled.Bytes())
+
+		// Applying the index reads the same results as the original output
+		expected, err := readApplyFile(filename)
+		require.NoError(t, err)
+		actual, err := readApplyFile(indexFilename)
+		require.NoError(t, err)
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("verify output smaller than the split size is written to a single part", func(t *testing.T) {
+		filename, indexFilename := writeSplit(t, 1024*1024)
+		original, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		data, err := os.ReadFile(indexFilename)
+		require.NoError(t, err)
+		var index splitIndex
+		require.NoError(t, json.Unmarshal(data, &index))
+		require.Len(t, index.Parts, 1)
+		part, err := os.ReadFile(filepath.Join(filepath.Dir(indexFilename), index.Parts[0].File))
+		require.NoError(t, err)
+		require.Equal(t, original, part)
+		require.Equal(t, "cat osiris.part001.json > osiris.json", index.Reassemble)
+	})
+
+	t.Run("verify a modified or missing part is rejected when applied", func(t *testing.T) {
+		_, indexFilename := writeSplit(t, 1024)
+		part := filepath.Join(filepath.Dir(indexFilename), "osiris.part002.json")
+		data, err := os.ReadFile(part)
+		require.NoError(t, err)
+		data[0] ^= 0xff
+		require.NoError(t, os.WriteFile(part, data, 0o600))
+		_, err = readApplyFile(indexFilename)
+		require.ErrorIs(t, err, ErrSplitMismatch)
+
+		require.NoError(t, os.Remove(part))
+		_, err = readApplyFile(indexFilename)
+		require.ErrorContains(t, err, "error reading split output part")
+	})
+
+	t.Run("verify invalid split options are rejected", func(t *testing.T) {
+		require.ErrorIs(t, validateSplit(DumpOptions{OutputSplitSize: -1}), ErrInvalidSplitSize)
+		require.ErrorIs(t, validateSplit(DumpOptions{
+			OutputSplitSize: 1024,
+			Output:          func() (io.WriteCloser, error) { return nil, nil },
+		}), ErrSplitOutputOpener)
+		require.NoError(t, validateSplit(DumpOptions{OutputSplitSize: 1024}))
+	})
+}
@@ -0,0 +1,424 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+)
+
+// ErrStreamIncompatible is returned when streaming a dump is combined with an
+// option that requires all of the items to be held in memory.
+var ErrStreamIncompatible = errors.New("option is incompatible with streaming")
+
+// validateStream returns an error wrapping ErrStreamIncompatible if an option
+// requiring all of the items to be held in memory is enabled.
+func validateStream(opts DumpOptions, config *config.Config) error {
+	switch {
+	case len(opts.SinceFile) > 0:
+		return fmt.Errorf("%w: since file", ErrStreamIncompatible)
+	case opts.NestTargets:
+		return fmt.Errorf("%w: nest targets", ErrStreamIncompatible)
+	case len(opts.OutputTemplate) > 0:
+		return fmt.Errorf("%w: output template", ErrStreamIncompatible)
+	case len(config.Hooks.PreWrite) > 0:
+		return fmt.Errorf("%w: pre-write hook", ErrStreamIncompatible)
+	default:
+		return nil
+	}
+}
+
+// streamDump opens the output and streams the results of the resources to it;
+// the number of items written is returned.
+func streamDump(ctx context.Context, client *client.Client, resources []resource.Resource, opts listOptions,
+	keyCase keyCase, out output, indent string, logger *zap.Logger,
+) (int, error) {
+	w, err := out.open()
+	if err != nil {
+		logger.Error("error opening output",
+			zap.String("output-filename", out.name),
+			zap.Error(err))
+		return 0, fmt.Errorf("error opening output: %w", err)
+	}
+	count, err := streamData(ctx, client, resources, opts, keyCase, newStreamWriter(w, indent), logger)
+	if closeErr := w.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("error closing output: %w", closeErr)
+	}
+	return count, err
+}
+
+// streamWriter writes the results to the output incrementally as the same
+// JSON document written for a dump that is not streamed; resources are
+// written one at a time and the items of each page are written as they are
+// listed. The bytes written are counted for each resource.
+type streamWriter struct {
+	w      *bufio.Writer
+	indent string
+	// resources is the number of resources written.
+	resources int
+	// items is the number of items written for the current resource.
+	items int
+	// bytes is the number of bytes written for the current resource.
+	bytes int64
+}
+
+// newStreamWriter creates the stream writer indenting the output using the
+// indent string (compact if empty).
+func newStreamWriter(w io.Writer, indent string) *streamWriter {
+	return &streamWriter{
+		w:      bufio.NewWriter(w),
+		indent: indent,
+	}
+}
+
+// write writes the data, counting the bytes written for the current resource.
+func (s *streamWriter) write(data ...string) error {
+	for _, d := range data {
+		n, err := s.w.WriteString(d)
+		s.bytes += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newline returns the separator starting a new line at the indent depth; the
+// separator is empty if the output is compact.
+func (s *streamWriter) newline(depth int) string {
+	if len(s.indent) == 0 {
+		return ""
+	}
+	return "\n" + strings.Repeat(s.indent, depth)
+}
+
+// begin writes the start of the document.
+func (s *streamWriter) begin() error {
+	return s.write("{")
+}
+
+// startResource writes the key of the resource; the value of the resource
+// must be written next.
+func (s *streamWriter) startResource(name string) error {
+	s.items = 0
+	s.bytes = 0
+	key, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	separator := ":"
+	if len(s.indent) > 0 {
+		separator = ": "
+	}
+	if s.resources > 0 {
+		if err := s.write(","); err != nil {
+			return err
+		}
+	}
+	s.resources++
+	return s.write(s.newline(1), string(key), separator)
+}
+
+// beginItems writes the start of the items of the current resource.
+func (s *streamWriter) beginItems() error {
+	return s.write("[")
+}
+
+// writeItems writes the items of a page of the current resource and flushes
+// the output so that the page is not retained.
+func (s *streamWriter) writeItems(items []map[string]interface{}) error {
+	prefix := strings.Repeat(s.indent, 2)
+	for _, item := range items {
+		var data []byte
+		var err error
+		if len(s.indent) > 0 {
+			data, err = json.MarshalIndent(item, prefix, s.indent)
+		} else {
+			data, err = json.Marshal(item)
+		}
+		if err != nil {
+			return fmt.Errorf("error marshaling item: %w", err)
+		}
+		if s.items > 0 {
+			if err := s.write(","); err != nil {
+				return err
+			}
+		}
+		if err := s.write(s.newline(2), string(data)); err != nil {
+			return err
+		}
+		s.items++
+	}
+	return s.w.Flush()
+}
+
+// endItems writes the end of the items of the current resource.
+func (s *streamWriter) endItems() error {
+	if s.items == 0 {
+		return s.write("]")
+	}
+	return s.write(s.newline(1), "]")
+}
+
+// writeEmpty writes the value of a resource without items according to the
+// empty resource policy.
+func (s *streamWriter) writeEmpty(name string, policy emptyResourcePolicy) error {
+	empty, ok := policy.data()
+	if !ok {
+		return nil
+	}
+	if err := s.startResource(name); err != nil {
+		return err
+	}
+	if empty == nil {
+		return s.write("null")
+	}
+	return s.write("[]")
+}
+
+// end writes the end of the document and flushes the output.
+func (s *streamWriter) end() error {
+	if s.resources > 0 {
+		if err := s.write(s.newline(0)); err != nil {
+			return err
+		}
+	}
+	if err := s.write("}"); err != nil {
+		return err
+	}
+	return s.w.Flush()
+}
+
+// streamData lists the items of the resources in name order and writes each
+// page to the stream writer as it is listed so that only a single page is
+// held in memory; the number of items written is returned. Resources that do
+// not implement resource.Streamer are listed in full before being written.
+// The items are validated, stripped, sanitized, and converted to the key case
+// as when listing the data; the resource retry policy is only applied to a
+// resource until its first page is written since the pages already written
+// cannot be retracted.
+func streamData(ctx context.Context, client *client.Client, resources []resource.Resource, opts listOptions,
+	keyCase keyCase, w *streamWriter, logger *zap.Logger,
+) (int, error) {
+	resources = append([]resource.Resource(nil), resources...)
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].Name() < resources[j].Name()
+	})
+
+	logger.Info("Streaming data from resources",
+		zap.Int("resource-count", len(resources)))
+
+	startTime := time.Now()
+	if err := w.begin(); err != nil {
+		return 0, fmt.Errorf("error writing results: %w", err)
+	}
+	count := 0
+	var errs []error
+	for _, res := range resources {
+		items, err := streamResource(ctx, client, res, opts, keyCase, w, logger)
+		count += items
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil || !opts.continueOnError {
+			return count, err
+		}
+		errs = append(errs, err)
+	}
+	if err := w.end(); err != nil {
+		return count, fmt.Errorf("error writing results: %w", err)
+	}
+	if len(errs) > 0 {
+		logger.Error("Errors occurred while streaming data from resources",
+			zap.Int("error-count", len(errs)),
+			zap.Int("resource-count", len(resources)),
+			zap.Duration("duration", time.Since(startTime)))
+		return count, errors.Join(errs...)
+	}
+
+	logger.Info("Successfully streamed data from resources",
+		zap.Int("resource-count", len(resources)),
+		zap.Int("item-count", count),
+		zap.Duration("duration", time.Since(startTime)))
+	return count, nil
+}
+
+// streamResource lists the items of a resource page by page, writing each
+// page to the stream writer, and returns the number of items written. The
+// items of a resource that fails after some pages were written are retained
+// so that the document remains valid.
+func streamResource(ctx context.Context, client *client.Client, res resource.Resource, opts listOptions,
+	keyCase keyCase, w *streamWriter, logger *zap.Logger,
+) (int, error) {
+	startTime := time.Now()
+	opts.events.Emit(event.Event{Event: event.ResourceStarted, Resource: res.Name()})
+	recordTiming := opts.timings.begin(client, res)
+
+	started := false
+	write := func(items []map[string]interface{}) error {
+		if err := validateItems(res, items, opts.strict, logger); err != nil {
+			return &operationError{
+				resource:  res.Name(),
+				operation: operationValidate,
+				err:       fmt.Errorf("error validating resource %s: %w", res.Name(), err),
+			}
+		}
+		opts.jsonFields.decode(res.Name(), items)
+		items = opts.filters.Filter(res.Name(), items)
+		if opts.defaults != nil {
+			opts.defaults.Strip(res.Name(), items)
+		}
+		if err := sanitizeItems(opts.stripper, opts.sanitizer, res.Name(), items); err != nil {
+			return &operationError{
+				resource:  res.Name(),
+				operation: operationSanitize,
+				err:       fmt.Errorf("error sanitizing resource %s: %w", res.Name(), err),
+			}
+		}
+		opts.transforms.Transform(res.Name(), items)
+		items = convertKeyCase([]resource.ResourceData{{Name: res.Name(), Data: items}}, keyCase)[0].Data
+		if !started {
+			if err := w.startResource(res.Name()); err != nil {
+				return err
+			}
+			if err := w.beginItems(); err != nil {
+				return err
+			}
+			started = true
+		}
+		return w.writeItems(items)
+	}
+
+	// A panic is recovered as an error so that the items already written are
+	// closed; the failure of the resource is emitted below
+	ctx = withResource(ctx, res)
+	list := func() error {
+		return recovered(res, operationList, nil, logger, func() error {
+			if streamer, ok := res.(resource.Streamer); ok {
+				return streamer.Stream(ctx, client, write, logger)
+			}
+			data, err := res.List(ctx, client, logger)
+			if (err == nil || isPartialPages(err)) && len(data.Data) > 0 {
+				if writeErr := write(data.Data); writeErr != nil {
+					return writeErr
+				}
+			}
+			return err
+		})
+	}
+	err := list()
+
+	// The listing is retried according to the retry policy only while no page
+	// of the resource is written; the pages already written cannot be
+	// retracted
+	for attempt := 1; !started && attempt <= opts.retry.retries && retryableStream(ctx, err); attempt++ {
+		wait := opts.retry.wait(attempt)
+		logger.Warn("Streaming resource failed; retrying",
+			zap.String("resource", res.Name()),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry-after", wait),
+			zap.Error(err))
+		if !sleep(ctx, wait) {
+			break
+		}
+		err = list()
+	}
+	for attempt := 1; !started && err == nil && attempt <= opts.retry.onEmpty; attempt++ {
+		wait := opts.retry.wait(attempt)
+		logger.Info("Streaming resource returned no items; retrying",
+			zap.String("resource", res.Name()),
+			zap.Int("attempt", attempt),
+			zap.Duration("retry-after", wait))
+		if !sleep(ctx, wait) {
+			break
+		}
+		err = list()
+	}
+
+	items := w.items
+	if !started {
+		items = 0
+	}
+	recordTiming(items)
+	switch {
+	case err == nil:
+	case opts.skipForbidden && isForbidden(err) && !started:
+		logger.Warn("Skipping resource; not authorized to list",
+			zap.String("resource", res.Name()),
+			zap.Error(err))
+		opts.skipped.add(res.Name())
+		emitCompleted(opts.events, res.Name(), 0, startTime)
+		return 0, nil
+	default:
+		logger.Error("error streaming resource",
+			zap.String("resource", res.Name()),
+			zap.Error(err))
+		var errOperation *operationError
+		if !errors.As(err, &errOperation) {
+			err = &operationError{
+				resource:  res.Name(),
+				operation: operationList,
+				err:       fmt.Errorf("error listing resource %s: %w", res.Name(), err),
+			}
+		}
+		emitFailed(opts.events, res.Name(), err)
+	}
+
+	// Close the items already written so that the document remains valid
+	if started {
+		if endErr := w.endItems(); endErr != nil {
+			return items, fmt.Errorf("error writing results: %w", endErr)
+		}
+	} else if err == nil {
+		logger.Debug("No data found for resource",
+			zap.String("resource", res.Name()),
+			zap.String("empty-resource-policy", string(opts.emptyPolicy)))
+		if endErr := w.writeEmpty(res.Name(), opts.emptyPolicy); endErr != nil {
+			return 0, fmt.Errorf("error writing results: %w", endErr)
+		}
+	}
+	if err != nil {
+		return items, err
+	}
+
+	logger.Info("Streamed resource",
+		zap.String("resource", res.Name()),
+		zap.Int("items", items),
+		zap.Int64("bytes", w.bytes))
+	emitCompleted(opts.events, res.Name(), items, startTime)
+	return items, nil
+}
+
+// retryableStream returns true if the listing of a resource failed with an
+// error that is retried; the listing is not retried if the context is done,
+// the bearer token is not authorized, or the items failed to be processed.
+func retryableStream(ctx context.Context, err error) bool {
+	var errOperation *operationError
+	return err != nil && ctx.Err() == nil && !isForbidden(err) && !errors.As(err, &errOperation)
+}
@@ -0,0 +1,285 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// syncBuffer is a buffer that is safe to read while it is being written.
+type syncBuffer struct {
+	mutex sync.Mutex
+	buf   bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Close() error { return nil }
+
+func (b *syncBuffer) String() string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buf.String()
+}
+
+// countingWriter discards the data written, counting the bytes.
+type countingWriter struct {
+	bytes int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.bytes += int64(len(p))
+	return len(p), nil
+}
+
+// heapInuse returns the bytes of the heap in use after a garbage collection.
+func heapInuse() uint64 {
+	runtime.GC()
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.HeapInuse
+}
+
+func TestStreamData(t *testing.T) {
+	t.Run("verify the streamed output matches the written results", func(t *testing.T) {
+		resources := []resource.Resource{
+			&fakeResource{name: "service", path: "services", items: newFakeItems(3)},
+			&fakeResource{name: "route", path: "routes", items: newFakeItems(2)},
+			&fakeResource{name: "plugin", path: "plugins", items: []map[string]interface{}{}},
+		}
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}))
+
+		for _, policy := range []emptyResourcePolicy{emptyResourceOmit, emptyResourceEmptyArray, emptyResourceNull} {
+			for _, indent := range []string{"", "  ", "\t"} {
+				name := fmt.Sprintf("%s/%q", policy, indent)
+				opts := listOptions{
+					sanitizer:   &sanitize.Sanitizer{},
+					emptyPolicy: policy,
+				}
+				results, err := listData(context.Background(), client, resources, opts, zap.NewNop())
+				require.NoError(t, err, name)
+				expected := filepath.Join(t.TempDir(), "expected.json")
+				require.NoError(t, writeResults(context.Background(), results, newHooks(config.Hooks{}, zap.NewNop()),
+					zap.NewNop(), fileOutput(expected), indent), name)
+				expectedData, err := os.ReadFile(expected)
+				require.NoError(t, err, name)
+
+				var actual bytes.Buffer
+				count, err := streamData(context.Background(), client, resources, opts, keyCaseNone,
+					newStreamWriter(&actual, indent), zap.NewNop())
+				require.NoError(t, err, name)
+				require.Equal(t, 5, count, name)
+				require.Equal(t, string(expectedData), actual.String(), name)
+			}
+		}
+	})
+
+	t.Run("verify each page is written before the next page is listed", func(t *testing.T) {
+		const pages = 10
+		const pageSize = 50
+		var output syncBuffer
+		var writtenBeforeRequest []int
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasSuffix(r.URL.Path, "/services") {
+				_, _ = w.Write([]byte(`{"data":[]}`))
+				return
+			}
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			writtenBeforeRequest = append(writtenBeforeRequest, strings.Count(output.String(), `"id"`))
+			items := make([]map[string]interface{}, pageSize)
+			for i := range items {
+				items[i] = map[string]interface{}{"id": fmt.Sprintf("service-%d-%d", page, i)}
+			}
+			next := ""
+			if page < pages-1 {
+				next = fmt.Sprintf("/services?page=%d", page+1)
+			}
+			data, _ := json.Marshal(map[string]interface{}{"data": items, "next": next})
+			_, _ = w.Write(data)
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		service, ok := registry.GetResource("service")
+		require.True(t, ok)
+
+		count, err := streamData(context.Background(), client, []resource.Resource{service}, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, keyCaseNone, newStreamWriter(&output, "  "), zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, pages*pageSize, count)
+
+		// At most a single page is held in memory; the items of each page are
+		// written before the next page is requested
+		require.Len(t, writtenBeforeRequest, pages)
+		for page, written := range writtenBeforeRequest {
+			require.Equal(t, page*pageSize, written, "page %d", page)
+		}
+
+		var actual map[string][]map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(output.String()), &actual))
+		require.Len(t, actual["service"], pages*pageSize)
+		for page := range pages {
+			for i := range pageSize {
+				require.Equal(t, fmt.Sprintf("service-%d-%d", page, i), actual["service"][page*pageSize+i]["id"])
+			}
+		}
+	})
+
+	t.Run("verify options requiring all items in memory are rejected", func(t *testing.T) {
+		require.ErrorIs(t, validateStream(DumpOptions{Stream: true, SinceFile: "prior.json"}, &config.Config{}),
+			ErrStreamIncompatible)
+		require.ErrorIs(t, validateStream(DumpOptions{Stream: true, NestTargets: true}, &config.Config{}),
+			ErrStreamIncompatible)
+		require.ErrorIs(t, validateStream(DumpOptions{Stream: true}, &config.Config{
+			Hooks: config.Hooks{PreWrite: "jq ."},
+		}), ErrStreamIncompatible)
+		require.NoError(t, validateStream(DumpOptions{Stream: true}, &config.Config{}))
+	})
+
+	t.Run("verify partial pages fail the stream unless continuing on error", func(t *testing.T) {
+		c := newTestClient(t, http.NotFoundHandler())
+		resources := []resource.Resource{
+			&fakePartialResource{fakeResource{name: "service", path: "services", items: newFakeItems(2)}},
+		}
+
+		var output bytes.Buffer
+		count, err := streamData(context.Background(), c, resources, listOptions{sanitizer: &sanitize.Sanitizer{}},
+			keyCaseNone, newStreamWriter(&output, ""), zap.NewNop())
+		require.ErrorIs(t, err, client.ErrPartialPages)
+		require.Equal(t, 2, count)
+	})
+
+	t.Run("verify peak memory does not grow with the number of pages", func(t *testing.T) {
+		const pages = 100
+		const pageSize = 250
+		padding := strings.Repeat("x", 1024)
+		var peak uint64
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasSuffix(r.URL.Path, "/services") {
+				_, _ = w.Write([]byte(`{"data":[]}`))
+				return
+			}
+			peak = max(peak, heapInuse())
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			items := make([]map[string]interface{}, pageSize)
+			for i := range items {
+				items[i] = map[string]interface{}{"id": fmt.Sprintf("service-%d-%d", page, i), "tags": []string{padding}}
+			}
+			next := ""
+			if page < pages-1 {
+				next = fmt.Sprintf("/services?page=%d", page+1)
+			}
+			data, _ := json.Marshal(map[string]interface{}{"data": items, "next": next})
+			_, _ = w.Write(data)
+		}))
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		service, ok := registry.GetResource("service")
+		require.True(t, ok)
+
+		output := &countingWriter{}
+		baseline := heapInuse()
+		count, err := streamData(context.Background(), client, []resource.Resource{service}, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, keyCaseNone, newStreamWriter(output, ""), zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, pages*pageSize, count)
+
+		// The pages already written are not retained; the heap in use while
+		// listing is bounded by a few pages rather than growing with the
+		// (more than 25MB of) items written
+		require.Greater(t, output.bytes, int64(pages*pageSize*len(padding)))
+		require.Less(t, peak-min(peak, baseline), uint64(output.bytes/10))
+	})
+
+	t.Run("verify resource listing is retried until the first page is written", func(t *testing.T) {
+		var requests atomic.Int32
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}]}`))
+		}))
+		resources := []resource.Resource{&fakeResource{name: "service", path: "services"}}
+
+		var output bytes.Buffer
+		count, err := streamData(context.Background(), c, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+			retry:     resourceRetry{retries: 2},
+		}, keyCaseNone, newStreamWriter(&output, ""), zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, 1, count)
+		require.Equal(t, int32(2), requests.Load())
+		require.JSONEq(t, `{"service":[{"id":"svc-1"}]}`, output.String())
+
+		requests.Store(0)
+		output.Reset()
+		_, err = streamData(context.Background(), c, resources, listOptions{
+			sanitizer: &sanitize.Sanitizer{},
+		}, keyCaseNone, newStreamWriter(&output, ""), zap.NewNop())
+		require.Error(t, err)
+		require.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("verify a resource is not retried after its first page is written", func(t *testing.T) {
+		c := newTestClient(t, http.NotFoundHandler())
+		resources := []resource.Resource{
+			&fakePartialResource{fakeResource{name: "service", path: "services", items: newFakeItems(2)}},
+		}
+
+		var output bytes.Buffer
+		count, err := streamData(context.Background(), c, resources, listOptions{
+			sanitizer:       &sanitize.Sanitizer{},
+			continueOnError: true,
+			retry:           resourceRetry{retries: 2},
+		}, keyCaseNone, newStreamWriter(&output, ""), zap.NewNop())
+		require.ErrorIs(t, err, client.ErrPartialPages)
+		require.Equal(t, 2, count)
+		var actual map[string][]map[string]interface{}
+		require.NoError(t, json.Unmarshal(output.Bytes(), &actual))
+		require.Len(t, actual["service"], 2)
+	})
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// templateFuncs are the helper functions available to an output template in
+// addition to the text/template built-in functions (e.g. `len` and `index`).
+var templateFuncs = template.FuncMap{
+	// toJSON encodes the value as compact JSON.
+	"toJSON": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	// toPrettyJSON encodes the value as JSON indented with two spaces.
+	"toPrettyJSON": func(v interface{}) (string, error) {
+		data, err := json.MarshalIndent(v, "", "  ")
+		return string(data), err
+	},
+	// keys returns the sorted keys of an item or of the results.
+	"keys": func(v interface{}) []string {
+		var keys []string
+		switch m := v.(type) {
+		case map[string]interface{}:
+			for key := range m {
+				keys = append(keys, key)
+			}
+		case map[string][]map[string]interface{}:
+			for key := range m {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		return keys
+	},
+	// join joins the values, formatted using their default format, with the
+	// separator.
+	"join": func(separator string, v interface{}) string {
+		var values []string
+		switch s := v.(type) {
+		case []string:
+			values = s
+		case []interface{}:
+			for _, value := range s {
+				values = append(values, fmt.Sprint(value))
+			}
+		default:
+			return fmt.Sprint(v)
+		}
+		return strings.Join(values, separator)
+	},
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// parseOutputTemplate parses the output template file so that an invalid
+// template is reported before any request is made; nil is returned if no
+// output template is specified.
+func parseOutputTemplate(filename string) (*template.Template, error) {
+	if len(filename) == 0 {
+		return nil, nil //nolint: nilnil
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading output template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(filename)).
+		Funcs(templateFuncs).
+		Option("missingkey=zero").
+		Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing output template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderTemplate renders the results through the output template; the
+// template is executed with the items of each resource keyed by resource
+// name.
+func renderTemplate(tmpl *template.Template, results []resource.ResourceData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, toResultMap(results)); err != nil {
+		return nil, fmt.Errorf("error rendering output template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestOutputTemplate(t *testing.T) {
+	results := []resource.ResourceData{
+		{Name: "service", Data: []map[string]interface{}{
+			{"id": "svc-1", "name": "billing", "host": "billing.internal", "port": json.Number("8080")},
+			{"id": "svc-2", "name": "orders", "host": "orders.internal", "port": json.Number("80")},
+		}},
+		{Name: "route", Data: []map[string]interface{}{
+			{"id": "route-1", "paths": []interface{}{"/billing", "/invoices"}},
+		}},
+	}
+
+	t.Run("verify the results are rendered through the template", func(t *testing.T) {
+		dir := t.TempDir()
+		filename := filepath.Join(dir, "hosts.tmpl")
+		require.NoError(t, os.WriteFile(filename, []byte(`name,host,port
+{{ range .service }}{{ .name }},{{ .host }},{{ .port }}
+{{ end }}{{ len .route }} route(s): {{ range .route }}{{ join "|" .paths }}{{ end }}
+resources: {{ join "," (keys .) }}
+first: {{ toJSON (index .service 0) }}
+`), 0o600))
+
+		tmpl, err := parseOutputTemplate(filename)
+		require.NoError(t, err)
+		outputFilename := filepath.Join(dir, "hosts.csv")
+		require.NoError(t, writeDump(context.Background(), results, tmpl, newHooks(config.Hooks{}, zap.NewNop()),
+			zap.NewNop(), fileOutput(outputFilename), "  "))
+		actual, err := os.ReadFile(outputFilename)
+		require.NoError(t, err)
+		require.Equal(t, `name,host,port
+billing,billing.internal,8080
+orders,orders.internal,80
+1 route(s): /billing|/invoices
+resources: route,service
+first: {"host":"billing.internal","id":"svc-1","name":"billing","port":8080}
+`, string(actual))
+	})
+
+	t.Run("verify an invalid template is rejected", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "invalid.tmpl")
+		require.NoError(t, os.WriteFile(filename, []byte(`{{ range .service }}`), 0o600))
+		_, err := parseOutputTemplate(filename)
+		require.ErrorContains(t, err, "error parsing output template")
+
+		require.NoError(t, os.WriteFile(filename, []byte(`{{ unknown .service }}`), 0o600))
+		_, err = parseOutputTemplate(filename)
+		require.ErrorContains(t, err, "error parsing output template")
+	})
+
+	t.Run("verify no template is parsed when none is specified", func(t *testing.T) {
+		tmpl, err := parseOutputTemplate("")
+		require.NoError(t, err)
+		require.Nil(t, tmpl)
+	})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/zap"
+)
+
+// resourceTiming is the timing of a resource processed by an operation.
+type resourceTiming struct {
+	// Resource is the name of the resource.
+	Resource string `json:"resource"`
+	// Duration is the wall-clock time spent processing the resource.
+	Duration time.Duration `json:"duration"`
+	// Requests is the number of requests issued for the resource, including
+	// retries.
+	Requests int64 `json:"requests"`
+	// Items is the number of items listed for the resource.
+	Items int `json:"items"`
+	// Total is the total number of items reported by the admin API for the
+	// resource, if any.
+	Total *int `json:"total,omitempty"`
+}
+
+// MarshalJSON encodes the duration of the timing as a string, consistent
+// with the duration of the reset report.
+func (t resourceTiming) MarshalJSON() ([]byte, error) {
+	type timing resourceTiming
+	return json.Marshal(struct {
+		timing
+		Duration string `json:"duration"`
+	}{timing: timing(t), Duration: t.Duration.String()})
+}
+
+// resourceTimings collects the timing of each resource processed by an
+// operation; it is safe for concurrent use and timings are not collected if
+// nil.
+type resourceTimings struct {
+	mutex   sync.Mutex
+	timings []resourceTiming
+}
+
+// begin starts timing the resource; the returned function records the timing
+// along with the number of items listed once the resource is processed.
+func (t *resourceTimings) begin(client *client.Client, res resource.Resource) func(items int) {
+	if t == nil {
+		return func(int) {}
+	}
+	startTime := time.Now()
+	startRequests := client.ResourceRequests(res.Path())
+	return func(items int) {
+		timing := resourceTiming{
+			Resource: res.Name(),
+			Duration: time.Since(startTime),
+			Requests: client.ResourceRequests(res.Path()) - startRequests,
+			Items:    items,
+		}
+		if total, ok := client.Totals().Get(res.Path()); ok {
+			timing.Total = &total
+		}
+		t.mutex.Lock()
+		defer t.mutex.Unlock()
+		t.timings = append(t.timings, timing)
+	}
+}
+
+// sorted returns the timings of the resources, slowest first.
+func (t *resourceTimings) sorted() []resourceTiming {
+	if t == nil {
+		return nil
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	timings := append([]resourceTiming(nil), t.timings...)
+	sort.SliceStable(timings, func(i, j int) bool {
+		if timings[i].Duration != timings[j].Duration {
+			return timings[i].Duration > timings[j].Duration
+		}
+		return timings[i].Resource < timings[j].Resource
+	})
+	return timings
+}
+
+// log logs the timings of the resources, slowest first, to identify the
+// resources whose pagination is the bottleneck of the operation.
+func (t *resourceTimings) log(logger *zap.Logger) {
+	timings := t.sorted()
+	if len(timings) == 0 {
+		return
+	}
+	logger.Info("Slowest resources", zap.Int("resource-count", len(timings)))
+	for i, timing := range timings {
+		fields := []zap.Field{
+			zap.Int("rank", i+1),
+			zap.String("resource", timing.Resource),
+			zap.Duration("duration", timing.Duration),
+			zap.Int64("requests", timing.Requests),
+			zap.Int("items", timing.Items),
+		}
+		if timing.Total != nil {
+			fields = append(fields, zap.Int("total", *timing.Total))
+		}
+		logger.Info("Resource timing", fields...)
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResourceTimings(t *testing.T) {
+	t.Run("verify a timing is collected for each listed resource", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/slow") {
+				time.Sleep(20 * time.Millisecond)
+				_, _ = w.Write([]byte(`{"data":[{"id":"slow-1"},{"id":"slow-2"}],"meta":{"page":{"total":2}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"fast-1"}]}`))
+		}))
+
+		timings := &resourceTimings{}
+		_, err := listData(context.Background(), client, []resource.Resource{
+			&fakeResource{name: "fast", path: "fast"},
+			&fakeResource{name: "slow", path: "slow"},
+		}, listOptions{sanitizer: &sanitize.Sanitizer{}, timings: timings}, zap.NewNop())
+		require.NoError(t, err)
+
+		sorted := timings.sorted()
+		require.Len(t, sorted, 2)
+		require.Equal(t, "slow", sorted[0].Resource)
+		require.Equal(t, int64(1), sorted[0].Requests)
+		require.Equal(t, 2, sorted[0].Items)
+		require.NotNil(t, sorted[0].Total)
+		require.Equal(t, 2, *sorted[0].Total)
+		require.GreaterOrEqual(t, sorted[0].Duration, 20*time.Millisecond)
+		require.Equal(t, "fast", sorted[1].Resource)
+		require.Equal(t, int64(1), sorted[1].Requests)
+		require.Equal(t, 1, sorted[1].Items)
+		require.Nil(t, sorted[1].Total)
+	})
+
+	t.Run("verify a timing is collected for each deleted resource", func(t *testing.T) {
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		opts := deleteOptions{audit: newNopAuditLog(), timings: &resourceTimings{}}
+		_, err := deleteLevels(context.Background(), client, [][]resource.Resource{
+			{&fakeResource{name: "fake-a", path: "fakes-a", items: newFakeItems(3)}},
+			{&fakeResource{name: "fake-b", path: "fakes-b", items: newFakeItems(1)}},
+		}, opts, &skippedResources{}, zap.NewNop())
+		require.NoError(t, err)
+
+		items := make(map[string]int)
+		requests := make(map[string]int64)
+		for _, timing := range opts.timings.sorted() {
+			items[timing.Resource] = timing.Items
+			requests[timing.Resource] = timing.Requests
+		}
+		require.Equal(t, map[string]int{"fake-a": 3, "fake-b": 1}, items)
+		require.Equal(t, map[string]int64{"fake-a": 3, "fake-b": 1}, requests)
+	})
+
+	t.Run("verify the timings are sorted slowest first and encoded with durations", func(t *testing.T) {
+		timings := &resourceTimings{timings: []resourceTiming{
+			{Resource: "route", Duration: time.Second},
+			{Resource: "service", Duration: 3 * time.Second, Requests: 4, Items: 250},
+			{Resource: "consumer", Duration: time.Second},
+		}}
+		sorted := timings.sorted()
+		require.Equal(t, []string{"service", "consumer", "route"},
+			[]string{sorted[0].Resource, sorted[1].Resource, sorted[2].Resource})
+
+		data, err := json.Marshal(sorted[0])
+		require.NoError(t, err)
+		require.JSONEq(t, `{"resource":"service","duration":"3s","requests":4,"items":250}`, string(data))
+
+		var nilTimings *resourceTimings
+		require.Empty(t, nilTimings.sorted())
+	})
+}
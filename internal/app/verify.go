@@ -0,0 +1,202 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/diff"
+	"github.com/mikefero/osiris/internal/logger"
+	"github.com/mikefero/osiris/internal/resource"
+	"go.uber.org/fx"
+	"go.uber.org/fx/fxevent"
+	"go.uber.org/zap"
+)
+
+// ErrDrift is returned when the live control plane differs from the golden
+// file.
+var ErrDrift = errors.New("control plane has drifted from golden file")
+
+// VerifyOptions contains the options for the verify command.
+type VerifyOptions struct {
+	// Against is the golden file to compare the live control plane against.
+	Against string
+	// IgnoreFields are the fields to ignore during comparison.
+	IgnoreFields []string
+	// Output is the writer used for the diff summary.
+	Output io.Writer
+}
+
+// NewVerify creates a new fx application for the verify command.
+// It provides the necessary dependencies and registers the verify
+// functionality.
+func NewVerify(opts VerifyOptions) *fx.App {
+	return fx.New(
+		fx.Supply(opts),
+		fx.Provide(
+			config.NewConfig,
+			func(config *config.Config) (*zap.Logger, error) {
+				return logger.NewLogger(config.Logger, logger.LoggerCommandTypeVerify)
+			},
+		),
+		fx.WithLogger(func(logger *zap.Logger) fxevent.Logger {
+			return &fxevent.ZapLogger{Logger: logger}
+		}),
+		fx.Invoke(registerVerify),
+	)
+}
+
+func registerVerify(lc fx.Lifecycle, config *config.Config, opts VerifyOptions, logger *zap.Logger) {
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			logger.Info("starting osiris",
+				zap.String("version", Version),
+				zap.String("commit", Commit),
+				zap.String("os-arch", OsArch),
+				zap.String("go-version", GoVersion),
+				zap.String("build-date", BuildDate),
+			)
+			ctx, cancel := withOperationTimeout(ctx, config)
+			defer cancel()
+			logger.Info("Starting verify",
+				zap.String("against", opts.Against),
+				zap.Strings("ignore-fields", opts.IgnoreFields))
+			golden, err := readResults(opts.Against)
+			if err != nil {
+				logger.Error("error reading golden file",
+					zap.String("against", opts.Against),
+					zap.Error(err))
+				return fmt.Errorf("error reading golden file: %w", err)
+			}
+
+			sanitizer, err := newSanitizer(config)
+			if err != nil {
+				logger.Error("error creating sanitizer", zap.Error(err))
+				return fmt.Errorf("error creating sanitizer: %w", err)
+			}
+			registry, err := resource.NewRegistry()
+			if err != nil {
+				logger.Error("error creating resource registry", zap.Error(err))
+				return fmt.Errorf("error creating resource registry: %w", err)
+			}
+			stripper, err := newStripper(config.ResourceStripFields, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating resource field stripper", zap.Error(err))
+				return fmt.Errorf("error creating resource field stripper: %w", err)
+			}
+			jsonFields, err := newJSONFields(config.ResourceJSONFields, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating resource JSON fields", zap.Error(err))
+				return fmt.Errorf("error creating resource JSON fields: %w", err)
+			}
+			transforms, err := newTransforms(config.Transforms, registry.GetResources())
+			if err != nil {
+				logger.Error("error creating transforms", zap.Error(err))
+				return fmt.Errorf("error creating transforms: %w", err)
+			}
+			if err := resolveBaseURL(ctx, config, logger); err != nil {
+				logger.Error("error discovering base URL", zap.Error(err))
+				return err
+			}
+			if err := resolveControlPlaneID(ctx, config, logger); err != nil {
+				logger.Error("error resolving control plane", zap.Error(err))
+				return err
+			}
+			client, err := newClient(config, logger)
+			if err != nil {
+				logger.Error("error creating client", zap.Error(err))
+				return fmt.Errorf("error creating client: %w", err)
+			}
+			defer logRequestStats(client, logger)
+			retry, err := newResourceRetry(config)
+			if err != nil {
+				logger.Error("error creating resource retry", zap.Error(err))
+				return fmt.Errorf("error creating resource retry: %w", err)
+			}
+			if err := client.Ping(ctx); err != nil {
+				logger.Error("error executing preflight request", zap.Error(err))
+				return fmt.Errorf("error executing preflight request: %w", err)
+			}
+			resources := supportedResources(ctx, client, registry.GetResources(), logger)
+			results, err := listData(ctx, client, resources, listOptions{
+				stripper:   stripper,
+				sanitizer:  sanitizer,
+				transforms: transforms,
+				jsonFields: jsonFields,
+				retry:      retry,
+			}, logger)
+			if err != nil {
+				logger.Error("error executing verify", zap.Error(err))
+				return fmt.Errorf("error listing data: %w", err)
+			}
+
+			// Round trip the live data through JSON to ensure it is comparable
+			// with the data read from the golden file
+			live, err := roundTrip(toResultMap(results))
+			if err != nil {
+				return fmt.Errorf("error normalizing live data: %w", err)
+			}
+
+			result := diff.Compare(golden, live, opts.IgnoreFields)
+			fmt.Fprintln(opts.Output, result.Summary())
+			if !result.Empty() {
+				logger.Warn("Control plane has drifted from golden file",
+					zap.Int("resource-count", len(result.Resources)))
+				return ErrDrift
+			}
+			logger.Info("Verify completed successfully")
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			logger.Info("stopping osiris")
+			if err := logger.Sync(); err != nil {
+				logger.Error("failed to sync logger", zap.Error(err))
+			}
+			return nil
+		},
+	})
+}
+
+// readResults reads a previously written dump file.
+func readResults(filename string) (map[string][]map[string]interface{}, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading file: %w", err)
+	}
+	var results map[string][]map[string]interface{}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("error unmarshaling file: %w", err)
+	}
+	return results, nil
+}
+
+func roundTrip(resultMap map[string][]map[string]interface{}) (map[string][]map[string]interface{}, error) {
+	data, err := json.Marshal(resultMap)
+	if err != nil {
+		return nil, err
+	}
+	var results map[string][]map[string]interface{}
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
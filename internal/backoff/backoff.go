@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backoff
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/mikefero/osiris/internal/config"
+)
+
+// Strategy is the strategy used to pace the attempts of a request.
+type Strategy string
+
+const (
+	// StrategyConstant waits the base duration between each attempt.
+	StrategyConstant Strategy = "constant"
+	// StrategyLinear waits the base duration multiplied by the attempt.
+	StrategyLinear Strategy = "linear"
+	// StrategyExponential waits the base duration doubled for each attempt.
+	StrategyExponential Strategy = "exponential"
+)
+
+const (
+	defaultBase = time.Second
+	defaultMax  = 60 * time.Second
+)
+
+// Backoff determines the duration to wait before the next attempt of a
+// request.
+type Backoff struct {
+	strategy Strategy
+	base     time.Duration
+	max      time.Duration
+	jitter   bool
+	// random returns a random number in [0.0, 1.0) used for jitter.
+	random func() float64
+}
+
+// Default returns the default backoff; exponential with jitter.
+func Default() *Backoff {
+	return &Backoff{
+		strategy: StrategyExponential,
+		base:     defaultBase,
+		max:      defaultMax,
+		jitter:   true,
+		random:   rand.Float64, //nolint: gosec
+	}
+}
+
+// New creates a new backoff from the backoff configuration. Zero values fall
+// back to the defaults; an error is returned if the strategy is unknown.
+func New(config config.Backoff) (*Backoff, error) {
+	backoff := Default()
+	if len(config.Strategy) > 0 {
+		backoff.strategy = Strategy(strings.ToLower(config.Strategy))
+	}
+	switch backoff.strategy {
+	case StrategyConstant, StrategyLinear, StrategyExponential:
+	default:
+		return nil, fmt.Errorf("invalid backoff strategy: %q", config.Strategy)
+	}
+	if config.Base > 0 {
+		backoff.base = config.Base
+	}
+	if config.Max > 0 {
+		backoff.max = config.Max
+	}
+	backoff.jitter = config.Jitter
+	return backoff, nil
+}
+
+// Next returns the duration to wait after the specified attempt (starting at
+// one). The duration is capped to the maximum; with jitter a random duration
+// between half and all of the duration is returned to avoid synchronized
+// retries.
+func (b *Backoff) Next(attempt int) time.Duration {
+	attempt = max(attempt, 1)
+	var delay time.Duration
+	switch b.strategy {
+	case StrategyConstant:
+		delay = b.base
+	case StrategyLinear:
+		delay = b.base * time.Duration(attempt)
+	default:
+		// Avoid overflowing the duration for large attempts
+		delay = b.max
+		if attempt < 63 && b.base <= b.max>>(attempt-1) {
+			delay = b.base << (attempt - 1)
+		}
+	}
+	if delay > b.max || delay < 0 {
+		delay = b.max
+	}
+
+	if b.jitter {
+		half := delay / 2
+		delay = half + time.Duration(b.random()*float64(delay-half))
+	}
+	return delay
+}
+
+// Jitter returns the duration with a random duration between zero and the
+// base added (additive jitter of up to the backoff base) so that requests
+// told to wait the same duration (e.g. by a Retry-After header) do not retry
+// at the same time; the duration is never shortened.
+func (b *Backoff) Jitter(duration time.Duration) time.Duration {
+	return duration + time.Duration(b.random()*float64(b.base))
+}
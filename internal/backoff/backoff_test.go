@@ -0,0 +1,139 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("verify sequence of delays for each strategy", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			config   config.Backoff
+			random   float64
+			expected []time.Duration
+		}{
+			{
+				name: "constant",
+				config: config.Backoff{
+					Strategy: "constant",
+					Base:     2 * time.Second,
+					Max:      time.Minute,
+				},
+				expected: []time.Duration{
+					2 * time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second, 2 * time.Second,
+				},
+			},
+			{
+				name: "linear",
+				config: config.Backoff{
+					Strategy: "linear",
+					Base:     2 * time.Second,
+					Max:      7 * time.Second,
+				},
+				expected: []time.Duration{
+					2 * time.Second, 4 * time.Second, 6 * time.Second, 7 * time.Second, 7 * time.Second,
+				},
+			},
+			{
+				name: "exponential",
+				config: config.Backoff{
+					Strategy: "exponential",
+					Base:     time.Second,
+					Max:      10 * time.Second,
+				},
+				expected: []time.Duration{
+					time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second,
+				},
+			},
+			{
+				name: "exponential with jitter",
+				config: config.Backoff{
+					Strategy: "EXPONENTIAL",
+					Base:     time.Second,
+					Max:      10 * time.Second,
+					Jitter:   true,
+				},
+				random: 0.5,
+				expected: []time.Duration{
+					750 * time.Millisecond, 1500 * time.Millisecond, 3 * time.Second, 6 * time.Second,
+					7500 * time.Millisecond,
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				backoff, err := New(tt.config)
+				require.NoError(t, err)
+				backoff.random = func() float64 { return tt.random }
+
+				actual := make([]time.Duration, 0, len(tt.expected))
+				for attempt := 1; attempt <= len(tt.expected); attempt++ {
+					actual = append(actual, backoff.Next(attempt))
+				}
+				require.Equal(t, tt.expected, actual)
+			})
+		}
+	})
+
+	t.Run("verify large attempts are capped to the maximum", func(t *testing.T) {
+		backoff, err := New(config.Backoff{Strategy: "exponential", Base: time.Second, Max: time.Minute})
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, backoff.Next(100))
+	})
+
+	t.Run("verify zero values fall back to the defaults", func(t *testing.T) {
+		backoff, err := New(config.Backoff{})
+		require.NoError(t, err)
+		require.Equal(t, StrategyExponential, backoff.strategy)
+		require.Equal(t, defaultBase, backoff.base)
+		require.Equal(t, defaultMax, backoff.max)
+	})
+
+	t.Run("verify invalid strategy returns error", func(t *testing.T) {
+		_, err := New(config.Backoff{Strategy: "fibonacci"})
+		require.ErrorContains(t, err, "invalid backoff strategy")
+	})
+	t.Run("verify jitter distributes the waits across the base", func(t *testing.T) {
+		backoff, err := New(config.Backoff{Base: time.Second})
+		require.NoError(t, err)
+		const wait = 5 * time.Second
+		const samples = 1000
+		waits := make(map[time.Duration]struct{}, samples)
+		var lower, upper int
+		for range samples {
+			duration := backoff.Jitter(wait)
+			require.GreaterOrEqual(t, duration, wait)
+			require.Less(t, duration, wait+time.Second)
+			waits[duration] = struct{}{}
+			if duration < wait+time.Second/2 {
+				lower++
+			} else {
+				upper++
+			}
+		}
+		// The waits are spread across the base rather than synchronized
+		require.Greater(t, len(waits), samples/2)
+		require.Greater(t, lower, samples/4)
+		require.Greater(t, upper, samples/4)
+	})
+}
@@ -0,0 +1,56 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/mikefero/osiris/internal/config"
+)
+
+// maxBaseURLDiscoveryBytes is the maximum size of the base URL discovery
+// response.
+const maxBaseURLDiscoveryBytes = 4096
+
+// DiscoverBaseURL requests the discovery URL using the transport of the
+// client (TLS, proxy, and timeouts) and returns the base URL from the body of
+// the response. The bearer token is not sent to the discovery URL; an error
+// wrapping config.ErrInvalidBaseURL is returned if the body is not an
+// absolute HTTP(S) URL.
+func (c *Client) DiscoverBaseURL(ctx context.Context, discoveryURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return "", fmt.Errorf("error making request: %w",
+			&RequestError{Method: http.MethodGet, URL: discoveryURL, Err: err})
+	}
+	//nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", &RequestError{Method: http.MethodGet, URL: discoveryURL, StatusCode: resp.StatusCode}
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBaseURLDiscoveryBytes))
+	if err != nil {
+		return "", fmt.Errorf("error reading response: %w", err)
+	}
+	return config.ParseBaseURL(string(data))
+}
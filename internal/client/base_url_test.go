@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDiscoverBaseURL(t *testing.T) {
+	t.Run("verify base URL is discovered without the bearer token", func(t *testing.T) {
+		var authorization []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = append(authorization, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte("https://kong-admin.internal:8444/\n"))
+		}))
+		defer server.Close()
+		config := newTestConfig("http://localhost:8001")
+		config.BearerToken = "token"
+
+		c := client.NewClient(config, zap.NewNop())
+		baseURL, err := c.DiscoverBaseURL(context.Background(), server.URL)
+		require.NoError(t, err)
+		require.Equal(t, "https://kong-admin.internal:8444", baseURL)
+		require.Equal(t, []string{""}, authorization)
+	})
+
+	t.Run("verify discovery is requested through the configured proxy", func(t *testing.T) {
+		proxy, requests := newTestProxy(t)
+		cfg := newTestConfig("http://localhost:8001")
+		cfg.ProxyURL = proxy.URL
+
+		c := client.NewClient(cfg, zap.NewNop())
+		_, err := c.DiscoverBaseURL(context.Background(), "http://discovery.example.com/admin")
+		// The proxy answers with a list of items rather than a base URL
+		require.ErrorIs(t, err, config.ErrInvalidBaseURL)
+
+		received := requests()
+		require.Len(t, received, 1)
+		require.Equal(t, "discovery.example.com", received[0].host)
+	})
+
+	t.Run("verify failed discovery returns the request error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig("http://localhost:8001"), zap.NewNop())
+		_, err := c.DiscoverBaseURL(context.Background(), server.URL)
+		var requestErr *client.RequestError
+		require.ErrorAs(t, err, &requestErr)
+		require.Equal(t, http.StatusServiceUnavailable, requestErr.StatusCode)
+	})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// checkpointEntry is a single page recorded in the checkpoint file. The
+// checkpoint file is a sequence of entries that is appended to as pages are
+// retrieved.
+type checkpointEntry struct {
+	// Endpoint is the endpoint the page was retrieved from.
+	Endpoint string `json:"endpoint"`
+	// Items are the items of the page.
+	Items []map[string]interface{} `json:"items"`
+	// Next is the URL of the next page; empty if the page was the last page.
+	Next string `json:"next"`
+	// Restart marks the start of a listing of the endpoint from the next URL;
+	// only the first Keep items recorded previously are retained so that the
+	// pages of an earlier attempt are not duplicated.
+	Restart bool `json:"restart,omitempty"`
+	// Keep is the number of items retained when restarting.
+	Keep int `json:"keep,omitempty"`
+}
+
+// endpointCheckpoint is the pagination progress of an endpoint.
+type endpointCheckpoint struct {
+	items    []map[string]interface{}
+	next     string
+	complete bool
+}
+
+// Checkpoint persists the pagination progress of endpoints so that an
+// interrupted run can be resumed from the last page retrieved rather than the
+// first page.
+type Checkpoint struct {
+	mutex     sync.Mutex
+	file      *os.File
+	encoder   *json.Encoder
+	endpoints map[string]*endpointCheckpoint
+}
+
+// OpenCheckpoint opens the checkpoint file. When resuming, the progress
+// recorded in an existing checkpoint file is loaded and new progress is
+// appended; otherwise the checkpoint file is truncated.
+func OpenCheckpoint(filename string, resume bool) (*Checkpoint, error) {
+	endpoints := make(map[string]*endpointCheckpoint)
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if resume {
+		var size int64
+		var err error
+		endpoints, size, err = loadCheckpoint(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		// Discard any truncated entry so that new progress is appended after
+		// the last complete entry
+		if err := os.Truncate(filename, size); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("unable to truncate checkpoint file: %w", err)
+		}
+		flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	file, err := os.OpenFile(filename, flags, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open checkpoint file: %w", err)
+	}
+	return &Checkpoint{
+		file:      file,
+		encoder:   json.NewEncoder(file),
+		endpoints: endpoints,
+	}, nil
+}
+
+// loadCheckpoint loads the progress recorded in the checkpoint file and
+// returns the size of the complete entries in the file.
+func loadCheckpoint(filename string) (map[string]*endpointCheckpoint, int64, error) {
+	endpoints := make(map[string]*endpointCheckpoint)
+	file, err := os.Open(filename)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return endpoints, 0, nil
+		}
+		return nil, 0, fmt.Errorf("unable to open checkpoint file: %w", err)
+	}
+	//nolint: errcheck
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+	var size int64
+	for {
+		var entry checkpointEntry
+		if err := decoder.Decode(&entry); err != nil {
+			// A truncated entry is the result of an interruption while writing
+			// and is ignored
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return endpoints, size, nil
+			}
+			return nil, 0, fmt.Errorf("unable to decode checkpoint file: %w", err)
+		}
+		endpoints[entry.Endpoint] = applyCheckpointEntry(endpoints[entry.Endpoint], entry)
+		size = decoder.InputOffset()
+	}
+}
+
+func applyCheckpointEntry(state *endpointCheckpoint, entry checkpointEntry) *endpointCheckpoint {
+	if state == nil {
+		state = &endpointCheckpoint{}
+	}
+	if entry.Restart {
+		state.items = state.items[:min(entry.Keep, len(state.items))]
+		state.next = entry.Next
+		state.complete = false
+		return state
+	}
+	state.items = append(state.items, entry.Items...)
+	state.next = entry.Next
+	state.complete = len(entry.Next) == 0
+	return state
+}
+
+// endpoint returns the pagination progress of an endpoint, if any.
+func (c *Checkpoint) endpoint(endpoint string) (endpointCheckpoint, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	state, ok := c.endpoints[endpoint]
+	if !ok {
+		return endpointCheckpoint{}, false
+	}
+	items := make([]map[string]interface{}, len(state.items))
+	copy(items, state.items)
+	return endpointCheckpoint{
+		items:    items,
+		next:     state.next,
+		complete: state.complete,
+	}, true
+}
+
+// record writes a page retrieved from an endpoint along with the URL of the
+// next page to the checkpoint file; an empty next URL marks the endpoint as
+// complete. Only the progress loaded when resuming is used by the current
+// run.
+func (c *Checkpoint) record(endpoint string, items []map[string]interface{}, next string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.encoder.Encode(checkpointEntry{
+		Endpoint: endpoint,
+		Items:    items,
+		Next:     next,
+	}); err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// restart records the start of a listing of an endpoint from the next URL,
+// retaining the first keep items recorded previously (those resumed from the
+// checkpoint); the pages recorded by an earlier listing of the endpoint are
+// discarded when the checkpoint is loaded.
+func (c *Checkpoint) restart(endpoint string, keep int, next string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if err := c.encoder.Encode(checkpointEntry{
+		Endpoint: endpoint,
+		Next:     next,
+		Restart:  true,
+		Keep:     keep,
+	}); err != nil {
+		return fmt.Errorf("unable to write checkpoint: %w", err)
+	}
+	return nil
+}
+
+// Close closes the checkpoint file.
+func (c *Checkpoint) Close() error {
+	if err := c.file.Close(); err != nil {
+		return fmt.Errorf("unable to close checkpoint file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestCheckpoint(t *testing.T) {
+	const pageCount = 5
+	var interrupted atomic.Bool
+	var mutex sync.Mutex
+	var requestedOffsets []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		mutex.Lock()
+		requestedOffsets = append(requestedOffsets, offset)
+		mutex.Unlock()
+
+		// Simulate an interruption after the first pages
+		if offset == 3 && interrupted.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		next := "null"
+		if offset < pageCount-1 {
+			next = fmt.Sprintf(`"services?offset=%d"`, offset+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-%d"}],"next":%s}`, offset, next)
+	}))
+	defer server.Close()
+	config := newTestConfig(server.URL)
+	config.Retries.MaxAttempts = 1
+	filename := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+
+	t.Run("verify pagination resumes from the saved cursor", func(t *testing.T) {
+		interrupted.Store(true)
+		checkpoint, err := client.OpenCheckpoint(filename, false)
+		require.NoError(t, err)
+		c := client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		_, err = c.GetEndpoint(context.Background(), "services")
+		require.Error(t, err)
+		require.NoError(t, checkpoint.Close())
+		require.Equal(t, []int{0, 1, 2, 3}, requestedOffsets)
+
+		// Resume the interrupted pagination
+		interrupted.Store(false)
+		requestedOffsets = nil
+		checkpoint, err = client.OpenCheckpoint(filename, true)
+		require.NoError(t, err)
+		c = client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.NoError(t, checkpoint.Close())
+		require.Equal(t, []int{3, 4}, requestedOffsets)
+		require.Equal(t, []map[string]interface{}{
+			{"id": "svc-0"},
+			{"id": "svc-1"},
+			{"id": "svc-2"},
+			{"id": "svc-3"},
+			{"id": "svc-4"},
+		}, data)
+	})
+
+	t.Run("verify completed endpoint is not requested again", func(t *testing.T) {
+		requestedOffsets = nil
+		checkpoint, err := client.OpenCheckpoint(filename, true)
+		require.NoError(t, err)
+		c := client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.NoError(t, checkpoint.Close())
+		require.Empty(t, requestedOffsets)
+		require.Len(t, data, pageCount)
+	})
+
+	t.Run("verify truncated checkpoint entry is discarded", func(t *testing.T) {
+		truncatedFilename := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+		next := fmt.Sprintf("%s/%s/services?offset=1", server.URL, config.ControlPlaneID)
+		require.NoError(t, os.WriteFile(truncatedFilename, []byte(
+			`{"endpoint":"services","items":[{"id":"svc-0"}],"next":"`+next+`"}`+"\n"+
+				`{"endpoint":"services","items":[{"id":"svc-1"}],"ne`), 0o600))
+
+		requestedOffsets = nil
+		checkpoint, err := client.OpenCheckpoint(truncatedFilename, true)
+		require.NoError(t, err)
+		c := client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.NoError(t, checkpoint.Close())
+		require.Equal(t, []int{1, 2, 3, 4}, requestedOffsets)
+		require.Len(t, data, pageCount)
+
+		// The checkpoint remains readable after appending to it
+		checkpoint, err = client.OpenCheckpoint(truncatedFilename, true)
+		require.NoError(t, err)
+		require.NoError(t, checkpoint.Close())
+	})
+
+	t.Run("verify pages of a retried listing are not duplicated when resuming", func(t *testing.T) {
+		retriedFilename := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+		interrupted.Store(true)
+		checkpoint, err := client.OpenCheckpoint(retriedFilename, false)
+		require.NoError(t, err)
+		c := client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		for range 2 {
+			_, err = c.GetEndpoint(context.Background(), "services")
+			require.Error(t, err)
+		}
+		require.NoError(t, checkpoint.Close())
+
+		interrupted.Store(false)
+		requestedOffsets = nil
+		checkpoint, err = client.OpenCheckpoint(retriedFilename, true)
+		require.NoError(t, err)
+		c = client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.NoError(t, checkpoint.Close())
+		require.Equal(t, []int{3, 4}, requestedOffsets)
+		require.Equal(t, []map[string]interface{}{
+			{"id": "svc-0"},
+			{"id": "svc-1"},
+			{"id": "svc-2"},
+			{"id": "svc-3"},
+			{"id": "svc-4"},
+		}, data)
+
+		// A listing resumed more than once retains the resumed items
+		checkpoint, err = client.OpenCheckpoint(retriedFilename, true)
+		require.NoError(t, err)
+		c = client.NewClient(config, zap.NewNop())
+		c.SetCheckpoint(checkpoint)
+		data, err = c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.NoError(t, checkpoint.Close())
+		require.Len(t, data, pageCount)
+	})
+}
@@ -16,16 +16,39 @@ limitations under the License.
 package client
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/mikefero/osiris/internal/backoff"
 	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
 	"go.uber.org/zap"
 )
 
-const defaultRateLimitWaitDuration = 10 * time.Second
+const (
+	defaultMaxAttempts       = 10
+	defaultMaxRetryWait      = 60 * time.Second
+	defaultReadinessAttempts = 1
+	defaultMaxResponseBytes  = 100 * 1024 * 1024
+	defaultMaxRedirects      = 10
+)
+
+// defaultDataKeys are the keys of a response containing the items when no
+// data keys are configured; `items` is used by the v1 API.
+var defaultDataKeys = []string{"data", "items"}
+
+// gatewayDataKeys are the keys of a response containing the items once the
+// version of the gateway is detected and no data keys are configured; the
+// admin API of the gateway only uses `data`.
+var gatewayDataKeys = []string{"data"}
 
 // HTTPClient is an interface that wraps the Do method of http.Client.
 type HTTPClient interface {
@@ -36,51 +59,301 @@ type HTTPClient interface {
 
 // Client is a struct that represents the API client.
 type Client struct {
-	httpClient     HTTPClient
-	baseURL        string
-	bearerToken    string
-	outputFilename string
-	logger         *zap.Logger
+	httpClient       HTTPClient
+	rootURL          string
+	baseURL          string
+	bearerToken      string
+	proxyAuth        string
+	identity         string
+	identityHeader   string
+	outputFilename   string
+	includeMeta      bool
+	enrich           bool
+	partialPages     bool
+	skipFailedPages  bool
+	strictTotals     bool
+	maxResponseBytes int64
+	dataKeys         []string
+	compressRequests bool
+	checkpoint       *Checkpoint
+	raw              *RawRecorder
+	maxAttempts      int
+	maxRetryWait     time.Duration
+	retryJitter      bool
+	timeout          time.Duration
+	methodTimeouts   map[string]time.Duration
+	backoff          *backoff.Backoff
+	rateLimiter      *rateLimiter
+	version          *GatewayVersion
+	events           *event.Emitter
+	totals           *Totals
+	stats            requestStats
+	logger           *zap.Logger
+
+	readinessAttempts int
+	readinessInterval time.Duration
+
+	maxRedirects            int
+	allowCrossHostRedirects bool
+
+	// dataKeysConfigured is set if the data keys are configured rather than
+	// chosen using the version of the gateway
+	dataKeysConfigured bool
+	// cursorPagination follows the cursor of the v1 API in addition to the
+	// next URL; disabled once the version of the gateway is detected
+	cursorPagination bool
+
+	// compressionRejected is set once a compressed request is rejected so
+	// that the remaining requests are not compressed
+	compressionRejected atomic.Bool
 }
 
 // NewClient creates a new API client with the provided configuration and logger.
 func NewClient(config *config.Config, logger *zap.Logger) *Client {
+	// Bound the connection attempts so that an unreachable host fails fast
+	// rather than waiting for the operating system default
+	dialer := &net.Dialer{
+		Timeout: config.Timeouts.Dial,
+	}
+	transport := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ResponseHeaderTimeout: config.Timeouts.ResponseHeader,
+	}
+	if len(config.TLSServerName) > 0 {
+		transport.TLSClientConfig = &tls.Config{
+			ServerName: config.TLSServerName,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+	proxyAuthorization := configureProxy(transport, config, logger)
+	// The request timeout is applied to each request by the client so that it
+	// may be overridden for each HTTP method
 	client := &http.Client{
-		Timeout: config.Timeouts.Timeout,
-		Transport: &http.Transport{
-			ResponseHeaderTimeout: config.Timeouts.ResponseHeader,
-		},
+		Transport: transport,
+	}
+	rootURL := strings.TrimSuffix(config.BaseURL, "/")
+	baseURL := fmt.Sprintf("%s/%s", rootURL, config.ControlPlaneID.String())
+	maxAttempts := config.Retries.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	maxRetryWait := config.Retries.MaxWait
+	if maxRetryWait <= 0 {
+		maxRetryWait = defaultMaxRetryWait
+	}
+	maxResponseBytes := config.MaxResponseBytes
+	if maxResponseBytes <= 0 {
+		maxResponseBytes = defaultMaxResponseBytes
+	}
+	readinessAttempts := config.Readiness.Attempts
+	if readinessAttempts <= 0 {
+		readinessAttempts = defaultReadinessAttempts
+	}
+	dataKeys := config.DataKeys
+	if len(dataKeys) == 0 {
+		dataKeys = defaultDataKeys
+	}
+	maxRedirects := defaultMaxRedirects
+	if config.Redirects.Max != nil {
+		maxRedirects = *config.Redirects.Max
 	}
-	baseURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(config.BaseURL, "/"),
-		config.ControlPlaneID.String())
 
-	return &Client{
-		httpClient:     client,
-		baseURL:        baseURL,
-		bearerToken:    config.BearerToken,
-		outputFilename: config.OutputFile,
+	c := &Client{
+		httpClient:       client,
+		rootURL:          rootURL,
+		baseURL:          baseURL,
+		bearerToken:      config.BearerToken,
+		proxyAuth:        proxyAuthorization,
+		identity:         config.OperatorIdentity,
+		identityHeader:   config.OperatorIdentityHeader,
+		outputFilename:   config.OutputFile,
+		includeMeta:      config.IncludeMetadata,
+		enrich:           !config.SkipSubResourceEnrichment,
+		partialPages:     config.PartialPages || config.ContinueOnError,
+		skipFailedPages:  config.SkipFailedPages,
+		maxResponseBytes: maxResponseBytes,
+		dataKeys:         dataKeys,
+		compressRequests: config.CompressRequests,
+		maxAttempts:      maxAttempts,
+		maxRetryWait:     maxRetryWait,
+		retryJitter:      config.Retries.Jitter,
+		timeout:          config.Timeouts.Timeout,
+		methodTimeouts: map[string]time.Duration{
+			http.MethodGet:    config.Timeouts.Get,
+			http.MethodDelete: config.Timeouts.Delete,
+			http.MethodPut:    config.Timeouts.Put,
+			http.MethodPatch:  config.Timeouts.Put,
+		},
+		backoff:     backoff.Default(),
+		totals:      newTotals(),
+		rateLimiter: newRateLimiter(config.RateLimit.RequestsPerSecond, config.RateLimit.PerResource),
 		logger: logger.With(
 			zap.String("base-url", baseURL),
 			zap.Any("control-plane-id", config.ControlPlaneID),
 		),
+		readinessAttempts: readinessAttempts,
+		readinessInterval: config.Readiness.Interval,
+
+		maxRedirects:            maxRedirects,
+		allowCrossHostRedirects: config.Redirects.AllowCrossHost,
+
+		dataKeysConfigured: len(config.DataKeys) > 0,
+		cursorPagination:   true,
 	}
+	client.CheckRedirect = c.checkRedirect
+	return c
+}
+
+// SetCheckpoint sets the checkpoint used to persist and resume the pagination
+// progress of endpoints.
+func (c *Client) SetCheckpoint(checkpoint *Checkpoint) {
+	c.checkpoint = checkpoint
 }
 
-func (c *Client) retryAfterDuration(resp *http.Response) time.Duration {
+// SetStrictTotals sets whether an endpoint whose number of items retrieved
+// does not match the total reported by the endpoint fails with an error
+// wrapping ErrTotalMismatch rather than logging a warning.
+func (c *Client) SetStrictTotals(strict bool) {
+	c.strictTotals = strict
+}
+
+// SetBackoff sets the backoff used to pace the attempts of a request when the
+// admin API does not specify the duration to wait.
+func (c *Client) SetBackoff(backoff *backoff.Backoff) {
+	c.backoff = backoff
+}
+
+// requestTimeout returns the timeout for a request using the HTTP method; the
+// request timeout is used unless overridden for the method.
+func (c *Client) requestTimeout(method string) time.Duration {
+	if timeout := c.methodTimeouts[method]; timeout > 0 {
+		return timeout
+	}
+	return c.timeout
+}
+
+// cancelOnClose is a response body that releases the context of the request
+// once the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	defer b.cancel()
+	return b.ReadCloser.Close()
+}
+
+// do executes the request bounded by the request timeout for its HTTP
+// method; the timeout covers reading the response body. The request is paced
+// by the rate limit and the operator identity, if any, is sent with the
+// request.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	if err := c.rateLimiter.wait(req.Context()); err != nil {
+		return nil, err
+	}
+	if len(c.identity) > 0 && len(c.identityHeader) > 0 {
+		req.Header.Set(c.identityHeader, c.identity)
+	}
+	// HTTPS requests are authenticated with the proxy by the CONNECT request
+	// of the tunnel; plain HTTP requests are forwarded by the proxy
+	if len(c.proxyAuth) > 0 && req.URL.Scheme == "http" {
+		req.Header.Set(proxyAuthorizationHeader, c.proxyAuth)
+	}
+	if req.ContentLength > 0 {
+		c.stats.bytes.Add(req.ContentLength)
+	}
+	timeout := c.requestTimeout(req.Method)
+	startTime := time.Now()
+	if timeout <= 0 {
+		resp, err := c.httpClient.Do(req)
+		c.countRequest(req, resp, startTime)
+		return resp, err
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := c.httpClient.Do(req.WithContext(ctx))
+	c.countRequest(req, resp, startTime)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// countRequest counts a request issued by the client along with its latency
+// and the resource it is made for; the bytes of the response body are counted
+// as the body is read.
+func (c *Client) countRequest(req *http.Request, resp *http.Response, startTime time.Time) {
+	c.stats.requests.Add(1)
+	c.stats.countResource(resourceFromContext(req.Context()))
+	c.stats.latency.Add(int64(time.Since(startTime)))
+	if resp != nil && resp.Body != nil {
+		resp.Body = &countingBody{ReadCloser: resp.Body, bytes: &c.stats.bytes}
+	}
+}
+
+// SetEmitter sets the emitter used to report the retries of requests in the
+// event stream; no events are emitted if nil.
+func (c *Client) SetEmitter(events *event.Emitter) {
+	c.events = events
+}
+
+// Totals returns the total numbers of items reported by the endpoints listed
+// by the client, keyed by endpoint.
+func (c *Client) Totals() *Totals {
+	return c.totals
+}
+
+// IncludeMetadata returns true if metadata fields should be retained in the
+// listed data rather than stripped.
+func (c *Client) IncludeMetadata() bool {
+	return c.includeMeta
+}
+
+// EnrichSubResources returns true if the listed resources should be enriched
+// with their sub-resources (e.g. the consumer groups of consumers).
+func (c *Client) EnrichSubResources() bool {
+	return c.enrich
+}
+
+// retryAfterDuration returns the duration to wait before the next attempt of
+// a request as specified by the Retry-After header of the response; the
+// backoff duration for the attempt is used if the header is missing or
+// invalid. With retry jitter enabled a random duration of up to the backoff
+// base is added to the duration specified by the header so that concurrent
+// requests limited at the same time do not retry in lockstep.
+func (c *Client) retryAfterDuration(resp *http.Response, attempt int) time.Duration {
 	retryAfter := resp.Header.Get("Retry-After")
 	if len(retryAfter) == 0 {
-		c.logger.Debug("Retry-After header not found; using default duration",
-			zap.Duration("duration", defaultRateLimitWaitDuration))
-		return defaultRateLimitWaitDuration
+		duration := c.backoff.Next(attempt)
+		c.logger.Debug("Retry-After header not found; using backoff duration",
+			zap.Int("attempt", attempt),
+			zap.Duration("duration", duration))
+		return duration
 	}
 
-	duration, err := time.ParseDuration(retryAfter)
-	if err != nil {
-		c.logger.Error("error parsing Retry-After header; using default duration",
-			zap.Duration("duration", defaultRateLimitWaitDuration),
-			zap.String("retry-after", retryAfter),
-			zap.Error(err))
-		return defaultRateLimitWaitDuration
+	// Retry-After is either a number of seconds or an HTTP date
+	if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+		return c.retryJitterDuration(time.Duration(seconds) * time.Second)
 	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		return c.retryJitterDuration(max(time.Until(date), 0))
+	}
+
+	duration := c.backoff.Next(attempt)
+	c.logger.Error("error parsing Retry-After header; using backoff duration",
+		zap.Int("attempt", attempt),
+		zap.Duration("duration", duration),
+		zap.String("retry-after", retryAfter))
 	return duration
 }
+
+// retryJitterDuration adds the backoff jitter to the duration specified by a
+// Retry-After header if retry jitter is enabled.
+func (c *Client) retryJitterDuration(duration time.Duration) time.Duration {
+	if !c.retryJitter {
+		return duration
+	}
+	return c.backoff.Jitter(duration)
+}
@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRequestTimeouts(t *testing.T) {
+	// The server responds slower than the method timeout but faster than the
+	// request timeout
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	t.Run("verify method timeout overrides the request timeout", func(t *testing.T) {
+		config := newTestConfig(server.URL)
+		config.Timeouts.Timeout = 5 * time.Second
+		config.Timeouts.Get = 50 * time.Millisecond
+		c := client.NewClient(config, zap.NewNop())
+
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.NoError(t, c.DeleteEndpoint(context.Background(), "services/1234"))
+	})
+
+	t.Run("verify method timeout may exceed the request timeout", func(t *testing.T) {
+		config := newTestConfig(server.URL)
+		config.Timeouts.Timeout = 50 * time.Millisecond
+		config.Timeouts.Delete = 5 * time.Second
+		c := client.NewClient(config, zap.NewNop())
+
+		require.NoError(t, c.DeleteEndpoint(context.Background(), "services/1234"))
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+	t.Run("verify put timeout overrides the request timeout for PUT and PATCH", func(t *testing.T) {
+		item := map[string]interface{}{"id": "1234"}
+		config := newTestConfig(server.URL)
+		config.Timeouts.Timeout = 5 * time.Second
+		config.Timeouts.Put = 50 * time.Millisecond
+		c := client.NewClient(config, zap.NewNop())
+
+		require.ErrorIs(t, c.PutEndpoint(context.Background(), "services/1234", item), context.DeadlineExceeded)
+		require.ErrorIs(t, c.PatchEndpoint(context.Background(), "services/1234", item), context.DeadlineExceeded)
+
+		config.Timeouts.Timeout = 50 * time.Millisecond
+		config.Timeouts.Put = 5 * time.Second
+		c = client.NewClient(config, zap.NewNop())
+
+		require.NoError(t, c.PutEndpoint(context.Background(), "services/1234", item))
+		require.NoError(t, c.PatchEndpoint(context.Background(), "services/1234", item))
+	})
+}
+
+func TestOperatorIdentity(t *testing.T) {
+	var headers []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	t.Run("verify operator identity header is sent on every request", func(t *testing.T) {
+		headers = nil
+		config := newTestConfig(server.URL)
+		config.OperatorIdentity = "ops@example.com"
+		config.OperatorIdentityHeader = "X-Acting-As"
+		c := client.NewClient(config, zap.NewNop())
+
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.NoError(t, c.DeleteEndpoint(context.Background(), "services/1234"))
+		require.Len(t, headers, 2)
+		for _, header := range headers {
+			require.Equal(t, "ops@example.com", header.Get("X-Acting-As"))
+		}
+	})
+
+	t.Run("verify operator identity header is not sent when identity is not set", func(t *testing.T) {
+		headers = nil
+		config := newTestConfig(server.URL)
+		config.OperatorIdentityHeader = "X-Acting-As"
+		c := client.NewClient(config, zap.NewNop())
+
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Len(t, headers, 1)
+		require.Empty(t, headers[0].Values("X-Acting-As"))
+	})
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+var (
+	// ErrControlPlaneNotFound is returned when no control plane matches the
+	// name being resolved.
+	ErrControlPlaneNotFound = errors.New("control plane not found")
+	// ErrControlPlaneAmbiguous is returned when more than one control plane
+	// matches the name being resolved.
+	ErrControlPlaneAmbiguous = errors.New("control plane name is ambiguous")
+)
+
+// LookupControlPlaneID resolves the ID of a control plane from its name using
+// the control planes list endpoint (the base URL of the admin API).
+func (c *Client) LookupControlPlaneID(ctx context.Context, name string) (uuid.UUID, error) {
+	lookupURL := fmt.Sprintf("%s?filter[name][eq]=%s", c.rootURL, url.QueryEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, lookupURL, nil)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error making request: %w",
+			&RequestError{Method: http.MethodGet, URL: lookupURL, Err: err})
+	}
+	//nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return uuid.Nil, &RequestError{Method: http.MethodGet, URL: lookupURL, StatusCode: resp.StatusCode}
+	}
+
+	listResp := struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}{}
+	body := &io.LimitedReader{R: resp.Body, N: c.maxResponseBytes + 1}
+	err = json.NewDecoder(body).Decode(&listResp)
+	if body.N <= 0 {
+		return uuid.Nil, &ResponseTooLargeError{URL: lookupURL, MaxBytes: c.maxResponseBytes}
+	}
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("error decoding response: %w", err)
+	}
+
+	// Only exact matches are considered in case the filter is not supported
+	var ids []string
+	for _, controlPlane := range listResp.Data {
+		if controlPlane.Name == name {
+			ids = append(ids, controlPlane.ID)
+		}
+	}
+	switch len(ids) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("%w: %q", ErrControlPlaneNotFound, name)
+	case 1:
+	default:
+		return uuid.Nil, fmt.Errorf("%w: %q matches %d control planes", ErrControlPlaneAmbiguous, name, len(ids))
+	}
+
+	id, err := uuid.Parse(ids[0])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid ID for control plane %q: %w", name, err)
+	}
+	c.logger.Info("Resolved control plane ID from name",
+		zap.String("control-plane-name", name),
+		zap.String("resolved-control-plane-id", id.String()))
+	return id, nil
+}
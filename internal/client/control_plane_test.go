@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestLookupControlPlaneID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/control-planes", r.URL.Path)
+		require.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("filter[name][eq]") {
+		case "production":
+			_, _ = w.Write([]byte(`{"data":[{"id":"37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b","name":"production"}]}`))
+		case "staging":
+			_, _ = w.Write([]byte(`{"data":[` +
+				`{"id":"4168295f-015e-4190-837e-0fcc5d72a52f","name":"staging"},` +
+				`{"id":"6c2c1b0e-8f3a-4a5e-9d2b-1f0e3c4b5a69","name":"staging"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	newLookupClient := func() *client.Client {
+		config := newTestConfig(server.URL + "/control-planes")
+		config.BearerToken = "test-token"
+		config.ControlPlaneID = uuid.Nil
+		return client.NewClient(config, zap.NewNop())
+	}
+
+	t.Run("verify control plane name is resolved to an ID", func(t *testing.T) {
+		id, err := newLookupClient().LookupControlPlaneID(context.Background(), "production")
+		require.NoError(t, err)
+		require.Equal(t, uuid.MustParse("37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b"), id)
+	})
+
+	t.Run("verify unknown control plane name returns error", func(t *testing.T) {
+		_, err := newLookupClient().LookupControlPlaneID(context.Background(), "unknown")
+		require.ErrorIs(t, err, client.ErrControlPlaneNotFound)
+	})
+
+	t.Run("verify ambiguous control plane name returns error", func(t *testing.T) {
+		_, err := newLookupClient().LookupControlPlaneID(context.Background(), "staging")
+		require.ErrorIs(t, err, client.ErrControlPlaneAmbiguous)
+	})
+}
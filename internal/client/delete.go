@@ -30,8 +30,8 @@ import (
 func (c *Client) DeleteEndpoint(ctx context.Context, endpointWithID string) error {
 	url := fmt.Sprintf("%s/%s", c.baseURL, endpointWithID)
 
-	// Keep trying until successful or an error occurs
-	for {
+	// Keep trying until successful, an error occurs, or retries are exhausted
+	for attempt := 1; ; attempt++ {
 		if err := ctx.Err(); err != nil {
 			c.logger.Warn("Context canceled during delete operation",
 				zap.String("url", url),
@@ -47,35 +47,60 @@ func (c *Client) DeleteEndpoint(ctx context.Context, endpointWithID string) erro
 		// Set the Authorization header with the bearer token and execute the request
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
 		startTime := time.Now()
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			c.logger.Error("error making request",
 				zap.String("url", url),
 				zap.Duration("request-duration", time.Since(startTime)),
 				zap.Error(err))
-			return fmt.Errorf("error making request: %w", err)
+			return fmt.Errorf("error making request: %w",
+				&RequestError{Method: http.MethodDelete, URL: url, Err: err})
 		}
 		//nolint: errcheck
-		defer resp.Body.Close()
+		resp.Body.Close()
 
-		switch resp.StatusCode {
-		case http.StatusNoContent:
+		switch {
+		case resp.StatusCode == http.StatusNoContent:
 			c.logger.Debug("Deleted item",
 				zap.String("url", url),
 				zap.Duration("request-duration", time.Since(startTime)))
 			return nil
-		case http.StatusTooManyRequests:
-			retryDuration := c.retryAfterDuration(resp)
+		case resp.StatusCode == http.StatusTooManyRequests:
+			retryDuration := c.retryAfterDuration(resp, attempt)
 			c.logger.Warn("Rate limit exceeded; retrying",
 				zap.String("url", url),
+				zap.Int("attempt", attempt),
 				zap.Duration("retry-after", retryDuration))
-			time.Sleep(retryDuration)
+			if err := c.waitForRetry(ctx, attempt, retryDuration); err != nil {
+				c.logger.Error("error waiting to retry delete",
+					zap.String("url", url),
+					zap.Int("attempt", attempt),
+					zap.Error(err))
+				return fmt.Errorf("unable to delete item %s: %w", endpointWithID, err)
+			}
+			continue
+		case isServerError(resp.StatusCode):
+			retryDuration := c.backoff.Next(attempt)
+			c.logger.Warn("Server error; retrying",
+				zap.String("url", url),
+				zap.Int("status-code", resp.StatusCode),
+				zap.Int("attempt", attempt),
+				zap.Duration("retry-after", retryDuration))
+			if err := c.waitForRetry(ctx, attempt, retryDuration); err != nil {
+				c.logger.Error("error waiting to retry delete",
+					zap.String("url", url),
+					zap.Int("attempt", attempt),
+					zap.Error(err))
+				return fmt.Errorf("unable to delete item %s: %w: %w", endpointWithID, err,
+					&RequestError{Method: http.MethodDelete, URL: url, StatusCode: resp.StatusCode})
+			}
 			continue
 		default:
 			c.logger.Error("error deleting item",
 				zap.String("url", url),
 				zap.Int("status-code", resp.StatusCode))
-			return fmt.Errorf("unable to delete item %s: status code %d", endpointWithID, resp.StatusCode)
+			return fmt.Errorf("unable to delete item %s: %w", endpointWithID,
+				&RequestError{Method: http.MethodDelete, URL: url, StatusCode: resp.StatusCode})
 		}
 	}
 }
@@ -0,0 +1,157 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/backoff"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestConfig(baseURL string) *config.Config {
+	return &config.Config{
+		BaseURL:        baseURL,
+		ControlPlaneID: uuid.New(),
+		Retries: config.Retries{
+			MaxAttempts: 10,
+			MaxWait:     time.Minute,
+		},
+	}
+}
+
+// newTestBackoff creates a backoff waiting a millisecond between attempts so
+// that retried requests do not slow down the tests.
+func newTestBackoff(t *testing.T) *backoff.Backoff {
+	t.Helper()
+	b, err := backoff.New(config.Backoff{Strategy: string(backoff.StrategyConstant), Base: time.Millisecond})
+	require.NoError(t, err)
+	return b
+}
+
+func TestDeleteEndpoint(t *testing.T) {
+	t.Run("verify item is deleted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodDelete, r.Method)
+			require.Contains(t, r.URL.Path, "/services/1234")
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		require.NoError(t, c.DeleteEndpoint(context.Background(), "services/1234"))
+	})
+
+	t.Run("verify rate limited delete is retried", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		require.NoError(t, c.DeleteEndpoint(context.Background(), "services/1234"))
+		require.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("verify context cancellation during rate limit wait returns promptly", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(100*time.Millisecond, cancel)
+
+		startTime := time.Now()
+		err := c.DeleteEndpoint(ctx, "services/1234")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Less(t, time.Since(startTime), 5*time.Second)
+	})
+
+	t.Run("verify operation deadline is respected during rate limit wait", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+
+		startTime := time.Now()
+		err := c.DeleteEndpoint(ctx, "services/1234")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(startTime), 5*time.Second)
+	})
+
+	t.Run("verify retries are exhausted", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.Retries.MaxAttempts = 3
+		c := client.NewClient(config, zap.NewNop())
+		err := c.DeleteEndpoint(context.Background(), "services/1234")
+		var errRetries *client.RetriesExhaustedError
+		require.ErrorAs(t, err, &errRetries)
+		require.Equal(t, 3, errRetries.Attempts)
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("verify rate limited delete without Retry-After uses the backoff", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		b, err := backoff.New(config.Backoff{Strategy: "constant", Base: 10 * time.Millisecond})
+		require.NoError(t, err)
+		c.SetBackoff(b)
+		startTime := time.Now()
+		require.NoError(t, c.DeleteEndpoint(context.Background(), "services/1234"))
+		require.Equal(t, int32(3), requests.Load())
+		require.Less(t, time.Since(startTime), time.Second)
+	})
+}
@@ -0,0 +1,77 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newUnansweredListener creates a local listener that never accepts and
+// whose accept queue is full so that further connection attempts are never
+// answered; the address of the listener is returned.
+func newUnansweredListener(t *testing.T) string {
+	t.Helper()
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_STREAM, 0)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = syscall.Close(fd) })
+	require.NoError(t, syscall.Bind(fd, &syscall.SockaddrInet4{Addr: [4]byte{127, 0, 0, 1}}))
+	require.NoError(t, syscall.Listen(fd, 0))
+	sockaddr, err := syscall.Getsockname(fd)
+	require.NoError(t, err)
+	address := fmt.Sprintf("127.0.0.1:%d", sockaddr.(*syscall.SockaddrInet4).Port)
+
+	// Fill the accept queue until a connection attempt is no longer answered
+	for range 16 {
+		conn, err := net.DialTimeout("tcp", address, 100*time.Millisecond)
+		if err != nil {
+			var errNet net.Error
+			require.ErrorAs(t, err, &errNet)
+			require.True(t, errNet.Timeout())
+			return address
+		}
+		t.Cleanup(func() { _ = conn.Close() })
+	}
+	require.FailNow(t, "accept queue of the listener was not filled")
+	return ""
+}
+
+func TestDialTimeout(t *testing.T) {
+	t.Run("verify connecting to an unanswered host fails within the dial timeout", func(t *testing.T) {
+		config := newTestConfig("http://" + newUnansweredListener(t))
+		config.Timeouts.Dial = 200 * time.Millisecond
+		config.Retries.MaxAttempts = 1
+		c := client.NewClient(config, zap.NewNop())
+
+		startTime := time.Now()
+		_, err := c.GetEndpoint(context.Background(), "services")
+		elapsed := time.Since(startTime)
+		var errNet net.Error
+		require.True(t, errors.As(err, &errNet), "expected a network error: %v", err)
+		require.True(t, errNet.Timeout())
+		require.GreaterOrEqual(t, elapsed, 200*time.Millisecond)
+		require.Less(t, elapsed, 2*time.Second)
+	})
+}
@@ -16,10 +16,49 @@ limitations under the License.
 package client
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
+// ErrPartialPages is returned along with the pages retrieved before a page
+// request failed when partial pages are enabled.
+var ErrPartialPages = errors.New("partial pages retrieved")
+
+// ErrTotalMismatch is returned in strict mode when the number of items
+// retrieved from an endpoint does not match the total reported by the
+// endpoint (e.g. a dropped page).
+var ErrTotalMismatch = errors.New("item count does not match the reported total")
+
+// ErrForbidden is returned when the bearer token is not authorized to list an
+// endpoint (e.g. a token scoped to some resources).
+var ErrForbidden = errors.New("forbidden: bearer token is not authorized for the endpoint")
+
+// ErrNotFound is returned when the item to patch does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrTooManyRedirects is returned when a request is redirected more than the
+// maximum number of redirects.
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// ErrCrossHostRedirect is returned when a request is redirected to a different
+// host and cross-host redirects are not allowed.
+var ErrCrossHostRedirect = errors.New("cross-host redirect refused")
+
+// ErrInsecureRedirect is returned when a request using HTTPS is redirected to
+// HTTP.
+var ErrInsecureRedirect = errors.New("insecure redirect refused")
+
+// ErrForeignNextURL is returned when the next page URL of a response refers
+// to a scheme or host other than the base URL; the bearer token is not sent
+// to another host.
+var ErrForeignNextURL = errors.New("next page URL refers to a different host")
+
+// ErrNotAdminAPI is returned by the preflight when the base URL looks like the
+// proxy or a UI rather than the admin API.
+var ErrNotAdminAPI = errors.New("base URL is not the admin API")
+
 // RateLimitError represent a rate limit error.
 type RateLimitError struct {
 	// RetryAfter is the duration to wait before retrying the request
@@ -31,3 +70,115 @@ type RateLimitError struct {
 func (e *RateLimitError) Error() string {
 	return fmt.Sprintf("rate limited, retry after %s", e.RetryAfter)
 }
+
+// TruncatedResponseError represents a response body that ended, or failed to
+// be read, before the complete JSON document was received (e.g. a dropped
+// connection).
+type TruncatedResponseError struct {
+	// URL is the URL of the request.
+	URL string
+	// Err is the underlying decode error.
+	Err error
+}
+
+// Error implements the error interface for TruncatedResponseError.
+func (e *TruncatedResponseError) Error() string {
+	return fmt.Sprintf("truncated response from %s: %s", e.URL, e.Err)
+}
+
+// Unwrap returns the underlying decode error.
+func (e *TruncatedResponseError) Unwrap() error {
+	return e.Err
+}
+
+// RetriesExhaustedError represents a request that was not successful after
+// the maximum number of attempts.
+type RetriesExhaustedError struct {
+	// Attempts is the number of attempts made before giving up.
+	Attempts int
+}
+
+// Error implements the error interface for RetriesExhaustedError.
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("retries exhausted after %d attempts", e.Attempts)
+}
+
+// ResponseTooLargeError represents a response whose body exceeds the maximum
+// response size.
+type ResponseTooLargeError struct {
+	// URL is the URL of the request.
+	URL string
+	// MaxBytes is the maximum response size in bytes.
+	MaxBytes int64
+}
+
+// Error implements the error interface for ResponseTooLargeError.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("response from %s exceeds the maximum response size of %d bytes (max_response_bytes)",
+		e.URL, e.MaxBytes)
+}
+
+// PageGap is a page of an endpoint that failed and was skipped.
+type PageGap struct {
+	// URL is the URL of the page.
+	URL string
+	// Page is the number of the page (starting at one).
+	Page int
+	// Err is the error of the page request.
+	Err error
+}
+
+// PageGapsError represents the failed pages of an endpoint that were skipped
+// when skipping failed pages; the remaining pages were retrieved. It wraps
+// ErrPartialPages so that the pages retrieved are retained.
+type PageGapsError struct {
+	// Endpoint is the endpoint of the pages.
+	Endpoint string
+	// Gaps are the skipped pages in order.
+	Gaps []PageGap
+}
+
+// Error implements the error interface for PageGapsError.
+func (e *PageGapsError) Error() string {
+	gaps := make([]string, 0, len(e.Gaps))
+	for _, gap := range e.Gaps {
+		gaps = append(gaps, fmt.Sprintf("page %d (%s): %v", gap.Page, gap.URL, gap.Err))
+	}
+	return fmt.Sprintf("%s: skipped %d failed pages of endpoint %s: %s",
+		ErrPartialPages, len(e.Gaps), e.Endpoint, strings.Join(gaps, "; "))
+}
+
+// Unwrap returns ErrPartialPages along with the errors of the skipped pages.
+func (e *PageGapsError) Unwrap() []error {
+	errs := []error{ErrPartialPages}
+	for _, gap := range e.Gaps {
+		errs = append(errs, gap.Err)
+	}
+	return errs
+}
+
+// RequestError represents a failed request to the admin API.
+type RequestError struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// URL is the URL of the request.
+	URL string
+	// StatusCode is the status code of the response; zero if no response was
+	// received.
+	StatusCode int
+	// Err is the underlying error, if any.
+	Err error
+}
+
+// Error implements the error interface for RequestError.
+func (e *RequestError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s %s: %v", e.Method, e.URL, e.Err)
+	}
+	return fmt.Sprintf("%s %s: unhandled status code: %d", e.Method, e.URL, e.StatusCode)
+}
+
+// Unwrap returns the underlying error.
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
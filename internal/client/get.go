@@ -16,30 +16,103 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/mikefero/osiris/internal/event"
 	"go.uber.org/zap"
 )
 
+// PageFunc is called with the items of each page retrieved from an
+// endpoint; the items are not retained by the client once it returns.
+type PageFunc func(items []map[string]interface{}) error
+
 // GetEndpoint retrieves all data from a specified endpoint, handling
 // pagination and rate limiting. It returns a slice of maps containing the
-// data from the endpoint, or an error if the request fails.
+// data from the endpoint, or an error if the request fails. When partial
+// pages are enabled and a page request fails after the first page, the pages
+// already retrieved are returned along with an error wrapping
+// ErrPartialPages.
 func (c *Client) GetEndpoint(ctx context.Context, endpoint string) ([]map[string]interface{}, error) {
-	endpointURL := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
 	var result []map[string]interface{}
+	err := c.StreamEndpoint(ctx, endpoint, func(items []map[string]interface{}) error {
+		result = append(result, items...)
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrPartialPages) {
+			return result, err
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamEndpoint retrieves all data from a specified endpoint page by page,
+// handling pagination and rate limiting; the items of each page are passed to
+// the page function as they are retrieved so that only a single page is held
+// in memory. An error returned by the page function stops the pagination.
+// When partial pages are enabled and a page request fails after the first
+// page, an error wrapping ErrPartialPages is returned. When skipping failed
+// pages, a page that fails is skipped and the pagination continues with the
+// following page; a *PageGapsError recording the skipped pages is returned
+// once the remaining pages are retrieved.
+func (c *Client) StreamEndpoint(ctx context.Context, endpoint string, fn PageFunc) error {
+	endpointURL := fmt.Sprintf("%s/%s", c.baseURL, endpoint)
 
 	c.logger.Debug("Getting endpoint",
 		zap.String("endpoint", endpoint),
 		zap.String("endpoint-url", endpointURL))
 
 	pageCount := 0
+	itemCount := 0
+	attempt := 0
 	pageURL := endpointURL
 	startTime := time.Now()
+	var gaps []PageGap
+	skipped := 0
+	total := -1
+
+	// Resume from the checkpointed progress of the endpoint, if any; the
+	// resumed items are recorded in the raw output since the checkpoint holds
+	// the items as they were retrieved
+	c.raw.reset(endpoint)
+	if c.checkpoint != nil {
+		if state, ok := c.checkpoint.endpoint(endpoint); ok {
+			c.logger.Info("Resuming endpoint from checkpoint",
+				zap.String("endpoint", endpoint),
+				zap.String("page-url", state.next),
+				zap.Int("item-count", len(state.items)),
+				zap.Bool("complete", state.complete))
+			if len(state.items) > 0 {
+				if c.raw != nil {
+					c.raw.record(endpoint, copyItems(state.items))
+				}
+				if err := fn(state.items); err != nil {
+					return err
+				}
+			}
+			if state.complete {
+				return nil
+			}
+			itemCount = len(state.items)
+			pageURL = state.next
+		}
+	}
+	if c.checkpoint != nil {
+		if err := c.checkpoint.restart(endpoint, itemCount, pageURL); err != nil {
+			return fmt.Errorf("error getting endpoint %s: %w", endpoint, err)
+		}
+	}
 	for len(pageURL) > 0 {
 		requestStartTime := time.Now()
 		if err := ctx.Err(); err != nil {
@@ -47,21 +120,85 @@ func (c *Client) GetEndpoint(ctx context.Context, endpoint string) ([]map[string
 				zap.String("endpoint", endpoint),
 				zap.String("endpoint-url", endpointURL),
 				zap.Error(err))
-			return nil, err
+			return err
 		}
 
 		pageCount++
+		attempt++
 		c.logger.Debug("Getting page",
 			zap.String("endpoint", endpoint),
 			zap.String("page-url", pageURL),
 			zap.Int("page-number", pageCount))
 
-		data, nextPageURL, err := c.getEndpointPage(ctx, pageURL)
+		page, err := c.getEndpointPage(ctx, pageURL, attempt)
+		data, nextPageURL := page.items, page.next
 		if err != nil {
+			// A truncated response is retried using the backoff since the
+			// request is idempotent
+			var errTruncated *TruncatedResponseError
+			if errors.As(err, &errTruncated) {
+				retryAfter := c.backoff.Next(attempt)
+				c.logger.Warn("Truncated response; retrying",
+					zap.String("endpoint", endpoint),
+					zap.String("page-url", pageURL),
+					zap.Int("page-number", pageCount),
+					zap.Int("attempt", attempt),
+					zap.Duration("retry-after", retryAfter),
+					zap.Error(errTruncated.Err))
+				if err := c.waitForRetry(ctx, attempt, retryAfter); err != nil {
+					return fmt.Errorf("error getting endpoint %s: %w: %w", endpoint, err, errTruncated)
+				}
+				pageCount--
+				continue
+			}
+
+			// A server error is retried using the backoff since the request is
+			// idempotent; once the attempts are exhausted the page is handled
+			// as any other failed page
+			var errRequest *RequestError
+			if errors.As(err, &errRequest) && isServerError(errRequest.StatusCode) && attempt < c.maxAttempts {
+				retryAfter := c.backoff.Next(attempt)
+				c.logger.Warn("Server error; retrying",
+					zap.String("endpoint", endpoint),
+					zap.String("page-url", pageURL),
+					zap.Int("page-number", pageCount),
+					zap.Int("status-code", errRequest.StatusCode),
+					zap.Int("attempt", attempt),
+					zap.Duration("retry-after", retryAfter))
+				if err := c.waitForRetry(ctx, attempt, retryAfter); err != nil {
+					return fmt.Errorf("error getting endpoint %s: %w: %w", endpoint, err, errRequest)
+				}
+				pageCount--
+				continue
+			}
+
 			// Check if the error is a RateLimitError
 			errRateLimit, ok := err.(*RateLimitError)
 			if !ok {
-				return nil, fmt.Errorf("error getting endpoint %s: %w", endpoint, err)
+				if nextPageURL, ok := c.skipFailedPage(ctx, pageURL, err, skipped); ok {
+					c.logger.Warn("Error getting page; skipping the page",
+						zap.String("endpoint", endpoint),
+						zap.String("page-url", pageURL),
+						zap.Int("page-number", pageCount),
+						zap.String("next-page-url", nextPageURL),
+						zap.Error(err))
+					gaps = append(gaps, PageGap{URL: pageURL, Page: pageCount, Err: err})
+					skipped++
+					attempt = 0
+					pageURL = nextPageURL
+					continue
+				}
+				if c.partialPages && itemCount > 0 {
+					c.logger.Warn("Error getting page; returning partial pages",
+						zap.String("endpoint", endpoint),
+						zap.String("page-url", pageURL),
+						zap.Int("page-number", pageCount),
+						zap.Int("item-count", itemCount),
+						zap.Error(err))
+					return fmt.Errorf("error getting endpoint %s after %d pages: %w: %w",
+						endpoint, pageCount-1, ErrPartialPages, err)
+				}
+				return fmt.Errorf("error getting endpoint %s: %w", endpoint, err)
 			}
 
 			// Handle rate limit Retry-After duration
@@ -69,18 +206,30 @@ func (c *Client) GetEndpoint(ctx context.Context, endpoint string) ([]map[string
 				zap.String("endpoint", endpoint),
 				zap.String("page-url", pageURL),
 				zap.Int("page-number", pageCount),
+				zap.Int("attempt", attempt),
 				zap.Duration("retry-after", errRateLimit.RetryAfter),
 				zap.Duration("request-duration", time.Since(requestStartTime)))
 
-			time.Sleep(errRateLimit.RetryAfter)
+			if err := c.waitForRetry(ctx, attempt, errRateLimit.RetryAfter); err != nil {
+				return fmt.Errorf("error getting endpoint %s: %w", endpoint, err)
+			}
+			pageCount--
 			continue
 		}
+		attempt = 0
+		skipped = 0
 
+		// The total is captured before an empty page ends the listing so that
+		// a reported total of zero is counted
+		if page.total >= 0 && total < 0 {
+			c.countTotal(endpoint, page.total)
+			total = page.total
+		}
 		if len(data) == 0 {
 			c.logger.Debug("No data found for endpoint",
 				zap.String("endpoint-url", pageURL),
 				zap.Duration("request-duration", time.Since(requestStartTime)))
-			return nil, nil
+			break
 		}
 
 		c.logger.Debug("Retrieved data from page",
@@ -90,7 +239,16 @@ func (c *Client) GetEndpoint(ctx context.Context, endpoint string) ([]map[string
 			zap.Int("item-count", len(data)),
 			zap.Duration("request-duration", time.Since(requestStartTime)))
 
-		result = append(result, data...)
+		if c.checkpoint != nil {
+			if err := c.checkpoint.record(endpoint, data, nextPageURL); err != nil {
+				return fmt.Errorf("error getting endpoint %s: %w", endpoint, err)
+			}
+		}
+		c.raw.record(endpoint, page.raw)
+		itemCount += len(data)
+		if err := fn(data); err != nil {
+			return err
+		}
 
 		if len(nextPageURL) == 0 {
 			c.logger.Debug("No more pages to get",
@@ -104,28 +262,113 @@ func (c *Client) GetEndpoint(ctx context.Context, endpoint string) ([]map[string
 	c.logger.Debug("Retrieved all pages",
 		zap.String("endpoint", endpoint),
 		zap.Int("total-pages", pageCount),
-		zap.Int("total-items", len(result)),
+		zap.Int("total-items", itemCount),
 		zap.Duration("get-duration", time.Since(startTime)))
 
-	return result, nil
+	if len(gaps) > 0 {
+		c.logger.Warn("Retrieved pages with gaps",
+			zap.String("endpoint", endpoint),
+			zap.Int("skipped-pages", len(gaps)))
+		return &PageGapsError{Endpoint: endpoint, Gaps: gaps}
+	}
+	return c.checkTotal(endpoint, total, itemCount)
 }
 
-func (c *Client) getEndpointPage(ctx context.Context, url string) ([]map[string]interface{}, string, error) {
+// checkTotal compares the number of items retrieved from the endpoint to the
+// total reported by the endpoint, if any; a mismatch indicates a dropped page
+// or pagination that terminated early. The mismatch is logged as a warning
+// or, in strict mode, returned as an error wrapping ErrTotalMismatch.
+func (c *Client) checkTotal(endpoint string, total int, itemCount int) error {
+	if total < 0 || itemCount == total {
+		return nil
+	}
+	if c.strictTotals {
+		return fmt.Errorf("error getting endpoint %s: %w: retrieved %d items, reported total %d",
+			endpoint, ErrTotalMismatch, itemCount, total)
+	}
+	c.logger.Warn("Item count does not match reported total",
+		zap.String("endpoint", endpoint),
+		zap.Int("total", total),
+		zap.Int("item-count", itemCount))
+	return nil
+}
+
+// countTotal records the total number of items reported by the endpoint and
+// reports it in the event stream so that progress can be estimated.
+func (c *Client) countTotal(endpoint string, total int) {
+	c.logger.Debug("Total count reported for endpoint",
+		zap.String("endpoint", endpoint),
+		zap.Int("total", total))
+	c.totals.set(endpoint, total)
+	c.events.Emit(event.Event{
+		Event:    event.TotalCounted,
+		Endpoint: endpoint,
+		Total:    &total,
+	})
+}
+
+// pageNumberParameters are the query parameters of numbered pagination; the
+// page following a failed page is requested by incrementing the parameter.
+var pageNumberParameters = []string{"page", "page[number]", "page.number"}
+
+// skipFailedPage returns the URL of the page following the failed page if
+// failed pages are skipped and the failed page can be skipped. A page can
+// only be skipped if its URL is numbered since an opaque cursor (e.g. an
+// offset) for the following page is only known from the failed response;
+// authorization errors and canceled requests are never skipped, and
+// consecutive failed pages are limited to the maximum number of attempts.
+func (c *Client) skipFailedPage(ctx context.Context, pageURL string, err error, skipped int) (string, bool) {
+	if !c.skipFailedPages || ctx.Err() != nil || errors.Is(err, ErrForbidden) || skipped+1 >= c.maxAttempts {
+		return "", false
+	}
+	parsed, parseErr := url.Parse(pageURL)
+	if parseErr != nil {
+		return "", false
+	}
+	query := parsed.Query()
+	for _, parameter := range pageNumberParameters {
+		page, convErr := strconv.Atoi(query.Get(parameter))
+		if convErr != nil {
+			continue
+		}
+		query.Set(parameter, strconv.Itoa(page+1))
+		parsed.RawQuery = query.Encode()
+		return parsed.String(), true
+	}
+	return "", false
+}
+
+// endpointPage is a page of items retrieved from an endpoint.
+type endpointPage struct {
+	// items are the items of the page.
+	items []map[string]interface{}
+	// raw are the unmodified items of the page; only captured when recording
+	// the raw output.
+	raw []map[string]interface{}
+	// next is the URL of the next page; empty if there are no more pages.
+	next string
+	// total is the total number of items of the endpoint reported by the
+	// page; -1 if not reported.
+	total int
+}
+
+func (c *Client) getEndpointPage(ctx context.Context, url string, attempt int) (endpointPage, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, "", fmt.Errorf("error creating request: %w", err)
+		return endpointPage{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	// Set the Authorization header with the bearer token and execute the request
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
 	startTime := time.Now()
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		c.logger.Error("error making request",
 			zap.String("url", url),
 			zap.Duration("request-duration", time.Since(startTime)),
 			zap.Error(err))
-		return nil, "", fmt.Errorf("error making request: %w", err)
+		return endpointPage{}, fmt.Errorf("error making request: %w",
+			&RequestError{Method: http.MethodGet, URL: url, Err: err})
 	}
 	//nolint: errcheck
 	defer resp.Body.Close()
@@ -140,52 +383,102 @@ func (c *Client) getEndpointPage(ctx context.Context, url string) ([]map[string]
 	switch resp.StatusCode {
 	case http.StatusOK:
 		pageResp := struct {
-			Data []map[string]interface{} `json:"data"`
-			Next string                   `json:"next"`
+			Next string `json:"next"`
 
-			Items []map[string]interface{} `json:"items"`
-			Page  struct {
+			Page struct {
 				HasNextPage bool   `json:"has_next_page"`
-				TotalCount  int    `json:"total_count"`
+				TotalCount  *int   `json:"total_count"`
 				NextCursor  string `json:"next_cursor"`
 			} `json:"page"`
+
+			Meta struct {
+				Page struct {
+					Total *int `json:"total"`
+				} `json:"page"`
+			} `json:"meta"`
 		}{}
-		if err := json.NewDecoder(resp.Body).Decode(&pageResp); err != nil {
+		// Decode numbers as json.Number so that large integers (e.g. 64-bit IDs
+		// and timestamps) round-trip exactly; the body is bounded to guard
+		// against exhausting memory
+		body := &io.LimitedReader{R: resp.Body, N: c.maxResponseBytes + 1}
+		decoder := json.NewDecoder(body)
+		decoder.UseNumber()
+		var fields map[string]json.RawMessage
+		err := decoder.Decode(&fields)
+		if body.N <= 0 {
+			c.logger.Error("response exceeds maximum size",
+				zap.String("url", url),
+				zap.Int64("max-response-bytes", c.maxResponseBytes))
+			return endpointPage{}, &ResponseTooLargeError{URL: url, MaxBytes: c.maxResponseBytes}
+		}
+		if err != nil {
 			c.logger.Error("error decoding response",
 				zap.String("url", url),
 				zap.Error(err))
-			return nil, "", fmt.Errorf("error decoding response: %w", err)
+			if isTruncated(err) {
+				return endpointPage{}, &TruncatedResponseError{URL: url, Err: err}
+			}
+			return endpointPage{}, fmt.Errorf("error decoding response: %w", err)
+		}
+		for key, value := range map[string]interface{}{
+			"next": &pageResp.Next,
+			"page": &pageResp.Page,
+			"meta": &pageResp.Meta,
+		} {
+			raw, ok := fields[key]
+			if !ok {
+				continue
+			}
+			if err := json.Unmarshal(raw, value); err != nil {
+				c.logger.Error("error decoding response pagination",
+					zap.String("url", url),
+					zap.Error(err))
+				return endpointPage{}, fmt.Errorf("error decoding response: %w", err)
+			}
+		}
+
+		data, err := c.decodeData(fields)
+		if err != nil {
+			c.logger.Error("error decoding response data",
+				zap.String("url", url),
+				zap.Error(err))
+			return endpointPage{}, fmt.Errorf("error decoding response: %w", err)
+		}
+
+		// Capture the unmodified items before any fields are removed
+		var raw []map[string]interface{}
+		if c.raw != nil {
+			raw = copyItems(data)
 		}
 
 		// Remove unwanted fields from each item
-		if len(pageResp.Data) > 0 {
-			for _, item := range pageResp.Data {
-				delete(item, "updated_at")
-				delete(item, "created_at")
-			}
-		} else if len(pageResp.Items) > 0 {
-			// Handle v1 API response
-			for _, item := range pageResp.Items {
+		if !c.includeMeta {
+			for _, item := range data {
 				delete(item, "updated_at")
 				delete(item, "created_at")
 			}
-			pageResp.Data = pageResp.Items
 		}
 
 		c.logger.Debug("Parsed response",
 			zap.String("url", url),
 			zap.String("next", pageResp.Next),
-			zap.Int("item-count", len(pageResp.Data)),
+			zap.Int("item-count", len(data)),
 			zap.Duration("parse-duration", time.Since(startTime)))
 
 		// Determine the next URL to request
 		var nextURL string
 		if len(pageResp.Next) > 0 {
-			nextURL = fmt.Sprintf("%s/%s", c.baseURL, strings.TrimPrefix(pageResp.Next, "/"))
+			if nextURL, err = c.resolveNextURL(pageResp.Next); err != nil {
+				c.logger.Error("error resolving next page URL",
+					zap.String("url", url),
+					zap.String("next", pageResp.Next),
+					zap.Error(err))
+				return endpointPage{}, err
+			}
 			c.logger.Debug("Next URL found",
 				zap.String("url", url),
 				zap.String("next-url", nextURL))
-		} else if pageResp.Page.HasNextPage {
+		} else if c.cursorPagination && pageResp.Page.HasNextPage {
 			// Handle v1 API pagination with cursor
 			nextURL = fmt.Sprintf("%s?page.next_cursor=%s", url, pageResp.Page.NextCursor)
 			c.logger.Debug("Next URL found with cursor",
@@ -193,22 +486,122 @@ func (c *Client) getEndpointPage(ctx context.Context, url string) ([]map[string]
 				zap.String("next-url", nextURL))
 		}
 
-		return pageResp.Data, nextURL, nil
+		// Capture the total number of items of the endpoint, if reported (v1
+		// page or meta page shapes)
+		total := -1
+		switch {
+		case pageResp.Page.TotalCount != nil:
+			total = *pageResp.Page.TotalCount
+		case pageResp.Meta.Page.Total != nil:
+			total = *pageResp.Meta.Page.Total
+		}
+
+		return endpointPage{items: data, raw: raw, next: nextURL, total: total}, nil
 	case http.StatusTooManyRequests:
-		retryDuration := c.retryAfterDuration(resp)
+		retryDuration := c.retryAfterDuration(resp, attempt)
 		c.logger.Warn("Rate limit exceeded; retrying",
 			zap.String("url", url),
 			zap.Duration("retry-after", retryDuration))
-		return nil, url, &RateLimitError{RetryAfter: retryDuration}
+		return endpointPage{}, &RateLimitError{RetryAfter: retryDuration}
+	case http.StatusForbidden:
+		c.logger.Error("Not authorized for endpoint",
+			zap.String("url", url),
+			zap.Int("status-code", resp.StatusCode))
+		return endpointPage{}, &RequestError{Method: http.MethodGet, URL: url, StatusCode: resp.StatusCode, Err: ErrForbidden}
 	case http.StatusNotFound:
 		c.logger.Error("Endpoint not found",
 			zap.String("url", url),
 			zap.Int("status-code", resp.StatusCode))
-		return nil, "", nil
+		return endpointPage{}, nil
 	default:
 		c.logger.Error("unhandled status code",
 			zap.String("url", url),
 			zap.Int("status-code", resp.StatusCode))
-		return nil, "", fmt.Errorf("unhandled status code: %d", resp.StatusCode)
+		return endpointPage{}, &RequestError{Method: http.MethodGet, URL: url, StatusCode: resp.StatusCode}
+	}
+}
+
+// resolveNextURL returns the URL of the next page. An absolute next URL is
+// used as is when it has the scheme and host of the base URL and refused
+// otherwise, a path already including the base URL path or the control plane
+// segment is resolved against the host or root URL respectively, and any
+// other path is resolved relative to the base URL.
+func (c *Client) resolveNextURL(next string) (string, error) {
+	base, baseErr := url.Parse(c.baseURL)
+	if nextURL, err := url.Parse(next); err == nil && nextURL.IsAbs() {
+		if baseErr != nil || !strings.EqualFold(nextURL.Scheme, base.Scheme) ||
+			!strings.EqualFold(nextURL.Host, base.Host) {
+			return "", fmt.Errorf("%w: %s", ErrForeignNextURL, next)
+		}
+		return next, nil
+	}
+	path := strings.TrimPrefix(next, "/")
+	if baseErr == nil && strings.HasPrefix("/"+path, base.Path+"/") {
+		return fmt.Sprintf("%s://%s/%s", base.Scheme, base.Host, path), nil
+	}
+	controlPlaneSegment := strings.TrimPrefix(c.baseURL, c.rootURL+"/")
+	if strings.HasPrefix(path, controlPlaneSegment+"/") {
+		return fmt.Sprintf("%s/%s", c.rootURL, path), nil
+	}
+	return fmt.Sprintf("%s/%s", c.baseURL, path), nil
+}
+
+// decodeData decodes the items of a response from the first data key, in
+// order, containing items (e.g. `data` and then `items` for the v1 API).
+func (c *Client) decodeData(fields map[string]json.RawMessage) ([]map[string]interface{}, error) {
+	for _, key := range c.dataKeys {
+		items, err := decodeItems(fields[key])
+		if err != nil {
+			return nil, fmt.Errorf("error decoding %s: %w", key, err)
+		}
+		if len(items) > 0 {
+			return items, nil
+		}
+	}
+	return nil, nil
+}
+
+// decodeItems decodes a data field of a response (e.g. `data`) into its
+// items. Single item endpoints return the item as an object rather than an
+// array, in which case the object is normalized to a single item; a missing
+// or null field has no items.
+func decodeItems(raw json.RawMessage) ([]map[string]interface{}, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+		return nil, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+	switch trimmed[0] {
+	case '[':
+		var items []map[string]interface{}
+		if err := decoder.Decode(&items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	case '{':
+		var item map[string]interface{}
+		if err := decoder.Decode(&item); err != nil {
+			return nil, err
+		}
+		return []map[string]interface{}{item}, nil
+	default:
+		return nil, errors.New("data is neither an array nor an object")
+	}
+}
+
+// isTruncated returns true if the error decoding a response body is caused
+// by the body ending, or failing to be read, before the complete JSON
+// document was received rather than by malformed JSON.
+func isTruncated(err error) bool {
+	var errSyntax *json.SyntaxError
+	var errType *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &errSyntax), errors.As(err, &errType):
+		return false
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	default:
+		return true
 	}
 }
@@ -0,0 +1,152 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// GetEndpointItem retrieves a single item from the specified resource
+// endpoint while handling rate limiting. It returns nil if the item does not
+// exist, or an error if the request fails.
+func (c *Client) GetEndpointItem(ctx context.Context, endpointWithID string) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/%s", c.baseURL, endpointWithID)
+
+	// Keep trying until successful, an error occurs, or retries are exhausted
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			c.logger.Warn("Context canceled during get item operation",
+				zap.String("url", url),
+				zap.Error(err))
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
+
+		// Set the Authorization header with the bearer token and execute the request
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+		startTime := time.Now()
+		resp, err := c.do(req)
+		if err != nil {
+			c.logger.Error("error making request",
+				zap.String("url", url),
+				zap.Duration("request-duration", time.Since(startTime)),
+				zap.Error(err))
+			return nil, fmt.Errorf("error making request: %w",
+				&RequestError{Method: http.MethodGet, URL: url, Err: err})
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			item, err := c.decodeItem(resp, url)
+			//nolint: errcheck
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			c.logger.Debug("Retrieved item",
+				zap.String("url", url),
+				zap.Duration("request-duration", time.Since(startTime)))
+			return item, nil
+		case resp.StatusCode == http.StatusNotFound:
+			//nolint: errcheck
+			resp.Body.Close()
+			c.logger.Debug("Item not found",
+				zap.String("url", url))
+			return nil, nil //nolint: nilnil
+		case resp.StatusCode == http.StatusTooManyRequests:
+			//nolint: errcheck
+			resp.Body.Close()
+			retryDuration := c.retryAfterDuration(resp, attempt)
+			c.logger.Warn("Rate limit exceeded; retrying",
+				zap.String("url", url),
+				zap.Int("attempt", attempt),
+				zap.Duration("retry-after", retryDuration))
+			if err := c.waitForRetry(ctx, attempt, retryDuration); err != nil {
+				c.logger.Error("error waiting to retry get item",
+					zap.String("url", url),
+					zap.Int("attempt", attempt),
+					zap.Error(err))
+				return nil, fmt.Errorf("unable to get item %s: %w", endpointWithID, err)
+			}
+			continue
+		case isServerError(resp.StatusCode):
+			//nolint: errcheck
+			resp.Body.Close()
+			retryDuration := c.backoff.Next(attempt)
+			c.logger.Warn("Server error; retrying",
+				zap.String("url", url),
+				zap.Int("status-code", resp.StatusCode),
+				zap.Int("attempt", attempt),
+				zap.Duration("retry-after", retryDuration))
+			if err := c.waitForRetry(ctx, attempt, retryDuration); err != nil {
+				c.logger.Error("error waiting to retry get item",
+					zap.String("url", url),
+					zap.Int("attempt", attempt),
+					zap.Error(err))
+				return nil, fmt.Errorf("unable to get item %s: %w: %w", endpointWithID, err,
+					&RequestError{Method: http.MethodGet, URL: url, StatusCode: resp.StatusCode})
+			}
+			continue
+		default:
+			//nolint: errcheck
+			resp.Body.Close()
+			c.logger.Error("error getting item",
+				zap.String("url", url),
+				zap.Int("status-code", resp.StatusCode))
+			return nil, fmt.Errorf("unable to get item %s: %w", endpointWithID,
+				&RequestError{Method: http.MethodGet, URL: url, StatusCode: resp.StatusCode})
+		}
+	}
+}
+
+// decodeItem decodes a single item from the response body, bounding the body
+// to the maximum response size and removing the timestamps unless metadata is
+// included.
+func (c *Client) decodeItem(resp *http.Response, url string) (map[string]interface{}, error) {
+	body := &io.LimitedReader{R: resp.Body, N: c.maxResponseBytes + 1}
+	decoder := json.NewDecoder(body)
+	decoder.UseNumber()
+	var item map[string]interface{}
+	err := decoder.Decode(&item)
+	if body.N <= 0 {
+		c.logger.Error("response exceeds maximum size",
+			zap.String("url", url),
+			zap.Int64("max-response-bytes", c.maxResponseBytes))
+		return nil, &ResponseTooLargeError{URL: url, MaxBytes: c.maxResponseBytes}
+	}
+	if err != nil {
+		c.logger.Error("error decoding response",
+			zap.String("url", url),
+			zap.Error(err))
+		return nil, fmt.Errorf("error decoding response: %w", err)
+	}
+	if !c.includeMeta {
+		delete(item, "updated_at")
+		delete(item, "created_at")
+	}
+	return item, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestGetEndpointItem(t *testing.T) {
+	t.Run("verify item is found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			require.Contains(t, r.URL.Path, "/services/svc-1")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"svc-1","port":8080,"created_at":1700000000}`))
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		item, err := c.GetEndpointItem(context.Background(), "services/svc-1")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{
+			"id":   "svc-1",
+			"port": json.Number("8080"),
+		}, item)
+	})
+
+	t.Run("verify missing item returns nil", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		item, err := c.GetEndpointItem(context.Background(), "services/svc-1")
+		require.NoError(t, err)
+		require.Nil(t, item)
+	})
+
+	t.Run("verify rate limited get is retried", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			if requests.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"id":"svc-1"}`))
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		item, err := c.GetEndpointItem(context.Background(), "services/svc-1")
+		require.NoError(t, err)
+		require.Equal(t, map[string]interface{}{"id": "svc-1"}, item)
+		require.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("verify failed get returns request error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.Retries.MaxAttempts = 1
+		c := client.NewClient(config, zap.NewNop())
+		_, err := c.GetEndpointItem(context.Background(), "services/svc-1")
+		var errRequest *client.RequestError
+		require.ErrorAs(t, err, &errRequest)
+		require.Equal(t, http.StatusInternalServerError, errRequest.StatusCode)
+	})
+}
@@ -0,0 +1,541 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/backoff"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/event"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestGetEndpoint(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"svc-1","created_at":1700000000,"updated_at":1700000001}],"next":null}`))
+	})
+
+	t.Run("verify timestamps are stripped by default", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}}, data)
+	})
+
+	t.Run("verify timestamps are retained when metadata is included", func(t *testing.T) {
+		server := httptest.NewServer(handler)
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.IncludeMetadata = true
+		c := client.NewClient(config, zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{
+			{
+				"id":         "svc-1",
+				"created_at": json.Number("1700000000"),
+				"updated_at": json.Number("1700000001"),
+			},
+		}, data)
+	})
+	t.Run("verify operation deadline is respected during rate limit wait", func(t *testing.T) {
+		server := newStubServer(t, statusResponse(http.StatusTooManyRequests).withHeader("Retry-After", "30"))
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+
+		startTime := time.Now()
+		_, err := c.GetEndpoint(ctx, "services")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(startTime), 5*time.Second)
+	})
+
+	t.Run("verify partial pages are returned when a page times out", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+			if offset == 3 {
+				time.Sleep(500 * time.Millisecond)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-%d"}],"next":"services?offset=%d"}`, offset, offset+1)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.Timeouts.ResponseHeader = 100 * time.Millisecond
+		config.PartialPages = true
+		c := client.NewClient(config, zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorIs(t, err, client.ErrPartialPages)
+		require.Equal(t, []map[string]interface{}{
+			{"id": "svc-0"},
+			{"id": "svc-1"},
+			{"id": "svc-2"},
+		}, data)
+
+		config.PartialPages = false
+		c = client.NewClient(config, zap.NewNop())
+		data, err = c.GetEndpoint(context.Background(), "services")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, client.ErrPartialPages)
+		require.Nil(t, data)
+	})
+
+	t.Run("verify a failed page is skipped and the following pages are retrieved", func(t *testing.T) {
+		var requested []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			requested = append(requested, strconv.Itoa(page))
+			if page == 2 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			next := ""
+			if page < 4 {
+				next = fmt.Sprintf("services?page=%d", page+1)
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-%d"}],"next":%q}`, page, next)
+		}))
+		defer server.Close()
+
+		// The failed page is retried before it is skipped
+		config := newTestConfig(server.URL)
+		config.Retries.MaxAttempts = 3
+		config.SkipFailedPages = true
+		c := client.NewClient(config, zap.NewNop())
+		c.SetBackoff(newTestBackoff(t))
+		data, err := c.GetEndpoint(context.Background(), "services?page=0")
+		require.ErrorIs(t, err, client.ErrPartialPages)
+		var errGaps *client.PageGapsError
+		require.ErrorAs(t, err, &errGaps)
+		require.Len(t, errGaps.Gaps, 1)
+		require.Equal(t, 3, errGaps.Gaps[0].Page)
+		require.Contains(t, errGaps.Gaps[0].URL, "page=2")
+		require.Equal(t, []string{"0", "1", "2", "2", "2", "3", "4"}, requested)
+		require.Equal(t, []map[string]interface{}{
+			{"id": "svc-0"},
+			{"id": "svc-1"},
+			{"id": "svc-3"},
+			{"id": "svc-4"},
+		}, data)
+
+		// Without skipping failed pages the resource fails at the failed page
+		requested = nil
+		config.SkipFailedPages = false
+		c = client.NewClient(config, zap.NewNop())
+		c.SetBackoff(newTestBackoff(t))
+		data, err = c.GetEndpoint(context.Background(), "services?page=0")
+		require.Error(t, err)
+		require.NotErrorIs(t, err, client.ErrPartialPages)
+		require.Nil(t, data)
+		require.Equal(t, []string{"0", "1", "2", "2", "2"}, requested)
+	})
+
+	t.Run("verify a failed page with an opaque cursor is not skipped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(r.URL.Query().Get("offset")) > 0 {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-0"}],"next":"services?offset=b3BhcXVl"}`))
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.Retries.MaxAttempts = 1
+		config.SkipFailedPages = true
+		c := client.NewClient(config, zap.NewNop())
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.Error(t, err)
+		var errGaps *client.PageGapsError
+		require.False(t, errors.As(err, &errGaps))
+	})
+
+	t.Run("verify oversized response is rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-1","description":%q}]}`, strings.Repeat("x", 4096))
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.MaxResponseBytes = 1024
+		c := client.NewClient(config, zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.Nil(t, data)
+		var errTooLarge *client.ResponseTooLargeError
+		require.ErrorAs(t, err, &errTooLarge)
+		require.Equal(t, int64(1024), errTooLarge.MaxBytes)
+		require.ErrorContains(t, err, "error getting endpoint services")
+		require.ErrorContains(t, err, "exceeds the maximum response size of 1024 bytes")
+
+		config.MaxResponseBytes = 8192
+		c = client.NewClient(config, zap.NewNop())
+		data, err = c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Len(t, data, 1)
+	})
+	t.Run("verify forbidden response is reported as an authorization error", func(t *testing.T) {
+		server := newStubServer(t, statusResponse(http.StatusForbidden))
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "vaults")
+		require.Nil(t, data)
+		require.ErrorIs(t, err, client.ErrForbidden)
+		var errRequest *client.RequestError
+		require.ErrorAs(t, err, &errRequest)
+		require.Equal(t, http.StatusForbidden, errRequest.StatusCode)
+		require.NotContains(t, err.Error(), "unhandled status code")
+	})
+
+	t.Run("verify next page URL is resolved without duplicating the base path", func(t *testing.T) {
+		var paths []string
+		var next string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.RequestURI())
+			w.Header().Set("Content-Type", "application/json")
+			if r.URL.Query().Get("offset") == "1" {
+				_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}],"next":null}`))
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-0"}],"next":%q}`, next)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL + "/v2/control-planes")
+		controlPlaneID := config.ControlPlaneID.String()
+		basePath := "/v2/control-planes/" + controlPlaneID
+		c := client.NewClient(config, zap.NewNop())
+		for name, value := range map[string]string{
+			"absolute":                         server.URL + basePath + "/services?offset=1",
+			"root-relative":                    basePath + "/services?offset=1",
+			"prefixed with control plane":      controlPlaneID + "/services?offset=1",
+			"root-prefixed with control plane": "/" + controlPlaneID + "/services?offset=1",
+			"relative":                         "services?offset=1",
+			"relative with leading slash":      "/services?offset=1",
+		} {
+			paths, next = nil, value
+			data, err := c.GetEndpoint(context.Background(), "services")
+			require.NoError(t, err, name)
+			require.Len(t, data, 2, name)
+			require.Equal(t, []string{basePath + "/services", basePath + "/services?offset=1"}, paths, name)
+		}
+	})
+
+	t.Run("verify next page URL of a different host or scheme is refused", func(t *testing.T) {
+		var foreignRequests atomic.Int32
+		foreign := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			foreignRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}],"next":null}`))
+		}))
+		defer foreign.Close()
+		var next string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"data":[{"id":"svc-0"}],"next":%q}`, next)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		for name, value := range map[string]string{
+			"host":   foreign.URL + "/services?offset=1",
+			"scheme": strings.Replace(server.URL, "http://", "https://", 1) + "/services?offset=1",
+		} {
+			next = value
+			data, err := c.GetEndpoint(context.Background(), "services")
+			require.ErrorIs(t, err, client.ErrForeignNextURL, name)
+			require.Nil(t, data, name)
+		}
+		require.Zero(t, foreignRequests.Load())
+	})
+
+	t.Run("verify truncated responses are retried", func(t *testing.T) {
+		server := newStubServer(t,
+			jsonResponse(`{"data":[{"id":"svc-1","name":"exam`),
+			jsonResponse(`{"data":[{"id":"svc-1","name":"example"}]}`),
+		)
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		b, err := backoff.New(config.Backoff{Strategy: "constant", Base: 10 * time.Millisecond})
+		require.NoError(t, err)
+		c.SetBackoff(b)
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1", "name": "example"}}, data)
+		require.Len(t, server.requestURIs(), 2)
+	})
+
+	t.Run("verify malformed responses are not retried", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":svc-1}]}`))
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorContains(t, err, "error decoding response")
+		var errTruncated *client.TruncatedResponseError
+		require.NotErrorAs(t, err, &errTruncated)
+		require.Equal(t, int32(1), requests.Load())
+	})
+}
+
+func TestStreamEndpoint(t *testing.T) {
+	t.Run("verify each page is passed to the page function", func(t *testing.T) {
+		server := newStubServer(t,
+			jsonResponse(`{"data":[{"id":"svc-1"},{"id":"svc-2"}],"next":"/services?page=2"}`),
+			jsonResponse(`{"data":[{"id":"svc-3"}]}`),
+		)
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		var pages [][]map[string]interface{}
+		err := c.StreamEndpoint(context.Background(), "services", func(items []map[string]interface{}) error {
+			pages = append(pages, items)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, [][]map[string]interface{}{
+			{{"id": "svc-1"}, {"id": "svc-2"}},
+			{{"id": "svc-3"}},
+		}, pages)
+		require.Len(t, server.requestURIs(), 2)
+		require.Contains(t, server.requestURIs()[1], "page=2")
+	})
+
+	t.Run("verify an error from the page function stops the pagination", func(t *testing.T) {
+		server := newStubServer(t, jsonResponse(`{"data":[{"id":"svc-1"}],"next":"/services?page=2"}`))
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		errStop := errors.New("stop")
+		err := c.StreamEndpoint(context.Background(), "services", func([]map[string]interface{}) error {
+			return errStop
+		})
+		require.ErrorIs(t, err, errStop)
+		require.Len(t, server.requestURIs(), 1)
+	})
+}
+
+func TestGetEndpointDataShape(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		expected []map[string]interface{}
+	}{
+		{
+			name:     "verify an array is decoded into its items",
+			body:     `{"data":[{"id":"svc-1"},{"id":"svc-2"}]}`,
+			expected: []map[string]interface{}{{"id": "svc-1"}, {"id": "svc-2"}},
+		},
+		{
+			name:     "verify an object is normalized to a single item",
+			body:     `{"data":{"id":"svc-1","port":8080}}`,
+			expected: []map[string]interface{}{{"id": "svc-1", "port": json.Number("8080")}},
+		},
+		{
+			name: "verify null data has no items",
+			body: `{"data":null}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+			data, err := c.GetEndpoint(context.Background(), "services")
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, data)
+		})
+	}
+
+	t.Run("verify scalar data is rejected", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":"unexpected"}`))
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorContains(t, err, "data is neither an array nor an object")
+	})
+
+	t.Run("verify items are extracted from a custom data key", func(t *testing.T) {
+		server := newStubServer(t,
+			jsonResponse(`{"results":[{"id":"svc-1"},{"id":"svc-2"}],"next":"/services?offset=2"}`),
+			jsonResponse(`{"results":[{"id":"svc-3"}],"next":null}`))
+
+		config := newTestConfig(server.URL)
+		config.DataKeys = []string{"data", "results"}
+		c := client.NewClient(config, zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}, {"id": "svc-2"}, {"id": "svc-3"}}, data)
+		require.Len(t, server.requestURIs(), 2)
+	})
+
+	t.Run("verify keys other than the data keys are ignored", func(t *testing.T) {
+		server := newStubServer(t, jsonResponse(`{"results":[{"id":"svc-1"}]}`))
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Empty(t, data)
+	})
+}
+
+func TestGetEndpointTotals(t *testing.T) {
+	tests := []struct {
+		name  string
+		pages []string
+	}{
+		{
+			name: "v1 page total count",
+			pages: []string{
+				`{"items":[{"id":"cp-1"},{"id":"cp-2"}],"page":{"has_next_page":true,"next_cursor":"c2","total_count":3}}`,
+				`{"items":[{"id":"cp-3"}],"page":{"has_next_page":false,"total_count":3}}`,
+			},
+		},
+		{
+			name: "meta page total",
+			pages: []string{
+				`{"data":[{"id":"cp-1"},{"id":"cp-2"}],"next":"/services?page=2","meta":{"page":{"total":3}}}`,
+				`{"data":[{"id":"cp-3"}],"meta":{"page":{"total":3}}}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newStubServer(t, jsonResponse(tt.pages[0]), jsonResponse(tt.pages[1]))
+
+			var events strings.Builder
+			c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+			c.SetEmitter(event.NewEmitter(&events))
+			data, err := c.GetEndpoint(context.Background(), "services")
+			require.NoError(t, err)
+			require.Len(t, data, 3)
+
+			total, ok := c.Totals().Get("services")
+			require.True(t, ok)
+			require.Equal(t, 3, total)
+
+			// The total is reported to the event stream once
+			lines := strings.Split(strings.TrimSpace(events.String()), "\n")
+			require.Len(t, lines, 1)
+			var reported event.Event
+			require.NoError(t, json.Unmarshal([]byte(lines[0]), &reported))
+			require.Equal(t, event.TotalCounted, reported.Event)
+			require.Equal(t, "services", reported.Endpoint)
+			require.NotNil(t, reported.Total)
+			require.Equal(t, 3, *reported.Total)
+		})
+	}
+
+	t.Run("verify a total of zero is reported", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[],"meta":{"page":{"total":0}}}`))
+		}))
+		defer server.Close()
+
+		var events strings.Builder
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		c.SetEmitter(event.NewEmitter(&events))
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		total, ok := c.Totals().Get("services")
+		require.True(t, ok)
+		require.Zero(t, total)
+		require.Contains(t, events.String(), `"total":0`)
+	})
+
+	t.Run("verify endpoints without a total count are not reported", func(t *testing.T) {
+		server := newStubServer(t, jsonResponse(`{"data":[{"id":"svc-1"}]}`))
+
+		var events strings.Builder
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		c.SetEmitter(event.NewEmitter(&events))
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		_, ok := c.Totals().Get("services")
+		require.False(t, ok)
+		require.Empty(t, events.String())
+	})
+
+	t.Run("verify a total larger than the items retrieved is reported", func(t *testing.T) {
+		server := newStubServer(t,
+			jsonResponse(`{"items":[{"id":"cp-1"},{"id":"cp-2"}],"page":{"has_next_page":false,"total_count":5}}`))
+
+		core, logs := observer.New(zap.WarnLevel)
+		c := client.NewClient(newTestConfig(server.URL), zap.New(core))
+		data, err := c.GetEndpoint(context.Background(), "control-planes")
+		require.NoError(t, err)
+		require.Len(t, data, 2)
+
+		warnings := logs.FilterMessage("Item count does not match reported total").All()
+		require.Len(t, warnings, 1)
+		fields := warnings[0].ContextMap()
+		require.Equal(t, "control-planes", fields["endpoint"])
+		require.EqualValues(t, 5, fields["total"])
+		require.EqualValues(t, 2, fields["item-count"])
+
+		// Strict mode fails the endpoint rather than warning
+		c.SetStrictTotals(true)
+		_, err = c.GetEndpoint(context.Background(), "control-planes")
+		require.ErrorIs(t, err, client.ErrTotalMismatch)
+	})
+
+	t.Run("verify a matching total is not reported", func(t *testing.T) {
+		server := newStubServer(t,
+			jsonResponse(`{"items":[{"id":"cp-1"},{"id":"cp-2"}],"page":{"has_next_page":false,"total_count":2}}`))
+
+		core, logs := observer.New(zap.WarnLevel)
+		c := client.NewClient(newTestConfig(server.URL), zap.New(core))
+		c.SetStrictTotals(true)
+		_, err := c.GetEndpoint(context.Background(), "control-planes")
+		require.NoError(t, err)
+		require.Zero(t, logs.Len())
+	})
+}
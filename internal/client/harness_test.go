@@ -0,0 +1,99 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// stubResponse is a scripted response of a stub server.
+type stubResponse struct {
+	status  int
+	headers map[string]string
+	body    string
+}
+
+// jsonResponse returns a 200 OK response with the JSON body.
+func jsonResponse(body string) stubResponse {
+	return stubResponse{
+		status:  http.StatusOK,
+		headers: map[string]string{"Content-Type": "application/json"},
+		body:    body,
+	}
+}
+
+// statusResponse returns a response with the status code and no body.
+func statusResponse(status int) stubResponse {
+	return stubResponse{status: status}
+}
+
+// withHeader returns a copy of the response with the header set (e.g. a
+// Retry-After of a rate limited response).
+func (r stubResponse) withHeader(key string, value string) stubResponse {
+	headers := make(map[string]string, len(r.headers)+1)
+	for k, v := range r.headers {
+		headers[k] = v
+	}
+	headers[key] = value
+	r.headers = headers
+	return r
+}
+
+// stubServer is a test server replying to each request with the next
+// response of a scripted sequence; the last response is repeated once the
+// sequence is exhausted. The URI of each request is recorded.
+type stubServer struct {
+	*httptest.Server
+	mutex     sync.Mutex
+	responses []stubResponse
+	uris      []string
+}
+
+// newStubServer starts a stub server replying with the responses in order;
+// the server is closed when the test completes.
+func newStubServer(t *testing.T, responses ...stubResponse) *stubServer {
+	t.Helper()
+	if len(responses) == 0 {
+		t.Fatal("stub server requires at least one response")
+	}
+	s := &stubServer{responses: responses}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *stubServer) serve(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	response := s.responses[min(len(s.uris), len(s.responses)-1)]
+	s.uris = append(s.uris, r.URL.RequestURI())
+	s.mutex.Unlock()
+
+	for key, value := range response.headers {
+		w.Header().Set(key, value)
+	}
+	w.WriteHeader(response.status)
+	_, _ = w.Write([]byte(response.body))
+}
+
+// requestURIs returns the URIs of the requests received in order.
+func (s *stubServer) requestURIs() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string(nil), s.uris...)
+}
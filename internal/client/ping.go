@@ -0,0 +1,166 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Ping performs a preflight request against the root of the admin API to
+// ensure it is reachable before any resources are processed. Connection
+// refused errors are retried for the configured number of readiness attempts,
+// waiting using the backoff capped to the readiness interval, to tolerate an
+// admin API that is still starting (e.g. in CI). Any HTTP
+// response is considered reachable unless it looks like the proxy or a UI
+// rather than the admin API (e.g. a base URL using the proxy port), in which
+// case an error wrapping ErrNotAdminAPI is returned with a hint.
+func (c *Client) Ping(ctx context.Context) error {
+	startTime := time.Now()
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.rootURL, nil)
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+
+		resp, err := c.do(req)
+		if err == nil {
+			hint, probeErr := checkRootResponse(resp)
+			if probeErr != nil {
+				c.logger.Error("Base URL does not look like the admin API",
+					zap.String("url", c.rootURL),
+					zap.Int("status-code", resp.StatusCode),
+					zap.String("hint", hint))
+				return &RequestError{
+					Method:     http.MethodGet,
+					URL:        c.rootURL,
+					StatusCode: resp.StatusCode,
+					Err:        probeErr,
+				}
+			}
+			if len(hint) > 0 {
+				c.logger.Warn("Unexpected response from the root of the admin API",
+					zap.String("url", c.rootURL),
+					zap.Int("status-code", resp.StatusCode),
+					zap.String("hint", hint))
+			}
+			c.logger.Debug("Admin API is reachable",
+				zap.String("url", c.rootURL),
+				zap.Int("status-code", resp.StatusCode),
+				zap.Int("attempt", attempt),
+				zap.Duration("duration", time.Since(startTime)))
+			return nil
+		}
+
+		if !errors.Is(err, syscall.ECONNREFUSED) || attempt >= c.readinessAttempts {
+			c.logger.Error("Admin API is not reachable",
+				zap.String("url", c.rootURL),
+				zap.Int("attempt", attempt),
+				zap.Duration("duration", time.Since(startTime)),
+				zap.Error(err))
+			return fmt.Errorf("admin API is not reachable: %w",
+				&RequestError{Method: http.MethodGet, URL: c.rootURL, Err: err})
+		}
+
+		// Wait using the backoff, capped to the readiness interval
+		interval := c.backoff.Next(attempt)
+		if c.readinessInterval > 0 && interval > c.readinessInterval {
+			interval = c.readinessInterval
+		}
+		c.logger.Warn("Admin API refused connection; waiting for readiness",
+			zap.String("url", c.rootURL),
+			zap.Int("attempt", attempt),
+			zap.Duration("interval", interval))
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// maxRootProbeBytes is the maximum number of bytes of the root response read
+// to determine whether the base URL is the admin API.
+const maxRootProbeBytes = 4096
+
+// proxyNoRouteMessage is the message of the proxy when no route matches the
+// request (e.g. a base URL using the proxy port rather than the admin port).
+const proxyNoRouteMessage = "no Route matched"
+
+// checkRootResponse inspects the response of the root probe using the
+// Content-Type and body to detect a base URL which is not the admin API. An
+// error wrapping ErrNotAdminAPI is returned along with the hint if the
+// response is from the proxy or is an HTML page (e.g. a UI); otherwise a hint
+// is returned without an error if the response is a 404 or is not JSON. The
+// response body is closed.
+func checkRootResponse(resp *http.Response) (string, error) {
+	//nolint: errcheck
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxRootProbeBytes))
+	body = bytes.TrimSpace(body)
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/html" || bytes.HasPrefix(body, []byte("<")) {
+		hint := "this looks like a UI endpoint, not the admin API; verify base_url"
+		return hint, fmt.Errorf("%w: received HTML: %s", ErrNotAdminAPI, hint)
+	}
+
+	// Only the first JSON token is checked since the root response of the
+	// admin API is larger than the probed bytes
+	isJSON := mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") || startsJSON(body)
+	var message struct {
+		Message string `json:"message"`
+	}
+	if isJSON && json.Unmarshal(body, &message) == nil && strings.Contains(message.Message, proxyNoRouteMessage) {
+		hint := "this looks like the proxy endpoint, not the admin API " +
+			"(e.g. port 8000 rather than 8001); verify base_url"
+		return hint, fmt.Errorf("%w: %q: %s", ErrNotAdminAPI, message.Message, hint)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "root of the admin API was not found; " +
+			"verify base_url is the admin API and not the proxy or UI endpoint", nil
+	}
+	if len(body) > 0 && !isJSON {
+		return "response is not JSON; " +
+			"verify base_url is the admin API and not the proxy or UI endpoint", nil
+	}
+	return "", nil
+}
+
+// startsJSON reports whether the body starts with a JSON object or array.
+func startsJSON(body []byte) bool {
+	token, err := json.NewDecoder(bytes.NewReader(body)).Token()
+	if err != nil {
+		return false
+	}
+	_, ok := token.(json.Delim)
+	return ok
+}
@@ -0,0 +1,178 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/backoff"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// reserveAddress returns a local address that is not listening and therefore
+// refuses connections.
+func reserveAddress(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+	return address
+}
+
+func TestPing(t *testing.T) {
+	t.Run("verify any response is considered reachable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		require.NoError(t, c.Ping(context.Background()))
+	})
+
+	t.Run("verify a 404 is reachable with a hint", func(t *testing.T) {
+		server := newStubServer(t, statusResponse(http.StatusNotFound))
+		core, logs := observer.New(zap.WarnLevel)
+
+		c := client.NewClient(newTestConfig(server.URL), zap.New(core))
+		require.NoError(t, c.Ping(context.Background()))
+		require.Equal(t, 1, logs.FilterMessage("Unexpected response from the root of the admin API").Len())
+	})
+
+	t.Run("verify an admin API JSON response is reachable without a hint", func(t *testing.T) {
+		server := newStubServer(t, jsonResponse(`{"version":"3.10.0.0","configuration":{}}`))
+		core, logs := observer.New(zap.WarnLevel)
+
+		c := client.NewClient(newTestConfig(server.URL), zap.New(core))
+		require.NoError(t, c.Ping(context.Background()))
+		require.Zero(t, logs.Len())
+	})
+
+	t.Run("verify a root response larger than the probe is reachable without a hint", func(t *testing.T) {
+		body := `{"version":"3.10.0.0","configuration":{"padding":"` + strings.Repeat("x", 8192) + `"}}`
+		for _, contentType := range []string{"application/json; charset=utf-8", ""} {
+			server := newStubServer(t, stubResponse{
+				status:  http.StatusOK,
+				headers: map[string]string{"Content-Type": contentType},
+				body:    body,
+			})
+			core, logs := observer.New(zap.WarnLevel)
+
+			c := client.NewClient(newTestConfig(server.URL), zap.New(core))
+			require.NoError(t, c.Ping(context.Background()))
+			require.Zero(t, logs.Len())
+		}
+	})
+
+	t.Run("verify an HTML response is not the admin API", func(t *testing.T) {
+		server := newStubServer(t, stubResponse{
+			status:  http.StatusOK,
+			headers: map[string]string{"Content-Type": "text/html; charset=utf-8"},
+			body:    "<!DOCTYPE html><html><body>Konnect</body></html>",
+		})
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		err := c.Ping(context.Background())
+		require.ErrorIs(t, err, client.ErrNotAdminAPI)
+		require.Contains(t, err.Error(), "this looks like a UI endpoint, not the admin API")
+	})
+
+	t.Run("verify a proxy response is not the admin API", func(t *testing.T) {
+		server := newStubServer(t, stubResponse{
+			status:  http.StatusNotFound,
+			headers: map[string]string{"Content-Type": "application/json; charset=utf-8"},
+			body:    `{"message":"no Route matched with those values"}`,
+		})
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		err := c.Ping(context.Background())
+		require.ErrorIs(t, err, client.ErrNotAdminAPI)
+		require.Contains(t, err.Error(), "this looks like the proxy endpoint, not the admin API")
+	})
+
+	t.Run("verify connection refused is retried until the admin API is ready", func(t *testing.T) {
+		address := reserveAddress(t)
+		core, logs := observer.New(zap.WarnLevel)
+
+		// Start listening once the connection has been refused twice
+		var requests atomic.Int32
+		go func() {
+			for logs.FilterMessage("Admin API refused connection; waiting for readiness").Len() < 2 {
+				time.Sleep(10 * time.Millisecond)
+			}
+			listener, err := net.Listen("tcp", address)
+			if err != nil {
+				return
+			}
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(http.StatusOK)
+			}))
+			server.Listener = listener
+			server.Start()
+			t.Cleanup(server.Close)
+		}()
+
+		config := newTestConfig("http://" + address)
+		config.Readiness.Attempts = 50
+		config.Readiness.Interval = 50 * time.Millisecond
+		c := client.NewClient(config, zap.New(core))
+		require.NoError(t, c.Ping(context.Background()))
+		require.GreaterOrEqual(t, logs.FilterMessage("Admin API refused connection; waiting for readiness").Len(), 2)
+		require.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("verify error is returned when readiness attempts are exhausted", func(t *testing.T) {
+		config := newTestConfig("http://" + reserveAddress(t))
+		config.Readiness.Attempts = 3
+		config.Readiness.Interval = 10 * time.Millisecond
+		c := client.NewClient(config, zap.NewNop())
+		err := c.Ping(context.Background())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "admin API is not reachable")
+	})
+
+	t.Run("verify readiness attempts are paced using the backoff capped to the interval", func(t *testing.T) {
+		b, err := backoff.New(config.Backoff{Strategy: string(backoff.StrategyLinear), Base: 10 * time.Millisecond})
+		require.NoError(t, err)
+		core, logs := observer.New(zap.WarnLevel)
+		config := newTestConfig("http://" + reserveAddress(t))
+		config.Readiness.Attempts = 4
+		config.Readiness.Interval = 25 * time.Millisecond
+		c := client.NewClient(config, zap.New(core))
+		c.SetBackoff(b)
+		require.Error(t, c.Ping(context.Background()))
+
+		var intervals []time.Duration
+		for _, entry := range logs.FilterMessage("Admin API refused connection; waiting for readiness").All() {
+			intervals = append(intervals, entry.ContextMap()["interval"].(time.Duration))
+		}
+		require.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond},
+			intervals)
+	})
+}
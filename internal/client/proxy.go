@@ -0,0 +1,54 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+
+	"github.com/mikefero/osiris/internal/config"
+	"go.uber.org/zap"
+)
+
+// proxyAuthorizationHeader is the header authenticating a request with the
+// proxy.
+const proxyAuthorizationHeader = "Proxy-Authorization"
+
+// configureProxy routes the requests of the transport through the configured
+// proxy. When proxy credentials are configured, the basic Proxy-Authorization
+// header is sent with the CONNECT request tunneling HTTPS requests and is
+// returned so that it can be set on plain HTTP requests forwarded by the
+// proxy; an empty string is returned if the proxy is not authenticated.
+func configureProxy(transport *http.Transport, config *config.Config, logger *zap.Logger) string {
+	if len(config.ProxyURL) == 0 {
+		return ""
+	}
+	proxyURL, err := url.Parse(config.ProxyURL)
+	if err != nil {
+		logger.Error("error parsing proxy URL; requests are not proxied", zap.Error(err))
+		return ""
+	}
+	transport.Proxy = http.ProxyURL(proxyURL)
+	if len(config.ProxyUsername) == 0 {
+		return ""
+	}
+
+	credentials := config.ProxyUsername + ":" + config.ProxyPassword
+	authorization := "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	transport.ProxyConnectHeader = http.Header{proxyAuthorizationHeader: []string{authorization}}
+	return authorization
+}
@@ -0,0 +1,122 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// proxyRequest is a request received by the test proxy.
+type proxyRequest struct {
+	method        string
+	host          string
+	authorization string
+}
+
+// newTestProxy creates a proxy recording the requests it receives; CONNECT
+// requests are refused and forwarded requests are answered with an empty
+// list of items.
+func newTestProxy(t *testing.T) (*httptest.Server, func() []proxyRequest) {
+	t.Helper()
+	var mutex sync.Mutex
+	var requests []proxyRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mutex.Lock()
+		requests = append(requests, proxyRequest{
+			method:        r.Method,
+			host:          r.Host,
+			authorization: r.Header.Get("Proxy-Authorization"),
+		})
+		mutex.Unlock()
+		if r.Method == http.MethodConnect {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []proxyRequest {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]proxyRequest(nil), requests...)
+	}
+}
+
+func TestProxy(t *testing.T) {
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("proxy-user:proxy-pass"))
+
+	t.Run("verify the proxy authorization is set on the CONNECT request", func(t *testing.T) {
+		proxy, requests := newTestProxy(t)
+		config := newTestConfig("https://admin.example.com")
+		config.Retries.MaxAttempts = 1
+		config.ProxyURL = proxy.URL
+		config.ProxyUsername = "proxy-user"
+		config.ProxyPassword = "proxy-pass"
+
+		c := client.NewClient(config, zap.NewNop())
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.Error(t, err)
+
+		received := requests()
+		require.NotEmpty(t, received)
+		require.Equal(t, http.MethodConnect, received[0].method)
+		require.Equal(t, "admin.example.com:443", received[0].host)
+		require.Equal(t, basic, received[0].authorization)
+	})
+
+	t.Run("verify the proxy authorization is set on plain HTTP requests", func(t *testing.T) {
+		proxy, requests := newTestProxy(t)
+		config := newTestConfig("http://admin.example.com")
+		config.ProxyURL = proxy.URL
+		config.ProxyUsername = "proxy-user"
+		config.ProxyPassword = "proxy-pass"
+
+		c := client.NewClient(config, zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Empty(t, data)
+
+		received := requests()
+		require.Len(t, received, 1)
+		require.Equal(t, http.MethodGet, received[0].method)
+		require.Equal(t, "admin.example.com", received[0].host)
+		require.Equal(t, basic, received[0].authorization)
+	})
+
+	t.Run("verify the proxy is not authenticated without credentials", func(t *testing.T) {
+		proxy, requests := newTestProxy(t)
+		config := newTestConfig("http://admin.example.com")
+		config.ProxyURL = proxy.URL
+
+		c := client.NewClient(config, zap.NewNop())
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+
+		received := requests()
+		require.Len(t, received, 1)
+		require.Empty(t, received[0].authorization)
+	})
+}
@@ -0,0 +1,185 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// PutEndpoint creates or replaces an item of the specified resource endpoint
+// while handling rate limiting. It returns an error if the request fails or if
+// the status code is not 200 OK or 201 Created.
+func (c *Client) PutEndpoint(ctx context.Context, endpointWithID string, item map[string]interface{}) error {
+	return c.writeEndpoint(ctx, http.MethodPut, endpointWithID, item)
+}
+
+// PatchEndpoint updates an existing item of the specified resource endpoint
+// with the fields of the item (a JSON merge patch) while handling rate
+// limiting; the fields of the existing item not present in the item (e.g.
+// stripped timestamps or server-managed state) are preserved. It returns an
+// error if the request fails or if the status code is not 200 OK; an error
+// wrapping ErrNotFound is returned if the item does not exist.
+func (c *Client) PatchEndpoint(ctx context.Context, endpointWithID string, item map[string]interface{}) error {
+	return c.writeEndpoint(ctx, http.MethodPatch, endpointWithID, item)
+}
+
+// writeEndpoint writes the item to the specified resource endpoint using the
+// HTTP method while handling rate limiting. When compressing requests, the
+// body is gzipped; a compressed request rejected with 415 Unsupported Media
+// Type is retried uncompressed and the remaining requests of the client are no
+// longer compressed.
+func (c *Client) writeEndpoint(ctx context.Context, method string, endpointWithID string,
+	item map[string]interface{},
+) error {
+	url := fmt.Sprintf("%s/%s", c.baseURL, endpointWithID)
+	body, err := json.Marshal(item)
+	if err != nil {
+		return fmt.Errorf("error marshaling item %s: %w", endpointWithID, err)
+	}
+	var compressed []byte
+	if c.compressRequests && !c.compressionRejected.Load() {
+		compressed, err = gzipBody(body)
+		if err != nil {
+			return fmt.Errorf("error compressing item %s: %w", endpointWithID, err)
+		}
+	}
+
+	// Keep trying until successful, an error occurs, or retries are exhausted
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			c.logger.Warn("Context canceled during write operation",
+				zap.String("method", method),
+				zap.String("url", url),
+				zap.Error(err))
+			return err
+		}
+
+		requestBody := body
+		if compressed != nil {
+			requestBody = compressed
+		}
+		req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(requestBody))
+		if err != nil {
+			return fmt.Errorf("error creating request: %w", err)
+		}
+
+		// Set the Authorization header with the bearer token and execute the request
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+		req.Header.Set("Content-Type", "application/json")
+		if compressed != nil {
+			req.Header.Set("Content-Encoding", "gzip")
+		}
+		startTime := time.Now()
+		resp, err := c.do(req)
+		if err != nil {
+			c.logger.Error("error making request",
+				zap.String("method", method),
+				zap.String("url", url),
+				zap.Duration("request-duration", time.Since(startTime)),
+				zap.Error(err))
+			return fmt.Errorf("error making request: %w",
+				&RequestError{Method: method, URL: url, Err: err})
+		}
+		//nolint: errcheck
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusOK || (method == http.MethodPut && resp.StatusCode == http.StatusCreated):
+			c.logger.Debug("Wrote item",
+				zap.String("method", method),
+				zap.String("url", url),
+				zap.Int("status-code", resp.StatusCode),
+				zap.Duration("request-duration", time.Since(startTime)))
+			return nil
+		case resp.StatusCode == http.StatusTooManyRequests:
+			retryDuration := c.retryAfterDuration(resp, attempt)
+			c.logger.Warn("Rate limit exceeded; retrying",
+				zap.String("method", method),
+				zap.String("url", url),
+				zap.Int("attempt", attempt),
+				zap.Duration("retry-after", retryDuration))
+			if err := c.waitForRetry(ctx, attempt, retryDuration); err != nil {
+				c.logger.Error("error waiting to retry write",
+					zap.String("method", method),
+					zap.String("url", url),
+					zap.Int("attempt", attempt),
+					zap.Error(err))
+				return fmt.Errorf("unable to write item %s: %w", endpointWithID, err)
+			}
+			continue
+		case isServerError(resp.StatusCode):
+			retryDuration := c.backoff.Next(attempt)
+			c.logger.Warn("Server error; retrying",
+				zap.String("method", method),
+				zap.String("url", url),
+				zap.Int("status-code", resp.StatusCode),
+				zap.Int("attempt", attempt),
+				zap.Duration("retry-after", retryDuration))
+			if err := c.waitForRetry(ctx, attempt, retryDuration); err != nil {
+				c.logger.Error("error waiting to retry write",
+					zap.String("method", method),
+					zap.String("url", url),
+					zap.Int("attempt", attempt),
+					zap.Error(err))
+				return fmt.Errorf("unable to write item %s: %w: %w", endpointWithID, err,
+					&RequestError{Method: method, URL: url, StatusCode: resp.StatusCode})
+			}
+			continue
+		case compressed != nil && resp.StatusCode == http.StatusUnsupportedMediaType:
+			if !c.compressionRejected.Swap(true) {
+				c.logger.Warn("Compressed request rejected; no longer compressing requests",
+					zap.String("method", method),
+					zap.String("url", url))
+			}
+			compressed = nil
+			continue
+		case method == http.MethodPatch && resp.StatusCode == http.StatusNotFound:
+			c.logger.Debug("Item not found",
+				zap.String("method", method),
+				zap.String("url", url))
+			return fmt.Errorf("unable to write item %s: %w", endpointWithID,
+				&RequestError{Method: method, URL: url, StatusCode: resp.StatusCode, Err: ErrNotFound})
+		default:
+			c.logger.Error("error writing item",
+				zap.String("method", method),
+				zap.String("url", url),
+				zap.Int("status-code", resp.StatusCode))
+			return fmt.Errorf("unable to write item %s: %w", endpointWithID,
+				&RequestError{Method: method, URL: url, StatusCode: resp.StatusCode})
+		}
+	}
+}
+
+// gzipBody returns the gzip compressed body of a request.
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
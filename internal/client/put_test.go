@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestPutEndpoint(t *testing.T) {
+	t.Run("verify item is put", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPut, r.Method)
+			require.Contains(t, r.URL.Path, "/services/1234")
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var item map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			require.Equal(t, "svc", item["name"])
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		require.NoError(t, c.PutEndpoint(context.Background(), "services/1234",
+			map[string]interface{}{"id": "1234", "name": "svc"}))
+	})
+
+	t.Run("verify failed put returns request error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		err := c.PutEndpoint(context.Background(), "services/1234", map[string]interface{}{"id": "1234"})
+		var errRequest *client.RequestError
+		require.ErrorAs(t, err, &errRequest)
+		require.Equal(t, http.StatusBadRequest, errRequest.StatusCode)
+	})
+
+	t.Run("verify request body is gzip encoded when compressing requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			reader, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			var item map[string]interface{}
+			require.NoError(t, json.NewDecoder(reader).Decode(&item))
+			require.Equal(t, "svc", item["name"])
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.CompressRequests = true
+		c := client.NewClient(config, zap.NewNop())
+		require.NoError(t, c.PutEndpoint(context.Background(), "services/1234",
+			map[string]interface{}{"id": "1234", "name": "svc"}))
+	})
+
+	t.Run("verify rejected compressed request is retried uncompressed", func(t *testing.T) {
+		var encodings []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			encodings = append(encodings, r.Header.Get("Content-Encoding"))
+			if len(r.Header.Get("Content-Encoding")) > 0 {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+			var item map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			require.Equal(t, "svc", item["name"])
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.CompressRequests = true
+		c := client.NewClient(config, zap.NewNop())
+		require.NoError(t, c.PatchEndpoint(context.Background(), "services/1234",
+			map[string]interface{}{"name": "svc"}))
+		require.Equal(t, []string{"gzip", ""}, encodings)
+
+		// The remaining requests are no longer compressed
+		require.NoError(t, c.PutEndpoint(context.Background(), "services/5678",
+			map[string]interface{}{"id": "5678", "name": "svc"}))
+		require.Equal(t, []string{"gzip", "", ""}, encodings)
+	})
+}
+
+func TestPatchEndpoint(t *testing.T) {
+	t.Run("verify patch body contains only the provided fields", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodPatch, r.Method)
+			require.Contains(t, r.URL.Path, "/services/1234")
+			require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			var item map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&item))
+			require.Equal(t, map[string]interface{}{"id": "1234", "host": "example.com"}, item)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		require.NoError(t, c.PatchEndpoint(context.Background(), "services/1234",
+			map[string]interface{}{"id": "1234", "host": "example.com"}))
+	})
+
+	t.Run("verify patching a missing item returns not found", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		err := c.PatchEndpoint(context.Background(), "services/1234", map[string]interface{}{"id": "1234"})
+		require.ErrorIs(t, err, client.ErrNotFound)
+		var errRequest *client.RequestError
+		require.ErrorAs(t, err, &errRequest)
+		require.Equal(t, http.StatusNotFound, errRequest.StatusCode)
+
+		err = c.PutEndpoint(context.Background(), "services/1234", map[string]interface{}{"id": "1234"})
+		require.NotErrorIs(t, err, client.ErrNotFound)
+	})
+}
@@ -0,0 +1,114 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// resourceContextKey is the context key of the path of the resource a request
+// is made for.
+type resourceContextKey struct{}
+
+// WithResource returns a context identifying the path of the resource the
+// requests made with the context are for; the requests of each resource are
+// rate limited independently when the rate limit is per resource.
+func WithResource(ctx context.Context, path string) context.Context {
+	return context.WithValue(ctx, resourceContextKey{}, path)
+}
+
+// resourceFromContext returns the path of the resource the request is made
+// for; the path is empty if the request is not made for a resource.
+func resourceFromContext(ctx context.Context) string {
+	path, _ := ctx.Value(resourceContextKey{}).(string)
+	return path
+}
+
+// limiter paces requests so that they are spaced by at least the interval.
+type limiter struct {
+	mutex    sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next request is allowed or the context is done.
+func (l *limiter) wait(ctx context.Context) error {
+	l.mutex.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	delay := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mutex.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// rateLimiter is the client-side rate limit of the requests; the requests
+// share a single limiter unless the rate limit is per resource, in which case
+// the limiters are keyed by the resource path.
+type rateLimiter struct {
+	mutex       sync.Mutex
+	interval    time.Duration
+	perResource bool
+	limiters    map[string]*limiter
+}
+
+// newRateLimiter creates a rate limiter allowing the number of requests per
+// second; nil is returned if the rate limit is disabled.
+func newRateLimiter(requestsPerSecond float64, perResource bool) *rateLimiter {
+	if requestsPerSecond <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		interval:    time.Duration(float64(time.Second) / requestsPerSecond),
+		perResource: perResource,
+		limiters:    make(map[string]*limiter),
+	}
+}
+
+// wait blocks until the request made with the context is allowed or the
+// context is done. It is a no-op if the rate limiter is nil.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	var key string
+	if r.perResource {
+		key = resourceFromContext(ctx)
+	}
+	r.mutex.Lock()
+	l, ok := r.limiters[key]
+	if !ok {
+		l = &limiter{interval: r.interval}
+		r.limiters[key] = l
+	}
+	r.mutex.Unlock()
+	return l.wait(ctx)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"item-1"}]}`))
+	}))
+	defer server.Close()
+
+	// getResources gets each resource three times concurrently across the
+	// resources and returns the elapsed duration; the errors are asserted once
+	// all requests complete since require must not be used in a goroutine
+	getResources := func(t *testing.T, c *client.Client, paths ...string) time.Duration {
+		t.Helper()
+		startTime := time.Now()
+		errs := make(chan error, len(paths)*3)
+		var wg sync.WaitGroup
+		for _, path := range paths {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx := client.WithResource(context.Background(), path)
+				for range 3 {
+					_, err := c.GetEndpoint(ctx, path)
+					errs <- err
+				}
+			}()
+		}
+		wg.Wait()
+		elapsed := time.Since(startTime)
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		return elapsed
+	}
+
+	t.Run("verify resources share the rate limit by default", func(t *testing.T) {
+		config := newTestConfig(server.URL)
+		config.RateLimit.RequestsPerSecond = 5
+		c := client.NewClient(config, zap.NewNop())
+
+		// Six requests spaced by 200ms
+		require.GreaterOrEqual(t, getResources(t, c, "services", "routes"), time.Second)
+	})
+
+	t.Run("verify resources have independent rate limits when per resource", func(t *testing.T) {
+		config := newTestConfig(server.URL)
+		config.RateLimit.RequestsPerSecond = 5
+		config.RateLimit.PerResource = true
+		c := client.NewClient(config, zap.NewNop())
+
+		// Three requests spaced by 200ms for each resource in parallel; a
+		// shared rate limit would take at least a second
+		elapsed := getResources(t, c, "services", "routes")
+		require.GreaterOrEqual(t, elapsed, 400*time.Millisecond)
+		require.Less(t, elapsed, time.Second)
+	})
+
+	t.Run("verify rate limit wait is bounded by the context", func(t *testing.T) {
+		config := newTestConfig(server.URL)
+		config.RateLimit.RequestsPerSecond = 0.1
+		c := client.NewClient(config, zap.NewNop())
+
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = c.GetEndpoint(ctx, "services")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
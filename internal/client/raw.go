@@ -0,0 +1,140 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RawRecorder records the unmodified items of each endpoint as they are
+// retrieved from the admin API, before any fields are stripped or sanitized;
+// used to debug field stripping and sanitization. The recorded items contain
+// secrets and must be handled accordingly.
+type RawRecorder struct {
+	mutex     sync.Mutex
+	endpoints map[string][]map[string]interface{}
+}
+
+// NewRawRecorder creates a recorder of the unmodified items of endpoints.
+func NewRawRecorder() *RawRecorder {
+	return &RawRecorder{
+		endpoints: make(map[string][]map[string]interface{}),
+	}
+}
+
+// SetRawRecorder sets the recorder of the unmodified items of the endpoints
+// listed by the client; no items are recorded if nil.
+func (c *Client) SetRawRecorder(raw *RawRecorder) {
+	c.raw = raw
+}
+
+// reset discards the items recorded for an endpoint so that an endpoint
+// listed again (e.g. a retried resource) is not recorded twice.
+func (r *RawRecorder) reset(endpoint string) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	delete(r.endpoints, endpoint)
+}
+
+// record appends the items of a page retrieved from an endpoint.
+func (r *RawRecorder) record(endpoint string, items []map[string]interface{}) {
+	if r == nil {
+		return
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.endpoints[endpoint] = append(r.endpoints[endpoint], items...)
+}
+
+// WriteFile writes the recorded items keyed by endpoint to the file as JSON
+// indented using the indent string (compact if empty). The file is only
+// readable by the owner since the items are not sanitized.
+func (r *RawRecorder) WriteFile(filename string, indent string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	var data []byte
+	var err error
+	if len(indent) > 0 {
+		data, err = json.MarshalIndent(r.endpoints, "", indent)
+	} else {
+		data, err = json.Marshal(r.endpoints)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to marshal raw output: %w", err)
+	}
+	// Write to a temporary file created only readable by the owner and rename
+	// it so that the items are never written to an existing file readable by
+	// others
+	file, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("unable to create raw output: %w", err)
+	}
+	tempFilename := file.Name()
+	if _, err := file.Write(data); err != nil {
+		//nolint: errcheck
+		file.Close()
+		//nolint: errcheck
+		os.Remove(tempFilename)
+		return fmt.Errorf("unable to write raw output: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		//nolint: errcheck
+		os.Remove(tempFilename)
+		return fmt.Errorf("unable to write raw output: %w", err)
+	}
+	if err := os.Rename(tempFilename, filename); err != nil {
+		//nolint: errcheck
+		os.Remove(tempFilename)
+		return fmt.Errorf("unable to write raw output: %w", err)
+	}
+	return nil
+}
+
+// copyItems returns a deep copy of the items so that the recorded items are
+// not affected by the mutation of the listed items.
+func copyItems(items []map[string]interface{}) []map[string]interface{} {
+	copied := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		copied = append(copied, copyValue(item).(map[string]interface{}))
+	}
+	return copied
+}
+
+func copyValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			copied[key] = copyValue(value)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, 0, len(v))
+		for _, value := range v {
+			copied = append(copied, copyValue(value))
+		}
+		return copied
+	default:
+		return v
+	}
+}
@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	"go.uber.org/zap"
+)
+
+// checkRedirect is the redirect policy of the HTTP client. The number of
+// redirects followed for a request is capped, redirects from HTTPS to HTTP are
+// refused, and redirects to a different host are refused unless allowed so
+// that the bearer token is not sent to an unexpected host or in the clear.
+// Each redirect followed is logged.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > c.maxRedirects {
+		return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, c.maxRedirects)
+	}
+	if via[0].URL.Scheme == "https" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: %s redirected to %s", ErrInsecureRedirect, via[0].URL.Redacted(),
+			req.URL.Redacted())
+	}
+	if !c.allowCrossHostRedirects && req.URL.Host != via[0].URL.Host {
+		return fmt.Errorf("%w: %s redirected to %s", ErrCrossHostRedirect, via[0].URL.Host, req.URL.Host)
+	}
+
+	from := via[len(via)-1].URL
+	c.logger.Info("Following redirect",
+		zap.String("from", from.String()),
+		zap.String("to", req.URL.String()),
+		zap.Int("redirect", len(via)),
+	)
+	return nil
+}
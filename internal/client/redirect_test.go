@@ -0,0 +1,118 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestRedirects(t *testing.T) {
+	// The server redirects /hop/N to /hop/N-1 until /hop/0 which redirects to
+	// the endpoint
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hops, ok := strings.CutPrefix(r.URL.Path, "/hop/"); ok {
+			n, _ := strconv.Atoi(hops)
+			target := "/services"
+			if n > 0 {
+				target = "/hop/" + strconv.Itoa(n-1)
+			}
+			http.Redirect(w, r, target, http.StatusFound)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/services") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"1234"}]}`))
+			return
+		}
+		// The endpoint is moved behind the redirects
+		http.Redirect(w, r, "/hop/"+r.URL.Query().Get("hops"), http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	t.Run("verify redirects are followed and logged within the cap", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		config := newTestConfig(server.URL)
+		maxRedirects := 3
+		config.Redirects.Max = &maxRedirects
+		c := client.NewClient(config, zap.New(core))
+
+		items, err := c.GetEndpoint(context.Background(), "moved?hops=1")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		redirects := logs.FilterMessage("Following redirect").All()
+		require.Len(t, redirects, 3)
+		require.Contains(t, redirects[2].ContextMap()["to"], "/services")
+	})
+
+	t.Run("verify redirects beyond the cap are refused and not logged", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		config := newTestConfig(server.URL)
+		maxRedirects := 3
+		config.Redirects.Max = &maxRedirects
+		c := client.NewClient(config, zap.New(core))
+
+		_, err := c.GetEndpoint(context.Background(), "moved?hops=2")
+		require.ErrorIs(t, err, client.ErrTooManyRedirects)
+		require.Equal(t, 3, logs.FilterMessage("Following redirect").Len())
+	})
+
+	t.Run("verify all redirects are refused when the maximum is zero", func(t *testing.T) {
+		config := newTestConfig(server.URL)
+		maxRedirects := 0
+		config.Redirects.Max = &maxRedirects
+		c := client.NewClient(config, zap.NewNop())
+
+		_, err := c.GetEndpoint(context.Background(), "moved?hops=0")
+		require.ErrorIs(t, err, client.ErrTooManyRedirects)
+	})
+
+	// A redirect to the same server using a different host name
+	crossHost := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + r.URL.Path
+		http.Redirect(w, r, target+"?hops=0", http.StatusFound)
+	}))
+	defer crossHost.Close()
+
+	t.Run("verify cross-host redirects are refused by default", func(t *testing.T) {
+		core, logs := observer.New(zap.InfoLevel)
+		config := newTestConfig(crossHost.URL)
+		c := client.NewClient(config, zap.New(core))
+
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorIs(t, err, client.ErrCrossHostRedirect)
+		require.Zero(t, logs.FilterMessage("Following redirect").Len())
+	})
+
+	t.Run("verify cross-host redirects are followed when allowed", func(t *testing.T) {
+		config := newTestConfig(crossHost.URL)
+		config.Redirects.AllowCrossHost = true
+		c := client.NewClient(config, zap.NewNop())
+
+		items, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+	})
+}
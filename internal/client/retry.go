@@ -0,0 +1,75 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mikefero/osiris/internal/event"
+	"go.uber.org/zap"
+)
+
+// waitForRetry waits for the specified duration before the next attempt of a
+// request is made. The duration is capped to the maximum retry wait and the
+// wait is abandoned if the context is canceled. An error is returned if the
+// context is canceled, if the wait would exceed the deadline of the context,
+// or if the attempt exceeds the maximum number of attempts.
+func (c *Client) waitForRetry(ctx context.Context, attempt int, duration time.Duration) error {
+	if attempt >= c.maxAttempts {
+		return &RetriesExhaustedError{Attempts: attempt}
+	}
+
+	if duration > c.maxRetryWait {
+		c.logger.Debug("Capping retry wait duration",
+			zap.Duration("duration", duration),
+			zap.Duration("max-wait", c.maxRetryWait))
+		duration = c.maxRetryWait
+	}
+
+	// Avoid waiting past the deadline of the operation; the request would not
+	// be retried anyway
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= duration {
+		c.logger.Debug("Retry wait exceeds operation deadline",
+			zap.Duration("duration", duration),
+			zap.Duration("remaining", time.Until(deadline)))
+		return fmt.Errorf("retry wait of %s exceeds operation deadline: %w", duration, context.DeadlineExceeded)
+	}
+
+	c.events.Emit(event.Event{
+		Event:    event.RequestRetried,
+		Attempt:  attempt,
+		Duration: duration.String(),
+	})
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		c.stats.retries.Add(1)
+		return nil
+	}
+}
+
+// isServerError returns true if the status code is a transient server error
+// (e.g. a 502 from a load balancer while the admin API restarts) that is
+// retried using the backoff; 501 Not Implemented is never retried.
+func isServerError(statusCode int) bool {
+	return statusCode >= http.StatusInternalServerError && statusCode != http.StatusNotImplemented
+}
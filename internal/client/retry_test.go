@@ -0,0 +1,119 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestServerErrorRetries(t *testing.T) {
+	// The server fails the first two requests of each method with a
+	// transient server error
+	newServer := func(t *testing.T, requests *atomic.Int32) *httptest.Server {
+		t.Helper()
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if requests.Add(1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			switch r.Method {
+			case http.MethodDelete:
+				w.WriteHeader(http.StatusNoContent)
+			case http.MethodPut:
+				w.WriteHeader(http.StatusOK)
+			default:
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}],"id":"svc-1"}`))
+			}
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	newClient := func(t *testing.T, requests *atomic.Int32) *client.Client {
+		t.Helper()
+		c := client.NewClient(newTestConfig(newServer(t, requests).URL), zap.NewNop())
+		c.SetBackoff(newTestBackoff(t))
+		return c
+	}
+
+	t.Run("verify a page is retried after a server error", func(t *testing.T) {
+		var requests atomic.Int32
+		items, err := newClient(t, &requests).GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Len(t, items, 1)
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("verify an item is retried after a server error", func(t *testing.T) {
+		var requests atomic.Int32
+		item, err := newClient(t, &requests).GetEndpointItem(context.Background(), "services/svc-1")
+		require.NoError(t, err)
+		require.Equal(t, "svc-1", item["id"])
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("verify a write is retried after a server error", func(t *testing.T) {
+		var requests atomic.Int32
+		require.NoError(t, newClient(t, &requests).PutEndpoint(context.Background(), "services/svc-1",
+			map[string]interface{}{"id": "svc-1"}))
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("verify a delete is retried after a server error", func(t *testing.T) {
+		var requests atomic.Int32
+		require.NoError(t, newClient(t, &requests).DeleteEndpoint(context.Background(), "services/svc-1"))
+		require.Equal(t, int32(3), requests.Load())
+	})
+
+	t.Run("verify the server error is returned once the attempts are exhausted", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		config := newTestConfig(server.URL)
+		config.Retries.MaxAttempts = 3
+		c := client.NewClient(config, zap.NewNop())
+		c.SetBackoff(newTestBackoff(t))
+		err := c.DeleteEndpoint(context.Background(), "services/svc-1")
+		var errExhausted *client.RetriesExhaustedError
+		require.ErrorAs(t, err, &errExhausted)
+		var errRequest *client.RequestError
+		require.ErrorAs(t, err, &errRequest)
+		require.Equal(t, http.StatusServiceUnavailable, errRequest.StatusCode)
+	})
+
+	t.Run("verify not implemented is not retried", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(http.StatusNotImplemented)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		require.Error(t, c.DeleteEndpoint(context.Background(), "services/svc-1"))
+		require.Equal(t, int32(1), requests.Load())
+	})
+}
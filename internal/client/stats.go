@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats are the request statistics of a client, used to tune the concurrency,
+// page size, and rate limit of operations.
+type Stats struct {
+	// Requests is the total number of requests issued, including retries.
+	Requests int64
+	// Retries is the total number of requests retried.
+	Retries int64
+	// Bytes is the total number of bytes transferred; the request bodies sent
+	// and the response bodies read.
+	Bytes int64
+	// AverageLatency is the average duration until the response headers of a
+	// request are received.
+	AverageLatency time.Duration
+}
+
+// requestStats are the counters of the requests issued by a client; they are
+// safe for concurrent use.
+type requestStats struct {
+	requests atomic.Int64
+	retries  atomic.Int64
+	bytes    atomic.Int64
+	latency  atomic.Int64
+
+	// resourceRequests is the number of requests issued for each resource,
+	// keyed by the path of the resource.
+	resourceMutex    sync.Mutex
+	resourceRequests map[string]int64
+}
+
+// countResource counts a request issued for the resource path; requests not
+// made for a resource are not counted.
+func (s *requestStats) countResource(path string) {
+	if len(path) == 0 {
+		return
+	}
+	s.resourceMutex.Lock()
+	defer s.resourceMutex.Unlock()
+	if s.resourceRequests == nil {
+		s.resourceRequests = make(map[string]int64)
+	}
+	s.resourceRequests[path]++
+}
+
+// snapshot returns the current statistics.
+func (s *requestStats) snapshot() Stats {
+	stats := Stats{
+		Requests: s.requests.Load(),
+		Retries:  s.retries.Load(),
+		Bytes:    s.bytes.Load(),
+	}
+	if stats.Requests > 0 {
+		stats.AverageLatency = time.Duration(s.latency.Load() / stats.Requests)
+	}
+	return stats
+}
+
+// countingBody is a response body counting the bytes read.
+type countingBody struct {
+	io.ReadCloser
+	bytes *atomic.Int64
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes.Add(int64(n))
+	return n, err
+}
+
+// Stats returns the request statistics of the client: the total number of
+// requests issued, requests retried, bytes transferred, and the average
+// request latency.
+func (c *Client) Stats() Stats {
+	return c.stats.snapshot()
+}
+
+// ResourceRequests returns the number of requests issued for the resource
+// path, including retries; requests are attributed to a resource by the
+// context created with WithResource.
+func (c *Client) ResourceRequests(path string) int64 {
+	c.stats.resourceMutex.Lock()
+	defer c.stats.resourceMutex.Unlock()
+	return c.stats.resourceRequests[path]
+}
@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStats(t *testing.T) {
+	t.Run("verify no requests are counted for a new client", func(t *testing.T) {
+		c := client.NewClient(newTestConfig("http://localhost"), zap.NewNop())
+		require.Equal(t, client.Stats{}, c.Stats())
+	})
+
+	t.Run("verify requests, retries, and bytes are counted", func(t *testing.T) {
+		body := `{"data":[{"id":"svc-1"}]}`
+		server := newStubServer(t,
+			statusResponse(http.StatusTooManyRequests).withHeader("Retry-After", "0"),
+			statusResponse(http.StatusTooManyRequests).withHeader("Retry-After", "0"),
+			jsonResponse(body),
+		)
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		data, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Len(t, data, 1)
+
+		stats := c.Stats()
+		require.Len(t, server.requestURIs(), 3)
+		require.Equal(t, int64(3), stats.Requests)
+		require.Equal(t, int64(2), stats.Retries)
+		require.Equal(t, int64(len(body)), stats.Bytes)
+		require.Positive(t, stats.AverageLatency)
+	})
+
+	t.Run("verify request bodies are counted", func(t *testing.T) {
+		server := newStubServer(t, statusResponse(http.StatusOK))
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		err := c.PutEndpoint(context.Background(), "services/1234", map[string]interface{}{"id": "1234"})
+		require.NoError(t, err)
+
+		stats := c.Stats()
+		require.Equal(t, int64(1), stats.Requests)
+		require.Zero(t, stats.Retries)
+		require.Equal(t, int64(len(`{"id":"1234"}`)), stats.Bytes)
+	})
+
+	t.Run("verify requests are counted for the resource of the context", func(t *testing.T) {
+		server := newStubServer(t, jsonResponse(`{"data":[]}`), jsonResponse(`{"data":[]}`),
+			jsonResponse(`{"data":[]}`))
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		ctx := client.WithResource(context.Background(), "services")
+		for range 2 {
+			_, err := c.GetEndpoint(ctx, "services")
+			require.NoError(t, err)
+		}
+		_, err := c.GetEndpoint(context.Background(), "routes")
+		require.NoError(t, err)
+
+		require.Equal(t, int64(2), c.ResourceRequests("services"))
+		require.Zero(t, c.ResourceRequests("routes"))
+		require.Equal(t, int64(3), c.Stats().Requests)
+	})
+}
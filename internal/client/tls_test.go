@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newTLSTestClient creates a client for the TLS server, dialed via localhost
+// which is not a name in the certificate of the server, that trusts the
+// certificate of the server.
+func newTLSTestClient(t *testing.T, server *httptest.Server, serverName string) *Client {
+	t.Helper()
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	c := NewClient(&config.Config{
+		BaseURL:        "https://localhost:" + serverURL.Port(),
+		TLSServerName:  serverName,
+		ControlPlaneID: uuid.New(),
+		Retries: config.Retries{
+			MaxAttempts: 1,
+		},
+	}, zap.NewNop())
+
+	// Trust the certificate of the test server
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	transport, ok := c.httpClient.(*http.Client).Transport.(*http.Transport)
+	require.True(t, ok)
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	transport.TLSClientConfig.RootCAs = pool
+	return c
+}
+
+func TestTLSServerName(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	t.Run("verify certificate verification fails for the dialed host", func(t *testing.T) {
+		c := newTLSTestClient(t, server, "")
+		_, err := c.GetEndpoint(context.Background(), "services")
+		var errHostname x509.HostnameError
+		require.ErrorAs(t, err, &errHostname)
+	})
+
+	t.Run("verify server name override passes certificate verification", func(t *testing.T) {
+		// The certificate of the test server is issued for example.com
+		c := newTLSTestClient(t, server, "example.com")
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+	})
+}
+
+func TestInsecureRedirect(t *testing.T) {
+	insecure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer insecure.Close()
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, insecure.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+
+	t.Run("verify a redirect from HTTPS to HTTP is refused even across hosts", func(t *testing.T) {
+		c := newTLSTestClient(t, server, "example.com")
+		c.allowCrossHostRedirects = true
+		_, err := c.GetEndpoint(context.Background(), "services")
+		require.ErrorIs(t, err, ErrInsecureRedirect)
+	})
+}
@@ -0,0 +1,49 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"sync"
+)
+
+// Totals are the total numbers of items of the endpoints, as reported by the
+// admin API on the first page of each endpoint, so that the progress of an
+// operation can be estimated. Totals are safe for concurrent use.
+type Totals struct {
+	mutex  sync.Mutex
+	counts map[string]int
+}
+
+// newTotals creates empty totals.
+func newTotals() *Totals {
+	return &Totals{counts: make(map[string]int)}
+}
+
+// set records the total number of items of the endpoint.
+func (t *Totals) set(endpoint string, total int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.counts[endpoint] = total
+}
+
+// Get returns the total number of items of the endpoint; false is returned
+// if the endpoint did not report a total.
+func (t *Totals) Get(endpoint string) (int, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	total, ok := t.counts[endpoint]
+	return total, ok
+}
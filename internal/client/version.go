@@ -0,0 +1,149 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// gatewayVersionRegex matches the numeric prefix of a gateway version (e.g.
+// `3.4.1.0-enterprise-edition`).
+var gatewayVersionRegex = regexp.MustCompile(`^(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// enterpriseVersionRegex matches the four numeric segments of an enterprise
+// gateway version (e.g. `3.10.0.0`); open source versions have three.
+var enterpriseVersionRegex = regexp.MustCompile(`^\d+\.\d+\.\d+\.\d+`)
+
+// GatewayVersion is the version of the gateway serving the admin API.
+type GatewayVersion struct {
+	Major int
+	Minor int
+	Patch int
+	raw   string
+}
+
+// ParseGatewayVersion parses the major, minor, and patch numbers of a gateway
+// version; any suffix (e.g. the enterprise edition) is ignored.
+func ParseGatewayVersion(version string) (GatewayVersion, error) {
+	matches := gatewayVersionRegex.FindStringSubmatch(version)
+	if matches == nil {
+		return GatewayVersion{}, fmt.Errorf("invalid gateway version: %q", version)
+	}
+	major, _ := strconv.Atoi(matches[1])
+	minor, _ := strconv.Atoi(matches[2])
+	patch, _ := strconv.Atoi(matches[3])
+	return GatewayVersion{
+		Major: major,
+		Minor: minor,
+		Patch: patch,
+		raw:   version,
+	}, nil
+}
+
+// AtLeast returns true if the version is the same as or newer than the other
+// version.
+func (v GatewayVersion) AtLeast(other GatewayVersion) bool {
+	if v.Major != other.Major {
+		return v.Major > other.Major
+	}
+	if v.Minor != other.Minor {
+		return v.Minor > other.Minor
+	}
+	return v.Patch >= other.Patch
+}
+
+// Enterprise returns true if the version is of the enterprise edition of the
+// gateway, either by its edition suffix (e.g. `3.4.1.0-enterprise-edition`) or
+// by its four numeric segments (e.g. `3.10.0.0`).
+func (v GatewayVersion) Enterprise() bool {
+	return strings.Contains(v.raw, "enterprise") || enterpriseVersionRegex.MatchString(v.raw)
+}
+
+// String returns the version as reported by the gateway.
+func (v GatewayVersion) String() string {
+	if len(v.raw) > 0 {
+		return v.raw
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// DetectVersion retrieves the version of the gateway from the information
+// endpoint (the root of the control plane) and stores it on the client. The
+// pagination defaults of the gateway are used once the version is detected:
+// items are read from `data` unless data keys are configured and only the
+// next URL is followed rather than the cursor of the v1 API.
+func (c *Client) DetectVersion(ctx context.Context) (GatewayVersion, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL, nil)
+	if err != nil {
+		return GatewayVersion{}, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.bearerToken))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return GatewayVersion{}, fmt.Errorf("error making request: %w",
+			&RequestError{Method: http.MethodGet, URL: c.baseURL, Err: err})
+	}
+	//nolint: errcheck
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return GatewayVersion{}, &RequestError{Method: http.MethodGet, URL: c.baseURL, StatusCode: resp.StatusCode}
+	}
+
+	infoResp := struct {
+		Version string `json:"version"`
+	}{}
+	body := &io.LimitedReader{R: resp.Body, N: c.maxResponseBytes + 1}
+	err = json.NewDecoder(body).Decode(&infoResp)
+	if body.N <= 0 {
+		return GatewayVersion{}, &ResponseTooLargeError{URL: c.baseURL, MaxBytes: c.maxResponseBytes}
+	}
+	if err != nil {
+		return GatewayVersion{}, fmt.Errorf("error decoding response: %w", err)
+	}
+	version, err := ParseGatewayVersion(infoResp.Version)
+	if err != nil {
+		return GatewayVersion{}, err
+	}
+
+	c.logger.Info("Detected gateway version",
+		zap.String("version", version.String()))
+	c.version = &version
+	if !c.dataKeysConfigured {
+		c.dataKeys = gatewayDataKeys
+	}
+	c.cursorPagination = false
+	c.logger.Debug("Using gateway pagination defaults",
+		zap.Strings("data-keys", c.dataKeys))
+	return version, nil
+}
+
+// Version returns the version of the gateway, if detected.
+func (c *Client) Version() (GatewayVersion, bool) {
+	if c.version == nil {
+		return GatewayVersion{}, false
+	}
+	return *c.version, true
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseGatewayVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		version  string
+		expected client.GatewayVersion
+		wantErr  bool
+	}{
+		{
+			name:     "verify open source version is parsed",
+			version:  "3.9.1",
+			expected: client.GatewayVersion{Major: 3, Minor: 9, Patch: 1},
+		},
+		{
+			name:     "verify enterprise version is parsed",
+			version:  "3.10.0.2-enterprise-edition",
+			expected: client.GatewayVersion{Major: 3, Minor: 10, Patch: 0},
+		},
+		{
+			name:     "verify version without patch is parsed",
+			version:  "3.4",
+			expected: client.GatewayVersion{Major: 3, Minor: 4},
+		},
+		{
+			name:    "verify invalid version returns error",
+			version: "latest",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			version, err := client.ParseGatewayVersion(tt.version)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expected.Major, version.Major)
+			require.Equal(t, tt.expected.Minor, version.Minor)
+			require.Equal(t, tt.expected.Patch, version.Patch)
+			require.Equal(t, tt.version, version.String())
+		})
+	}
+}
+
+func TestGatewayVersionEnterprise(t *testing.T) {
+	for version, expected := range map[string]bool{
+		"3.4.1.0-enterprise-edition": true,
+		"3.10.0.0":                   true,
+		"3.10.0.2":                   true,
+		"3.9.1":                      false,
+		"3.4":                        false,
+	} {
+		parsed, err := client.ParseGatewayVersion(version)
+		require.NoError(t, err)
+		require.Equal(t, expected, parsed.Enterprise(), version)
+	}
+}
+
+func TestGatewayVersionAtLeast(t *testing.T) {
+	older := client.GatewayVersion{Major: 3, Minor: 4}
+	newer := client.GatewayVersion{Major: 3, Minor: 10}
+	require.True(t, newer.AtLeast(older))
+	require.False(t, older.AtLeast(newer))
+	require.True(t, older.AtLeast(older))
+	require.True(t, client.GatewayVersion{Major: 4}.AtLeast(newer))
+}
+
+func TestDetectVersion(t *testing.T) {
+	t.Run("verify version is captured from the information endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodGet, r.Method)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"version":"3.10.0.2-enterprise-edition","tagline":"Welcome to kong"}`))
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		_, ok := c.Version()
+		require.False(t, ok)
+
+		version, err := c.DetectVersion(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 10, version.Minor)
+		stored, ok := c.Version()
+		require.True(t, ok)
+		require.Equal(t, version, stored)
+	})
+
+	t.Run("verify the detected version selects the gateway pagination defaults", func(t *testing.T) {
+		var requests atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if !strings.HasSuffix(r.URL.Path, "/services") {
+				_, _ = w.Write([]byte(`{"version":"3.9.1"}`))
+				return
+			}
+			requests.Add(1)
+			_, _ = w.Write([]byte(`{"data":[{"id":"svc-1"}],"items":[{"id":"item-1"}],` +
+				`"page":{"has_next_page":true,"next_cursor":"cursor"}}`))
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		_, err := c.DetectVersion(context.Background())
+		require.NoError(t, err)
+		items, err := c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "svc-1"}}, items)
+		require.Equal(t, int32(1), requests.Load())
+
+		// Configured data keys take precedence over the gateway defaults
+		config := newTestConfig(server.URL)
+		config.DataKeys = []string{"items"}
+		c = client.NewClient(config, zap.NewNop())
+		_, err = c.DetectVersion(context.Background())
+		require.NoError(t, err)
+		items, err = c.GetEndpoint(context.Background(), "services")
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "item-1"}}, items)
+	})
+
+	t.Run("verify error is returned when the version is unavailable", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer server.Close()
+
+		c := client.NewClient(newTestConfig(server.URL), zap.NewNop())
+		_, err := c.DetectVersion(context.Background())
+		require.Error(t, err)
+		_, ok := c.Version()
+		require.False(t, ok)
+	})
+}
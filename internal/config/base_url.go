@@ -0,0 +1,68 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// ErrConflictingBaseURLSources is returned when the base URL is sourced from
+// both a file and a discovery URL.
+var ErrConflictingBaseURLSources = errors.New("base_url_file and base_url_discovery are mutually exclusive")
+
+// ErrInvalidBaseURL is returned when the base URL read from a file or a
+// discovery URL is not an absolute HTTP(S) URL.
+var ErrInvalidBaseURL = errors.New("invalid base URL")
+
+// resolveBaseURL replaces the base URL with the address read from the base
+// URL file, if configured. The base URL discovery URL is requested when the
+// application starts rather than here so that the request uses the
+// configured transport.
+func resolveBaseURL(config *Config) error {
+	switch {
+	case len(config.BaseURLFile) > 0 && len(config.BaseURLDiscovery) > 0:
+		return ErrConflictingBaseURLSources
+	case len(config.BaseURLFile) > 0:
+		data, err := os.ReadFile(config.BaseURLFile)
+		if err != nil {
+			return fmt.Errorf("unable to read base URL file: %w", err)
+		}
+		baseURL, err := ParseBaseURL(string(data))
+		if err != nil {
+			return fmt.Errorf("invalid base URL in file %s: %w", config.BaseURLFile, err)
+		}
+		config.BaseURL = baseURL
+	}
+	return nil
+}
+
+// ParseBaseURL returns the trimmed base URL; an error wrapping
+// ErrInvalidBaseURL is returned if it is not an absolute HTTP(S) URL.
+func ParseBaseURL(value string) (string, error) {
+	baseURL := strings.TrimSpace(value)
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidBaseURL, err)
+	}
+	if (parsed.Scheme != "http" && parsed.Scheme != "https") || len(parsed.Host) == 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidBaseURL, baseURL)
+	}
+	return strings.TrimSuffix(baseURL, "/"), nil
+}
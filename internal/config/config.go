@@ -16,9 +16,14 @@ limitations under the License.
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
 	"reflect"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-viper/mapstructure/v2"
@@ -27,14 +32,72 @@ import (
 )
 
 const (
-	defaultBaseURL               = "http://localhost:3737"
-	defaultSanitize              = true
-	defaultOutputFile            = "osiris.json"
-	defaultTimeoutTimeout        = 15 * time.Second
-	defaultTimeoutResponseHeader = 15 * time.Second
+	defaultBaseURL                 = "http://localhost:3737"
+	defaultSanitize                = true
+	defaultIncludeMetadata         = false
+	defaultSkipEnrichment          = false
+	defaultOutputFile              = "osiris.json"
+	defaultIndentString            = "  "
+	defaultEmptyResourcePolicy     = "omit"
+	defaultOutputKeyCase           = "none"
+	defaultContinueOnError         = false
+	defaultReadOnly                = false
+	defaultPartialPages            = false
+	defaultSkipFailedPages         = false
+	defaultErrorFile               = "errors.json"
+	defaultMaxResponseBytes        = 100 * 1024 * 1024
+	defaultCompressRequests        = false
+	defaultTimeoutTimeout          = 15 * time.Second
+	defaultTimeoutResponseHeader   = 15 * time.Second
+	defaultTimeoutDial             = 10 * time.Second
+	defaultTimeoutOperation        = 0
+	defaultTimeoutGet              = 0
+	defaultTimeoutDelete           = 0
+	defaultTimeoutPut              = 0
+	defaultRetriesMaxAttempts      = 10
+	defaultRetriesMaxWait          = 60 * time.Second
+	defaultRetriesJitter           = true
+	defaultResourceRetries         = 0
+	defaultRetryOnEmpty            = 0
+	defaultDeleteConcurrency       = 0
+	defaultDeleteConfirmThreshold  = 0
+	defaultControlPlaneConcurrency = 0
+	defaultBackoffStrategy         = "exponential"
+	defaultBackoffBase             = time.Second
+	defaultBackoffMax              = 60 * time.Second
+	defaultBackoffJitter           = true
+	defaultReadinessAttempts       = 5
+	defaultReadinessInterval       = 2 * time.Second
+	defaultRedirectsMax            = 10
+	defaultRedirectsCrossHost      = false
+	defaultRateLimitRequests       = 0
+	defaultRateLimitPerResource    = false
+	defaultSanitizationStrategy    = "mask"
+	defaultSanitizationMask        = "<redacted>"
+	defaultLoggerLevel             = "info"
+	defaultLoggerFilename          = "osiris.log"
+	defaultLoggerRetention         = 7
+	defaultLoggerAuditFilename     = "osiris-audit.log"
+	defaultLoggerEncoding          = "json"
+	defaultHooksTimeout            = 30 * time.Second
+	defaultHooksIgnoreErrors       = false
+	defaultOperatorIdentityHeader  = "X-On-Behalf-Of"
 )
 
-var defaultControlPlaneID = uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f")
+var (
+	defaultControlPlaneID = uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f")
+
+	// defaultSanitizationFields are the secret fields for each resource.
+	defaultSanitizationFields = map[string][]string{
+		"basic-auth":  {"password"},
+		"certificate": {"key", "key_alt"},
+		"hmac-auth":   {"secret"},
+		"jwt":         {"secret"},
+		"key":         {"jwk", "pem.private_key"},
+		"key-auth":    {"key"},
+		"license":     {"payload"},
+	}
+)
 
 // Config is the configuration struct for osiris.
 // It contains the base URL for the admin API, the bearer token for
@@ -44,20 +107,198 @@ var defaultControlPlaneID = uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f
 type Config struct {
 	// BaseURL is the base URL for the admin API.
 	BaseURL string `yaml:"base_url" mapstructure:"base_url"`
+	// BaseURLFile is a file containing the base URL for the admin API (e.g.
+	// written by an init container); when set, the base URL is read from the
+	// file at startup.
+	BaseURLFile string `yaml:"base_url_file" mapstructure:"base_url_file"`
+	// BaseURLDiscovery is a URL returning the base URL for the admin API in
+	// the body of its response (e.g. a service discovery endpoint); when set,
+	// the base URL is discovered at startup.
+	BaseURLDiscovery string `yaml:"base_url_discovery" mapstructure:"base_url_discovery"`
 	// BearerToken is the bearer token for authenticating with the admin API.
 	BearerToken string `yaml:"bearer_token" mapstructure:"bearer_token"`
+	// TLSServerName overrides the server name used to verify the certificate
+	// of the admin API (e.g. when connecting through a load balancer).
+	TLSServerName string `yaml:"tls_server_name" mapstructure:"tls_server_name"`
+	// ProxyURL is the URL of the HTTP proxy the requests to the admin API are
+	// sent through (e.g. http://proxy.example.com:3128); no proxy is used if
+	// empty.
+	ProxyURL string `yaml:"proxy_url" mapstructure:"proxy_url"`
+	// ProxyUsername is the username used to authenticate with the proxy; the
+	// proxy is not authenticated if empty.
+	ProxyUsername string `yaml:"proxy_username" mapstructure:"proxy_username"`
+	// ProxyPassword is the password used to authenticate with the proxy.
+	ProxyPassword string `yaml:"proxy_password" mapstructure:"proxy_password"`
 	// ControlPlaneID is the control plane ID for the GET/PUT/POST requests.
 	ControlPlaneID uuid.UUID `yaml:"control_plane_id" mapstructure:"control_plane_id"`
+	// ControlPlaneName is the name of the control plane; when set and the
+	// control plane ID is not configured, the ID is resolved from the name at
+	// startup.
+	ControlPlaneName string `yaml:"control_plane_name" mapstructure:"control_plane_name"`
 	// Logger is the logger configuration.
 	Logger Logger `yaml:"logger" mapstructure:"logger"`
 	// Sanitize is a flag to enable or disable sanitization of the response body
 	// fields.
 	Sanitize bool `yaml:"sanitize" mapstructure:"sanitize"`
+	// Sanitization is the sanitization configuration used when sanitize is
+	// enabled.
+	Sanitization Sanitization `yaml:"sanitization" mapstructure:"sanitization"`
+	// ResourceStripFields are the fields excluded from the output for each
+	// resource, keyed by resource name. Nested fields are specified using a dot
+	// separated path.
+	ResourceStripFields map[string][]string `yaml:"resource_strip_fields" mapstructure:"resource_strip_fields"`
+	// ResourceDependencies override the declared dependencies of resources
+	// for the insertion (apply) and deletion (reset) orders, keyed by resource
+	// name (e.g. when a gateway version changes the relationships between
+	// resources). The dependencies of a resource replace its declared
+	// dependencies.
+	ResourceDependencies map[string][]string `yaml:"resource_dependencies" mapstructure:"resource_dependencies"`
+	// ResourceJSONFields are the fields of each resource whose values are JSON
+	// encoded strings, keyed by resource name; the fields are decoded into
+	// nested values in the output and encoded again when applied. Nested
+	// fields are specified using a dot separated path.
+	ResourceJSONFields map[string][]string `yaml:"resource_json_fields" mapstructure:"resource_json_fields"`
+	// Transforms are the expression based rules applied in order to the
+	// listed items of the matching resources (e.g. to drop, rename, or redact
+	// org-specific fields); no items are transformed if empty.
+	Transforms []TransformRule `yaml:"transforms" mapstructure:"transforms"`
+	// IncludeMetadata is a flag to retain the metadata fields (e.g. timestamps
+	// and certificate metadata) that are otherwise stripped from the response
+	// body.
+	IncludeMetadata bool `yaml:"include_metadata" mapstructure:"include_metadata"`
+	// SkipSubResourceEnrichment is a flag to skip enriching the resources
+	// listed with their sub-resources (e.g. the consumer groups of consumers
+	// and the secret keys of config stores). Enriching requires an additional
+	// request for each item.
+	SkipSubResourceEnrichment bool `yaml:"skip_sub_resource_enrichment" mapstructure:"skip_sub_resource_enrichment"`
 	// OutputFile is the output file for the sanitized configuration of a control
 	// plane.
 	OutputFile string `yaml:"output_file" mapstructure:"output_file"`
+	// IndentString is the whitespace used to indent the output file; the
+	// output is written compact (without indentation) if empty.
+	IndentString string `yaml:"indent_string" mapstructure:"indent_string"`
+	// EmptyResourcePolicy is how resources without items are written to the
+	// output file; omit, empty-array, or null.
+	EmptyResourcePolicy string `yaml:"empty_resource_policy" mapstructure:"empty_resource_policy"`
+	// OutputKeyCase is the casing of the item keys, including nested keys, in
+	// the output file; none, snake, or camel.
+	OutputKeyCase string `yaml:"output_key_case" mapstructure:"output_key_case"`
+	// ContinueOnError is a flag to continue processing the remaining resources
+	// when a resource fails; all errors are aggregated and reported at the end
+	// of the operation.
+	ContinueOnError bool `yaml:"continue_on_error" mapstructure:"continue_on_error"`
+	// ReadOnly is a flag to refuse the operations that modify the control
+	// plane (reset and apply); dump and verify are unaffected.
+	ReadOnly bool `yaml:"readonly" mapstructure:"readonly"`
+	// PartialPages is a flag to retain the pages of a resource that were
+	// retrieved before a page request failed (e.g. a response header timeout
+	// mid-pagination); enabled implicitly when continue on error is enabled.
+	PartialPages bool `yaml:"partial_pages" mapstructure:"partial_pages"`
+	// SkipFailedPages is a flag to skip a page of a resource that fails and
+	// continue the pagination with the following page rather than failing the
+	// resource; the skipped pages are reported as gaps. Only numbered pages
+	// can be skipped.
+	SkipFailedPages bool `yaml:"skip_failed_pages" mapstructure:"skip_failed_pages"`
+	// ErrorFile is the output file for the structured error report written
+	// when errors occur and continue on error is enabled.
+	ErrorFile string `yaml:"error_file" mapstructure:"error_file"`
+	// CheckpointFile is the file used to persist the pagination progress of a
+	// dump so that an interrupted dump can be resumed; checkpointing is
+	// disabled if empty. The checkpoint contains unsanitized data and is
+	// removed when the dump completes successfully.
+	CheckpointFile string `yaml:"checkpoint_file" mapstructure:"checkpoint_file"`
+	// MaxResponseBytes is the maximum size of a response body from the admin
+	// API; larger responses result in an error rather than exhausting memory.
+	MaxResponseBytes int64 `yaml:"max_response_bytes" mapstructure:"max_response_bytes"`
+	// DataKeys are the candidate keys of a response containing the items,
+	// tried in order, for gateways or proxies wrapping the items under other
+	// keys (e.g. `results`); `data` and `items` are used if empty.
+	DataKeys []string `yaml:"data_keys" mapstructure:"data_keys"`
+	// CompressRequests is a flag to gzip the request bodies of PUT and PATCH
+	// requests (e.g. large plugin configurations) for gateways that support
+	// compressed requests; a request rejected as unsupported is retried
+	// uncompressed.
+	CompressRequests bool `yaml:"compress_requests" mapstructure:"compress_requests"`
 	// Timeouts are the timeouts for the API requests.
 	Timeouts Timeouts `yaml:"timeouts" mapstructure:"timeouts"`
+	// ResourceRetries is the number of times the listing of an entire resource
+	// is retried, pacing the attempts using the backoff, after the listing
+	// fails (e.g. once the request retries are exhausted). Re-listing is
+	// idempotent; a value of zero disables the retries.
+	ResourceRetries int `yaml:"resource_retries" mapstructure:"resource_retries"`
+	// RetryOnEmpty is the number of times the listing of a resource is
+	// retried, paced using the backoff, after it returns no items since an
+	// empty listing may be transient (e.g. eventual consistency right after
+	// writes). Resources that are legitimately empty incur every retry; a
+	// value of zero disables the retries.
+	RetryOnEmpty int `yaml:"retry_on_empty" mapstructure:"retry_on_empty"`
+	// DeleteConcurrency is the maximum number of delete requests in flight
+	// across all of the resources of a deletion level; the resources of a
+	// level wait for a free slot when the cap is reached. A value of zero
+	// leaves the deletes unbounded (one in flight per resource).
+	DeleteConcurrency int `yaml:"delete_concurrency" mapstructure:"delete_concurrency"`
+	// ControlPlaneConcurrency is the maximum number of control planes of a
+	// fleet (see --control-planes-file) dumped or reset simultaneously; the
+	// control planes are processed one at a time when zero or one.
+	ControlPlaneConcurrency int `yaml:"control_plane_concurrency" mapstructure:"control_plane_concurrency"`
+	// DeleteConfirmThreshold is the maximum number of items of a resource a
+	// reset deletes; a reset exceeding the threshold for any resource is
+	// aborted before any item is deleted unless large deletes are allowed
+	// (e.g. to catch pointing at the wrong control plane). A value of zero
+	// disables the threshold.
+	DeleteConfirmThreshold int `yaml:"delete_confirm_threshold" mapstructure:"delete_confirm_threshold"`
+	// Retries is the retry configuration for the API requests.
+	Retries Retries `yaml:"retries" mapstructure:"retries"`
+	// Backoff is the pacing of the attempts of a request when the admin API
+	// does not specify the duration to wait.
+	Backoff Backoff `yaml:"backoff" mapstructure:"backoff"`
+	// Readiness is the readiness configuration for the preflight request.
+	Readiness Readiness `yaml:"readiness" mapstructure:"readiness"`
+	// Redirects is the handling of the redirects issued by the admin API.
+	Redirects Redirects `yaml:"redirects" mapstructure:"redirects"`
+	// RateLimit is the client-side rate limit of the API requests.
+	RateLimit RateLimit `yaml:"rate_limit" mapstructure:"rate_limit"`
+	// Operator is the operator recorded in the audit log.
+	Operator string `yaml:"operator" mapstructure:"operator"`
+	// OperatorIdentity identifies the human operator behind the bearer token
+	// to gateways auditing automated tokens; when set it is sent on every
+	// request using the operator identity header.
+	OperatorIdentity string `yaml:"operator_identity" mapstructure:"operator_identity"`
+	// OperatorIdentityHeader is the name of the header used to send the
+	// operator identity.
+	OperatorIdentityHeader string `yaml:"operator_identity_header" mapstructure:"operator_identity_header"`
+	// Hooks are the external commands executed when writing the output file.
+	Hooks Hooks `yaml:"hooks" mapstructure:"hooks"`
+	// Git is the Git repository the output of a dump is committed to once the
+	// dump completes successfully.
+	Git Git `yaml:"git" mapstructure:"git"`
+}
+
+// ErrInvalidHeaderName is returned when a configured header name is not a
+// valid HTTP header name.
+var ErrInvalidHeaderName = errors.New("invalid header name")
+
+// ErrInvalidProxyURL is returned when the configured proxy URL is not an
+// absolute URL.
+var ErrInvalidProxyURL = errors.New("invalid proxy URL")
+
+// headerNameRegex matches a valid HTTP header name (a token as defined by RFC
+// 9110).
+var headerNameRegex = regexp.MustCompile("^[!#$%&'*+\\-.^_`|~0-9A-Za-z]+$")
+
+// files are the configuration files specified by the user; the default
+// configuration file is used if empty.
+var files []string
+
+// loadMutex serializes the loading of the configuration.
+var loadMutex sync.Mutex
+
+// SetFiles sets the configuration files read by NewConfig. Later files are
+// merged over earlier files; nested maps are merged key by key while lists
+// and scalar values are replaced. Environment variables take precedence over
+// all files.
+func SetFiles(filenames []string) {
+	files = filenames
 }
 
 // Logger is the logger configuration for osiris.
@@ -70,6 +311,49 @@ type Logger struct {
 	Filename string `yaml:"filename" mapstructure:"filename"`
 	// Retention is the number of days to retain the log files.
 	Retention int `yaml:"retention" mapstructure:"retention"`
+	// AuditFilename is the audit log file name; the audit log records each
+	// item deleted regardless of the log level.
+	AuditFilename string `yaml:"audit_filename" mapstructure:"audit_filename"`
+	// Encoding is the encoding of the log lines; json, logfmt, or console.
+	// The audit log is always encoded as JSON.
+	Encoding string `yaml:"encoding" mapstructure:"encoding"`
+	// NoColor disables the colored levels of the console encoding; the levels
+	// are only colored when stdout is a terminal and NO_COLOR is not set.
+	NoColor bool `yaml:"no_color" mapstructure:"no_color"`
+}
+
+// Sanitization is the sanitization configuration for osiris.
+// It contains the strategy used to sanitize secret fields and the secret
+// fields for each resource.
+type Sanitization struct {
+	// Strategy is the strategy used to sanitize secret fields; drop, mask,
+	// hash, or reference.
+	Strategy string `yaml:"strategy" mapstructure:"strategy"`
+	// Mask is the value used to replace secret fields when using the mask
+	// strategy.
+	Mask string `yaml:"mask" mapstructure:"mask"`
+	// Salt is the salt used when using the hash strategy. If empty, a random
+	// salt is generated for each run.
+	Salt string `yaml:"salt" mapstructure:"salt"`
+	// SecretReferenceTemplate is the Go text/template generating the reference
+	// that replaces secret fields when using the reference strategy (e.g.
+	// `{vault://kv/kong/{{ .Resource }}/{{ .ID }}/{{ .Field }}}`); executed
+	// with the resource, ID, name, and field of each secret field.
+	SecretReferenceTemplate string `yaml:"secret_reference_template" mapstructure:"secret_reference_template"`
+	// Fields are the secret fields for each resource, keyed by resource name.
+	// Nested fields are specified using a dot separated path.
+	Fields map[string][]string `yaml:"fields" mapstructure:"fields"`
+}
+
+// TransformRule is an expression based rule transforming the listed items of
+// a resource.
+type TransformRule struct {
+	// Resource is the name of the resource whose items are transformed; `*`
+	// matches every resource.
+	Resource string `yaml:"resource" mapstructure:"resource"`
+	// Expression is the semicolon separated calls of drop, rename, redact, and
+	// set applied to each item (e.g. `rename(tags, labels); drop(config.secret)`).
+	Expression string `yaml:"expression" mapstructure:"expression"`
 }
 
 // Timeouts is the timeouts configuration for osiris.
@@ -78,23 +362,258 @@ type Timeouts struct {
 	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
 	// ResponseHeader is the timeout for reading the headers.
 	ResponseHeader time.Duration `yaml:"response_header" mapstructure:"response_header"`
+	// Dial is the timeout for establishing a connection so that connecting to
+	// an unreachable host fails fast rather than waiting for the operating
+	// system default. A value of zero uses no dial timeout.
+	Dial time.Duration `yaml:"dial" mapstructure:"dial"`
+	// Operation is the timeout for the entire operation (e.g. dump or reset),
+	// including any retries. A value of zero disables the timeout.
+	Operation time.Duration `yaml:"operation" mapstructure:"operation"`
+	// Get overrides the request timeout for GET requests; the request timeout
+	// is used if zero.
+	Get time.Duration `yaml:"get" mapstructure:"get"`
+	// Delete overrides the request timeout for DELETE requests; the request
+	// timeout is used if zero.
+	Delete time.Duration `yaml:"delete" mapstructure:"delete"`
+	// Put overrides the request timeout for PUT and PATCH requests; the
+	// request timeout is used if zero.
+	Put time.Duration `yaml:"put" mapstructure:"put"`
+}
+
+// Retries is the retry configuration for osiris.
+// It bounds the number of attempts made for a single request and the
+// duration waited between attempts (e.g. when rate limited).
+type Retries struct {
+	// MaxAttempts is the maximum number of attempts for a single request.
+	MaxAttempts int `yaml:"max_attempts" mapstructure:"max_attempts"`
+	// MaxWait is the maximum duration to wait between attempts.
+	MaxWait time.Duration `yaml:"max_wait" mapstructure:"max_wait"`
+	// Jitter adds a random duration of up to the backoff base (additive
+	// jitter) to the duration specified by a Retry-After header so that
+	// concurrent requests do not retry in lockstep; the duration specified is
+	// never shortened.
+	Jitter bool `yaml:"jitter" mapstructure:"jitter"`
+}
+
+// Backoff is the backoff configuration for osiris.
+// It determines the duration waited between the attempts of a request when
+// the admin API does not specify the duration (e.g. a 5xx server error or a
+// rate limited response without a Retry-After header) and between the
+// preflight readiness attempts.
+type Backoff struct {
+	// Strategy is the backoff strategy; constant, linear, or exponential.
+	Strategy string `yaml:"strategy" mapstructure:"strategy"`
+	// Base is the duration waited after the first attempt.
+	Base time.Duration `yaml:"base" mapstructure:"base"`
+	// Max is the maximum duration waited between attempts.
+	Max time.Duration `yaml:"max" mapstructure:"max"`
+	// Jitter is a flag to randomize the duration waited between attempts to
+	// avoid synchronized retries.
+	Jitter bool `yaml:"jitter" mapstructure:"jitter"`
+}
+
+// Readiness is the readiness configuration for osiris.
+// It bounds how long the preflight request tolerates an admin API that
+// refuses connections (e.g. a gateway that is still starting).
+type Readiness struct {
+	// Attempts is the maximum number of preflight attempts.
+	Attempts int `yaml:"attempts" mapstructure:"attempts"`
+	// Interval is the maximum duration to wait between preflight attempts;
+	// the attempts are paced using the backoff.
+	Interval time.Duration `yaml:"interval" mapstructure:"interval"`
+}
+
+// Redirects is the redirect configuration for osiris.
+// It controls how the redirects issued by the admin API (e.g. a proxy
+// normalizing trailing slashes or upgrading to HTTPS) are followed.
+type Redirects struct {
+	// Max is the maximum number of redirects followed for a request; 0
+	// refuses all redirects and nil uses the default.
+	Max *int `yaml:"max" mapstructure:"max"`
+	// AllowCrossHost is a flag to follow redirects to a different host; such
+	// redirects are refused by default so that requests are not sent to an
+	// unexpected host.
+	AllowCrossHost bool `yaml:"allow_cross_host" mapstructure:"allow_cross_host"`
+}
+
+// RateLimit is the client-side rate limit configuration for osiris.
+// It paces the API requests to stay below the rate limit of the admin API;
+// the requests of each resource may be paced independently so that a heavily
+// paginated resource does not starve the other resources.
+type RateLimit struct {
+	// RequestsPerSecond is the maximum number of requests per second; a value
+	// of zero disables the rate limit.
+	RequestsPerSecond float64 `yaml:"requests_per_second" mapstructure:"requests_per_second"`
+	// PerResource is a flag to apply the rate limit to the requests of each
+	// resource independently rather than to all requests.
+	PerResource bool `yaml:"per_resource" mapstructure:"per_resource"`
+}
+
+// Hooks is the hooks configuration for osiris.
+// It contains the external commands executed when writing the output file;
+// `{file}` in a command is replaced with the output filename.
+type Hooks struct {
+	// PreWrite is the command used to filter the output before it is written;
+	// the output is provided on stdin and is replaced by stdout.
+	PreWrite string `yaml:"pre_write" mapstructure:"pre_write"`
+	// PostWrite is the command executed after the output is written.
+	PostWrite string `yaml:"post_write" mapstructure:"post_write"`
+	// Timeout is the timeout for each command.
+	Timeout time.Duration `yaml:"timeout" mapstructure:"timeout"`
+	// IgnoreErrors is a flag to continue the operation when a command fails.
+	IgnoreErrors bool `yaml:"ignore_errors" mapstructure:"ignore_errors"`
+}
+
+// Git is the configuration of the Git repository the output of a dump is
+// committed to; the output must be written within the working tree of the
+// repository. A commit is only created when the output changed.
+type Git struct {
+	// RepoPath is the path of the working tree of the repository; no commit
+	// is created if empty.
+	RepoPath string `yaml:"repo_path" mapstructure:"repo_path"`
+	// CommitMessageTemplate is the Go text/template generating the commit
+	// message; executed with the control plane ID, the control plane name,
+	// the timestamp of the dump, and the number of items dumped.
+	CommitMessageTemplate string `yaml:"commit_message_template" mapstructure:"commit_message_template"`
+	// Author is the author and committer of the commits (e.g.
+	// `Osiris <osiris@example.com>`); the identity configured for the
+	// repository is used if empty.
+	Author string `yaml:"author" mapstructure:"author"`
+}
+
+// readFiles reads the configuration files, merging each file over the
+// previous files. Unlike the default configuration file, the files must
+// exist.
+func readFiles(filenames []string) error {
+	for _, filename := range filenames {
+		file, err := os.Open(filename)
+		if err != nil {
+			return fmt.Errorf("unable to open config file: %w", err)
+		}
+		err = viper.MergeConfig(file)
+		//nolint: errcheck
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("unable to read config file %s: %w", filename, err)
+		}
+	}
+	return nil
+}
+
+// envReferenceRegex matches a `${VAR}` reference to an environment variable
+// or an escaped `$$`.
+var envReferenceRegex = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// expandEnv expands the references to environment variables in the string
+// values read from the configuration files (e.g. `bearer_token: ${TOKEN}`).
+// Only the `${VAR}` form is expanded and `$$` is an escaped `$`; references
+// to unset variables are left as is so that a `$` in a legitimate value is
+// not corrupted, and values overridden by their direct environment variable
+// (e.g. OSIRIS_BEARER_TOKEN) are used as is.
+func expandEnv() {
+	for _, key := range viper.AllKeys() {
+		if !viper.InConfig(key) {
+			continue
+		}
+		envKey := "OSIRIS_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if _, ok := os.LookupEnv(envKey); ok {
+			continue
+		}
+		value, ok := viper.Get(key).(string)
+		if !ok || !strings.Contains(value, "$") {
+			continue
+		}
+		viper.Set(key, envReferenceRegex.ReplaceAllStringFunc(value, func(reference string) string {
+			if reference == "$$" {
+				return "$"
+			}
+			name := envReferenceRegex.FindStringSubmatch(reference)[1]
+			if value, ok := os.LookupEnv(name); ok {
+				return value
+			}
+			return reference
+		}))
+	}
 }
 
 func NewConfig() (*Config, error) {
+	// The configuration is loaded through the global viper instance; the
+	// control planes of a fleet may be loaded concurrently
+	loadMutex.Lock()
+	defer loadMutex.Unlock()
+
 	// Defaults
 	viper.SetDefault("base_url", defaultBaseURL)
 	viper.SetDefault("control_plane_id", defaultControlPlaneID)
 	viper.SetDefault("output_file", defaultOutputFile)
+	viper.SetDefault("indent_string", defaultIndentString)
+	viper.SetDefault("empty_resource_policy", defaultEmptyResourcePolicy)
+	viper.SetDefault("output_key_case", defaultOutputKeyCase)
+	viper.SetDefault("continue_on_error", defaultContinueOnError)
+	viper.SetDefault("readonly", defaultReadOnly)
+	viper.SetDefault("partial_pages", defaultPartialPages)
+	viper.SetDefault("skip_failed_pages", defaultSkipFailedPages)
+	viper.SetDefault("error_file", defaultErrorFile)
 	viper.SetDefault("sanitize", defaultSanitize)
+	viper.SetDefault("include_metadata", defaultIncludeMetadata)
+	viper.SetDefault("skip_sub_resource_enrichment", defaultSkipEnrichment)
+	viper.SetDefault("max_response_bytes", defaultMaxResponseBytes)
+	viper.SetDefault("compress_requests", defaultCompressRequests)
+	viper.SetDefault("operator_identity_header", defaultOperatorIdentityHeader)
+
+	// Sanitization defaults
+	viper.SetDefault("sanitization.strategy", defaultSanitizationStrategy)
+	viper.SetDefault("sanitization.mask", defaultSanitizationMask)
+	viper.SetDefault("sanitization.fields", defaultSanitizationFields)
 
 	// Logger defaults
-	viper.SetDefault("logger.level", "info")
-	viper.SetDefault("logger.filename", "osiris.log")
-	viper.SetDefault("logger.retention", 7)
+	viper.SetDefault("logger.level", defaultLoggerLevel)
+	viper.SetDefault("logger.filename", defaultLoggerFilename)
+	viper.SetDefault("logger.retention", defaultLoggerRetention)
+	viper.SetDefault("logger.audit_filename", defaultLoggerAuditFilename)
+	viper.SetDefault("logger.encoding", defaultLoggerEncoding)
+	viper.SetDefault("logger.no_color", false)
 
 	// Timeout defaults
 	viper.SetDefault("timeouts.timeout", defaultTimeoutTimeout)
 	viper.SetDefault("timeouts.response_header", defaultTimeoutResponseHeader)
+	viper.SetDefault("timeouts.dial", defaultTimeoutDial)
+	viper.SetDefault("timeouts.operation", defaultTimeoutOperation)
+	viper.SetDefault("timeouts.get", defaultTimeoutGet)
+	viper.SetDefault("timeouts.delete", defaultTimeoutDelete)
+	viper.SetDefault("timeouts.put", defaultTimeoutPut)
+
+	// Retry defaults
+	viper.SetDefault("retries.max_attempts", defaultRetriesMaxAttempts)
+	viper.SetDefault("retries.max_wait", defaultRetriesMaxWait)
+	viper.SetDefault("retries.jitter", defaultRetriesJitter)
+	viper.SetDefault("resource_retries", defaultResourceRetries)
+	viper.SetDefault("retry_on_empty", defaultRetryOnEmpty)
+	viper.SetDefault("delete_concurrency", defaultDeleteConcurrency)
+	viper.SetDefault("delete_confirm_threshold", defaultDeleteConfirmThreshold)
+	viper.SetDefault("control_plane_concurrency", defaultControlPlaneConcurrency)
+
+	// Backoff configuration
+	viper.SetDefault("backoff.strategy", defaultBackoffStrategy)
+	viper.SetDefault("backoff.base", defaultBackoffBase)
+	viper.SetDefault("backoff.max", defaultBackoffMax)
+	viper.SetDefault("backoff.jitter", defaultBackoffJitter)
+
+	// Readiness defaults
+	viper.SetDefault("readiness.attempts", defaultReadinessAttempts)
+	viper.SetDefault("readiness.interval", defaultReadinessInterval)
+
+	// Redirect defaults
+	viper.SetDefault("redirects.max", defaultRedirectsMax)
+	viper.SetDefault("redirects.allow_cross_host", defaultRedirectsCrossHost)
+
+	// Rate limit defaults
+	viper.SetDefault("rate_limit.requests_per_second", defaultRateLimitRequests)
+	viper.SetDefault("rate_limit.per_resource", defaultRateLimitPerResource)
+
+	// Hooks defaults
+	viper.SetDefault("hooks.timeout", defaultHooksTimeout)
+	viper.SetDefault("hooks.ignore_errors", defaultHooksIgnoreErrors)
 
 	// Osiris configuration setup for viper
 	viper.SetConfigName("osiris")
@@ -105,9 +624,57 @@ func NewConfig() (*Config, error) {
 	// Bind environment variables to viper that do not have a corresponding
 	// default value
 	viper.SetEnvPrefix("osiris")
+	if err := viper.BindEnv("base_url_file"); err != nil {
+		return nil, fmt.Errorf("unable to bind base_url_file environment variable: %w", err)
+	}
+	if err := viper.BindEnv("base_url_discovery"); err != nil {
+		return nil, fmt.Errorf("unable to bind base_url_discovery environment variable: %w", err)
+	}
 	if err := viper.BindEnv("bearer_token"); err != nil {
 		return nil, fmt.Errorf("unable to bind bearer_token environment variable: %w", err)
 	}
+	if err := viper.BindEnv("control_plane_name"); err != nil {
+		return nil, fmt.Errorf("unable to bind control_plane_name environment variable: %w", err)
+	}
+	if err := viper.BindEnv("tls_server_name"); err != nil {
+		return nil, fmt.Errorf("unable to bind tls_server_name environment variable: %w", err)
+	}
+	if err := viper.BindEnv("proxy_url"); err != nil {
+		return nil, fmt.Errorf("unable to bind proxy_url environment variable: %w", err)
+	}
+	if err := viper.BindEnv("proxy_username"); err != nil {
+		return nil, fmt.Errorf("unable to bind proxy_username environment variable: %w", err)
+	}
+	if err := viper.BindEnv("proxy_password"); err != nil {
+		return nil, fmt.Errorf("unable to bind proxy_password environment variable: %w", err)
+	}
+	if err := viper.BindEnv("sanitization.salt"); err != nil {
+		return nil, fmt.Errorf("unable to bind sanitization.salt environment variable: %w", err)
+	}
+	if err := viper.BindEnv("sanitization.secret_reference_template"); err != nil {
+		return nil, fmt.Errorf("unable to bind sanitization.secret_reference_template environment variable: %w", err)
+	}
+	if err := viper.BindEnv("operator"); err != nil {
+		return nil, fmt.Errorf("unable to bind operator environment variable: %w", err)
+	}
+	if err := viper.BindEnv("operator_identity"); err != nil {
+		return nil, fmt.Errorf("unable to bind operator_identity environment variable: %w", err)
+	}
+	if err := viper.BindEnv("checkpoint_file"); err != nil {
+		return nil, fmt.Errorf("unable to bind checkpoint_file environment variable: %w", err)
+	}
+	if err := viper.BindEnv("hooks.pre_write"); err != nil {
+		return nil, fmt.Errorf("unable to bind hooks.pre_write environment variable: %w", err)
+	}
+	if err := viper.BindEnv("hooks.post_write"); err != nil {
+		return nil, fmt.Errorf("unable to bind hooks.post_write environment variable: %w", err)
+	}
+	if err := viper.BindEnv("git.repo_path"); err != nil {
+		return nil, fmt.Errorf("unable to bind git.repo_path environment variable: %w", err)
+	}
+	if err := viper.BindEnv("git.author"); err != nil {
+		return nil, fmt.Errorf("unable to bind git.author environment variable: %w", err)
+	}
 
 	// Enable automatic environment variable binding
 	viper.AutomaticEnv()
@@ -117,7 +684,20 @@ func NewConfig() (*Config, error) {
 	// configuration fields are not present then and error will be returned
 	// further down the line.
 	var config Config
-	_ = viper.ReadInConfig()
+	if len(files) > 0 {
+		if err := readFiles(files); err != nil {
+			return nil, err
+		}
+	} else {
+		_ = viper.ReadInConfig()
+	}
+	expandEnv()
+
+	// The default control plane ID is not used when the control plane is
+	// identified by name; the ID is resolved from the name instead
+	if len(viper.GetString("control_plane_name")) > 0 {
+		viper.SetDefault("control_plane_id", uuid.Nil.String())
+	}
 	err := viper.Unmarshal(&config, viper.DecodeHook(
 		mapstructure.ComposeDecodeHookFunc(
 			// Custom UUID conversion hook
@@ -145,5 +725,17 @@ func NewConfig() (*Config, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to unmarshal config: %w", err)
 	}
+	if !headerNameRegex.MatchString(config.OperatorIdentityHeader) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidHeaderName, config.OperatorIdentityHeader)
+	}
+	if len(config.ProxyURL) > 0 {
+		if proxyURL, err := url.Parse(config.ProxyURL); err != nil || len(proxyURL.Scheme) == 0 ||
+			len(proxyURL.Host) == 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidProxyURL, config.ProxyURL)
+		}
+	}
+	if err := resolveBaseURL(&config); err != nil {
+		return nil, err
+	}
 	return &config, nil
 }
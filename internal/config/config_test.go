@@ -27,6 +27,20 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+var defaultSanitizationFields = map[string][]string{
+	"basic-auth":  {"password"},
+	"certificate": {"key", "key_alt"},
+	"hmac-auth":   {"secret"},
+	"jwt":         {"secret"},
+	"key":         {"jwk", "pem.private_key"},
+	"key-auth":    {"key"},
+	"license":     {"payload"},
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
 func TestConfig(t *testing.T) {
 	t.Run("verify defaults are set when overrides are not provided", func(t *testing.T) {
 		actual, err := config.NewConfig()
@@ -36,15 +50,50 @@ func TestConfig(t *testing.T) {
 			BaseURL:        "http://localhost:3737",
 			ControlPlaneID: uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f"),
 			Logger: config.Logger{
-				Level:     "info",
-				Filename:  "osiris.log",
-				Retention: 7,
+				Level:         "info",
+				Filename:      "osiris.log",
+				Retention:     7,
+				AuditFilename: "osiris-audit.log",
+				Encoding:      "json",
 			},
 			OutputFile: "osiris.json",
+			ErrorFile:  "errors.json",
 			Sanitize:   true,
+			Sanitization: config.Sanitization{
+				Strategy: "mask",
+				Mask:     "<redacted>",
+				Fields:   defaultSanitizationFields,
+			},
+			MaxResponseBytes:       100 * 1024 * 1024,
+			IndentString:           "  ",
+			EmptyResourcePolicy:    "omit",
+			OutputKeyCase:          "none",
+			OperatorIdentityHeader: "X-On-Behalf-Of",
 			Timeouts: config.Timeouts{
 				Timeout:        15 * time.Second,
 				ResponseHeader: 15 * time.Second,
+				Dial:           10 * time.Second,
+			},
+			Retries: config.Retries{
+				MaxAttempts: 10,
+				MaxWait:     60 * time.Second,
+				Jitter:      true,
+			},
+			Backoff: config.Backoff{
+				Strategy: "exponential",
+				Base:     time.Second,
+				Max:      60 * time.Second,
+				Jitter:   true,
+			},
+			Readiness: config.Readiness{
+				Attempts: 5,
+				Interval: 2 * time.Second,
+			},
+			Redirects: config.Redirects{
+				Max: intPointer(10),
+			},
+			Hooks: config.Hooks{
+				Timeout: 30 * time.Second,
 			},
 		}
 		require.Equal(t, expected, actual)
@@ -53,31 +102,139 @@ func TestConfig(t *testing.T) {
 	t.Run("verify overrides are set when overrides are provided", func(t *testing.T) {
 		t.Setenv("OSIRIS_BASE_URL", "http://example.com")
 		t.Setenv("OSIRIS_BEARER_TOKEN", "test-token-123")
+		t.Setenv("OSIRIS_TLS_SERVER_NAME", "admin.example.com")
+		t.Setenv("OSIRIS_PROXY_URL", "http://proxy.example.com:3128")
+		t.Setenv("OSIRIS_PROXY_USERNAME", "proxy-user")
+		t.Setenv("OSIRIS_PROXY_PASSWORD", "proxy-pass")
 		t.Setenv("OSIRIS_CONTROL_PLANE_ID", "37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b")
 		t.Setenv("OSIRIS_LOGGER_LEVEL", "debug")
 		t.Setenv("OSIRIS_LOGGER_FILENAME", "osiris-debug.log")
 		t.Setenv("OSIRIS_LOGGER_RETENTION", "14")
+		t.Setenv("OSIRIS_LOGGER_ENCODING", "logfmt")
+		t.Setenv("OSIRIS_LOGGER_NO_COLOR", "true")
 		t.Setenv("OSIRIS_OUTPUT_FILE", "output.json")
+		t.Setenv("OSIRIS_INCLUDE_METADATA", "true")
+		t.Setenv("OSIRIS_SKIP_SUB_RESOURCE_ENRICHMENT", "true")
+		t.Setenv("OSIRIS_CONTINUE_ON_ERROR", "true")
+		t.Setenv("OSIRIS_PARTIAL_PAGES", "true")
+		t.Setenv("OSIRIS_SKIP_FAILED_PAGES", "true")
+		t.Setenv("OSIRIS_READONLY", "1")
+		t.Setenv("OSIRIS_MAX_RESPONSE_BYTES", "1024")
+		t.Setenv("OSIRIS_COMPRESS_REQUESTS", "true")
+		t.Setenv("OSIRIS_RESOURCE_RETRIES", "2")
+		t.Setenv("OSIRIS_RETRY_ON_EMPTY", "1")
+		t.Setenv("OSIRIS_DELETE_CONCURRENCY", "4")
+		t.Setenv("OSIRIS_CONTROL_PLANE_CONCURRENCY", "3")
+		t.Setenv("OSIRIS_DELETE_CONFIRM_THRESHOLD", "1000")
+		t.Setenv("OSIRIS_OPERATOR_IDENTITY", "ops@example.com")
+		t.Setenv("OSIRIS_OPERATOR_IDENTITY_HEADER", "X-Acting-As")
+		t.Setenv("OSIRIS_ERROR_FILE", "failures.json")
 		t.Setenv("OSIRIS_SANITIZE", "false")
+		t.Setenv("OSIRIS_SANITIZATION_STRATEGY", "hash")
+		t.Setenv("OSIRIS_SANITIZATION_SALT", "pepper")
+		t.Setenv("OSIRIS_SANITIZATION_SECRET_REFERENCE_TEMPLATE", "{vault://env/{{ .ID }}}")
 		t.Setenv("OSIRIS_TIMEOUTS_TIMEOUT", "20s")
 		t.Setenv("OSIRIS_TIMEOUTS_RESPONSE_HEADER", "25s")
+		t.Setenv("OSIRIS_TIMEOUTS_DIAL", "3s")
+		t.Setenv("OSIRIS_TIMEOUTS_OPERATION", "10m")
+		t.Setenv("OSIRIS_TIMEOUTS_GET", "5s")
+		t.Setenv("OSIRIS_TIMEOUTS_DELETE", "2m")
+		t.Setenv("OSIRIS_TIMEOUTS_PUT", "30s")
+		t.Setenv("OSIRIS_RETRIES_MAX_ATTEMPTS", "3")
+		t.Setenv("OSIRIS_RETRIES_MAX_WAIT", "5s")
+		t.Setenv("OSIRIS_RETRIES_JITTER", "false")
+		t.Setenv("OSIRIS_INDENT_STRING", "    ")
+		t.Setenv("OSIRIS_EMPTY_RESOURCE_POLICY", "null")
+		t.Setenv("OSIRIS_OUTPUT_KEY_CASE", "camel")
+		t.Setenv("OSIRIS_BACKOFF_STRATEGY", "linear")
+		t.Setenv("OSIRIS_BACKOFF_BASE", "500ms")
+		t.Setenv("OSIRIS_BACKOFF_MAX", "10s")
+		t.Setenv("OSIRIS_BACKOFF_JITTER", "false")
+		t.Setenv("OSIRIS_READINESS_ATTEMPTS", "10")
+		t.Setenv("OSIRIS_READINESS_INTERVAL", "1s")
+		t.Setenv("OSIRIS_REDIRECTS_MAX", "3")
+		t.Setenv("OSIRIS_REDIRECTS_ALLOW_CROSS_HOST", "true")
+		t.Setenv("OSIRIS_RATE_LIMIT_REQUESTS_PER_SECOND", "2.5")
+		t.Setenv("OSIRIS_RATE_LIMIT_PER_RESOURCE", "true")
 		actual, err := config.NewConfig()
 		require.NoError(t, err)
 
 		expected := &config.Config{
 			BaseURL:        "http://example.com",
 			BearerToken:    "test-token-123",
+			TLSServerName:  "admin.example.com",
+			ProxyURL:       "http://proxy.example.com:3128",
+			ProxyUsername:  "proxy-user",
+			ProxyPassword:  "proxy-pass",
 			ControlPlaneID: uuid.MustParse("37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b"),
 			Logger: config.Logger{
-				Level:     "debug",
-				Filename:  "osiris-debug.log",
-				Retention: 14,
+				Level:         "debug",
+				Filename:      "osiris-debug.log",
+				Retention:     14,
+				AuditFilename: "osiris-audit.log",
+				Encoding:      "logfmt",
+				NoColor:       true,
 			},
-			OutputFile: "output.json",
-			Sanitize:   false,
+			IncludeMetadata:           true,
+			SkipSubResourceEnrichment: true,
+			OutputFile:                "output.json",
+			ContinueOnError:           true,
+			PartialPages:              true,
+			SkipFailedPages:           true,
+			ReadOnly:                  true,
+			ErrorFile:                 "failures.json",
+			Sanitize:                  false,
+			Sanitization: config.Sanitization{
+				Strategy:                "hash",
+				Mask:                    "<redacted>",
+				Salt:                    "pepper",
+				SecretReferenceTemplate: "{vault://env/{{ .ID }}}",
+				Fields:                  defaultSanitizationFields,
+			},
+			MaxResponseBytes:        1024,
+			CompressRequests:        true,
+			ResourceRetries:         2,
+			RetryOnEmpty:            1,
+			DeleteConcurrency:       4,
+			ControlPlaneConcurrency: 3,
+			DeleteConfirmThreshold:  1000,
+			IndentString:            "    ",
+			EmptyResourcePolicy:     "null",
+			OutputKeyCase:           "camel",
+			OperatorIdentity:        "ops@example.com",
+			OperatorIdentityHeader:  "X-Acting-As",
 			Timeouts: config.Timeouts{
 				Timeout:        20 * time.Second,
 				ResponseHeader: 25 * time.Second,
+				Dial:           3 * time.Second,
+				Operation:      10 * time.Minute,
+				Get:            5 * time.Second,
+				Delete:         2 * time.Minute,
+				Put:            30 * time.Second,
+			},
+			Retries: config.Retries{
+				MaxAttempts: 3,
+				MaxWait:     5 * time.Second,
+			},
+			Backoff: config.Backoff{
+				Strategy: "linear",
+				Base:     500 * time.Millisecond,
+				Max:      10 * time.Second,
+			},
+			Readiness: config.Readiness{
+				Attempts: 10,
+				Interval: time.Second,
+			},
+			Redirects: config.Redirects{
+				Max:            intPointer(3),
+				AllowCrossHost: true,
+			},
+			RateLimit: config.RateLimit{
+				RequestsPerSecond: 2.5,
+				PerResource:       true,
+			},
+			Hooks: config.Hooks{
+				Timeout: 30 * time.Second,
 			},
 		}
 		require.Equal(t, expected, actual)
@@ -97,6 +254,56 @@ func TestConfig(t *testing.T) {
 		require.Equal(t, "test-token-123", actual.BearerToken)
 	})
 
+	t.Run("verify control plane ID is not defaulted when identified by name", func(t *testing.T) {
+		t.Setenv("OSIRIS_CONTROL_PLANE_NAME", "production")
+		actual, err := config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, "production", actual.ControlPlaneName)
+		require.Equal(t, uuid.Nil, actual.ControlPlaneID)
+
+		t.Setenv("OSIRIS_CONTROL_PLANE_ID", "37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b")
+		actual, err = config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, uuid.MustParse("37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b"), actual.ControlPlaneID)
+	})
+
+	t.Run("verify base URL is read from file", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "base-url")
+		require.NoError(t, os.WriteFile(filename, []byte("http://kong-admin.internal:8001/\n"), 0o600))
+		t.Setenv("OSIRIS_BASE_URL", "http://ignored.example.com")
+		t.Setenv("OSIRIS_BASE_URL_FILE", filename)
+		actual, err := config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, "http://kong-admin.internal:8001", actual.BaseURL)
+		require.Equal(t, filename, actual.BaseURLFile)
+	})
+
+	t.Run("verify invalid base URL file returns error", func(t *testing.T) {
+		t.Setenv("OSIRIS_BASE_URL_FILE", filepath.Join(t.TempDir(), "missing"))
+		_, err := config.NewConfig()
+		require.ErrorIs(t, err, os.ErrNotExist)
+
+		filename := filepath.Join(t.TempDir(), "base-url")
+		require.NoError(t, os.WriteFile(filename, []byte("kong-admin:8001"), 0o600))
+		t.Setenv("OSIRIS_BASE_URL_FILE", filename)
+		_, err = config.NewConfig()
+		require.ErrorIs(t, err, config.ErrInvalidBaseURL)
+	})
+
+	t.Run("verify base URL discovery is deferred to the application", func(t *testing.T) {
+		// The discovery URL is not requested while loading the configuration
+		t.Setenv("OSIRIS_BASE_URL", "http://localhost:8001")
+		t.Setenv("OSIRIS_BASE_URL_DISCOVERY", "http://discovery.invalid")
+		actual, err := config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, "http://localhost:8001", actual.BaseURL)
+		require.Equal(t, "http://discovery.invalid", actual.BaseURLDiscovery)
+
+		t.Setenv("OSIRIS_BASE_URL_FILE", filepath.Join(t.TempDir(), "base-url"))
+		_, err = config.NewConfig()
+		require.ErrorIs(t, err, config.ErrConflictingBaseURLSources)
+	})
+
 	t.Run("verify partial overrides work correctly", func(t *testing.T) {
 		// Only override some settings, not all
 		t.Setenv("OSIRIS_BASE_URL", "http://partial-example.com")
@@ -131,9 +338,32 @@ logger:
   retention: 14
 output_file: output.json
 sanitize: false
+sanitization:
+  strategy: drop
+  fields:
+    service:
+      - client_certificate
+resource_strip_fields:
+  service:
+    - tls_verify_depth
+resource_dependencies:
+  upstream:
+    - service
+resource_json_fields:
+  plugin:
+    - config.payload
+transforms:
+  - resource: service
+    expression: drop(tags)
 timeouts:
   timeout: 20s
   response_header: 25s
+retries:
+  max_attempts: 3
+  max_wait: 5s
+git:
+  repo_path: backups
+  author: Osiris <osiris@example.com>
 `))
 		if err != nil {
 			t.Fatalf("unable to write config file: %v", err)
@@ -149,15 +379,68 @@ timeouts:
 			BearerToken:    "test-token-123",
 			ControlPlaneID: uuid.MustParse("37b0c1f3-4a2e-4d5b-8f7c-9a2e6d5f3a1b"),
 			Logger: config.Logger{
-				Level:     "debug",
-				Filename:  "osiris-debug.log",
-				Retention: 14,
+				Level:         "debug",
+				Filename:      "osiris-debug.log",
+				Retention:     14,
+				AuditFilename: "osiris-audit.log",
+				Encoding:      "json",
 			},
 			OutputFile: "output.json",
+			ErrorFile:  "errors.json",
 			Sanitize:   false,
+			Sanitization: config.Sanitization{
+				Strategy: "drop",
+				Mask:     "<redacted>",
+				Fields: map[string][]string{
+					"service": {"client_certificate"},
+				},
+			},
+			ResourceStripFields: map[string][]string{
+				"service": {"tls_verify_depth"},
+			},
+			ResourceDependencies: map[string][]string{
+				"upstream": {"service"},
+			},
+			ResourceJSONFields: map[string][]string{
+				"plugin": {"config.payload"},
+			},
+			Transforms: []config.TransformRule{
+				{Resource: "service", Expression: "drop(tags)"},
+			},
+			MaxResponseBytes:       100 * 1024 * 1024,
+			IndentString:           "  ",
+			EmptyResourcePolicy:    "omit",
+			OutputKeyCase:          "none",
+			OperatorIdentityHeader: "X-On-Behalf-Of",
 			Timeouts: config.Timeouts{
 				Timeout:        20 * time.Second,
 				ResponseHeader: 25 * time.Second,
+				Dial:           10 * time.Second,
+			},
+			Retries: config.Retries{
+				MaxAttempts: 3,
+				MaxWait:     5 * time.Second,
+				Jitter:      true,
+			},
+			Backoff: config.Backoff{
+				Strategy: "exponential",
+				Base:     time.Second,
+				Max:      60 * time.Second,
+				Jitter:   true,
+			},
+			Readiness: config.Readiness{
+				Attempts: 5,
+				Interval: 2 * time.Second,
+			},
+			Redirects: config.Redirects{
+				Max: intPointer(10),
+			},
+			Hooks: config.Hooks{
+				Timeout: 30 * time.Second,
+			},
+			Git: config.Git{
+				RepoPath: "backups",
+				Author:   "Osiris <osiris@example.com>",
 			},
 		}
 		require.Equal(t, expected, actual)
@@ -178,9 +461,17 @@ logger:
   retention: 14
 output_file: output.json
 sanitize: false
+sanitization:
+  strategy: drop
+  fields:
+    service:
+      - client_certificate
 timeouts:
   timeout: 20s
   response_header: 25s
+retries:
+  max_attempts: 3
+  max_wait: 5s
 `))
 		if err != nil {
 			t.Fatalf("unable to write config file: %v", err)
@@ -204,15 +495,52 @@ timeouts:
 			BearerToken:    "environment-test-token-123",
 			ControlPlaneID: uuid.MustParse("869b5090-71bd-4387-be27-567d67ec286d"),
 			Logger: config.Logger{
-				Level:     "debug",
-				Filename:  "osiris-debug.log",
-				Retention: 14,
+				Level:         "debug",
+				Filename:      "osiris-debug.log",
+				Retention:     14,
+				AuditFilename: "osiris-audit.log",
+				Encoding:      "json",
 			},
 			OutputFile: "output.json",
+			ErrorFile:  "errors.json",
 			Sanitize:   false,
+			Sanitization: config.Sanitization{
+				Strategy: "drop",
+				Mask:     "<redacted>",
+				Fields: map[string][]string{
+					"service": {"client_certificate"},
+				},
+			},
+			MaxResponseBytes:       100 * 1024 * 1024,
+			IndentString:           "  ",
+			EmptyResourcePolicy:    "omit",
+			OutputKeyCase:          "none",
+			OperatorIdentityHeader: "X-On-Behalf-Of",
 			Timeouts: config.Timeouts{
 				Timeout:        20 * time.Second,
 				ResponseHeader: 25 * time.Second,
+				Dial:           10 * time.Second,
+			},
+			Retries: config.Retries{
+				MaxAttempts: 3,
+				MaxWait:     5 * time.Second,
+				Jitter:      true,
+			},
+			Backoff: config.Backoff{
+				Strategy: "exponential",
+				Base:     time.Second,
+				Max:      60 * time.Second,
+				Jitter:   true,
+			},
+			Readiness: config.Readiness{
+				Attempts: 5,
+				Interval: 2 * time.Second,
+			},
+			Redirects: config.Redirects{
+				Max: intPointer(10),
+			},
+			Hooks: config.Hooks{
+				Timeout: 30 * time.Second,
 			},
 		}
 		require.Equal(t, expected, actual)
@@ -224,4 +552,97 @@ timeouts:
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "time: invalid duration")
 	})
+
+	t.Run("verify invalid operator identity header returns error", func(t *testing.T) {
+		t.Setenv("OSIRIS_OPERATOR_IDENTITY_HEADER", "X-On Behalf: Of")
+		_, err := config.NewConfig()
+		require.ErrorIs(t, err, config.ErrInvalidHeaderName)
+	})
+
+	t.Run("verify invalid proxy URL returns error", func(t *testing.T) {
+		t.Setenv("OSIRIS_PROXY_URL", "proxy.example.com:3128")
+		_, err := config.NewConfig()
+		require.ErrorIs(t, err, config.ErrInvalidProxyURL)
+	})
+
+	t.Run("verify multiple configuration files are merged in order", func(t *testing.T) {
+		dir := t.TempDir()
+		base := filepath.Join(dir, "base.yaml")
+		require.NoError(t, os.WriteFile(base, []byte(`base_url: http://base.example.com
+output_file: base.json
+sanitization:
+  strategy: drop
+  fields:
+    service:
+      - client_certificate
+    jwt:
+      - secret
+timeouts:
+  timeout: 20s
+  response_header: 25s
+`), 0o600))
+		prod := filepath.Join(dir, "prod.yaml")
+		require.NoError(t, os.WriteFile(prod, []byte(`base_url: http://prod.example.com
+sanitization:
+  fields:
+    service:
+      - tls_verify_depth
+timeouts:
+  timeout: 30s
+`), 0o600))
+		t.Setenv("OSIRIS_OUTPUT_FILE", "env.json")
+		config.SetFiles([]string{base, prod})
+		defer config.SetFiles(nil)
+		defer viper.Reset()
+
+		actual, err := config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, "http://prod.example.com", actual.BaseURL)
+		require.Equal(t, "env.json", actual.OutputFile)
+		require.Equal(t, "drop", actual.Sanitization.Strategy)
+		require.Equal(t, map[string][]string{
+			"service": {"tls_verify_depth"},
+			"jwt":     {"secret"},
+		}, actual.Sanitization.Fields)
+		require.Equal(t, 30*time.Second, actual.Timeouts.Timeout)
+		require.Equal(t, 25*time.Second, actual.Timeouts.ResponseHeader)
+	})
+
+	t.Run("verify environment variable references in configuration files are expanded", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "osiris.yaml")
+		require.NoError(t, os.WriteFile(filename, []byte(`bearer_token: ${KONG_TOKEN}
+base_url: http://${KONG_HOST}:8001
+output_file: ${KONG_OUTPUT}
+sanitization:
+  mask: $redacted
+  salt: ${KONG_SALT}
+operator: $KONG_SALT and $${KONG_SALT} cost $$5
+`), 0o600))
+		t.Setenv("KONG_TOKEN", "token-123")
+		t.Setenv("KONG_HOST", "kong.example.com")
+		t.Setenv("KONG_OUTPUT", "kong.json")
+		t.Setenv("KONG_SALT", "pepper")
+		t.Setenv("OSIRIS_OUTPUT_FILE", "env.json")
+		config.SetFiles([]string{filename})
+		defer config.SetFiles(nil)
+		defer viper.Reset()
+
+		actual, err := config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, "token-123", actual.BearerToken)
+		require.Equal(t, "http://kong.example.com:8001", actual.BaseURL)
+		require.Equal(t, "env.json", actual.OutputFile)
+		require.Equal(t, "$redacted", actual.Sanitization.Mask)
+		require.Equal(t, "pepper", actual.Sanitization.Salt)
+		require.Equal(t, "$KONG_SALT and ${KONG_SALT} cost $5", actual.Operator)
+	})
+
+	t.Run("verify missing configuration file returns error", func(t *testing.T) {
+		config.SetFiles([]string{filepath.Join(t.TempDir(), "missing.yaml")})
+		defer config.SetFiles(nil)
+		defer viper.Reset()
+
+		_, err := config.NewConfig()
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
 }
@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ControlPlane identifies a control plane operated on as part of a fleet.
+type ControlPlane struct {
+	// ID is the control plane ID.
+	ID uuid.UUID
+	// Name is the optional name of the control plane.
+	Name string
+}
+
+// ReadControlPlanesFile reads the control planes from a file containing one
+// control plane ID, optionally followed by a comma and the control plane name,
+// per line. Blank lines and lines starting with `#` are ignored. Invalid lines
+// are skipped and returned as errors alongside the valid control planes.
+func ReadControlPlanesFile(filename string) ([]ControlPlane, []error, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to open control planes file: %w", err)
+	}
+	//nolint: errcheck
+	defer file.Close()
+
+	var controlPlanes []ControlPlane
+	var invalid []error
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if len(line) == 0 || strings.HasPrefix(line, "#") {
+			continue
+		}
+		idValue, name, _ := strings.Cut(line, ",")
+		id, err := uuid.Parse(strings.TrimSpace(idValue))
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("invalid control plane ID on line %d: %w", lineNumber, err))
+			continue
+		}
+		controlPlanes = append(controlPlanes, ControlPlane{
+			ID:   id,
+			Name: strings.TrimSpace(name),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("unable to read control planes file: %w", err)
+	}
+	return controlPlanes, invalid, nil
+}
+
+// Filename returns the filename suffixed with the control plane ID so that
+// each control plane produces its own file.
+func (c ControlPlane) Filename(filename string) string {
+	return withControlPlaneSuffix(filename, c.ID)
+}
+
+// ForControlPlane returns a copy of the configuration targeting the specified
+// control plane. The output, error, and checkpoint files are suffixed with the
+// control plane ID so that each control plane produces its own outputs.
+func (c *Config) ForControlPlane(controlPlane ControlPlane) *Config {
+	config := *c
+	config.ControlPlaneID = controlPlane.ID
+	config.ControlPlaneName = controlPlane.Name
+	config.OutputFile = withControlPlaneSuffix(c.OutputFile, controlPlane.ID)
+	config.ErrorFile = withControlPlaneSuffix(c.ErrorFile, controlPlane.ID)
+	config.CheckpointFile = withControlPlaneSuffix(c.CheckpointFile, controlPlane.ID)
+	return &config
+}
+
+// withControlPlaneSuffix adds the control plane ID to a filename before its
+// extension (e.g. `osiris.json` becomes `osiris-<id>.json`).
+func withControlPlaneSuffix(filename string, id uuid.UUID) string {
+	if len(filename) == 0 {
+		return filename
+	}
+	ext := filepath.Ext(filename)
+	return fmt.Sprintf("%s-%s%s", strings.TrimSuffix(filename, ext), id, ext)
+}
@@ -0,0 +1,71 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadControlPlanesFile(t *testing.T) {
+	t.Run("verify control planes are read and invalid lines are skipped", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), "control-planes.txt")
+		require.NoError(t, os.WriteFile(filename, []byte(`# fleet
+4168295f-015e-4190-837e-0fcc5d72a52f
+not-a-uuid
+
+c0ffee00-015e-4190-837e-0fcc5d72a52f, production
+`), 0o600))
+
+		controlPlanes, invalid, err := config.ReadControlPlanesFile(filename)
+		require.NoError(t, err)
+		require.Equal(t, []config.ControlPlane{
+			{ID: uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f")},
+			{ID: uuid.MustParse("c0ffee00-015e-4190-837e-0fcc5d72a52f"), Name: "production"},
+		}, controlPlanes)
+		require.Len(t, invalid, 1)
+		require.ErrorContains(t, invalid[0], "line 3")
+	})
+
+	t.Run("verify missing control planes file returns error", func(t *testing.T) {
+		_, _, err := config.ReadControlPlanesFile(filepath.Join(t.TempDir(), "missing.txt"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func TestForControlPlane(t *testing.T) {
+	t.Run("verify output files are suffixed with the control plane ID", func(t *testing.T) {
+		id := uuid.MustParse("4168295f-015e-4190-837e-0fcc5d72a52f")
+		base := &config.Config{
+			ControlPlaneID: uuid.New(),
+			OutputFile:     "out/osiris.json",
+			ErrorFile:      "errors.json",
+		}
+
+		actual := base.ForControlPlane(config.ControlPlane{ID: id, Name: "production"})
+		require.Equal(t, id, actual.ControlPlaneID)
+		require.Equal(t, "production", actual.ControlPlaneName)
+		require.Equal(t, "out/osiris-"+id.String()+".json", actual.OutputFile)
+		require.Equal(t, "errors-"+id.String()+".json", actual.ErrorFile)
+		require.Empty(t, actual.CheckpointFile)
+		require.Equal(t, "out/osiris.json", base.OutputFile)
+	})
+}
@@ -0,0 +1,390 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+)
+
+// DefaultExampleFilename is the default filename for the example
+// configuration file.
+const DefaultExampleFilename = "osiris.yaml"
+
+// ErrConfigExists is returned when the example configuration file already
+// exists and overwriting is not forced.
+var ErrConfigExists = errors.New("configuration file already exists")
+
+// exampleTemplate is the template for the commented example configuration
+// file; it is executed with the default configuration.
+var exampleTemplate = template.Must(template.New("example").Parse(`# Base URL for the admin API
+base_url: {{ printf "%q" .BaseURL }}
+
+# File containing the base URL, read at startup (e.g. written by an init
+# container)
+base_url_file: ""
+
+# URL returning the base URL in the body of its response, requested at startup
+# (e.g. a service discovery endpoint)
+base_url_discovery: ""
+
+# Bearer token for API authentication (OSIRIS_BEARER_TOKEN)
+bearer_token: ""
+
+# Server name used to verify the admin API certificate (defaults to the host
+# of the base URL)
+tls_server_name: ""
+
+# URL of the HTTP proxy the admin API requests are sent through, along with
+# the credentials sent in the Proxy-Authorization header (no proxy if empty)
+proxy_url: ""
+proxy_username: ""
+proxy_password: ""
+
+# Control plane ID for API requests
+control_plane_id: {{ printf "%q" .ControlPlaneID.String }}
+
+# Control plane name; when the control plane ID is not configured the ID is
+# resolved from the name using the control planes list endpoint (base URL)
+# control_plane_name: "production"
+
+# Enable/disable sanitization of response body fields
+sanitize: {{ .Sanitize }}
+
+# Sanitization of secret fields; drop removes the field, mask replaces the
+# value with the mask, hash replaces the value with a salted hash so that
+# changes can still be detected without revealing the value, and reference
+# replaces the value with a reference to where the value lives
+sanitization:
+  strategy: {{ printf "%q" .Sanitization.Strategy }}
+  mask: {{ printf "%q" .Sanitization.Mask }}
+  # Salt used by the hash strategy (random per run if empty)
+  salt: ""
+  # Template generating the reference used by the reference strategy from the
+  # resource, ID, name, and field of each secret field
+  # secret_reference_template: {{ printf "%q" "{vault://kv/kong/{{ .Resource }}/{{ .ID }}/{{ .Field }}}" }}
+  # Secret fields for each resource (dot separated for nested fields)
+  fields:
+{{- range $resource, $fields := .Sanitization.Fields }}
+    {{ $resource }}: [{{ range $i, $field := $fields }}{{ if $i }}, {{ end }}{{ printf "%q" $field }}{{ end }}]
+{{- end }}
+
+# Fields excluded from the output for each resource (dot separated for nested
+# fields)
+# resource_strip_fields:
+#   service: ["client_certificate"]
+
+# Dependencies of resources overriding their declared dependencies for the
+# apply and reset orders (replacing the declared dependencies)
+# resource_dependencies:
+#   upstream: ["certificate", "ca-certificate"]
+
+# Fields of each resource whose values are JSON encoded strings; decoded into
+# nested values in the output and encoded again when applied (dot separated
+# for nested fields)
+# resource_json_fields:
+#   plugin: ["config.payload"]
+
+# Expression based rules applied in order to the listed items of a resource
+# ("*" for every resource) using drop, rename, redact, and set
+# transforms:
+#   - resource: service
+#     expression: "rename(tags, labels); redact(host)"
+
+# Retain timestamps and certificate metadata that are otherwise stripped
+include_metadata: {{ .IncludeMetadata }}
+
+# Skip enriching the resources with their sub-resources (e.g. the consumer
+# groups of consumers and the secret keys of config stores); enriching requires
+# an additional request for each item
+skip_sub_resource_enrichment: {{ .SkipSubResourceEnrichment }}
+
+# Output file for the sanitized configuration
+output_file: {{ printf "%q" .OutputFile }}
+
+# Whitespace used to indent the output file (e.g. "\t"); the output is
+# written compact if empty
+indent_string: {{ printf "%q" .IndentString }}
+
+# How resources without items are written to the output file; omit leaves the
+# resource out, empty-array writes [], and null writes null
+empty_resource_policy: {{ printf "%q" .EmptyResourcePolicy }}
+
+# Casing of the item keys, including nested keys, in the output file; none
+# leaves the keys as returned by the admin API, snake or camel converts them
+output_key_case: {{ printf "%q" .OutputKeyCase }}
+
+# Continue with the remaining resources when a resource fails; failures are
+# written to the error file as a structured report
+continue_on_error: {{ .ContinueOnError }}
+error_file: {{ printf "%q" .ErrorFile }}
+
+# Retain the pages retrieved before a page request fails
+partial_pages: {{ .PartialPages }}
+
+# Skip a numbered page that fails and continue with the following page rather
+# than failing the resource; the skipped pages are reported as gaps
+skip_failed_pages: {{ .SkipFailedPages }}
+
+# Refuse to run the operations that modify the control plane (reset and
+# apply); dump and verify are unaffected
+readonly: {{ .ReadOnly }}
+
+# File used to persist the pagination progress of a dump so that an
+# interrupted dump can be resumed with --resume (disabled if empty)
+checkpoint_file: ""
+
+# Maximum size of a response body from the admin API in bytes
+max_response_bytes: {{ .MaxResponseBytes }}
+
+# Candidate keys of a response containing the items, tried in order (e.g. for
+# a proxy wrapping the items under results)
+# data_keys: ["data", "items", "results"]
+
+# Gzip the request bodies of PUT and PATCH requests; a request rejected with
+# 415 Unsupported Media Type is retried uncompressed and compression is disabled
+# for the remaining requests
+compress_requests: {{ .CompressRequests }}
+
+# Logger configuration
+logger:
+  level: {{ printf "%q" .Logger.Level }}
+  filename: {{ printf "%q" .Logger.Filename }}
+  retention: {{ .Logger.Retention }}
+  # Audit log recording each item deleted regardless of the log level
+  audit_filename: {{ printf "%q" .Logger.AuditFilename }}
+  # Encoding of the log lines; json, logfmt, or console (the audit log is
+  # always JSON)
+  encoding: {{ printf "%q" .Logger.Encoding }}
+  # Disable the colored levels of the console encoding; levels are only
+  # colored when stdout is a terminal and NO_COLOR is not set
+  no_color: {{ .Logger.NoColor }}
+
+# Operator recorded in the audit log
+operator: ""
+
+# Identity of the human operator behind the bearer token sent on every request
+# using the operator identity header (not sent if empty)
+operator_identity: ""
+operator_identity_header: {{ printf "%q" .OperatorIdentityHeader }}
+
+# API request timeouts; the operation timeout bounds the entire operation
+# including retries (0s disables). The get, delete, and put timeouts override
+# the request timeout for the respective requests (0s uses the request timeout)
+timeouts:
+  timeout: {{ .Timeouts.Timeout }}
+  response_header: {{ .Timeouts.ResponseHeader }}
+  # Timeout for establishing a connection (0s uses no dial timeout)
+  dial: {{ .Timeouts.Dial }}
+  operation: {{ .Timeouts.Operation }}
+  get: {{ .Timeouts.Get }}
+  delete: {{ .Timeouts.Delete }}
+  put: {{ .Timeouts.Put }}
+
+# API request retries (e.g. when rate limited or on a 5xx server error)
+retries:
+  max_attempts: {{ .Retries.MaxAttempts }}
+  max_wait: {{ .Retries.MaxWait }}
+  # Add a random duration of up to the backoff base to a Retry-After duration
+  jitter: {{ .Retries.Jitter }}
+
+# Number of times the listing of an entire resource is retried, paced using the
+# backoff, after it fails (e.g. once the request retries are exhausted)
+resource_retries: {{ .ResourceRetries }}
+
+# Number of times the listing of a resource is retried, paced using the
+# backoff, after it returns no items (e.g. eventual consistency right after
+# writes); legitimately empty resources incur every retry
+retry_on_empty: {{ .RetryOnEmpty }}
+
+# Maximum number of delete requests in flight across the resources of a reset
+# deletion level (0 leaves the deletes unbounded)
+delete_concurrency: {{ .DeleteConcurrency }}
+
+# Maximum number of control planes of a fleet (--control-planes-file) dumped or
+# reset simultaneously (0 or 1 processes them one at a time)
+control_plane_concurrency: {{ .ControlPlaneConcurrency }}
+
+# Maximum number of items of a resource a reset deletes; a reset exceeding the
+# threshold is aborted before deleting unless --allow-large-delete is given
+# (0 disables)
+delete_confirm_threshold: {{ .DeleteConfirmThreshold }}
+
+# Pacing of the attempts of a request when the admin API does not specify the
+# duration to wait (e.g. a 5xx server error, a truncated response, or no
+# Retry-After header) and of the preflight readiness attempts; constant,
+# linear, or exponential, capped to the max and optionally randomized with
+# jitter
+backoff:
+  strategy: {{ printf "%q" .Backoff.Strategy }}
+  base: {{ .Backoff.Base }}
+  max: {{ .Backoff.Max }}
+  jitter: {{ .Backoff.Jitter }}
+
+# Preflight readiness; tolerates an admin API that refuses connections while
+# it is starting, waiting using the backoff capped to the interval
+readiness:
+  attempts: {{ .Readiness.Attempts }}
+  interval: {{ .Readiness.Interval }}
+
+# Redirects issued by the admin API (e.g. by a proxy); each redirect followed is
+# logged, 0 refuses all redirects, redirects from HTTPS to HTTP are refused, and
+# redirects to a different host are refused unless allowed
+redirects:
+  max: {{ .Redirects.Max }}
+  allow_cross_host: {{ .Redirects.AllowCrossHost }}
+
+# Client-side rate limit of the API requests (disabled if 0); with per resource
+# the requests of each resource are limited independently so that a heavily
+# paginated resource does not starve the other resources
+rate_limit:
+  requests_per_second: {{ .RateLimit.RequestsPerSecond }}
+  per_resource: {{ .RateLimit.PerResource }}
+
+# External commands executed when writing the output file; {file} is replaced
+# with the output filename. The pre-write command filters the output (stdin to
+# stdout) and the post-write command runs after the output is written.
+hooks:
+  pre_write: ""
+  post_write: ""
+  timeout: {{ .Hooks.Timeout }}
+  ignore_errors: {{ .Hooks.IgnoreErrors }}
+
+# Git repository the output of a dump is committed to once the dump completes
+# successfully (the output file must be within the working tree); a commit is
+# only created when the output changed
+git:
+  repo_path: ""
+  # Template generating the commit message from the control plane ID and name,
+  # the timestamp of the dump, and the number of items dumped
+  # commit_message_template: {{ printf "%q" "Dump control plane {{ .ControlPlaneID }} at {{ .Timestamp }}" }}
+  # Author and committer of the commits (the repository identity if empty)
+  author: ""
+`))
+
+// defaultConfig returns the configuration containing the default values used
+// by NewConfig.
+func defaultConfig() *Config {
+	redirectsMax := defaultRedirectsMax
+	return &Config{
+		BaseURL:        defaultBaseURL,
+		ControlPlaneID: defaultControlPlaneID,
+		Logger: Logger{
+			Level:         defaultLoggerLevel,
+			Filename:      defaultLoggerFilename,
+			Retention:     defaultLoggerRetention,
+			AuditFilename: defaultLoggerAuditFilename,
+			Encoding:      defaultLoggerEncoding,
+		},
+		Sanitize: defaultSanitize,
+		Sanitization: Sanitization{
+			Strategy: defaultSanitizationStrategy,
+			Mask:     defaultSanitizationMask,
+			Fields:   defaultSanitizationFields,
+		},
+		IncludeMetadata:        defaultIncludeMetadata,
+		OutputFile:             defaultOutputFile,
+		IndentString:           defaultIndentString,
+		EmptyResourcePolicy:    defaultEmptyResourcePolicy,
+		OutputKeyCase:          defaultOutputKeyCase,
+		ContinueOnError:        defaultContinueOnError,
+		ReadOnly:               defaultReadOnly,
+		PartialPages:           defaultPartialPages,
+		SkipFailedPages:        defaultSkipFailedPages,
+		ErrorFile:              defaultErrorFile,
+		MaxResponseBytes:       defaultMaxResponseBytes,
+		CompressRequests:       defaultCompressRequests,
+		OperatorIdentityHeader: defaultOperatorIdentityHeader,
+		Timeouts: Timeouts{
+			Timeout:        defaultTimeoutTimeout,
+			ResponseHeader: defaultTimeoutResponseHeader,
+			Dial:           defaultTimeoutDial,
+			Operation:      defaultTimeoutOperation,
+			Get:            defaultTimeoutGet,
+			Delete:         defaultTimeoutDelete,
+			Put:            defaultTimeoutPut,
+		},
+		Retries: Retries{
+			MaxAttempts: defaultRetriesMaxAttempts,
+			MaxWait:     defaultRetriesMaxWait,
+			Jitter:      defaultRetriesJitter,
+		},
+		ResourceRetries:         defaultResourceRetries,
+		RetryOnEmpty:            defaultRetryOnEmpty,
+		DeleteConcurrency:       defaultDeleteConcurrency,
+		ControlPlaneConcurrency: defaultControlPlaneConcurrency,
+		DeleteConfirmThreshold:  defaultDeleteConfirmThreshold,
+		Backoff: Backoff{
+			Strategy: defaultBackoffStrategy,
+			Base:     defaultBackoffBase,
+			Max:      defaultBackoffMax,
+			Jitter:   defaultBackoffJitter,
+		},
+		Readiness: Readiness{
+			Attempts: defaultReadinessAttempts,
+			Interval: defaultReadinessInterval,
+		},
+		Redirects: Redirects{
+			Max:            &redirectsMax,
+			AllowCrossHost: defaultRedirectsCrossHost,
+		},
+		RateLimit: RateLimit{
+			RequestsPerSecond: defaultRateLimitRequests,
+			PerResource:       defaultRateLimitPerResource,
+		},
+		Hooks: Hooks{
+			Timeout:      defaultHooksTimeout,
+			IgnoreErrors: defaultHooksIgnoreErrors,
+		},
+	}
+}
+
+// WriteExample writes the commented example configuration, with all keys set
+// to their default values, to the writer.
+func WriteExample(w io.Writer) error {
+	if err := exampleTemplate.Execute(w, defaultConfig()); err != nil {
+		return fmt.Errorf("unable to generate example configuration: %w", err)
+	}
+	return nil
+}
+
+// WriteExampleFile writes the commented example configuration to the
+// specified file. ErrConfigExists is returned if the file already exists
+// unless force is set.
+func WriteExampleFile(filename string, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+	file, err := os.OpenFile(filename, flags, 0o600)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%w: %s", ErrConfigExists, filename)
+		}
+		return fmt.Errorf("unable to create configuration file: %w", err)
+	}
+	if err := WriteExample(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("unable to close configuration file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteExampleFile(t *testing.T) {
+	t.Run("verify example configuration parses back to the default configuration", func(t *testing.T) {
+		expected, err := config.NewConfig()
+		require.NoError(t, err)
+		viper.Reset()
+
+		dir := t.TempDir()
+		filename := filepath.Join(dir, config.DefaultExampleFilename)
+		require.NoError(t, config.WriteExampleFile(filename, false))
+		viper.AddConfigPath(dir)
+		defer viper.Reset()
+		actual, err := config.NewConfig()
+		require.NoError(t, err)
+		require.Equal(t, filename, viper.ConfigFileUsed())
+		require.Equal(t, expected, actual)
+	})
+
+	t.Run("verify existing configuration file is not overwritten", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), config.DefaultExampleFilename)
+		require.NoError(t, os.WriteFile(filename, []byte("base_url: http://example.com\n"), 0o600))
+
+		err := config.WriteExampleFile(filename, false)
+		require.ErrorIs(t, err, config.ErrConfigExists)
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		require.Equal(t, "base_url: http://example.com\n", string(data))
+	})
+
+	t.Run("verify existing configuration file is overwritten when forced", func(t *testing.T) {
+		filename := filepath.Join(t.TempDir(), config.DefaultExampleFilename)
+		require.NoError(t, os.WriteFile(filename, []byte("base_url: http://example.com\n"), 0o600))
+
+		require.NoError(t, config.WriteExampleFile(filename, true))
+		data, err := os.ReadFile(filename)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `base_url: "http://localhost:3737"`)
+	})
+}
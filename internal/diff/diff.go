@@ -0,0 +1,210 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package diff
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ResourceDiff contains the differences found for a single resource.
+type ResourceDiff struct {
+	// Name is the name of the resource.
+	Name string
+	// Added contains the keys of items present in the actual data but missing
+	// from the expected data.
+	Added []string
+	// Removed contains the keys of items present in the expected data but
+	// missing from the actual data.
+	Removed []string
+	// Changed contains the keys of items present in both the expected and
+	// actual data whose contents differ.
+	Changed []string
+}
+
+// Result contains the differences found between two dumps.
+type Result struct {
+	// Resources contains the differences for each resource that has drifted,
+	// sorted by resource name.
+	Resources []ResourceDiff
+}
+
+// Empty returns true if no differences were found.
+func (r Result) Empty() bool {
+	return len(r.Resources) == 0
+}
+
+// Summary returns a concise, human readable summary of the differences.
+func (r Result) Summary() string {
+	if r.Empty() {
+		return "no differences found"
+	}
+
+	var sb strings.Builder
+	for _, res := range r.Resources {
+		fmt.Fprintf(&sb, "%s: %d added, %d removed, %d changed\n",
+			res.Name, len(res.Added), len(res.Removed), len(res.Changed))
+		for _, key := range res.Added {
+			fmt.Fprintf(&sb, "  + %s\n", key)
+		}
+		for _, key := range res.Removed {
+			fmt.Fprintf(&sb, "  - %s\n", key)
+		}
+		for _, key := range res.Changed {
+			fmt.Fprintf(&sb, "  ~ %s\n", key)
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Compare compares the expected data against the actual data, keyed by
+// resource name, and returns the differences. Items are matched by their
+// `id` field, falling back to their `name` field. Fields listed in
+// ignoreFields are removed from both sides before comparison; nested fields
+// may be specified using a dot separated path (e.g. `config.timeout`).
+func Compare(expected map[string][]map[string]interface{}, actual map[string][]map[string]interface{},
+	ignoreFields []string,
+) Result {
+	names := make(map[string]struct{})
+	for name := range expected {
+		names[name] = struct{}{}
+	}
+	for name := range actual {
+		names[name] = struct{}{}
+	}
+
+	var result Result
+	for name := range names {
+		expectedItems := keyItems(expected[name], ignoreFields)
+		actualItems := keyItems(actual[name], ignoreFields)
+
+		resourceDiff := ResourceDiff{Name: name}
+		for key, actualItem := range actualItems {
+			expectedItem, ok := expectedItems[key]
+			if !ok {
+				resourceDiff.Added = append(resourceDiff.Added, key)
+				continue
+			}
+			if !reflect.DeepEqual(normalize(expectedItem), normalize(actualItem)) {
+				resourceDiff.Changed = append(resourceDiff.Changed, key)
+			}
+		}
+		for key := range expectedItems {
+			if _, ok := actualItems[key]; !ok {
+				resourceDiff.Removed = append(resourceDiff.Removed, key)
+			}
+		}
+
+		if len(resourceDiff.Added) == 0 && len(resourceDiff.Removed) == 0 && len(resourceDiff.Changed) == 0 {
+			continue
+		}
+		sort.Strings(resourceDiff.Added)
+		sort.Strings(resourceDiff.Removed)
+		sort.Strings(resourceDiff.Changed)
+		result.Resources = append(result.Resources, resourceDiff)
+	}
+
+	sort.Slice(result.Resources, func(i, j int) bool {
+		return result.Resources[i].Name < result.Resources[j].Name
+	})
+	return result
+}
+
+// keyItems creates a map of items keyed by their identity with the ignored
+// fields removed. The original items are not modified.
+func keyItems(items []map[string]interface{}, ignoreFields []string) map[string]map[string]interface{} {
+	keyed := make(map[string]map[string]interface{}, len(items))
+	for i, item := range items {
+		key := ItemKey(item, i)
+		keyed[key] = withoutFields(item, ignoreFields)
+	}
+	return keyed
+}
+
+// ItemKey determines the identity of an item using its `id` field, falling
+// back to its `name` field and finally its index.
+func ItemKey(item map[string]interface{}, index int) string {
+	if id, ok := item["id"]; ok && id != nil {
+		return fmt.Sprint(id)
+	}
+	if name, ok := item["name"]; ok && name != nil {
+		return fmt.Sprint(name)
+	}
+	return fmt.Sprintf("#%d", index)
+}
+
+// withoutFields returns a copy of the item with the specified fields removed.
+func withoutFields(item map[string]interface{}, fields []string) map[string]interface{} {
+	copied := make(map[string]interface{}, len(item))
+	for k, v := range item {
+		copied[k] = v
+	}
+	for _, field := range fields {
+		removeField(copied, strings.Split(field, "."))
+	}
+	return copied
+}
+
+func removeField(item map[string]interface{}, path []string) {
+	if len(path) == 1 {
+		delete(item, path[0])
+		return
+	}
+	nested, ok := item[path[0]].(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	// Copy the nested map to avoid modifying the original item
+	copied := make(map[string]interface{}, len(nested))
+	for k, v := range nested {
+		copied[k] = v
+	}
+	removeField(copied, path[1:])
+	item[path[0]] = copied
+}
+
+// normalize converts values into a comparable form so that data decoded from
+// a file and data retrieved from the API compare equally (e.g. []string and
+// []interface{}).
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			normalized[k] = normalize(val)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, len(v))
+		for i, val := range v {
+			normalized[i] = normalize(val)
+		}
+		return normalized
+	case []string:
+		normalized := make([]interface{}, len(v))
+		for i, val := range v {
+			normalized[i] = val
+		}
+		return normalized
+	case int:
+		return float64(v)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package diff_test
+
+import (
+	"testing"
+
+	"github.com/mikefero/osiris/internal/diff"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	golden := map[string][]map[string]interface{}{
+		"service": {
+			{"id": "svc-1", "name": "one", "host": "one.example.com", "updated_at": float64(1)},
+			{"id": "svc-2", "name": "two", "host": "two.example.com", "updated_at": float64(2)},
+		},
+		"route": {
+			{"id": "route-1", "paths": []interface{}{"/one"}},
+		},
+	}
+
+	t.Run("verify matching data produces no differences", func(t *testing.T) {
+		live := map[string][]map[string]interface{}{
+			"route": {
+				{"id": "route-1", "paths": []string{"/one"}},
+			},
+			"service": {
+				{"id": "svc-2", "name": "two", "host": "two.example.com", "updated_at": float64(2)},
+				{"id": "svc-1", "name": "one", "host": "one.example.com", "updated_at": float64(1)},
+			},
+		}
+		result := diff.Compare(golden, live, nil)
+		require.True(t, result.Empty())
+		require.Equal(t, "no differences found", result.Summary())
+	})
+
+	t.Run("verify drifting data is reported", func(t *testing.T) {
+		live := map[string][]map[string]interface{}{
+			"service": {
+				{"id": "svc-1", "name": "one", "host": "changed.example.com", "updated_at": float64(1)},
+				{"id": "svc-3", "name": "three", "host": "three.example.com", "updated_at": float64(3)},
+			},
+		}
+		result := diff.Compare(golden, live, nil)
+		require.False(t, result.Empty())
+		require.Equal(t, []diff.ResourceDiff{
+			{
+				Name:    "route",
+				Removed: []string{"route-1"},
+			},
+			{
+				Name:    "service",
+				Added:   []string{"svc-3"},
+				Removed: []string{"svc-2"},
+				Changed: []string{"svc-1"},
+			},
+		}, result.Resources)
+		require.Equal(t, `route: 0 added, 1 removed, 0 changed
+  - route-1
+service: 1 added, 1 removed, 1 changed
+  + svc-3
+  - svc-2
+  ~ svc-1`, result.Summary())
+	})
+
+	t.Run("verify ignored fields are not compared", func(t *testing.T) {
+		live := map[string][]map[string]interface{}{
+			"service": {
+				{"id": "svc-1", "name": "one", "host": "one.example.com", "updated_at": float64(10)},
+				{"id": "svc-2", "name": "two", "host": "two.example.com", "updated_at": float64(20)},
+			},
+			"route": {
+				{"id": "route-1", "paths": []interface{}{"/one"}},
+			},
+		}
+		require.False(t, diff.Compare(golden, live, nil).Empty())
+		require.True(t, diff.Compare(golden, live, []string{"updated_at"}).Empty())
+	})
+
+	t.Run("verify nested ignored fields are not compared", func(t *testing.T) {
+		expected := map[string][]map[string]interface{}{
+			"plugin": {
+				{"id": "plugin-1", "config": map[string]interface{}{"minute": float64(5), "seed": "a"}},
+			},
+		}
+		actual := map[string][]map[string]interface{}{
+			"plugin": {
+				{"id": "plugin-1", "config": map[string]interface{}{"minute": float64(5), "seed": "b"}},
+			},
+		}
+		require.False(t, diff.Compare(expected, actual, nil).Empty())
+		require.True(t, diff.Compare(expected, actual, []string{"config.seed"}).Empty())
+
+		// Ensure the original data was not modified
+		require.Equal(t, "a", expected["plugin"][0]["config"].(map[string]interface{})["seed"])
+	})
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package event
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Type is the type of an event.
+type Type string
+
+const (
+	// ResourceStarted is emitted when processing of a resource starts.
+	ResourceStarted Type = "resource_started"
+	// ResourceCompleted is emitted when processing of a resource completes.
+	ResourceCompleted Type = "resource_completed"
+	// ResourceFailed is emitted when processing of a resource fails.
+	ResourceFailed Type = "resource_failed"
+	// ItemDeleted is emitted for each item deleted.
+	ItemDeleted Type = "item_deleted"
+	// RequestRetried is emitted when a request is retried (e.g. when rate
+	// limited).
+	RequestRetried Type = "request_retried"
+	// TotalCounted is emitted when the total number of items of an endpoint
+	// is reported by the admin API so that progress can be estimated.
+	TotalCounted Type = "total_counted"
+	// Done is emitted once the operation completes.
+	Done Type = "done"
+)
+
+// Event is a single event of the event stream.
+type Event struct {
+	// Event is the type of the event.
+	Event Type `json:"event"`
+	// Time is the time the event was emitted.
+	Time time.Time `json:"time"`
+	// Resource is the name of the resource the event relates to, if any.
+	Resource string `json:"resource,omitempty"`
+	// Item is the ID of the item the event relates to, if any.
+	Item string `json:"item,omitempty"`
+	// Endpoint is the endpoint the event relates to, if any.
+	Endpoint string `json:"endpoint,omitempty"`
+	// Count is the number of items processed, if any.
+	Count int `json:"count,omitempty"`
+	// Total is the total number of items of the endpoint, if any; a pointer so
+	// that a reported total of zero is retained.
+	Total *int `json:"total,omitempty"`
+	// Attempt is the attempt of a retried request.
+	Attempt int `json:"attempt,omitempty"`
+	// Duration is the duration of the operation or the wait before the next
+	// attempt of a request.
+	Duration string `json:"duration,omitempty"`
+	// Error is the error message of a failure.
+	Error string `json:"error,omitempty"`
+}
+
+// Emitter writes events as JSON lines (JSONL) as they happen so that the
+// progress of an operation can be monitored by a supervising process. A nil
+// emitter discards all events.
+type Emitter struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	now     func() time.Time
+}
+
+// NewEmitter creates an emitter writing events to the writer.
+func NewEmitter(w io.Writer) *Emitter {
+	return &Emitter{
+		encoder: json.NewEncoder(w),
+		now:     time.Now,
+	}
+}
+
+// Emit writes the event to the event stream; the time of the event is set
+// when emitted. Events are best effort and write errors are ignored so that
+// monitoring never fails the operation.
+func (e *Emitter) Emit(event Event) {
+	if e == nil {
+		return
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	event.Time = e.now().UTC()
+	_ = e.encoder.Encode(event)
+}
@@ -0,0 +1,46 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package event
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmitter(t *testing.T) {
+	t.Run("verify events are written as JSON lines", func(t *testing.T) {
+		var buf bytes.Buffer
+		emitter := NewEmitter(&buf)
+		emitter.now = func() time.Time { return time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+		emitter.Emit(Event{Event: ResourceStarted, Resource: "service"})
+		emitter.Emit(Event{Event: Done, Count: 2})
+		require.Equal(t,
+			`{"event":"resource_started","time":"2025-01-02T03:04:05Z","resource":"service"}`+"\n"+
+				`{"event":"done","time":"2025-01-02T03:04:05Z","count":2}`+"\n",
+			buf.String())
+	})
+
+	t.Run("verify nil emitter discards events", func(t *testing.T) {
+		var emitter *Emitter
+		require.NotPanics(t, func() {
+			emitter.Emit(Event{Event: Done})
+		})
+	})
+}
@@ -0,0 +1,169 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/jmespath/go-jmespath"
+)
+
+// ErrInvalidFilter is returned when a filter expression cannot be parsed.
+var ErrInvalidFilter = errors.New("invalid filter expression")
+
+// AllResources is the resource of a filter expression without a resource
+// name; such a filter applies to the items of every resource.
+const AllResources = "*"
+
+// itemFilter is a parsed filter expression.
+type itemFilter struct {
+	resource   string
+	projection *jmespath.JMESPath
+}
+
+// Filters keeps only the items of resources matching JMESPath filter
+// expressions (e.g. `service[?protocol == 'grpc']`). An expression is an
+// optional resource name followed by a single JMESPath filter projection; an
+// expression without a resource name applies to the items of every resource.
+type Filters struct {
+	filters []itemFilter
+}
+
+// New parses the filter expressions; an error wrapping ErrInvalidFilter is
+// returned if an expression is not a resource name followed by a filter
+// projection.
+func New(expressions []string) (*Filters, error) {
+	filters := make([]itemFilter, 0, len(expressions))
+	for _, expression := range expressions {
+		trimmed := strings.TrimSpace(expression)
+		projection := strings.TrimLeftFunc(trimmed, func(c rune) bool {
+			return unicode.IsLetter(c) || unicode.IsDigit(c) || c == '_' || c == '-'
+		})
+		resource := strings.TrimSpace(trimmed[:len(trimmed)-len(projection)])
+		projection = strings.TrimSpace(projection)
+		predicate, ok := strings.CutPrefix(projection, "[?")
+		if ok {
+			predicate, ok = strings.CutSuffix(predicate, "]")
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: expected a filter projection '[?...]' in %q", ErrInvalidFilter, expression)
+		}
+
+		// The predicate must be an expression on its own so that the
+		// projection is a single filter rather than a filter followed by
+		// further expressions (e.g. `[?a].b[0]`)
+		if _, err := jmespath.Compile(predicate); err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidFilter, expression, err)
+		}
+		compiled, err := jmespath.Compile(projection)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q: %w", ErrInvalidFilter, expression, err)
+		}
+		if len(resource) == 0 {
+			resource = AllResources
+		}
+		filters = append(filters, itemFilter{resource: resource, projection: compiled})
+	}
+	return &Filters{filters: filters}, nil
+}
+
+// Resources returns the resources matched by the filters; AllResources is
+// returned for a filter applying to every resource.
+func (f *Filters) Resources() []string {
+	resources := make([]string, 0, len(f.filters))
+	for _, filter := range f.filters {
+		resources = append(resources, filter.resource)
+	}
+	return resources
+}
+
+// Filter returns the items of the resource matching every filter applying to
+// the resource; the items are returned as is if no filter applies. An item
+// is excluded if a filter cannot be evaluated against it.
+func (f *Filters) Filter(resourceName string, items []map[string]interface{}) []map[string]interface{} {
+	if f == nil {
+		return items
+	}
+	var projections []*jmespath.JMESPath
+	for _, filter := range f.filters {
+		if filter.resource == AllResources || filter.resource == resourceName {
+			projections = append(projections, filter.projection)
+		}
+	}
+	if len(projections) == 0 {
+		return items
+	}
+
+	filtered := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		// Each item is projected on its own so that the matched items are
+		// returned as is rather than normalized
+		normalized := []interface{}{normalizeValue(item)}
+		matched := true
+		for _, projection := range projections {
+			result, err := projection.Search(normalized)
+			if matches, ok := result.([]interface{}); err != nil || !ok || len(matches) == 0 {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// normalizeValue converts the numbers of the value, including nested
+// numbers, to float64 since JMESPath only compares float64 numbers.
+func normalizeValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		number, err := v.Float64()
+		if err != nil {
+			return v.String()
+		}
+		return number
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			normalized[key] = normalizeValue(nested)
+		}
+		return normalized
+	case []interface{}:
+		normalized := make([]interface{}, 0, len(v))
+		for _, nested := range v {
+			normalized = append(normalized, normalizeValue(nested))
+		}
+		return normalized
+	case []map[string]interface{}:
+		normalized := make([]interface{}, 0, len(v))
+		for _, nested := range v {
+			normalized = append(normalized, normalizeValue(nested))
+		}
+		return normalized
+	default:
+		return value
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package filter_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func newServices() []map[string]interface{} {
+	return []map[string]interface{}{
+		{"name": "grpc-a", "protocol": "grpc", "port": json.Number("9000"), "tags": []interface{}{"edge"}},
+		{"name": "http-a", "protocol": "http", "port": json.Number("80"), "tags": []interface{}{}},
+		{"name": "grpc-b", "protocol": "grpc", "port": json.Number("9443"), "config": map[string]interface{}{
+			"enabled": true,
+		}},
+	}
+}
+
+func filteredNames(items []map[string]interface{}) []string {
+	var result []string
+	for _, item := range items {
+		result = append(result, item["name"].(string))
+	}
+	return result
+}
+
+func TestFilters(t *testing.T) {
+	t.Run("verify items not matching the predicate are excluded", func(t *testing.T) {
+		filters, err := filter.New([]string{"service[?protocol == 'grpc']"})
+		require.NoError(t, err)
+
+		items := filters.Filter("service", newServices())
+		require.Equal(t, []string{"grpc-a", "grpc-b"}, filteredNames(items))
+	})
+
+	t.Run("verify filters only apply to their resource", func(t *testing.T) {
+		filters, err := filter.New([]string{"route[?protocol == 'grpc']"})
+		require.NoError(t, err)
+
+		items := filters.Filter("service", newServices())
+		require.Equal(t, []string{"grpc-a", "http-a", "grpc-b"}, filteredNames(items))
+	})
+
+	t.Run("verify a filter without a resource applies to every resource", func(t *testing.T) {
+		filters, err := filter.New([]string{"[?tags]"})
+		require.NoError(t, err)
+		require.Equal(t, []string{filter.AllResources}, filters.Resources())
+
+		items := filters.Filter("service", newServices())
+		require.Equal(t, []string{"grpc-a"}, filteredNames(items))
+	})
+
+	t.Run("verify numbers, literals, functions, and logical operators", func(t *testing.T) {
+		tests := []struct {
+			expression string
+			expected   []string
+		}{
+			{expression: "service[?port >= `9000` && port < `9443`]", expected: []string{"grpc-a"}},
+			{expression: "service[?port == `80` || config.enabled]", expected: []string{"http-a", "grpc-b"}},
+			{expression: "service[?!(protocol == 'grpc')]", expected: []string{"http-a"}},
+			{expression: `service[?"name" != 'http-a' && !config]`, expected: []string{"grpc-a"}},
+			{expression: "service[?tags == `[\"edge\"]`]", expected: []string{"grpc-a"}},
+			{expression: "service[?protocol > 'a']", expected: nil},
+			{expression: "service[?contains(tags, 'edge')]", expected: []string{"grpc-a"}},
+			{expression: "service[?starts_with(name, 'grpc')]", expected: []string{"grpc-a", "grpc-b"}},
+		}
+		for _, test := range tests {
+			filters, err := filter.New([]string{test.expression})
+			require.NoError(t, err, test.expression)
+			require.Equal(t, test.expected, filteredNames(filters.Filter("service", newServices())), test.expression)
+		}
+	})
+
+	t.Run("verify every filter of a resource must match", func(t *testing.T) {
+		filters, err := filter.New([]string{
+			"service[?protocol == 'grpc']",
+			"[?port > `9000`]",
+		})
+		require.NoError(t, err)
+
+		items := filters.Filter("service", newServices())
+		require.Equal(t, []string{"grpc-b"}, filteredNames(items))
+	})
+
+	t.Run("verify a nil filter returns the items as is", func(t *testing.T) {
+		var filters *filter.Filters
+		require.Len(t, filters.Filter("service", newServices()), 3)
+	})
+
+	t.Run("verify invalid expressions are rejected", func(t *testing.T) {
+		for _, expression := range []string{
+			"service",
+			"service[?]",
+			"service[?protocol == 'grpc'",
+			"service[?protocol == 'grpc",
+			"service[?port == `{`]",
+			"service[?(protocol == 'grpc']",
+			"service[?protocol == 'grpc'] extra",
+			"service[?protocol == 'grpc'].name",
+			"service[?protocol == 'grpc'] | [0]",
+			"service[0]",
+		} {
+			_, err := filter.New([]string{expression})
+			require.ErrorIs(t, err, filter.ErrInvalidFilter, expression)
+		}
+	})
+}
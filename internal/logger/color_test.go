@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColor(t *testing.T) {
+	// logConsole logs a warning with stdout forced to be a terminal or not and
+	// returns the log line.
+	logConsole := func(t *testing.T, config config.Logger, terminal bool) string {
+		t.Helper()
+		isTerminalFunc := isTerminal
+		isTerminal = func(*os.File) bool { return terminal }
+		defer func() {
+			isTerminal = isTerminalFunc
+		}()
+
+		config.Level, config.Encoding = "info", "console"
+		config.Filename = filepath.Join(t.TempDir(), "osiris.log")
+		log, err := NewLogger(config, LoggerCommandTypeDump)
+		require.NoError(t, err)
+		log.Warn("Starting dump")
+		require.NoError(t, log.Sync())
+		data, err := os.ReadFile(config.Filename)
+		require.NoError(t, err)
+		return string(data)
+	}
+
+	t.Run("verify console levels are colored when stdout is a terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		require.Contains(t, logConsole(t, config.Logger{}, true), "\x1b[33mwarn\x1b[0m")
+	})
+
+	t.Run("verify console levels are not colored when stdout is not a terminal", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		line := logConsole(t, config.Logger{}, false)
+		require.Contains(t, line, "\twarn\t")
+		require.NotContains(t, line, "\x1b[")
+	})
+
+	t.Run("verify NO_COLOR disables colored levels", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "1")
+		require.NotContains(t, logConsole(t, config.Logger{}, true), "\x1b[")
+	})
+
+	t.Run("verify no color disables colored levels", func(t *testing.T) {
+		t.Setenv("NO_COLOR", "")
+		require.NotContains(t, logConsole(t, config.Logger{NoColor: true}, true), "\x1b[")
+	})
+}
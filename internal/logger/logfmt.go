@@ -0,0 +1,139 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// logfmtTimeLayout is the layout of the entry time; ISO8601 as with the JSON
+// encoding.
+const logfmtTimeLayout = "2006-01-02T15:04:05.000Z0700"
+
+var logfmtPool = buffer.NewPool()
+
+// logfmtEncoder is a zapcore.Encoder writing each entry as a single line of
+// space separated key=value pairs. The entry time, level, and message come
+// first, followed by the context fields (e.g. the command) in key order and
+// the fields of the entry in the order they are logged.
+type logfmtEncoder struct {
+	*zapcore.MapObjectEncoder
+}
+
+// newLogfmtEncoder creates a new logfmt encoder.
+func newLogfmtEncoder() zapcore.Encoder {
+	return &logfmtEncoder{MapObjectEncoder: zapcore.NewMapObjectEncoder()}
+}
+
+// Clone copies the encoder along with its context fields.
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for key, value := range e.Fields {
+		clone.Fields[key] = value
+	}
+	return &logfmtEncoder{MapObjectEncoder: clone}
+}
+
+// EncodeEntry encodes the entry and its fields as a logfmt line.
+func (e *logfmtEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	line := logfmtPool.Get()
+	writePair := func(key string, value interface{}) {
+		if line.Len() > 0 {
+			line.AppendByte(' ')
+		}
+		line.AppendString(key)
+		line.AppendByte('=')
+		line.AppendString(logfmtValue(value))
+	}
+
+	writePair("ts", entry.Time)
+	writePair("level", entry.Level.String())
+	if len(entry.LoggerName) > 0 {
+		writePair("logger", entry.LoggerName)
+	}
+	writePair("msg", entry.Message)
+	writeFields(e.Fields, writePair)
+	for _, field := range fields {
+		// Encode each field on its own so that the fields keep their order
+		encoder := zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+		writeFields(encoder.Fields, writePair)
+	}
+	if len(entry.Stack) > 0 {
+		writePair("stacktrace", entry.Stack)
+	}
+	line.AppendString(zapcore.DefaultLineEnding)
+	return line, nil
+}
+
+// writeFields writes the fields in key order.
+func writeFields(fields map[string]interface{}, writePair func(string, interface{})) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		writePair(key, fields[key])
+	}
+}
+
+// logfmtValue formats the value of a field, quoting the value if it is empty
+// or contains spaces, quotes, equals signs, or non-printable characters.
+// Arrays and objects are formatted as JSON.
+func logfmtValue(value interface{}) string {
+	var formatted string
+	switch value := value.(type) {
+	case string:
+		formatted = value
+	case time.Time:
+		formatted = value.Format(logfmtTimeLayout)
+	case time.Duration:
+		formatted = value.String()
+	case []byte:
+		formatted = base64.StdEncoding.EncodeToString(value)
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128:
+		formatted = fmt.Sprint(value)
+	case error:
+		formatted = value.Error()
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			formatted = fmt.Sprint(value)
+		} else {
+			formatted = string(data)
+		}
+	}
+
+	needsQuotes := len(formatted) == 0 || strings.IndexFunc(formatted, func(r rune) bool {
+		return r == ' ' || r == '=' || r == '"' || !unicode.IsPrint(r)
+	}) >= 0
+	if needsQuotes {
+		return strconv.Quote(formatted)
+	}
+	return formatted
+}
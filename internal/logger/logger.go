@@ -16,14 +16,28 @@ limitations under the License.
 package logger
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"sync"
 
 	"github.com/mikefero/osiris/internal/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
+// ErrInvalidEncoding is returned when the log encoding is not a known
+// encoding.
+var ErrInvalidEncoding = errors.New("log encoding must be json, logfmt, or console")
+
+// isTerminal returns true if the file is a terminal; the levels are only
+// colored when stdout is a terminal.
+var isTerminal = func(file *os.File) bool {
+	return term.IsTerminal(int(file.Fd()))
+}
+
 // LoggerCommandType is the type of command for the logger.
 type LoggerCommandType int
 
@@ -32,6 +46,12 @@ const (
 	LoggerCommandTypeDump LoggerCommandType = iota
 	// LoggerCommandTypeReset is the command type for reset.
 	LoggerCommandTypeReset
+	// LoggerCommandTypeVerify is the command type for verify.
+	LoggerCommandTypeVerify
+	// LoggerCommandTypeApply is the command type for apply.
+	LoggerCommandTypeApply
+	// LoggerCommandTypeGet is the command type for get.
+	LoggerCommandTypeGet
 )
 
 // LoggerCommandTypeString returns the string representation of the command type.
@@ -39,11 +59,15 @@ func (l LoggerCommandType) String() string {
 	return [...]string{
 		"dump",
 		"reset",
+		"verify",
+		"apply",
+		"get",
 	}[l]
 }
 
 // NewLogger creates a new zap logger with the specified configuration and command type.
-// It uses lumberjack for log rotation and compression.
+// It uses lumberjack for log rotation and compression; the console encoding is
+// colored only when stdout is a terminal.
 // The log level is set based on the configuration.
 // The command type is added as a field to the logger.
 // Returns a zap.Logger instance and an error if any occurs during creation.
@@ -52,25 +76,90 @@ func NewLogger(config config.Logger, commandType LoggerCommandType) (*zap.Logger
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse log level: %w", err)
 	}
+	encoder, err := newEncoder(config.Encoding, colorEnabled(config))
+	if err != nil {
+		return nil, err
+	}
+
+	core := zapcore.NewCore(
+		encoder,
+		rotatedFile(config.Filename, config.Retention),
+		zapLoggerLevel,
+	).With([]zapcore.Field{
+		zap.String("command", commandType.String()),
+	})
+	zapLogger := zap.New(core)
+	return zapLogger, nil
+}
+
+// rotatedFiles are the rotated log files opened by the process, keyed by
+// filename.
+var rotatedFiles = struct {
+	sync.Mutex
+	files map[string]*lumberjack.Logger
+}{files: make(map[string]*lumberjack.Logger)}
+
+// rotatedFile returns the daily rotated log file of the filename. The loggers
+// writing to the same file (e.g. the operations of the control planes of a
+// fleet running simultaneously) share a single rotated file so that their
+// writes and rotations are serialized.
+func rotatedFile(filename string, retention int) zapcore.WriteSyncer {
+	rotatedFiles.Lock()
+	defer rotatedFiles.Unlock()
+	file, ok := rotatedFiles.files[filename]
+	if !ok {
+		file = &lumberjack.Logger{
+			Filename:   filename,
+			MaxSize:    0, // unlimited
+			MaxBackups: retention,
+			MaxAge:     retention,
+			Compress:   true,
+		}
+		rotatedFiles.files[filename] = file
+	}
+	return zapcore.AddSync(file)
+}
+
+// colorEnabled returns false if stdout is not a terminal or if colored output
+// is disabled by the configuration or the NO_COLOR environment variable
+// (https://no-color.org).
+func colorEnabled(config config.Logger) bool {
+	return !config.NoColor && len(os.Getenv("NO_COLOR")) == 0 && isTerminal(os.Stdout)
+}
 
-	// Add daily log rotator for zap logger
-	logger := &lumberjack.Logger{
-		Filename:   config.Filename,
-		MaxSize:    0, // unlimited
-		MaxBackups: config.Retention,
-		MaxAge:     config.Retention,
-		Compress:   true,
+// newEncoder creates the encoder of the log lines for the encoding; JSON is
+// used if the encoding is empty. The levels of the console encoding are
+// colored if color is true.
+func newEncoder(encoding string, color bool) (zapcore.Encoder, error) {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	switch encoding {
+	case "", "json":
+		return zapcore.NewJSONEncoder(encoderConfig), nil
+	case "logfmt":
+		return newLogfmtEncoder(), nil
+	case "console":
+		if color {
+			encoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+		}
+		return zapcore.NewConsoleEncoder(encoderConfig), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrInvalidEncoding, encoding)
 	}
+}
 
+// NewAuditLogger creates a new zap logger for the audit log using the audit
+// filename of the specified configuration. Audit entries are written at the
+// info level and are always recorded regardless of the configured log level.
+func NewAuditLogger(config config.Logger, commandType LoggerCommandType) *zap.Logger {
 	encoderConfig := zap.NewProductionEncoderConfig()
 	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	core := zapcore.NewCore(
 		zapcore.NewJSONEncoder(encoderConfig),
-		zapcore.AddSync(logger),
-		zapLoggerLevel,
+		rotatedFile(config.AuditFilename, config.Retention),
+		zap.InfoLevel,
 	).With([]zapcore.Field{
 		zap.String("command", commandType.String()),
 	})
-	zapLogger := zap.New(core)
-	return zapLogger, nil
+	return zap.New(core)
 }
@@ -16,9 +16,14 @@ limitations under the License.
 package logger_test
 
 import (
+	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/mikefero/osiris/internal/config"
 	"github.com/mikefero/osiris/internal/logger"
@@ -39,6 +44,21 @@ func TestLogger(t *testing.T) {
 				cmdType:  logger.LoggerCommandTypeDump,
 				expected: "dump",
 			},
+			{
+				name:     "verify command",
+				cmdType:  logger.LoggerCommandTypeVerify,
+				expected: "verify",
+			},
+			{
+				name:     "apply command",
+				cmdType:  logger.LoggerCommandTypeApply,
+				expected: "apply",
+			},
+			{
+				name:     "get command",
+				cmdType:  logger.LoggerCommandTypeGet,
+				expected: "get",
+			},
 		}
 
 		for _, tt := range tests {
@@ -127,4 +147,84 @@ func TestLogger(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("verify audit entries are written regardless of log level", func(t *testing.T) {
+		dir := t.TempDir()
+		config := config.Logger{
+			Level:         "error",
+			Filename:      filepath.Join(dir, "osiris.log"),
+			AuditFilename: filepath.Join(dir, "osiris-audit.log"),
+		}
+		auditLogger := logger.NewAuditLogger(config, logger.LoggerCommandTypeReset)
+		auditLogger.Info("Deleted item", zap.String("resource", "service"))
+		require.NoError(t, auditLogger.Sync())
+
+		data, err := os.ReadFile(config.AuditFilename)
+		require.NoError(t, err)
+		require.Contains(t, string(data), `"msg":"Deleted item"`)
+		require.Contains(t, string(data), `"resource":"service"`)
+		require.Contains(t, string(data), `"command":"reset"`)
+	})
+	t.Run("verify log lines are written in logfmt", func(t *testing.T) {
+		dir := t.TempDir()
+		config := config.Logger{
+			Level:    "info",
+			Filename: filepath.Join(dir, "osiris.log"),
+			Encoding: "logfmt",
+		}
+		log, err := logger.NewLogger(config, logger.LoggerCommandTypeDump)
+		require.NoError(t, err)
+		log.Info("Starting dump",
+			zap.String("resource", "service"),
+			zap.Int("item-count", 3),
+			zap.Duration("duration", 1500*time.Millisecond),
+			zap.Error(errors.New("connection refused")))
+		require.NoError(t, log.Sync())
+
+		data, err := os.ReadFile(config.Filename)
+		require.NoError(t, err)
+		line := strings.TrimSpace(string(data))
+		require.Regexp(t, `^ts=\S+ level=info msg="Starting dump" command=dump `, line)
+		require.True(t, strings.HasSuffix(line,
+			`resource=service item-count=3 duration=1.5s error="connection refused"`), line)
+	})
+
+	t.Run("verify loggers of the same file share the rotated file", func(t *testing.T) {
+		dir := t.TempDir()
+		config := config.Logger{
+			Level:    "info",
+			Filename: filepath.Join(dir, "osiris.log"),
+		}
+		var wg sync.WaitGroup
+		for range 4 {
+			log, err := logger.NewLogger(config, logger.LoggerCommandTypeDump)
+			require.NoError(t, err)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 100 {
+					log.Info("Listed resource", zap.String("resource", strings.Repeat("service", 64)))
+				}
+			}()
+		}
+		wg.Wait()
+
+		data, err := os.ReadFile(config.Filename)
+		require.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		require.Len(t, lines, 400)
+		for _, line := range lines {
+			require.True(t, json.Valid([]byte(line)), line)
+		}
+	})
+
+	t.Run("verify unknown log encoding is rejected", func(t *testing.T) {
+		config := config.Logger{
+			Level:    "info",
+			Filename: filepath.Join(t.TempDir(), "osiris.log"),
+			Encoding: "xml",
+		}
+		_, err := logger.NewLogger(config, logger.LoggerCommandTypeDump)
+		require.ErrorIs(t, err, logger.ErrInvalidEncoding)
+	})
 }
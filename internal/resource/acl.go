@@ -27,6 +27,7 @@ func NewACL() Resource {
 			name:         "acl",
 			path:         "acls",
 			dependencies: []string{"consumer"},
+			references:   map[string]string{"consumer": "consumer"},
 		},
 	}
 }
@@ -28,6 +28,7 @@ func NewBasicAuth() Resource {
 			name:         "basic-auth",
 			path:         "basic-auths",
 			dependencies: []string{"consumer"},
+			references:   map[string]string{"consumer": "consumer"},
 		},
 	}
 }
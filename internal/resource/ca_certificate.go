@@ -17,10 +17,8 @@ package resource
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/mikefero/osiris/internal/client"
-	"go.uber.org/zap"
 )
 
 // CACertificateResource represents SSL CA Certificates in Kong Gateway.
@@ -34,35 +32,22 @@ func NewCACertificate() Resource {
 		BaseResource: BaseResource{
 			name: "ca-certificate",
 			path: "ca_certificates",
+			// Remove metadata from CA certificates before returning
+			listTransform: cleanCertificateData,
 		},
 	}
 }
 
-// List retrieves a list of CA certificates from the Kong Gateway and removes
-// metadata from the response.
-func (r *CACertificateResource) List(ctx context.Context, client *client.Client, logger *zap.Logger) (
-	ResourceData, error,
-) {
-	caCertificateData, err := client.GetEndpoint(ctx, r.path)
-	if err != nil {
-		return ResourceData{}, fmt.Errorf("failed to list CA certificates: %w", err)
+// cleanCertificateData removes the metadata from the certificates unless
+// metadata is to be included.
+func cleanCertificateData(_ context.Context, client *client.Client,
+	certificates []map[string]interface{},
+) ([]map[string]interface{}, error) {
+	if client.IncludeMetadata() {
+		return certificates, nil
 	}
-	if len(caCertificateData) == 0 {
-		logger.Debug("No data found for resource",
-			zap.String("resource", r.name))
-		return ResourceData{}, nil
-	}
-
-	// Remove metadata from CA certificates before returning
-	return ResourceData{
-		Data: cleanCertificateData(caCertificateData),
-		Name: r.Name(),
-	}, nil
-}
-
-func cleanCertificateData(certificates []map[string]interface{}) []map[string]interface{} {
 	for i := range certificates {
 		delete(certificates[i], "metadata")
 	}
-	return certificates
+	return certificates, nil
 }
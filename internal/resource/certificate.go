@@ -15,14 +15,6 @@ limitations under the License.
 */
 package resource
 
-import (
-	"context"
-	"fmt"
-
-	"github.com/mikefero/osiris/internal/client"
-	"go.uber.org/zap"
-)
-
 // CertificateResource represents SSL Certificates in Kong Gateway.
 type CertificateResource struct {
 	BaseResource
@@ -34,28 +26,8 @@ func NewCertificate() Resource {
 		BaseResource: BaseResource{
 			name: "certificate",
 			path: "certificates",
+			// Remove metadata from certificates before returning
+			listTransform: cleanCertificateData,
 		},
 	}
 }
-
-// List retrieves a list of certificates from the Kong Gateway and removes
-// metadata from the response.
-func (r *CertificateResource) List(ctx context.Context, client *client.Client, logger *zap.Logger) (
-	ResourceData, error,
-) {
-	certificateData, err := client.GetEndpoint(ctx, r.path)
-	if err != nil {
-		return ResourceData{}, fmt.Errorf("failed to list certificates: %w", err)
-	}
-	if len(certificateData) == 0 {
-		logger.Debug("No data found for resource",
-			zap.String("resource", r.name))
-		return ResourceData{}, nil
-	}
-
-	// Remove metadata from certificates before returning
-	return ResourceData{
-		Data: cleanCertificateData(certificateData),
-		Name: r.Name(),
-	}, nil
-}
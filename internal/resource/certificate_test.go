@@ -0,0 +1,79 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestClient(t *testing.T, handler http.Handler, includeMetadata bool) *client.Client {
+	t.Helper()
+	return newConfiguredTestClient(t, handler, func(config *config.Config) {
+		config.IncludeMetadata = includeMetadata
+	})
+}
+
+func newConfiguredTestClient(t *testing.T, handler http.Handler, configure func(*config.Config)) *client.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	config := &config.Config{
+		BaseURL:        server.URL,
+		ControlPlaneID: uuid.New(),
+	}
+	configure(config)
+	return client.NewClient(config, zap.NewNop())
+}
+
+func TestCertificate(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"data":[{"id":"cert-1","created_at":1,"metadata":{"issuer":"test"}}]}`))
+	})
+
+	for _, res := range []resource.Resource{resource.NewCertificate(), resource.NewCACertificate()} {
+		t.Run("verify metadata is removed by default for "+res.Name(), func(t *testing.T) {
+			client := newTestClient(t, handler, false)
+			data, err := res.List(context.Background(), client, zap.NewNop())
+			require.NoError(t, err)
+			require.Equal(t, []map[string]interface{}{{"id": "cert-1"}}, data.Data)
+		})
+
+		t.Run("verify metadata is retained when included for "+res.Name(), func(t *testing.T) {
+			client := newTestClient(t, handler, true)
+			data, err := res.List(context.Background(), client, zap.NewNop())
+			require.NoError(t, err)
+			require.Equal(t, []map[string]interface{}{
+				{
+					"id":         "cert-1",
+					"created_at": json.Number("1"),
+					"metadata":   map[string]interface{}{"issuer": "test"},
+				},
+			}, data.Data)
+		})
+	}
+}
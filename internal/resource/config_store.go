@@ -34,6 +34,8 @@ func NewConfigStore() Resource {
 		BaseResource: BaseResource{
 			name: "config-store",
 			path: "config-stores",
+			// Secrets are deleted before the config store
+			childPaths: []string{"secrets"},
 		},
 	}
 }
@@ -52,31 +54,8 @@ func (r *ConfigStoreResource) List(ctx context.Context, client *client.Client, l
 		return ResourceData{}, nil
 	}
 
-	// Gather consumer IDs to determine if they are part of a consumer group
-	for i, configStore := range configStoreData {
-		id, ok := configStore["id"].(string)
-		if !ok {
-			return ResourceData{}, fmt.Errorf("invalid config store ID for item %d", i)
-		}
-
-		// List secrets keys for this config store since the values are not
-		// returned in the list
-		secretsPath := fmt.Sprintf("%s/%s/secrets", r.path, id)
-		secrets, _ := client.GetEndpoint(ctx, secretsPath)
-		if len(secrets) > 0 {
-			secretKeys := make([]string, len(secrets))
-			for j, secret := range secrets {
-				secretKey, ok := secret["key"].(string)
-				if !ok {
-					return ResourceData{}, fmt.Errorf("invalid secret key for item %d in config store %d", i, j)
-				}
-				secretKeys[j] = secretKey
-			}
-			configStore["secret"] = secretKeys
-		}
-
-		// Update the config store data with the modified config store
-		configStoreData[i] = configStore
+	if err := r.addSecretKeys(ctx, client, configStoreData, logger); err != nil {
+		return ResourceData{}, err
 	}
 
 	return ResourceData{
@@ -85,31 +64,64 @@ func (r *ConfigStoreResource) List(ctx context.Context, client *client.Client, l
 	}, nil
 }
 
-func (r *ConfigStoreResource) Delete(ctx context.Context, client *client.Client, item map[string]interface{},
+// Stream retrieves the config stores page by page and includes the keys of
+// their secrets.
+func (r *ConfigStoreResource) Stream(ctx context.Context, client *client.Client, fn client.PageFunc,
 	logger *zap.Logger,
 ) error {
-	id, ok := item["id"].(string)
-	if !ok {
-		return fmt.Errorf("invalid config store ID: %v", item)
-	}
+	return r.stream(ctx, client, r.secretKeysTransform(logger), fn, logger)
+}
 
-	// Delete secrets keys for this config store
-	secrets, ok := item["secret"].([]string)
-	if ok && len(secrets) > 0 {
-		for _, secretKey := range secrets {
-			// Construct the path to delete the secret
-			secretPath := fmt.Sprintf("%s/%s/secrets/%s", r.path, id, secretKey)
-			if err := client.DeleteEndpoint(ctx, secretPath); err != nil {
-				return fmt.Errorf("failed to delete secret %s for config store %s: %w", secretKey, id, err)
-			}
-		}
+// secretKeysTransform returns the list transform adding the secret keys of each
+// config store.
+func (r *ConfigStoreResource) secretKeysTransform(logger *zap.Logger) ListTransformFunc {
+	return func(ctx context.Context, client *client.Client, configStores []map[string]interface{}) (
+		[]map[string]interface{}, error,
+	) {
+		return configStores, r.addSecretKeys(ctx, client, configStores, logger)
 	}
+}
 
-	// Delete the config store by ID
-	path := fmt.Sprintf("%s/%s", r.path, id)
-	if err := client.DeleteEndpoint(ctx, path); err != nil {
-		return fmt.Errorf("failed to delete config store %s: %w", id, err)
+// addSecretKeys adds the keys of the secrets of each config store to the
+// `secret` field of the config store unless sub-resource enrichment is
+// disabled.
+func (r *ConfigStoreResource) addSecretKeys(ctx context.Context, client *client.Client,
+	configStores []map[string]interface{}, logger *zap.Logger,
+) error {
+	if !client.EnrichSubResources() {
+		logger.Debug("Skipping sub-resource enrichment",
+			zap.String("resource", r.name))
+		return nil
 	}
+	for i, configStore := range configStores {
+		id, err := ParseID(configStore["id"])
+		if err != nil {
+			return fmt.Errorf("invalid config store ID for item %d: %w", i, err)
+		}
 
+		// List secrets keys for this config store since the values are not
+		// returned in the list; a partial list would be mistaken for the
+		// complete set of secrets so any error fails the listing
+		secretsPath := fmt.Sprintf("%s/%s/secrets", r.path, id)
+		secrets, err := client.GetEndpoint(ctx, secretsPath)
+		if err != nil {
+			logger.Error("error listing secrets for config store",
+				zap.String("resource", r.name),
+				zap.String("config-store", id),
+				zap.Error(err))
+			return fmt.Errorf("failed to list secrets for config store %s: %w", id, err)
+		}
+		if len(secrets) > 0 {
+			secretKeys := make([]string, len(secrets))
+			for j, secret := range secrets {
+				secretKey, ok := secret["key"].(string)
+				if !ok {
+					return fmt.Errorf("invalid secret key for item %d in config store %d", i, j)
+				}
+				secretKeys[j] = secretKey
+			}
+			configStore["secret"] = secretKeys
+		}
+	}
 	return nil
 }
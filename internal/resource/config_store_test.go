@@ -0,0 +1,80 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConfigStore(t *testing.T) {
+	t.Run("verify secret keys are listed", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			if strings.HasSuffix(r.URL.Path, "/secrets") {
+				_, _ = w.Write([]byte(`{"data":[{"key":"api-key"},{"key":"password"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"store-1"}]}`))
+		}), false)
+
+		data, err := resource.NewConfigStore().List(context.Background(), c, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []string{"api-key", "password"}, data.Data[0]["secret"])
+	})
+
+	t.Run("verify secrets error is surfaced", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/secrets") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"store-1"}]}`))
+		}), false)
+
+		_, err := resource.NewConfigStore().List(context.Background(), c, zap.NewNop())
+		var requestErr *client.RequestError
+		require.ErrorAs(t, err, &requestErr)
+		require.Equal(t, http.StatusForbidden, requestErr.StatusCode)
+		require.ErrorContains(t, err, "store-1")
+	})
+	t.Run("verify no sub-resource requests are made when enrichment is disabled", func(t *testing.T) {
+		var paths []string
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"store-1"}]}`))
+		})
+		c := newConfiguredTestClient(t, handler, func(config *config.Config) {
+			config.SkipSubResourceEnrichment = true
+		})
+
+		data, err := resource.NewConfigStore().List(context.Background(), c, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "store-1"}}, data.Data)
+		require.Len(t, paths, 1)
+		require.True(t, strings.HasSuffix(paths[0], "/config-stores"))
+	})
+}
@@ -52,34 +52,74 @@ func (r *ConsumerResource) List(ctx context.Context, client *client.Client, logg
 		return ResourceData{}, nil
 	}
 
+	if err := r.addGroups(ctx, client, consumerData, logger); err != nil {
+		return ResourceData{}, err
+	}
+
+	return ResourceData{
+		Data: consumerData,
+		Name: r.Name(),
+	}, nil
+}
+
+// Stream retrieves the consumers page by page and includes their associated
+// consumer groups.
+func (r *ConsumerResource) Stream(ctx context.Context, client *client.Client, fn client.PageFunc,
+	logger *zap.Logger,
+) error {
+	return r.stream(ctx, client, r.groupsTransform(logger), fn, logger)
+}
+
+// groupsTransform returns the list transform adding the consumer groups of each
+// consumer.
+func (r *ConsumerResource) groupsTransform(logger *zap.Logger) ListTransformFunc {
+	return func(ctx context.Context, client *client.Client, consumers []map[string]interface{}) (
+		[]map[string]interface{}, error,
+	) {
+		return consumers, r.addGroups(ctx, client, consumers, logger)
+	}
+}
+
+// addGroups adds the IDs of the consumer groups of each consumer to the
+// `groups` field of the consumer unless sub-resource enrichment is disabled.
+func (r *ConsumerResource) addGroups(ctx context.Context, client *client.Client, consumers []map[string]interface{},
+	logger *zap.Logger,
+) error {
+	if !client.EnrichSubResources() {
+		logger.Debug("Skipping sub-resource enrichment",
+			zap.String("resource", r.name))
+		return nil
+	}
 	// Gather consumer IDs to determine if they are part of a consumer group
-	for i, consumer := range consumerData {
-		id, ok := consumer["id"].(string)
-		if !ok {
-			return ResourceData{}, fmt.Errorf("invalid consumer ID for item %d", i)
+	for i, consumer := range consumers {
+		id, err := ParseID(consumer["id"])
+		if err != nil {
+			return fmt.Errorf("invalid consumer ID for item %d: %w", i, err)
 		}
 
-		// List consumer group IDs for this consumer
+		// List consumer group IDs for this consumer; a partial list would be
+		// mistaken for the complete membership so any error fails the listing
 		consumerGroupsPath := fmt.Sprintf("%s/%s/consumer_groups", r.path, id)
-		consumerGroups, _ := client.GetEndpoint(ctx, consumerGroupsPath)
+		consumerGroups, err := client.GetEndpoint(ctx, consumerGroupsPath)
+		if err != nil {
+			logger.Error("error listing consumer groups for consumer",
+				zap.String("resource", r.name),
+				zap.String("consumer", id),
+				zap.Error(err))
+			return fmt.Errorf("failed to list consumer groups for consumer %s: %w", id, err)
+		}
 		if len(consumerGroups) > 0 {
 			consumerGroupIDs := make([]string, len(consumerGroups))
 			for j, group := range consumerGroups {
-				groupID, ok := group["id"].(string)
-				if !ok {
-					return ResourceData{}, fmt.Errorf("invalid consumer group ID for item %d in consumer group %d", i, j)
+				groupID, err := ParseID(group["id"])
+				if err != nil {
+					return fmt.Errorf("invalid consumer group ID for item %d in consumer group %d: %w",
+						i, j, err)
 				}
 				consumerGroupIDs[j] = groupID
 			}
 			consumer["groups"] = consumerGroupIDs
 		}
-
-		// Update the consumer data with the modified consumer
-		consumerData[i] = consumer
 	}
-
-	return ResourceData{
-		Data: consumerData,
-		Name: r.Name(),
-	}, nil
+	return nil
 }
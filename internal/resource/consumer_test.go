@@ -0,0 +1,85 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConsumer(t *testing.T) {
+	t.Run("verify consumer groups are listed across pages", func(t *testing.T) {
+		c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case strings.HasSuffix(r.URL.Path, "/consumers"):
+				_, _ = w.Write([]byte(`{"data":[{"id":"consumer-1"}]}`))
+			case r.URL.Query().Get("offset") == "page-2":
+				_, _ = w.Write([]byte(`{"data":[{"id":"group-2"}]}`))
+			default:
+				_, _ = w.Write([]byte(`{"data":[{"id":"group-1"}],"next":"/consumers/consumer-1/consumer_groups?offset=page-2"}`))
+			}
+		}), false)
+
+		data, err := resource.NewConsumer().List(context.Background(), c, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []string{"group-1", "group-2"}, data.Data[0]["groups"])
+	})
+
+	t.Run("verify consumer group error is surfaced", func(t *testing.T) {
+		c := newConfiguredTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/consumer_groups") {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"consumer-1"}]}`))
+		}), func(config *config.Config) {
+			config.Retries.MaxAttempts = 1
+		})
+
+		_, err := resource.NewConsumer().List(context.Background(), c, zap.NewNop())
+		var requestErr *client.RequestError
+		require.ErrorAs(t, err, &requestErr)
+		require.Equal(t, http.StatusInternalServerError, requestErr.StatusCode)
+		require.ErrorContains(t, err, "consumer-1")
+	})
+	t.Run("verify no sub-resource requests are made when enrichment is disabled", func(t *testing.T) {
+		var paths []string
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			paths = append(paths, r.URL.Path)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":"consumer-1"}]}`))
+		})
+		c := newConfiguredTestClient(t, handler, func(config *config.Config) {
+			config.SkipSubResourceEnrichment = true
+		})
+
+		data, err := resource.NewConsumer().List(context.Background(), c, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []map[string]interface{}{{"id": "consumer-1"}}, data.Data)
+		require.Len(t, paths, 1)
+		require.True(t, strings.HasSuffix(paths[0], "/consumers"))
+	})
+}
@@ -27,6 +27,7 @@ func NewDegraphQLRoute() Resource {
 			name:         "degraphql-route",
 			path:         "degraphql_routes",
 			dependencies: []string{"route", "service"},
+			references:   map[string]string{"service": "service"},
 		},
 	}
 }
@@ -27,6 +27,7 @@ func NewHMACAuth() Resource {
 			name:         "hmac-auth",
 			path:         "hmac-auths",
 			dependencies: []string{"consumer"},
+			references:   map[string]string{"consumer": "consumer"},
 		},
 	}
 }
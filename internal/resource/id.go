@@ -0,0 +1,76 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrInvalidID is returned when the ID of an item is missing or is not a
+// supported ID shape.
+var ErrInvalidID = errors.New("invalid id")
+
+// nestedIDFields are the fields of an object ID holding the ID value (e.g.
+// `{"id": {"oid": "..."}}`), in order of precedence.
+var nestedIDFields = []string{"id", "oid", "$oid"}
+
+// ParseID returns the string form of an ID value. String, numeric (decoded as
+// json.Number or float64), and object IDs nesting the value under one of the
+// nested ID fields are supported; an error wrapping ErrInvalidID is returned
+// otherwise.
+func ParseID(value interface{}) (string, error) {
+	switch value := value.(type) {
+	case string:
+		if len(value) == 0 {
+			return "", fmt.Errorf("%w: empty string", ErrInvalidID)
+		}
+		return value, nil
+	case json.Number:
+		return value.String(), nil
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case map[string]interface{}:
+		for _, field := range nestedIDFields {
+			if nested, ok := value[field]; ok {
+				return ParseID(nested)
+			}
+		}
+		return "", fmt.Errorf("%w: object without id, oid, or $oid field", ErrInvalidID)
+	case nil:
+		return "", fmt.Errorf("%w: missing", ErrInvalidID)
+	default:
+		return "", fmt.Errorf("%w: unsupported type %T", ErrInvalidID, value)
+	}
+}
+
+// ItemID returns the ID of an item, falling back to its name when the item
+// has no ID.
+func ItemID(item map[string]interface{}) (string, error) {
+	if value, ok := item["id"]; ok && value != nil {
+		return ParseID(value)
+	}
+	if name, ok := item["name"].(string); ok && len(name) > 0 {
+		return name, nil
+	}
+	return "", fmt.Errorf("%w: missing id or name field", ErrInvalidID)
+}
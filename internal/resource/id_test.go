@@ -0,0 +1,70 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseID(t *testing.T) {
+	t.Run("verify supported id shapes are converted to a string", func(t *testing.T) {
+		for expected, value := range map[string]interface{}{
+			"4168295f-015e-4190-837e-0fcc5d72a52f": "4168295f-015e-4190-837e-0fcc5d72a52f",
+			"9007199254740993":                     json.Number("9007199254740993"),
+			"42":                                   float64(42),
+			"1.5":                                  1.5,
+			"7":                                    7,
+			"nested-id":                            map[string]interface{}{"id": "nested-id"},
+			"507f1f77bcf86cd799439011":             map[string]interface{}{"oid": "507f1f77bcf86cd799439011"},
+			"507f191e810c19729de860ea":             map[string]interface{}{"$oid": "507f191e810c19729de860ea"},
+			"12":                                   map[string]interface{}{"id": map[string]interface{}{"oid": 12}},
+		} {
+			actual, err := resource.ParseID(value)
+			require.NoError(t, err)
+			require.Equal(t, expected, actual)
+		}
+	})
+
+	t.Run("verify unsupported id shapes return an error", func(t *testing.T) {
+		for _, value := range []interface{}{
+			nil,
+			"",
+			true,
+			[]interface{}{"id-1"},
+			map[string]interface{}{"uuid": "id-1"},
+		} {
+			_, err := resource.ParseID(value)
+			require.ErrorIs(t, err, resource.ErrInvalidID, "%v", value)
+		}
+	})
+
+	t.Run("verify item id falls back to the item name", func(t *testing.T) {
+		id, err := resource.ItemID(map[string]interface{}{"id": json.Number("42"), "name": "example"})
+		require.NoError(t, err)
+		require.Equal(t, "42", id)
+
+		id, err = resource.ItemID(map[string]interface{}{"name": "example"})
+		require.NoError(t, err)
+		require.Equal(t, "example", id)
+
+		_, err = resource.ItemID(map[string]interface{}{"description": "example"})
+		require.ErrorIs(t, err, resource.ErrInvalidID)
+	})
+}
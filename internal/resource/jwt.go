@@ -27,6 +27,7 @@ func NewJWT() Resource {
 			name:         "jwt",
 			path:         "jwts",
 			dependencies: []string{"consumer"},
+			references:   map[string]string{"consumer": "consumer"},
 		},
 	}
 }
@@ -27,6 +27,11 @@ func NewKey() Resource {
 			name:         "key",
 			path:         "keys",
 			dependencies: []string{"key-set"},
+			references:   map[string]string{"set": "key-set"},
+			// Keys are identified by their key ID within a key set
+			requiredFields: []string{"kid"},
+			// Keys were introduced in Kong Gateway 3.1
+			minVersion: "3.1",
 		},
 	}
 }
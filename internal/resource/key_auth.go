@@ -27,6 +27,7 @@ func NewKeyAuth() Resource {
 			name:         "key-auth",
 			path:         "key-auths",
 			dependencies: []string{"consumer"},
+			references:   map[string]string{"consumer": "consumer"},
 		},
 	}
 }
@@ -26,6 +26,8 @@ func NewKeySet() Resource {
 		BaseResource: BaseResource{
 			name: "key-set",
 			path: "key-sets",
+			// Key sets were introduced in Kong Gateway 3.1
+			minVersion: "3.1",
 		},
 	}
 }
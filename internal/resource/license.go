@@ -0,0 +1,35 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+// LicenseResource represents the licenses of the enterprise edition of Kong
+// Gateway. The payload of a license holds the license key and is sanitized by
+// default.
+type LicenseResource struct {
+	BaseResource
+}
+
+// NewLicense creates a new license resource.
+func NewLicense() Resource {
+	return &LicenseResource{
+		BaseResource: BaseResource{
+			name: "license",
+			path: "licenses",
+			// Licenses are only available in the enterprise edition
+			enterprise: true,
+		},
+	}
+}
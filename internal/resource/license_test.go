@@ -0,0 +1,63 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLicense(t *testing.T) {
+	t.Run("verify the license resource is created", func(t *testing.T) {
+		license := resource.NewLicense()
+		require.Equal(t, "license", license.Name())
+		require.Equal(t, "licenses", license.Path())
+		require.Empty(t, license.Dependencies())
+	})
+
+	t.Run("verify licenses are only supported by the enterprise edition", func(t *testing.T) {
+		constrained, ok := resource.NewLicense().(resource.VersionConstrained)
+		require.True(t, ok)
+
+		enterprise, err := client.ParseGatewayVersion("3.4.1.0-enterprise-edition")
+		require.NoError(t, err)
+		require.True(t, constrained.Supported(enterprise))
+		oss, err := client.ParseGatewayVersion("3.4.1")
+		require.NoError(t, err)
+		require.False(t, constrained.Supported(oss))
+	})
+
+	t.Run("verify the license payload is masked by default", func(t *testing.T) {
+		cfg, err := config.NewConfig()
+		require.NoError(t, err)
+		sanitizer, err := sanitize.NewSanitizer(cfg.Sanitization)
+		require.NoError(t, err)
+
+		items := []map[string]interface{}{{
+			"id":         "license-1",
+			"payload":    `{"license":{"payload":{"license_key":"secret"}}}`,
+			"updated_at": 1700000000,
+		}}
+		require.NoError(t, sanitizer.Sanitize("license", items))
+		require.Equal(t, "<redacted>", items[0]["payload"])
+		require.Equal(t, "license-1", items[0]["id"])
+	})
+}
@@ -27,6 +27,7 @@ func NewMTLSAuth() Resource {
 			name:         "mtls-auth",
 			path:         "mtls-auths",
 			dependencies: []string{"consumer"},
+			references:   map[string]string{"consumer": "consumer"},
 		},
 	}
 }
@@ -26,6 +26,8 @@ func NewPartial() Resource {
 		BaseResource: BaseResource{
 			name: "partial",
 			path: "partials",
+			// Partials were introduced in Kong Gateway 3.10
+			minVersion: "3.10",
 		},
 	}
 }
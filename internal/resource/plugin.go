@@ -35,6 +35,12 @@ func NewPlugin() Resource {
 				"route",
 				"service",
 			},
+			references: map[string]string{
+				"consumer":       "consumer",
+				"consumer_group": "consumer-group",
+				"route":          "route",
+				"service":        "service",
+			},
 		},
 	}
 }
@@ -18,12 +18,16 @@ package resource
 import (
 	"errors"
 	"fmt"
+	"sort"
 )
 
 // Registry provides a structure for organizing and ordering resources
 // based on their dependencies.
 type Registry struct {
 	resources []Resource
+	// dependencies are the overridden dependencies of resources, keyed by
+	// resource name.
+	dependencies map[string][]string
 }
 
 // orderType defines the sorting order type for resource operations.
@@ -55,6 +59,7 @@ var resourceRegistry = []Resource{
 	NewKey(),
 	NewKeyAuth(),
 	NewKeySet(),
+	NewLicense(),
 	NewMTLSAuth(),
 	NewPartial(),
 	NewPlugin(),
@@ -67,11 +72,32 @@ var resourceRegistry = []Resource{
 	NewVault(),
 }
 
+// ErrDuplicateResource is returned when more than one resource is registered
+// with the same name.
+var ErrDuplicateResource = errors.New("duplicate resource")
+
+// ErrInvalidDependencies is returned when the dependency overrides reference
+// unknown resources or introduce a cyclic dependency.
+var ErrInvalidDependencies = errors.New("invalid resource dependencies")
+
 // NewRegistry creates a new resource registry with all predefined resources.
-func NewRegistry() *Registry {
-	return &Registry{
-		resources: resourceRegistry,
+func NewRegistry() (*Registry, error) {
+	return newRegistry(resourceRegistry)
+}
+
+// newRegistry creates a new resource registry with the specified resources,
+// ensuring that each resource is registered only once.
+func newRegistry(resources []Resource) (*Registry, error) {
+	names := make(map[string]struct{}, len(resources))
+	for _, res := range resources {
+		if _, exists := names[res.Name()]; exists {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateResource, res.Name())
+		}
+		names[res.Name()] = struct{}{}
 	}
+	return &Registry{
+		resources: resources,
+	}, nil
 }
 
 // GetResources returns all resources in the registry.
@@ -79,11 +105,73 @@ func (r *Registry) GetResources() []Resource {
 	return r.resources
 }
 
+// GetResource returns the resource with the specified name, if any.
+func (r *Registry) GetResource(name string) (Resource, bool) {
+	for _, res := range r.resources {
+		if res.Name() == name {
+			return res, true
+		}
+	}
+	return nil, false
+}
+
+// SetDependencies overrides the declared dependencies of resources, keyed by
+// resource name, for the deletion and insertion orders (e.g. when a gateway
+// version changes the relationships between resources). The dependencies of
+// a resource replace its declared dependencies; the declared dependencies are
+// included to augment them. An error wrapping ErrInvalidDependencies is
+// returned and the overrides are not applied if a resource is unknown or the
+// overrides introduce a cyclic dependency.
+func (r *Registry) SetDependencies(dependencies map[string][]string) error {
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		if _, ok := r.GetResource(name); !ok {
+			errs = append(errs, fmt.Errorf("unknown resource %q", name))
+		}
+		for _, dep := range dependencies[name] {
+			if _, ok := r.GetResource(dep); !ok {
+				errs = append(errs, fmt.Errorf("unknown dependency %q of resource %s", dep, name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%w: %w", ErrInvalidDependencies, errors.Join(errs...))
+	}
+
+	previous := r.dependencies
+	r.dependencies = dependencies
+	if _, err := r.getOrderedResources(insertOrder); err != nil {
+		r.dependencies = previous
+		return fmt.Errorf("%w: %w", ErrInvalidDependencies, err)
+	}
+	return nil
+}
+
+// resourceDependencies returns the dependencies of the resource; the
+// overridden dependencies, if any, or the declared dependencies.
+func (r *Registry) resourceDependencies(res Resource) []string {
+	if deps, ok := r.dependencies[res.Name()]; ok {
+		return deps
+	}
+	return res.Dependencies()
+}
+
 // GetResourcesForDeletion returns resources ordered for deletion operations.
 func (r *Registry) GetResourcesForDeletion() ([][]Resource, error) {
 	return r.getOrderedResources(deleteOrder)
 }
 
+// GetResourcesForInsertion returns resources ordered for insertion operations.
+func (r *Registry) GetResourcesForInsertion() ([][]Resource, error) {
+	return r.getOrderedResources(insertOrder)
+}
+
 func (r *Registry) getOrderedResources(orderType orderType) ([][]Resource, error) {
 	// Build a map of resource names to resources for quick lookup
 	resourceMap := make(map[string]Resource)
@@ -102,7 +190,7 @@ func (r *Registry) getOrderedResources(orderType orderType) ([][]Resource, error
 	// Add edges according to the correct direction for each order type
 	for _, res := range r.resources {
 		name := res.Name()
-		deps := res.Dependencies()
+		deps := r.resourceDependencies(res)
 
 		for _, dep := range deps {
 			// Ensure the dependency exists in our resource map
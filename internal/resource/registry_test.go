@@ -0,0 +1,138 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource_test
+
+import (
+	"testing"
+
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/stretchr/testify/require"
+)
+
+// resourceLevel returns the level of the named resource in the ordered levels.
+func resourceLevel(t *testing.T, levels [][]resource.Resource, name string) int {
+	t.Helper()
+	for i, level := range levels {
+		for _, res := range level {
+			if res.Name() == name {
+				return i
+			}
+		}
+	}
+	require.Failf(t, "resource not found in levels", "resource: %s", name)
+	return -1
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("verify resource is looked up by name", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		res, ok := registry.GetResource("route")
+		require.True(t, ok)
+		require.Equal(t, "routes", res.Path())
+		_, ok = registry.GetResource("routes")
+		require.False(t, ok)
+	})
+
+	t.Run("verify deletion order is acyclic and contains all resources", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		levels, err := registry.GetResourcesForDeletion()
+		require.NoError(t, err)
+
+		count := 0
+		for _, level := range levels {
+			count += len(level)
+		}
+		require.Len(t, registry.GetResources(), count)
+	})
+
+	t.Run("verify plugins are deleted before their scoped entities", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		levels, err := registry.GetResourcesForDeletion()
+		require.NoError(t, err)
+
+		pluginLevel := resourceLevel(t, levels, "plugin")
+		for _, name := range []string{"service", "route", "consumer"} {
+			require.Less(t, pluginLevel, resourceLevel(t, levels, name),
+				"plugin must be deleted before %s", name)
+		}
+	})
+	t.Run("verify keys are deleted before and inserted after their key set", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		levels, err := registry.GetResourcesForDeletion()
+		require.NoError(t, err)
+		require.Less(t, resourceLevel(t, levels, "key"), resourceLevel(t, levels, "key-set"))
+
+		levels, err = registry.GetResourcesForInsertion()
+		require.NoError(t, err)
+		require.Greater(t, resourceLevel(t, levels, "key"), resourceLevel(t, levels, "key-set"))
+	})
+	t.Run("verify snis are deleted before their certificate", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		levels, err := registry.GetResourcesForDeletion()
+		require.NoError(t, err)
+		certificateLevel := resourceLevel(t, levels, "certificate")
+		require.Less(t, resourceLevel(t, levels, "sni"), certificateLevel)
+		require.Less(t, resourceLevel(t, levels, "service"), certificateLevel)
+	})
+
+	t.Run("verify dependency overrides change the order", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+		levels, err := registry.GetResourcesForInsertion()
+		require.NoError(t, err)
+		require.Equal(t, resourceLevel(t, levels, "upstream"), resourceLevel(t, levels, "ca-certificate"))
+
+		require.NoError(t, registry.SetDependencies(map[string][]string{"upstream": {"service"}}))
+		levels, err = registry.GetResourcesForInsertion()
+		require.NoError(t, err)
+		require.Greater(t, resourceLevel(t, levels, "upstream"), resourceLevel(t, levels, "service"))
+		require.Greater(t, resourceLevel(t, levels, "target"), resourceLevel(t, levels, "upstream"))
+
+		levels, err = registry.GetResourcesForDeletion()
+		require.NoError(t, err)
+		require.Less(t, resourceLevel(t, levels, "upstream"), resourceLevel(t, levels, "service"))
+	})
+
+	t.Run("verify invalid dependency overrides are rejected", func(t *testing.T) {
+		registry, err := resource.NewRegistry()
+		require.NoError(t, err)
+
+		err = registry.SetDependencies(map[string][]string{
+			"services": {"certificate"},
+			"route":    {"gateway"},
+		})
+		require.ErrorIs(t, err, resource.ErrInvalidDependencies)
+		require.ErrorContains(t, err, `unknown resource "services"`)
+		require.ErrorContains(t, err, `unknown dependency "gateway" of resource route`)
+
+		err = registry.SetDependencies(map[string][]string{"service": {"route"}})
+		require.ErrorIs(t, err, resource.ErrInvalidDependencies)
+		require.ErrorContains(t, err, "cyclic dependency")
+
+		// The rejected overrides are not applied
+		levels, err := registry.GetResourcesForInsertion()
+		require.NoError(t, err)
+		require.Greater(t, resourceLevel(t, levels, "route"), resourceLevel(t, levels, "service"))
+	})
+}
@@ -17,6 +17,7 @@ package resource
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/mikefero/osiris/internal/client"
@@ -49,12 +50,86 @@ type Resource interface {
 	Delete(ctx context.Context, client *client.Client, item map[string]interface{}, logger *zap.Logger) error
 }
 
+// ErrBulkDeleteNotSupported is returned by a BulkDeleter when the gateway
+// does not support bulk deletion for the resource; callers should fall back
+// to deleting each item individually.
+var ErrBulkDeleteNotSupported = errors.New("bulk delete not supported")
+
+// BulkDeleter is an optional interface implemented by resources that are able
+// to delete multiple items with a single request.
+type BulkDeleter interface {
+	// BulkDelete removes all of the specified items from the resource.
+	BulkDelete(ctx context.Context, client *client.Client, items []map[string]interface{}, logger *zap.Logger) error
+}
+
+// Referencer is an optional interface implemented by resources whose items
+// reference the items of other resources (e.g. the service of a route).
+type Referencer interface {
+	// References returns the fields of an item referencing another resource,
+	// keyed by field name, along with the name of the referenced resource.
+	References() map[string]string
+}
+
+// Streamer is an optional interface implemented by resources whose items can
+// be listed page by page so that only a single page is held in memory (e.g.
+// when streaming a massive control plane to disk).
+type Streamer interface {
+	// Stream retrieves all items of the resource type, passing the items of
+	// each page to the page function as they are retrieved.
+	Stream(ctx context.Context, client *client.Client, fn client.PageFunc, logger *zap.Logger) error
+}
+
+// ErrInvalidItem is returned by a Validator when an item violates the
+// expectations of the resource.
+var ErrInvalidItem = errors.New("invalid item")
+
+// Validator is an optional interface implemented by resources whose items
+// have invariants that are checked after listing (e.g. a key must have a
+// `kid`); violations indicate a change in the shape of the API.
+type Validator interface {
+	// Validate returns an error wrapping ErrInvalidItem if the item violates
+	// the expectations of the resource.
+	Validate(item map[string]interface{}) error
+}
+
+// VersionConstrained is an optional interface implemented by resources that
+// are only available in some versions or editions of the gateway (e.g.
+// partials were introduced in 3.10 and licenses are enterprise only).
+type VersionConstrained interface {
+	// Supported returns true if the resource is available in the specified
+	// version of the gateway.
+	Supported(version client.GatewayVersion) bool
+}
+
+// ListTransformFunc transforms the items of a resource after they have been
+// listed (e.g. to enrich or clean the items) and returns the transformed
+// items.
+type ListTransformFunc func(ctx context.Context, client *client.Client,
+	items []map[string]interface{}) ([]map[string]interface{}, error)
+
 // BaseResource provides a basic implementation of the Resource interface
 // that can be embedded in specific resource types.
 type BaseResource struct {
 	name         string
 	path         string
 	dependencies []string
+	// listTransform is an optional transform applied to the listed items.
+	listTransform ListTransformFunc
+	// references maps the fields of an item referencing another resource to
+	// the name of the referenced resource.
+	references map[string]string
+	// requiredFields are the fields every listed item is expected to have.
+	requiredFields []string
+	// minVersion is the earliest version of the gateway providing the
+	// resource; the resource is available in all versions if empty.
+	minVersion string
+	// enterprise restricts the resource to the enterprise edition of the
+	// gateway.
+	enterprise bool
+	// childPaths are the sub-paths of an item (e.g. `secrets` for
+	// `config-stores/{id}/secrets`) whose children are deleted before the
+	// item itself.
+	childPaths []string
 }
 
 // Name returns the display name of the resource.
@@ -74,14 +149,72 @@ func (r *BaseResource) Dependencies() []string {
 	return deps
 }
 
-// List retrieves all items of the resource type.
-func (r *BaseResource) List(ctx context.Context, client *client.Client, logger *zap.Logger) (ResourceData, error) {
-	data, err := client.GetEndpoint(ctx, r.path)
+// References returns the fields of an item referencing another resource,
+// keyed by field name, along with the name of the referenced resource.
+func (r *BaseResource) References() map[string]string {
+	// Return a copy of the references map to prevent external modification
+	references := make(map[string]string, len(r.references))
+	for field, name := range r.references {
+		references[field] = name
+	}
+	return references
+}
+
+// Validate returns an error wrapping ErrInvalidItem if the item is missing
+// any of the required fields of the resource.
+func (r *BaseResource) Validate(item map[string]interface{}) error {
+	for _, field := range r.requiredFields {
+		if value, ok := item[field]; !ok || value == nil || value == "" {
+			return fmt.Errorf("%w: %s %s: missing required field %q", ErrInvalidItem, r.name,
+				itemIdentity(item), field)
+		}
+	}
+	return nil
+}
+
+// Supported returns true if the resource is available in the specified
+// version of the gateway.
+func (r *BaseResource) Supported(version client.GatewayVersion) bool {
+	if r.enterprise && !version.Enterprise() {
+		return false
+	}
+	if len(r.minVersion) == 0 {
+		return true
+	}
+	minVersion, err := client.ParseGatewayVersion(r.minVersion)
+	if err != nil {
+		return true
+	}
+	return version.AtLeast(minVersion)
+}
+
+// itemIdentity returns the ID of an item, falling back to its name, for
+// reporting purposes.
+func itemIdentity(item map[string]interface{}) string {
+	if id, err := ItemID(item); err == nil {
+		return id
+	}
+	return "<unknown>"
+}
+
+// List retrieves all items of the resource type and applies the list
+// transform, if any. If only some of the pages were retrieved, the partial
+// items are returned along with the error.
+func (r *BaseResource) List(ctx context.Context, c *client.Client, logger *zap.Logger) (ResourceData, error) {
+	data, err := c.GetEndpoint(ctx, r.path)
+	var errPartial error
 	if err != nil {
-		logger.Error("error listing resource",
+		if !errors.Is(err, client.ErrPartialPages) {
+			logger.Error("error listing resource",
+				zap.String("resource", r.name),
+				zap.Error(err))
+			return ResourceData{}, fmt.Errorf("error listing resource %s: %w", r.name, err)
+		}
+		logger.Warn("Partial data listed for resource",
 			zap.String("resource", r.name),
+			zap.Int("items", len(data)),
 			zap.Error(err))
-		return ResourceData{}, fmt.Errorf("error listing resource %s: %w", r.name, err)
+		errPartial = fmt.Errorf("error listing resource %s: %w", r.name, err)
 	}
 
 	if len(data) == 0 {
@@ -90,6 +223,17 @@ func (r *BaseResource) List(ctx context.Context, client *client.Client, logger *
 		return ResourceData{}, nil
 	}
 
+	// Apply the resource specific transform to the listed items
+	if r.listTransform != nil {
+		data, err = r.listTransform(ctx, c, data)
+		if err != nil {
+			logger.Error("error transforming resource",
+				zap.String("resource", r.name),
+				zap.Error(err))
+			return ResourceData{}, fmt.Errorf("error transforming resource %s: %w", r.name, err)
+		}
+	}
+
 	logger.Info("Listed data for resource",
 		zap.String("resource", r.name),
 		zap.Int("items", len(data)))
@@ -97,20 +241,61 @@ func (r *BaseResource) List(ctx context.Context, client *client.Client, logger *
 	return ResourceData{
 		Data: data,
 		Name: r.name,
-	}, nil
+	}, errPartial
+}
+
+// Stream retrieves all items of the resource type page by page and applies
+// the list transform, if any, to each page. If only some of the pages were
+// retrieved, the error wraps client.ErrPartialPages.
+func (r *BaseResource) Stream(ctx context.Context, client *client.Client, fn client.PageFunc,
+	logger *zap.Logger,
+) error {
+	return r.stream(ctx, client, r.listTransform, fn, logger)
 }
 
+// stream retrieves the items of the resource page by page, applying the
+// transform to each page before passing it to the page function.
+func (r *BaseResource) stream(ctx context.Context, client *client.Client, transform ListTransformFunc,
+	fn client.PageFunc, logger *zap.Logger,
+) error {
+	err := client.StreamEndpoint(ctx, r.path, func(items []map[string]interface{}) error {
+		if transform != nil {
+			var err error
+			items, err = transform(ctx, client, items)
+			if err != nil {
+				logger.Error("error transforming resource",
+					zap.String("resource", r.name),
+					zap.Error(err))
+				return fmt.Errorf("error transforming resource %s: %w", r.name, err)
+			}
+		}
+		return fn(items)
+	})
+	if err != nil {
+		logger.Error("error streaming resource",
+			zap.String("resource", r.name),
+			zap.Error(err))
+		return fmt.Errorf("error listing resource %s: %w", r.name, err)
+	}
+	return nil
+}
+
+// Delete removes a specific item by ID from the resource. The children of the
+// item found at the child paths of the resource are deleted first.
 func (r *BaseResource) Delete(ctx context.Context, client *client.Client, item map[string]interface{},
 	logger *zap.Logger,
 ) error {
 	// Determine the ID of the item to delete
-	id, ok := item["id"].(string)
-	if !ok {
-		name, ok := item["name"].(string)
-		if !ok {
-			return fmt.Errorf("invalid item format: missing id or name field")
+	id, err := ItemID(item)
+	if err != nil {
+		return fmt.Errorf("invalid item format: %w", err)
+	}
+
+	// Delete the children of the item before the item itself
+	for _, childPath := range r.childPaths {
+		if err := r.deleteChildren(ctx, client, id, childPath, logger); err != nil {
+			return err
 		}
-		id = name
 	}
 
 	endpointWithID := fmt.Sprintf("%s/%s", r.path, id)
@@ -128,3 +313,46 @@ func (r *BaseResource) Delete(ctx context.Context, client *client.Client, item m
 
 	return nil
 }
+
+// deleteChildren lists and deletes all children of an item found at the
+// specified child path.
+func (r *BaseResource) deleteChildren(ctx context.Context, client *client.Client, id string, childPath string,
+	logger *zap.Logger,
+) error {
+	path := fmt.Sprintf("%s/%s/%s", r.path, id, childPath)
+	children, err := client.GetEndpoint(ctx, path)
+	if err != nil {
+		return fmt.Errorf("error listing %s for resource %s with ID %s: %w", childPath, r.name, id, err)
+	}
+
+	for i, child := range children {
+		// Children may be identified by their id, name, or key (e.g. secrets)
+		var childID string
+		if value, ok := child["id"]; ok && value != nil {
+			if childID, err = ParseID(value); err != nil {
+				return fmt.Errorf("invalid %s item %d for resource %s with ID %s: %w", childPath, i, r.name, id, err)
+			}
+		}
+		for _, field := range []string{"name", "key"} {
+			if value, ok := child[field].(string); ok && len(childID) == 0 {
+				childID = value
+			}
+		}
+		if len(childID) == 0 {
+			return fmt.Errorf("invalid %s item %d for resource %s with ID %s: missing id, name, or key field",
+				childPath, i, r.name, id)
+		}
+
+		childEndpoint := fmt.Sprintf("%s/%s", path, childID)
+		if err := client.DeleteEndpoint(ctx, childEndpoint); err != nil {
+			return fmt.Errorf("error deleting %s %s for resource %s with ID %s: %w", childPath, childID, r.name, id, err)
+		}
+	}
+
+	logger.Debug("Deleted children of resource",
+		zap.String("resource", r.name),
+		zap.String("id", id),
+		zap.String("child-path", childPath),
+		zap.Int("count", len(children)))
+	return nil
+}
@@ -0,0 +1,200 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mikefero/osiris/internal/backoff"
+	"github.com/mikefero/osiris/internal/client"
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newInternalTestClient(t *testing.T, handler http.Handler) *client.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return client.NewClient(&config.Config{
+		BaseURL:        server.URL,
+		ControlPlaneID: uuid.New(),
+	}, zap.NewNop())
+}
+
+func TestBaseResourceList(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[{"id":"item-1"},{"id":"item-2"}]}`))
+	})
+
+	t.Run("verify list transform is applied to listed items", func(t *testing.T) {
+		res := &BaseResource{
+			name: "fake",
+			path: "fakes",
+			listTransform: func(_ context.Context, _ *client.Client,
+				items []map[string]interface{},
+			) ([]map[string]interface{}, error) {
+				for _, item := range items {
+					item["enriched"] = true
+				}
+				return items[:1], nil
+			},
+		}
+		data, err := res.List(context.Background(), newInternalTestClient(t, handler), zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, ResourceData{
+			Name: "fake",
+			Data: []map[string]interface{}{{"id": "item-1", "enriched": true}},
+		}, data)
+	})
+
+	t.Run("verify list transform error is returned", func(t *testing.T) {
+		res := &BaseResource{
+			name: "fake",
+			path: "fakes",
+			listTransform: func(context.Context, *client.Client,
+				[]map[string]interface{},
+			) ([]map[string]interface{}, error) {
+				return nil, errors.New("transform failed")
+			},
+		}
+		_, err := res.List(context.Background(), newInternalTestClient(t, handler), zap.NewNop())
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "transform failed")
+	})
+}
+
+func TestBaseResourceDelete(t *testing.T) {
+	t.Run("verify children are deleted before the parent", func(t *testing.T) {
+		var mutex sync.Mutex
+		var deleted []string
+		client := newInternalTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case http.MethodGet:
+				require.True(t, strings.HasSuffix(r.URL.Path, "/parents/parent-1/children"))
+				_, _ = w.Write([]byte(`{"data":[{"id":"child-1"},{"key":"child-2"}]}`))
+			case http.MethodDelete:
+				mutex.Lock()
+				deleted = append(deleted, r.URL.Path[strings.Index(r.URL.Path, "/parents"):])
+				mutex.Unlock()
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+
+		res := &BaseResource{
+			name:       "parent",
+			path:       "parents",
+			childPaths: []string{"children"},
+		}
+		err := res.Delete(context.Background(), client, map[string]interface{}{"id": "parent-1"}, zap.NewNop())
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"/parents/parent-1/children/child-1",
+			"/parents/parent-1/children/child-2",
+			"/parents/parent-1",
+		}, deleted)
+	})
+
+	t.Run("verify parent is not deleted when a child fails to delete", func(t *testing.T) {
+		var parentDeleted bool
+		client := newInternalTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch {
+			case r.Method == http.MethodGet:
+				_, _ = w.Write([]byte(`{"data":[{"id":"child-1"}]}`))
+			case strings.HasSuffix(r.URL.Path, "/children/child-1"):
+				w.WriteHeader(http.StatusInternalServerError)
+			default:
+				parentDeleted = true
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+		b, err := backoff.New(config.Backoff{Strategy: string(backoff.StrategyConstant), Base: time.Millisecond})
+		require.NoError(t, err)
+		client.SetBackoff(b)
+
+		res := &BaseResource{
+			name:       "parent",
+			path:       "parents",
+			childPaths: []string{"children"},
+		}
+		err = res.Delete(context.Background(), client, map[string]interface{}{"id": "parent-1"}, zap.NewNop())
+		require.Error(t, err)
+		require.False(t, parentDeleted)
+	})
+
+	t.Run("verify numeric and nested ids are deleted by their string form", func(t *testing.T) {
+		var mutex sync.Mutex
+		var deleted []string
+		client := newInternalTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mutex.Lock()
+			deleted = append(deleted, r.URL.Path[strings.Index(r.URL.Path, "/items"):])
+			mutex.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		}))
+
+		res := &BaseResource{name: "item", path: "items"}
+		for _, item := range []map[string]interface{}{
+			{"id": json.Number("9007199254740993")},
+			{"id": map[string]interface{}{"oid": "507f1f77bcf86cd799439011"}},
+		} {
+			require.NoError(t, res.Delete(context.Background(), client, item, zap.NewNop()))
+		}
+		require.Equal(t, []string{"/items/9007199254740993", "/items/507f1f77bcf86cd799439011"}, deleted)
+
+		err := res.Delete(context.Background(), client, map[string]interface{}{"id": true}, zap.NewNop())
+		require.ErrorIs(t, err, ErrInvalidID)
+	})
+}
+
+func TestBaseResourceValidate(t *testing.T) {
+	t.Run("verify items with the required fields are valid", func(t *testing.T) {
+		res := NewKey().(Validator)
+		require.NoError(t, res.Validate(map[string]interface{}{"id": "key-1", "kid": "kid-1"}))
+	})
+
+	t.Run("verify items missing a required field are rejected", func(t *testing.T) {
+		res := NewService().(Validator)
+		err := res.Validate(map[string]interface{}{"id": "svc-1", "host": ""})
+		require.ErrorIs(t, err, ErrInvalidItem)
+		require.ErrorContains(t, err, `service svc-1: missing required field "host"`)
+	})
+}
+
+func TestNewRegistryDuplicateResource(t *testing.T) {
+	t.Run("verify duplicate resource names are rejected", func(t *testing.T) {
+		_, err := newRegistry([]Resource{
+			NewService(),
+			NewRoute(),
+			&BaseResource{name: "service", path: "other-services"},
+		})
+		require.ErrorIs(t, err, ErrDuplicateResource)
+		require.ErrorContains(t, err, "service")
+	})
+
+	t.Run("verify duplicate resource instances are rejected", func(t *testing.T) {
+		_, err := newRegistry([]Resource{NewService(), NewService()})
+		require.ErrorIs(t, err, ErrDuplicateResource)
+	})
+}
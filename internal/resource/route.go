@@ -27,6 +27,7 @@ func NewRoute() Resource {
 			name:         "route",
 			path:         "routes",
 			dependencies: []string{"service"},
+			references:   map[string]string{"service": "service"},
 		},
 	}
 }
@@ -30,6 +30,10 @@ func NewService() Resource {
 				"ca-certificate",
 				"certificate",
 			},
+			references: map[string]string{
+				"client_certificate": "certificate",
+			},
+			requiredFields: []string{"host"},
 		},
 	}
 }
@@ -27,6 +27,7 @@ func NewSNI() Resource {
 			name:         "sni",
 			path:         "snis",
 			dependencies: []string{"certificate"},
+			references:   map[string]string{"certificate": "certificate"},
 		},
 	}
 }
@@ -28,6 +28,7 @@ func NewTarget() Resource {
 			// TODO(fero): We should add /targets endpoint to Konnect translator
 			path:         "v1/targets",
 			dependencies: []string{"upstream"},
+			references:   map[string]string{"upstream": "upstream"},
 		},
 	}
 }
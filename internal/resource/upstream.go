@@ -26,6 +26,9 @@ func NewUpstream() Resource {
 		BaseResource: BaseResource{
 			name: "upstream",
 			path: "upstreams",
+			// Targets are deleted before the upstream in case deletion does not
+			// cascade
+			childPaths: []string{"targets"},
 		},
 	}
 }
@@ -0,0 +1,103 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults strips the fields of resource items whose value matches the
+// default value of the field, reducing the output to the fields that were
+// explicitly configured.
+type Defaults struct {
+	fields map[string]map[string]interface{}
+}
+
+// ReadDefaultsFile reads the default values from a JSON or YAML file mapping
+// resource name to field to default value. Nested fields are specified using
+// a dot separated path (e.g. `healthchecks.active.timeout`).
+func ReadDefaultsFile(filename string) (*Defaults, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read defaults file: %w", err)
+	}
+	var fields map[string]map[string]interface{}
+	if err := yaml.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("unable to parse defaults file %s: %w", filename, err)
+	}
+
+	// Round trip the default values through JSON so that they are comparable
+	// with the listed items (e.g. numbers are decoded as json.Number)
+	normalized, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("unable to normalize defaults file %s: %w", filename, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(normalized))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("unable to normalize defaults file %s: %w", filename, err)
+	}
+	return &Defaults{fields: fields}, nil
+}
+
+// Resources returns the names of the resources having default values, in
+// sorted order.
+func (d *Defaults) Resources() []string {
+	names := make([]string, 0, len(d.fields))
+	for name := range d.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Strip removes the fields of the items for the specified resource whose
+// value matches the default value of the field in place.
+func (d *Defaults) Strip(resourceName string, items []map[string]interface{}) {
+	defaults, ok := d.fields[resourceName]
+	if !ok {
+		return
+	}
+	for _, item := range items {
+		for field, value := range defaults {
+			stripDefault(item, strings.Split(field, "."), value)
+		}
+	}
+}
+
+func stripDefault(item map[string]interface{}, path []string, defaultValue interface{}) {
+	value, ok := item[path[0]]
+	if !ok {
+		return
+	}
+	if len(path) > 1 {
+		if nested, ok := value.(map[string]interface{}); ok {
+			stripDefault(nested, path[1:], defaultValue)
+		}
+		return
+	}
+	if reflect.DeepEqual(value, defaultValue) {
+		delete(item, path[0])
+	}
+}
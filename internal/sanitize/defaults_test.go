@@ -0,0 +1,98 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sanitize_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func writeDefaultsFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(filename, []byte(contents), 0o600))
+	return filename
+}
+
+func TestDefaults(t *testing.T) {
+	newServices := func() []map[string]interface{} {
+		return []map[string]interface{}{
+			{
+				"id":       "svc-1",
+				"port":     json.Number("80"),
+				"protocol": "http",
+				"retries":  json.Number("3"),
+				"tls":      map[string]interface{}{"verify": false, "depth": nil},
+			},
+		}
+	}
+	expected := []map[string]interface{}{
+		{
+			"id":      "svc-1",
+			"retries": json.Number("3"),
+			"tls":     map[string]interface{}{"depth": nil},
+		},
+	}
+
+	t.Run("verify fields matching the YAML defaults are stripped", func(t *testing.T) {
+		defaults, err := sanitize.ReadDefaultsFile(writeDefaultsFile(t, "defaults.yaml", `
+service:
+  port: 80
+  protocol: http
+  retries: 5
+  tls.verify: false
+route:
+  strip_path: true
+`))
+		require.NoError(t, err)
+		require.Equal(t, []string{"route", "service"}, defaults.Resources())
+
+		items := newServices()
+		defaults.Strip("service", items)
+		require.Equal(t, expected, items)
+	})
+
+	t.Run("verify fields matching the JSON defaults are stripped", func(t *testing.T) {
+		defaults, err := sanitize.ReadDefaultsFile(writeDefaultsFile(t, "defaults.json",
+			`{"service":{"port":80,"protocol":"http","retries":5,"tls.verify":false}}`))
+		require.NoError(t, err)
+
+		items := newServices()
+		defaults.Strip("service", items)
+		require.Equal(t, expected, items)
+	})
+
+	t.Run("verify resources without defaults are not stripped", func(t *testing.T) {
+		defaults, err := sanitize.ReadDefaultsFile(writeDefaultsFile(t, "defaults.yaml", "route:\n  port: 80\n"))
+		require.NoError(t, err)
+
+		items := newServices()
+		defaults.Strip("service", items)
+		require.Equal(t, newServices(), items)
+	})
+
+	t.Run("verify invalid defaults file returns error", func(t *testing.T) {
+		_, err := sanitize.ReadDefaultsFile(writeDefaultsFile(t, "defaults.yaml", "service: [80]\n"))
+		require.Error(t, err)
+		_, err = sanitize.ReadDefaultsFile(filepath.Join(t.TempDir(), "missing.yaml"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,207 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sanitize
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+)
+
+// ErrSecretReference is returned when the secret reference of a field cannot
+// be generated (e.g. the item has no valid ID); the secret field is neither
+// retained nor silently dropped.
+var ErrSecretReference = errors.New("unable to generate secret reference")
+
+// Strategy is the strategy used to sanitize a secret field.
+type Strategy string
+
+const (
+	// StrategyDrop removes the secret field from the item.
+	StrategyDrop Strategy = "drop"
+	// StrategyMask replaces the secret field value with a fixed mask.
+	StrategyMask Strategy = "mask"
+	// StrategyHash replaces the secret field value with a salted hash of the
+	// value, allowing changes to be detected without revealing the value.
+	StrategyHash Strategy = "hash"
+	// StrategyReference replaces the secret field value with a reference to
+	// where the value lives (e.g. a vault reference) generated from the secret
+	// reference template so that the value can be resolved when applied.
+	StrategyReference Strategy = "reference"
+)
+
+// Reference is the data the secret reference template is executed with for
+// each secret field.
+type Reference struct {
+	// Resource is the name of the resource of the item (e.g. key-auth).
+	Resource string
+	// ID is the ID of the item; an item without a valid ID cannot be
+	// referenced.
+	ID string
+	// Name is the name of the item; empty if the item has no name.
+	Name string
+	// Field is the dot separated path of the secret field.
+	Field string
+}
+
+// hashPrefix is the prefix added to hashed values to identify them.
+const hashPrefix = "sha256:"
+
+// Sanitizer sanitizes the secret fields of resource items. The zero value
+// performs no sanitization.
+type Sanitizer struct {
+	strategy  Strategy
+	mask      string
+	salt      []byte
+	reference *template.Template
+	fields    map[string][]string
+}
+
+// NewSanitizer creates a new sanitizer from the sanitization configuration.
+// When the hash strategy is used without a configured salt, a random salt is
+// generated making the hashes deterministic for the current run only. The
+// reference strategy requires a valid secret reference template.
+func NewSanitizer(config config.Sanitization) (*Sanitizer, error) {
+	strategy := Strategy(strings.ToLower(config.Strategy))
+	switch strategy {
+	case StrategyDrop, StrategyMask, StrategyHash, StrategyReference:
+	default:
+		return nil, fmt.Errorf("invalid sanitize strategy: %q", config.Strategy)
+	}
+
+	salt := []byte(config.Salt)
+	if strategy == StrategyHash && len(salt) == 0 {
+		salt = make([]byte, 32)
+		if _, err := rand.Read(salt); err != nil {
+			return nil, fmt.Errorf("unable to generate salt: %w", err)
+		}
+	}
+
+	var reference *template.Template
+	if strategy == StrategyReference {
+		var err error
+		if reference, err = parseReferenceTemplate(config.SecretReferenceTemplate); err != nil {
+			return nil, err
+		}
+	}
+	return &Sanitizer{
+		strategy:  strategy,
+		mask:      config.Mask,
+		salt:      salt,
+		reference: reference,
+		fields:    config.Fields,
+	}, nil
+}
+
+// Sanitize sanitizes the secret fields of the items for the specified
+// resource in place. Nested fields are specified using a dot separated path
+// (e.g. `pem.private_key`). An error wrapping ErrSecretReference is returned
+// if the reference of a secret field cannot be generated.
+func (s *Sanitizer) Sanitize(resourceName string, items []map[string]interface{}) error {
+	fields, ok := s.fields[resourceName]
+	if !ok {
+		return nil
+	}
+	for _, item := range items {
+		for _, field := range fields {
+			reference := Reference{
+				Resource: resourceName,
+				Name:     stringField(item, "name"),
+				Field:    field,
+			}
+			if err := s.sanitizeField(item, strings.Split(field, "."), reference, item["id"]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// sanitizeField sanitizes the field of the item at the path; id is the ID
+// value of the top-level item used to generate the secret reference.
+func (s *Sanitizer) sanitizeField(item map[string]interface{}, path []string, reference Reference,
+	id interface{},
+) error {
+	value, ok := item[path[0]]
+	if !ok || value == nil {
+		return nil
+	}
+	if len(path) > 1 {
+		if nested, ok := value.(map[string]interface{}); ok {
+			return s.sanitizeField(nested, path[1:], reference, id)
+		}
+		return nil
+	}
+
+	switch s.strategy {
+	case StrategyDrop:
+		delete(item, path[0])
+	case StrategyMask:
+		item[path[0]] = s.mask
+	case StrategyHash:
+		item[path[0]] = s.hash(value)
+	case StrategyReference:
+		var err error
+		if reference.ID, err = resource.ParseID(id); err != nil {
+			return fmt.Errorf("%w for %s field %s: %w", ErrSecretReference, reference.Resource, reference.Field, err)
+		}
+		var buf strings.Builder
+		if err := s.reference.Execute(&buf, reference); err != nil {
+			return fmt.Errorf("%w for %s field %s of item %s: %w", ErrSecretReference, reference.Resource,
+				reference.Field, reference.ID, err)
+		}
+		item[path[0]] = buf.String()
+	}
+	return nil
+}
+
+func (s *Sanitizer) hash(value interface{}) string {
+	mac := hmac.New(sha256.New, s.salt)
+	fmt.Fprint(mac, value)
+	return hashPrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// parseReferenceTemplate parses the secret reference template, executing it
+// with sample values so that an invalid template is reported up front.
+func parseReferenceTemplate(text string) (*template.Template, error) {
+	if len(text) == 0 {
+		return nil, fmt.Errorf("secret reference template is required for the %s strategy", StrategyReference)
+	}
+	tmpl, err := template.New("secret-reference").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret reference template: %w", err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, Reference{Resource: "resource", ID: "id", Name: "name", Field: "field"}); err != nil {
+		return nil, fmt.Errorf("invalid secret reference template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// stringField returns the string value of the top-level field of the item;
+// empty if the field is missing or not a string.
+func stringField(item map[string]interface{}, field string) string {
+	value, _ := item[field].(string)
+	return value
+}
@@ -0,0 +1,232 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sanitize_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/resource"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func newItems() []map[string]interface{} {
+	return []map[string]interface{}{
+		{
+			"id":  "key-1",
+			"jwk": "secret-jwk",
+			"pem": map[string]interface{}{
+				"private_key": "secret-private-key",
+				"public_key":  "public-key",
+			},
+		},
+	}
+}
+
+func TestSanitizer(t *testing.T) {
+	fields := map[string][]string{
+		"key": {"jwk", "pem.private_key"},
+	}
+
+	t.Run("verify drop strategy removes secret fields", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: "drop",
+			Fields:   fields,
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		require.NoError(t, sanitizer.Sanitize("key", items))
+		require.Equal(t, []map[string]interface{}{
+			{
+				"id": "key-1",
+				"pem": map[string]interface{}{
+					"public_key": "public-key",
+				},
+			},
+		}, items)
+	})
+
+	t.Run("verify mask strategy replaces secret fields", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: "mask",
+			Mask:     "<redacted>",
+			Fields:   fields,
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		require.NoError(t, sanitizer.Sanitize("key", items))
+		require.Equal(t, []map[string]interface{}{
+			{
+				"id":  "key-1",
+				"jwk": "<redacted>",
+				"pem": map[string]interface{}{
+					"private_key": "<redacted>",
+					"public_key":  "public-key",
+				},
+			},
+		}, items)
+	})
+
+	t.Run("verify hash strategy replaces secret fields with a deterministic hash", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: "hash",
+			Salt:     "pepper",
+			Fields:   fields,
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		require.NoError(t, sanitizer.Sanitize("key", items))
+		hashed, ok := items[0]["jwk"].(string)
+		require.True(t, ok)
+		require.NotContains(t, hashed, "secret-jwk")
+		require.Regexp(t, "^sha256:[0-9a-f]{64}$", hashed)
+
+		// Same salt and value produce the same hash
+		again := newItems()
+		require.NoError(t, sanitizer.Sanitize("key", again))
+		require.Equal(t, items, again)
+
+		// Different salt produces a different hash
+		other, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: "hash",
+			Salt:     "salt",
+			Fields:   fields,
+		})
+		require.NoError(t, err)
+		otherItems := newItems()
+		require.NoError(t, other.Sanitize("key", otherItems))
+		require.NotEqual(t, hashed, otherItems[0]["jwk"])
+	})
+
+	t.Run("verify reference strategy replaces secret fields with generated references", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{vault://kv/kong/{{ .Resource }}/{{ .ID }}/{{ .Field }}}",
+			Fields:                  fields,
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		require.NoError(t, sanitizer.Sanitize("key", items))
+		require.Equal(t, []map[string]interface{}{
+			{
+				"id":  "key-1",
+				"jwk": "{vault://kv/kong/key/key-1/jwk}",
+				"pem": map[string]interface{}{
+					"private_key": "{vault://kv/kong/key/key-1/pem.private_key}",
+					"public_key":  "public-key",
+				},
+			},
+		}, items)
+
+		// The name of the item is available to the template
+		named, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{vault://env/{{ .Name }}-{{ .Field }}}",
+			Fields:                  map[string][]string{"key-auth": {"key"}},
+		})
+		require.NoError(t, err)
+		credentials := []map[string]interface{}{
+			{"id": "cred-1", "name": "alice", "key": "secret"},
+		}
+		require.NoError(t, named.Sanitize("key-auth", credentials))
+		require.Equal(t, "{vault://env/alice-key}", credentials[0]["key"])
+	})
+
+	t.Run("verify reference strategy parses numeric and object IDs", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{vault://kv/{{ .ID }}/{{ .Field }}}",
+			Fields:                  map[string][]string{"key-auth": {"key"}},
+		})
+		require.NoError(t, err)
+
+		credentials := []map[string]interface{}{
+			{"id": json.Number("9007199254740993"), "key": "secret"},
+			{"id": map[string]interface{}{"$oid": "507f1f77bcf86cd799439011"}, "key": "secret"},
+		}
+		require.NoError(t, sanitizer.Sanitize("key-auth", credentials))
+		require.Equal(t, "{vault://kv/9007199254740993/key}", credentials[0]["key"])
+		require.Equal(t, "{vault://kv/507f1f77bcf86cd799439011/key}", credentials[1]["key"])
+	})
+
+	t.Run("verify reference strategy fails when a reference cannot be generated", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{vault://kv/{{ .ID }}/{{ .Field }}}",
+			Fields:                  map[string][]string{"key-auth": {"key"}},
+		})
+		require.NoError(t, err)
+		for _, id := range []interface{}{nil, "", true} {
+			credentials := []map[string]interface{}{{"id": id, "key": "secret"}}
+			err = sanitizer.Sanitize("key-auth", credentials)
+			require.ErrorIs(t, err, sanitize.ErrSecretReference)
+			require.ErrorIs(t, err, resource.ErrInvalidID)
+		}
+
+		// The template is valid for the sample values but fails to execute for
+		// the item
+		failing, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{vault://kv/{{ index .Name 3 }}/{{ .Field }}}",
+			Fields:                  map[string][]string{"key-auth": {"key"}},
+		})
+		require.NoError(t, err)
+		credentials := []map[string]interface{}{{"id": "cred-1", "name": "bob", "key": "secret"}}
+		err = failing.Sanitize("key-auth", credentials)
+		require.ErrorIs(t, err, sanitize.ErrSecretReference)
+		require.ErrorContains(t, err, "cred-1")
+		require.Equal(t, "secret", credentials[0]["key"])
+	})
+
+	t.Run("verify reference strategy requires a valid template", func(t *testing.T) {
+		_, err := sanitize.NewSanitizer(config.Sanitization{Strategy: "reference"})
+		require.ErrorContains(t, err, "secret reference template is required")
+		_, err = sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{{ .ID",
+		})
+		require.ErrorContains(t, err, "invalid secret reference template")
+		_, err = sanitize.NewSanitizer(config.Sanitization{
+			Strategy:                "reference",
+			SecretReferenceTemplate: "{{ .Unknown }}",
+		})
+		require.ErrorContains(t, err, "invalid secret reference template")
+	})
+
+	t.Run("verify unconfigured resources are not sanitized", func(t *testing.T) {
+		sanitizer, err := sanitize.NewSanitizer(config.Sanitization{
+			Strategy: "drop",
+			Fields:   fields,
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		require.NoError(t, sanitizer.Sanitize("service", items))
+		require.Equal(t, newItems(), items)
+	})
+
+	t.Run("verify invalid strategy returns error", func(t *testing.T) {
+		_, err := sanitize.NewSanitizer(config.Sanitization{Strategy: "invalid"})
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "invalid sanitize strategy")
+	})
+}
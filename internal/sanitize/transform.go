@@ -0,0 +1,395 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sanitize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/mikefero/osiris/internal/config"
+)
+
+// AllResources is the resource matcher of a transform rule applying to the
+// items of every resource.
+const AllResources = "*"
+
+// defaultRedaction is the value replacing a redacted field when no value is
+// specified.
+const defaultRedaction = "REDACTED"
+
+// ErrInvalidExpression is returned when the expression of a transform rule
+// cannot be parsed.
+var ErrInvalidExpression = errors.New("invalid transform expression")
+
+// operation is a single function call of a transform expression applied to
+// an item.
+type operation func(item map[string]interface{})
+
+// transformRule is a parsed transform rule.
+type transformRule struct {
+	resource   string
+	operations []operation
+}
+
+// Transforms applies expression based rules to the items of resources for
+// release independent field manipulation (e.g. org-specific redactions). The
+// expressions are limited to a fixed set of functions operating on the item
+// so that the rules cannot execute arbitrary code:
+//
+//   - drop(field) removes the field
+//   - rename(field, to) moves the value of the field to another field
+//   - redact(field) or redact(field, value) replaces the value of the field
+//     with REDACTED or the value
+//   - set(field, value) sets the field to the value
+//
+// Fields are dot separated paths (e.g. `config.secret`), either bare or
+// quoted, and values are JSON literals (strings, numbers, booleans, or null).
+// Multiple calls in a single expression are separated by semicolons.
+type Transforms struct {
+	rules []transformRule
+}
+
+// NewTransforms parses the transform rules; an error wrapping
+// ErrInvalidExpression is returned if an expression cannot be parsed.
+func NewTransforms(rules []config.TransformRule) (*Transforms, error) {
+	parsed := make([]transformRule, 0, len(rules))
+	for i, rule := range rules {
+		if len(rule.Resource) == 0 {
+			return nil, fmt.Errorf("transform rule %d: resource is required", i)
+		}
+		operations, err := parseExpression(rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("transform rule %d (%s): %w", i, rule.Resource, err)
+		}
+		parsed = append(parsed, transformRule{resource: rule.Resource, operations: operations})
+	}
+	return &Transforms{rules: parsed}, nil
+}
+
+// Resources returns the resources matched by the rules; AllResources is
+// returned for a rule applying to every resource.
+func (t *Transforms) Resources() []string {
+	resources := make([]string, 0, len(t.rules))
+	for _, rule := range t.rules {
+		resources = append(resources, rule.resource)
+	}
+	return resources
+}
+
+// Transform applies the rules matching the resource to the items in place,
+// in the order the rules are configured.
+func (t *Transforms) Transform(resourceName string, items []map[string]interface{}) {
+	if t == nil {
+		return
+	}
+	for _, rule := range t.rules {
+		if rule.resource != AllResources && rule.resource != resourceName {
+			continue
+		}
+		for _, item := range items {
+			for _, op := range rule.operations {
+				op(item)
+			}
+		}
+	}
+}
+
+// parseExpression parses the semicolon separated function calls of an
+// expression.
+func parseExpression(expression string) ([]operation, error) {
+	var operations []operation
+	p := &expressionParser{input: expression}
+	for {
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		op, err := p.parseCall()
+		if err != nil {
+			return nil, err
+		}
+		operations = append(operations, op)
+		p.skipSpace()
+		if p.done() {
+			break
+		}
+		if !p.consume(';') {
+			return nil, p.errorf("expected ';'")
+		}
+	}
+	if len(operations) == 0 {
+		return nil, fmt.Errorf("%w: empty expression", ErrInvalidExpression)
+	}
+	return operations, nil
+}
+
+// argument is an argument of a function call; literal is true if the
+// argument is a JSON literal rather than a bare field path.
+type argument struct {
+	text    string
+	value   interface{}
+	literal bool
+}
+
+// field returns the dot separated path of a field argument.
+func (a argument) field() ([]string, bool) {
+	text := a.text
+	if a.literal {
+		s, ok := a.value.(string)
+		if !ok {
+			return nil, false
+		}
+		text = s
+	}
+	path := strings.Split(text, ".")
+	for _, key := range path {
+		if len(key) == 0 {
+			return nil, false
+		}
+	}
+	return path, true
+}
+
+type expressionParser struct {
+	input string
+	pos   int
+}
+
+func (p *expressionParser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *expressionParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *expressionParser) consume(c byte) bool {
+	p.skipSpace()
+	if p.done() || p.input[p.pos] != c {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *expressionParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at offset %d of %q", ErrInvalidExpression, fmt.Sprintf(format, args...),
+		p.pos, p.input)
+}
+
+// parseCall parses a function call and returns its operation.
+func (p *expressionParser) parseCall() (operation, error) {
+	name := p.parseBare()
+	if len(name) == 0 {
+		return nil, p.errorf("expected function name")
+	}
+	if !p.consume('(') {
+		return nil, p.errorf("expected '(' after %s", name)
+	}
+	var args []argument
+	for !p.consume(')') {
+		if len(args) > 0 && !p.consume(',') {
+			return nil, p.errorf("expected ',' or ')'")
+		}
+		arg, err := p.parseArgument()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return newOperation(name, args)
+}
+
+// parseBare parses a bare identifier or field path.
+func (p *expressionParser) parseBare() string {
+	p.skipSpace()
+	start := p.pos
+	for !p.done() {
+		c := rune(p.input[p.pos])
+		if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '_' && c != '-' && c != '.' {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+// parseArgument parses a quoted string, a JSON literal, or a bare field path.
+func (p *expressionParser) parseArgument() (argument, error) {
+	p.skipSpace()
+	if p.done() {
+		return argument{}, p.errorf("expected argument")
+	}
+	if p.input[p.pos] == '"' {
+		start := p.pos
+		for p.pos++; !p.done() && p.input[p.pos] != '"'; p.pos++ {
+			if p.input[p.pos] == '\\' {
+				p.pos++
+			}
+		}
+		if p.done() {
+			return argument{}, p.errorf("unterminated string")
+		}
+		p.pos++
+		value, err := strconv.Unquote(p.input[start:p.pos])
+		if err != nil {
+			return argument{}, p.errorf("invalid string: %v", err)
+		}
+		return argument{text: p.input[start:p.pos], value: value, literal: true}, nil
+	}
+	text := p.parseBare()
+	if len(text) == 0 {
+		return argument{}, p.errorf("expected argument")
+	}
+	switch text {
+	case "true":
+		return argument{text: text, value: true, literal: true}, nil
+	case "false":
+		return argument{text: text, value: false, literal: true}, nil
+	case "null":
+		return argument{text: text, value: nil, literal: true}, nil
+	}
+	if _, err := strconv.ParseFloat(text, 64); err == nil {
+		return argument{text: text, value: json.Number(text), literal: true}, nil
+	}
+	return argument{text: text}, nil
+}
+
+// newOperation returns the operation of the function call.
+func newOperation(name string, args []argument) (operation, error) {
+	fields := make([][]string, 0, len(args))
+	for _, arg := range args {
+		path, _ := arg.field()
+		fields = append(fields, path)
+	}
+	requireArgs := func(counts ...int) error {
+		for _, count := range counts {
+			if len(args) == count {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: %s requires %v arguments, got %d", ErrInvalidExpression, name, counts, len(args))
+	}
+	requireField := func(i int) error {
+		if fields[i] == nil {
+			return fmt.Errorf("%w: %s argument %d must be a field, got %s", ErrInvalidExpression, name, i+1,
+				args[i].text)
+		}
+		return nil
+	}
+
+	switch name {
+	case "drop":
+		if err := requireArgs(1); err != nil {
+			return nil, err
+		}
+		if err := requireField(0); err != nil {
+			return nil, err
+		}
+		return func(item map[string]interface{}) {
+			if parent, key, ok := fieldParent(item, fields[0], false); ok {
+				delete(parent, key)
+			}
+		}, nil
+	case "rename":
+		if err := requireArgs(2); err != nil {
+			return nil, err
+		}
+		if err := requireField(0); err != nil {
+			return nil, err
+		}
+		if err := requireField(1); err != nil {
+			return nil, err
+		}
+		return func(item map[string]interface{}) {
+			parent, key, ok := fieldParent(item, fields[0], false)
+			if !ok {
+				return
+			}
+			value, exists := parent[key]
+			if !exists {
+				return
+			}
+			delete(parent, key)
+			if target, targetKey, ok := fieldParent(item, fields[1], true); ok {
+				target[targetKey] = value
+			}
+		}, nil
+	case "redact":
+		if err := requireArgs(1, 2); err != nil {
+			return nil, err
+		}
+		if err := requireField(0); err != nil {
+			return nil, err
+		}
+		var value interface{} = defaultRedaction
+		if len(args) == 2 {
+			if !args[1].literal {
+				return nil, fmt.Errorf("%w: redact value must be a literal, got %s", ErrInvalidExpression, args[1].text)
+			}
+			value = args[1].value
+		}
+		return func(item map[string]interface{}) {
+			if parent, key, ok := fieldParent(item, fields[0], false); ok {
+				if _, exists := parent[key]; exists {
+					parent[key] = value
+				}
+			}
+		}, nil
+	case "set":
+		if err := requireArgs(2); err != nil {
+			return nil, err
+		}
+		if err := requireField(0); err != nil {
+			return nil, err
+		}
+		if !args[1].literal {
+			return nil, fmt.Errorf("%w: set value must be a literal, got %s", ErrInvalidExpression, args[1].text)
+		}
+		value := args[1].value
+		return func(item map[string]interface{}) {
+			if parent, key, ok := fieldParent(item, fields[0], true); ok {
+				parent[key] = value
+			}
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown function %q", ErrInvalidExpression, name)
+	}
+}
+
+// fieldParent returns the object containing the last key of the path along
+// with the key. Missing intermediate objects are created if create is true;
+// otherwise false is returned.
+func fieldParent(item map[string]interface{}, path []string, create bool) (map[string]interface{}, string, bool) {
+	for _, key := range path[:len(path)-1] {
+		nested, ok := item[key].(map[string]interface{})
+		if !ok {
+			if !create || item[key] != nil {
+				return nil, "", false
+			}
+			nested = make(map[string]interface{})
+			item[key] = nested
+		}
+		item = nested
+	}
+	return item, path[len(path)-1], true
+}
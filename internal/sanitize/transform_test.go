@@ -0,0 +1,109 @@
+/*
+Copyright © 2025 Michael Fero
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package sanitize_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/mikefero/osiris/internal/config"
+	"github.com/mikefero/osiris/internal/sanitize"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransforms(t *testing.T) {
+	t.Run("verify a rule drops a field", func(t *testing.T) {
+		transforms, err := sanitize.NewTransforms([]config.TransformRule{
+			{Resource: "key", Expression: "drop(pem.private_key)"},
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		transforms.Transform("key", items)
+		require.Equal(t, map[string]interface{}{"public_key": "public-key"}, items[0]["pem"])
+		require.Equal(t, "secret-jwk", items[0]["jwk"])
+	})
+
+	t.Run("verify a rule renames a key", func(t *testing.T) {
+		transforms, err := sanitize.NewTransforms([]config.TransformRule{
+			{Resource: "key", Expression: `rename(jwk, "material.jwk")`},
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		transforms.Transform("key", items)
+		require.NotContains(t, items[0], "jwk")
+		require.Equal(t, map[string]interface{}{"jwk": "secret-jwk"}, items[0]["material"])
+	})
+
+	t.Run("verify calls are applied in order to the matching resources", func(t *testing.T) {
+		transforms, err := sanitize.NewTransforms([]config.TransformRule{
+			{Resource: "*", Expression: `set(owner, "platform"); set(version, 2)`},
+			{Resource: "key", Expression: `redact(jwk); redact(pem.public_key, null)`},
+			{Resource: "service", Expression: "drop(id)"},
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		transforms.Transform("key", items)
+		require.Equal(t, []map[string]interface{}{
+			{
+				"id":      "key-1",
+				"jwk":     "REDACTED",
+				"owner":   "platform",
+				"version": json.Number("2"),
+				"pem": map[string]interface{}{
+					"private_key": "secret-private-key",
+					"public_key":  nil,
+				},
+			},
+		}, items)
+	})
+
+	t.Run("verify missing fields are left as is", func(t *testing.T) {
+		transforms, err := sanitize.NewTransforms([]config.TransformRule{
+			{Resource: "key", Expression: "drop(missing.field); rename(missing, other); redact(absent)"},
+		})
+		require.NoError(t, err)
+
+		items := newItems()
+		transforms.Transform("key", items)
+		require.Equal(t, newItems(), items)
+	})
+
+	t.Run("verify invalid expressions are rejected", func(t *testing.T) {
+		for _, expression := range []string{
+			"",
+			"drop",
+			"drop(",
+			"drop(a, b)",
+			"rename(a)",
+			"set(a, b)",
+			"set(1, 2)",
+			`redact(a, "unterminated)`,
+			"exec(a)",
+			"drop(a) drop(b)",
+		} {
+			_, err := sanitize.NewTransforms([]config.TransformRule{{Resource: "key", Expression: expression}})
+			require.ErrorIs(t, err, sanitize.ErrInvalidExpression, expression)
+		}
+	})
+
+	t.Run("verify the resource is required", func(t *testing.T) {
+		_, err := sanitize.NewTransforms([]config.TransformRule{{Expression: "drop(a)"}})
+		require.Error(t, err)
+	})
+}